@@ -20,6 +20,7 @@ package externalversions
 import (
 	fmt "fmt"
 
+	v1 "github.com/VictoriaMetrics/operator/api/operator/v1"
 	v1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
@@ -51,7 +52,11 @@ func (f *genericInformer) Lister() cache.GenericLister {
 // TODO extend this to unknown resources with a client pool
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
-	// Group=operator, Version=v1beta1
+	// Group=operator, Version=v1
+	case v1.SchemeGroupVersion.WithResource("vmrules"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1().VMRules().Informer()}, nil
+
+		// Group=operator, Version=v1beta1
 	case v1beta1.SchemeGroupVersion.WithResource("vlogs"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1beta1().VLogs().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("vmagents"):
@@ -62,20 +67,28 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1beta1().VMAlertmanagers().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("vmalertmanagerconfigs"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1beta1().VMAlertmanagerConfigs().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("vmalertmanagersilences"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1beta1().VMAlertmanagerSilences().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("vmauths"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1beta1().VMAuths().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("vmclusters"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1beta1().VMClusters().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("vmclusterscrapedefaults"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1beta1().VMClusterScrapeDefaults().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("vmnodescrapes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1beta1().VMNodeScrapes().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("vmpodscrapes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1beta1().VMPodScrapes().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("vmprobes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1beta1().VMProbes().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("vmrolloutpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1beta1().VMRolloutPolicies().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("vmrules"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1beta1().VMRules().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("vmscrapeconfigs"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1beta1().VMScrapeConfigs().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("vmscrapedefaults"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1beta1().VMScrapeDefaults().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("vmservicescrapes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1beta1().VMServiceScrapes().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("vmsingles"):
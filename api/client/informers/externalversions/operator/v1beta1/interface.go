@@ -33,20 +33,28 @@ type Interface interface {
 	VMAlertmanagers() VMAlertmanagerInformer
 	// VMAlertmanagerConfigs returns a VMAlertmanagerConfigInformer.
 	VMAlertmanagerConfigs() VMAlertmanagerConfigInformer
+	// VMAlertmanagerSilences returns a VMAlertmanagerSilenceInformer.
+	VMAlertmanagerSilences() VMAlertmanagerSilenceInformer
 	// VMAuths returns a VMAuthInformer.
 	VMAuths() VMAuthInformer
 	// VMClusters returns a VMClusterInformer.
 	VMClusters() VMClusterInformer
+	// VMClusterScrapeDefaults returns a VMClusterScrapeDefaultsInformer.
+	VMClusterScrapeDefaults() VMClusterScrapeDefaultsInformer
 	// VMNodeScrapes returns a VMNodeScrapeInformer.
 	VMNodeScrapes() VMNodeScrapeInformer
 	// VMPodScrapes returns a VMPodScrapeInformer.
 	VMPodScrapes() VMPodScrapeInformer
 	// VMProbes returns a VMProbeInformer.
 	VMProbes() VMProbeInformer
+	// VMRolloutPolicies returns a VMRolloutPolicyInformer.
+	VMRolloutPolicies() VMRolloutPolicyInformer
 	// VMRules returns a VMRuleInformer.
 	VMRules() VMRuleInformer
 	// VMScrapeConfigs returns a VMScrapeConfigInformer.
 	VMScrapeConfigs() VMScrapeConfigInformer
+	// VMScrapeDefaults returns a VMScrapeDefaultsInformer.
+	VMScrapeDefaults() VMScrapeDefaultsInformer
 	// VMServiceScrapes returns a VMServiceScrapeInformer.
 	VMServiceScrapes() VMServiceScrapeInformer
 	// VMSingles returns a VMSingleInformer.
@@ -93,6 +101,11 @@ func (v *version) VMAlertmanagerConfigs() VMAlertmanagerConfigInformer {
 	return &vMAlertmanagerConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// VMAlertmanagerSilences returns a VMAlertmanagerSilenceInformer.
+func (v *version) VMAlertmanagerSilences() VMAlertmanagerSilenceInformer {
+	return &vMAlertmanagerSilenceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // VMAuths returns a VMAuthInformer.
 func (v *version) VMAuths() VMAuthInformer {
 	return &vMAuthInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -103,6 +116,11 @@ func (v *version) VMClusters() VMClusterInformer {
 	return &vMClusterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// VMClusterScrapeDefaults returns a VMClusterScrapeDefaultsInformer.
+func (v *version) VMClusterScrapeDefaults() VMClusterScrapeDefaultsInformer {
+	return &vMClusterScrapeDefaultsInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // VMNodeScrapes returns a VMNodeScrapeInformer.
 func (v *version) VMNodeScrapes() VMNodeScrapeInformer {
 	return &vMNodeScrapeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -118,6 +136,11 @@ func (v *version) VMProbes() VMProbeInformer {
 	return &vMProbeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// VMRolloutPolicies returns a VMRolloutPolicyInformer.
+func (v *version) VMRolloutPolicies() VMRolloutPolicyInformer {
+	return &vMRolloutPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // VMRules returns a VMRuleInformer.
 func (v *version) VMRules() VMRuleInformer {
 	return &vMRuleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -128,6 +151,11 @@ func (v *version) VMScrapeConfigs() VMScrapeConfigInformer {
 	return &vMScrapeConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// VMScrapeDefaults returns a VMScrapeDefaultsInformer.
+func (v *version) VMScrapeDefaults() VMScrapeDefaultsInformer {
+	return &vMScrapeDefaultsInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // VMServiceScrapes returns a VMServiceScrapeInformer.
 func (v *version) VMServiceScrapes() VMServiceScrapeInformer {
 	return &vMServiceScrapeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -57,6 +57,14 @@ type VMAlertmanagerConfigListerExpansion interface{}
 // VMAlertmanagerConfigNamespaceLister.
 type VMAlertmanagerConfigNamespaceListerExpansion interface{}
 
+// VMAlertmanagerSilenceListerExpansion allows custom methods to be added to
+// VMAlertmanagerSilenceLister.
+type VMAlertmanagerSilenceListerExpansion interface{}
+
+// VMAlertmanagerSilenceNamespaceListerExpansion allows custom methods to be added to
+// VMAlertmanagerSilenceNamespaceLister.
+type VMAlertmanagerSilenceNamespaceListerExpansion interface{}
+
 // VMAuthListerExpansion allows custom methods to be added to
 // VMAuthLister.
 type VMAuthListerExpansion interface{}
@@ -73,6 +81,10 @@ type VMClusterListerExpansion interface{}
 // VMClusterNamespaceLister.
 type VMClusterNamespaceListerExpansion interface{}
 
+// VMClusterScrapeDefaultsListerExpansion allows custom methods to be added to
+// VMClusterScrapeDefaultsLister.
+type VMClusterScrapeDefaultsListerExpansion interface{}
+
 // VMNodeScrapeListerExpansion allows custom methods to be added to
 // VMNodeScrapeLister.
 type VMNodeScrapeListerExpansion interface{}
@@ -97,6 +109,14 @@ type VMProbeListerExpansion interface{}
 // VMProbeNamespaceLister.
 type VMProbeNamespaceListerExpansion interface{}
 
+// VMRolloutPolicyListerExpansion allows custom methods to be added to
+// VMRolloutPolicyLister.
+type VMRolloutPolicyListerExpansion interface{}
+
+// VMRolloutPolicyNamespaceListerExpansion allows custom methods to be added to
+// VMRolloutPolicyNamespaceLister.
+type VMRolloutPolicyNamespaceListerExpansion interface{}
+
 // VMRuleListerExpansion allows custom methods to be added to
 // VMRuleLister.
 type VMRuleListerExpansion interface{}
@@ -113,6 +133,14 @@ type VMScrapeConfigListerExpansion interface{}
 // VMScrapeConfigNamespaceLister.
 type VMScrapeConfigNamespaceListerExpansion interface{}
 
+// VMScrapeDefaultsListerExpansion allows custom methods to be added to
+// VMScrapeDefaultsLister.
+type VMScrapeDefaultsListerExpansion interface{}
+
+// VMScrapeDefaultsNamespaceListerExpansion allows custom methods to be added to
+// VMScrapeDefaultsNamespaceLister.
+type VMScrapeDefaultsNamespaceListerExpansion interface{}
+
 // VMServiceScrapeListerExpansion allows custom methods to be added to
 // VMServiceScrapeLister.
 type VMServiceScrapeListerExpansion interface{}
@@ -0,0 +1,47 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen-v0.32. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	operatorv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	listers "k8s.io/client-go/listers"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// VMClusterScrapeDefaultsLister helps list VMClusterScrapeDefaults.
+// All objects returned here must be treated as read-only.
+type VMClusterScrapeDefaultsLister interface {
+	// List lists all VMClusterScrapeDefaults in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*operatorv1beta1.VMClusterScrapeDefaults, err error)
+	// Get retrieves the VMClusterScrapeDefaults from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*operatorv1beta1.VMClusterScrapeDefaults, error)
+	VMClusterScrapeDefaultsListerExpansion
+}
+
+// vMClusterScrapeDefaultsLister implements the VMClusterScrapeDefaultsLister interface.
+type vMClusterScrapeDefaultsLister struct {
+	listers.ResourceIndexer[*operatorv1beta1.VMClusterScrapeDefaults]
+}
+
+// NewVMClusterScrapeDefaultsLister returns a new VMClusterScrapeDefaultsLister.
+func NewVMClusterScrapeDefaultsLister(indexer cache.Indexer) VMClusterScrapeDefaultsLister {
+	return &vMClusterScrapeDefaultsLister{listers.New[*operatorv1beta1.VMClusterScrapeDefaults](indexer, operatorv1beta1.Resource("vmclusterscrapedefaults"))}
+}
@@ -0,0 +1,69 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen-v0.32. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	operatorv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	listers "k8s.io/client-go/listers"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// VMRolloutPolicyLister helps list VMRolloutPolicies.
+// All objects returned here must be treated as read-only.
+type VMRolloutPolicyLister interface {
+	// List lists all VMRolloutPolicies in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*operatorv1beta1.VMRolloutPolicy, err error)
+	// VMRolloutPolicies returns an object that can list and get VMRolloutPolicies.
+	VMRolloutPolicies(namespace string) VMRolloutPolicyNamespaceLister
+	VMRolloutPolicyListerExpansion
+}
+
+// vMRolloutPolicyLister implements the VMRolloutPolicyLister interface.
+type vMRolloutPolicyLister struct {
+	listers.ResourceIndexer[*operatorv1beta1.VMRolloutPolicy]
+}
+
+// NewVMRolloutPolicyLister returns a new VMRolloutPolicyLister.
+func NewVMRolloutPolicyLister(indexer cache.Indexer) VMRolloutPolicyLister {
+	return &vMRolloutPolicyLister{listers.New[*operatorv1beta1.VMRolloutPolicy](indexer, operatorv1beta1.Resource("vmrolloutpolicy"))}
+}
+
+// VMRolloutPolicies returns an object that can list and get VMRolloutPolicies.
+func (s *vMRolloutPolicyLister) VMRolloutPolicies(namespace string) VMRolloutPolicyNamespaceLister {
+	return vMRolloutPolicyNamespaceLister{listers.NewNamespaced[*operatorv1beta1.VMRolloutPolicy](s.ResourceIndexer, namespace)}
+}
+
+// VMRolloutPolicyNamespaceLister helps list and get VMRolloutPolicies.
+// All objects returned here must be treated as read-only.
+type VMRolloutPolicyNamespaceLister interface {
+	// List lists all VMRolloutPolicies in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*operatorv1beta1.VMRolloutPolicy, err error)
+	// Get retrieves the VMRolloutPolicy from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*operatorv1beta1.VMRolloutPolicy, error)
+	VMRolloutPolicyNamespaceListerExpansion
+}
+
+// vMRolloutPolicyNamespaceLister implements the VMRolloutPolicyNamespaceLister
+// interface.
+type vMRolloutPolicyNamespaceLister struct {
+	listers.ResourceIndexer[*operatorv1beta1.VMRolloutPolicy]
+}
@@ -0,0 +1,69 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen-v0.32. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	operatorv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	listers "k8s.io/client-go/listers"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// VMScrapeDefaultsLister helps list VMScrapeDefaults.
+// All objects returned here must be treated as read-only.
+type VMScrapeDefaultsLister interface {
+	// List lists all VMScrapeDefaults in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*operatorv1beta1.VMScrapeDefaults, err error)
+	// VMScrapeDefaults returns an object that can list and get VMScrapeDefaults.
+	VMScrapeDefaults(namespace string) VMScrapeDefaultsNamespaceLister
+	VMScrapeDefaultsListerExpansion
+}
+
+// vMScrapeDefaultsLister implements the VMScrapeDefaultsLister interface.
+type vMScrapeDefaultsLister struct {
+	listers.ResourceIndexer[*operatorv1beta1.VMScrapeDefaults]
+}
+
+// NewVMScrapeDefaultsLister returns a new VMScrapeDefaultsLister.
+func NewVMScrapeDefaultsLister(indexer cache.Indexer) VMScrapeDefaultsLister {
+	return &vMScrapeDefaultsLister{listers.New[*operatorv1beta1.VMScrapeDefaults](indexer, operatorv1beta1.Resource("vmscrapedefaults"))}
+}
+
+// VMScrapeDefaults returns an object that can list and get VMScrapeDefaults.
+func (s *vMScrapeDefaultsLister) VMScrapeDefaults(namespace string) VMScrapeDefaultsNamespaceLister {
+	return vMScrapeDefaultsNamespaceLister{listers.NewNamespaced[*operatorv1beta1.VMScrapeDefaults](s.ResourceIndexer, namespace)}
+}
+
+// VMScrapeDefaultsNamespaceLister helps list and get VMScrapeDefaults.
+// All objects returned here must be treated as read-only.
+type VMScrapeDefaultsNamespaceLister interface {
+	// List lists all VMScrapeDefaults in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*operatorv1beta1.VMScrapeDefaults, err error)
+	// Get retrieves the VMScrapeDefaults from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*operatorv1beta1.VMScrapeDefaults, error)
+	VMScrapeDefaultsNamespaceListerExpansion
+}
+
+// vMScrapeDefaultsNamespaceLister implements the VMScrapeDefaultsNamespaceLister
+// interface.
+type vMScrapeDefaultsNamespaceLister struct {
+	listers.ResourceIndexer[*operatorv1beta1.VMScrapeDefaults]
+}
@@ -0,0 +1,47 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen-v0.32. DO NOT EDIT.
+
+package fake
+
+import (
+	operatorv1 "github.com/VictoriaMetrics/operator/api/client/versioned/typed/operator/v1"
+	v1 "github.com/VictoriaMetrics/operator/api/operator/v1"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// fakeVMRules implements VMRuleInterface
+type fakeVMRules struct {
+	*gentype.FakeClientWithList[*v1.VMRule, *v1.VMRuleList]
+	Fake *FakeOperatorV1
+}
+
+func newFakeVMRules(fake *FakeOperatorV1, namespace string) operatorv1.VMRuleInterface {
+	return &fakeVMRules{
+		gentype.NewFakeClientWithList[*v1.VMRule, *v1.VMRuleList](
+			fake.Fake,
+			namespace,
+			v1.SchemeGroupVersion.WithResource("vmrules"),
+			v1.SchemeGroupVersion.WithKind("VMRule"),
+			func() *v1.VMRule { return &v1.VMRule{} },
+			func() *v1.VMRuleList { return &v1.VMRuleList{} },
+			func(dst, src *v1.VMRuleList) { dst.ListMeta = src.ListMeta },
+			func(list *v1.VMRuleList) []*v1.VMRule { return gentype.ToPointerSlice(list.Items) },
+			func(list *v1.VMRuleList, items []*v1.VMRule) { list.Items = gentype.FromPointerSlice(items) },
+		),
+		fake,
+	}
+}
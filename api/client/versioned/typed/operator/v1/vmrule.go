@@ -0,0 +1,69 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen-v0.32. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+
+	scheme "github.com/VictoriaMetrics/operator/api/client/versioned/scheme"
+	operatorv1 "github.com/VictoriaMetrics/operator/api/operator/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// VMRulesGetter has a method to return a VMRuleInterface.
+// A group's client should implement this interface.
+type VMRulesGetter interface {
+	VMRules(namespace string) VMRuleInterface
+}
+
+// VMRuleInterface has methods to work with VMRule resources.
+type VMRuleInterface interface {
+	Create(ctx context.Context, vMRule *operatorv1.VMRule, opts metav1.CreateOptions) (*operatorv1.VMRule, error)
+	Update(ctx context.Context, vMRule *operatorv1.VMRule, opts metav1.UpdateOptions) (*operatorv1.VMRule, error)
+	// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+	UpdateStatus(ctx context.Context, vMRule *operatorv1.VMRule, opts metav1.UpdateOptions) (*operatorv1.VMRule, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*operatorv1.VMRule, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*operatorv1.VMRuleList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *operatorv1.VMRule, err error)
+	VMRuleExpansion
+}
+
+// vMRules implements VMRuleInterface
+type vMRules struct {
+	*gentype.ClientWithList[*operatorv1.VMRule, *operatorv1.VMRuleList]
+}
+
+// newVMRules returns a VMRules
+func newVMRules(c *OperatorV1Client, namespace string) *vMRules {
+	return &vMRules{
+		gentype.NewClientWithList[*operatorv1.VMRule, *operatorv1.VMRuleList](
+			"vmrules",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			namespace,
+			func() *operatorv1.VMRule { return &operatorv1.VMRule{} },
+			func() *operatorv1.VMRuleList { return &operatorv1.VMRuleList{} },
+		),
+	}
+}
@@ -47,6 +47,10 @@ func (c *FakeOperatorV1beta1) VMAlertmanagerConfigs(namespace string) v1beta1.VM
 	return newFakeVMAlertmanagerConfigs(c, namespace)
 }
 
+func (c *FakeOperatorV1beta1) VMAlertmanagerSilences(namespace string) v1beta1.VMAlertmanagerSilenceInterface {
+	return newFakeVMAlertmanagerSilences(c, namespace)
+}
+
 func (c *FakeOperatorV1beta1) VMAuths(namespace string) v1beta1.VMAuthInterface {
 	return newFakeVMAuths(c, namespace)
 }
@@ -55,6 +59,10 @@ func (c *FakeOperatorV1beta1) VMClusters(namespace string) v1beta1.VMClusterInte
 	return newFakeVMClusters(c, namespace)
 }
 
+func (c *FakeOperatorV1beta1) VMClusterScrapeDefaults() v1beta1.VMClusterScrapeDefaultsInterface {
+	return newFakeVMClusterScrapeDefaults(c)
+}
+
 func (c *FakeOperatorV1beta1) VMNodeScrapes(namespace string) v1beta1.VMNodeScrapeInterface {
 	return newFakeVMNodeScrapes(c, namespace)
 }
@@ -67,6 +75,10 @@ func (c *FakeOperatorV1beta1) VMProbes(namespace string) v1beta1.VMProbeInterfac
 	return newFakeVMProbes(c, namespace)
 }
 
+func (c *FakeOperatorV1beta1) VMRolloutPolicies(namespace string) v1beta1.VMRolloutPolicyInterface {
+	return newFakeVMRolloutPolicies(c, namespace)
+}
+
 func (c *FakeOperatorV1beta1) VMRules(namespace string) v1beta1.VMRuleInterface {
 	return newFakeVMRules(c, namespace)
 }
@@ -75,6 +87,10 @@ func (c *FakeOperatorV1beta1) VMScrapeConfigs(namespace string) v1beta1.VMScrape
 	return newFakeVMScrapeConfigs(c, namespace)
 }
 
+func (c *FakeOperatorV1beta1) VMScrapeDefaults(namespace string) v1beta1.VMScrapeDefaultsInterface {
+	return newFakeVMScrapeDefaults(c, namespace)
+}
+
 func (c *FakeOperatorV1beta1) VMServiceScrapes(namespace string) v1beta1.VMServiceScrapeInterface {
 	return newFakeVMServiceScrapes(c, namespace)
 }
@@ -0,0 +1,51 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen-v0.32. DO NOT EDIT.
+
+package fake
+
+import (
+	operatorv1beta1 "github.com/VictoriaMetrics/operator/api/client/versioned/typed/operator/v1beta1"
+	v1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// fakeVMAlertmanagerSilences implements VMAlertmanagerSilenceInterface
+type fakeVMAlertmanagerSilences struct {
+	*gentype.FakeClientWithList[*v1beta1.VMAlertmanagerSilence, *v1beta1.VMAlertmanagerSilenceList]
+	Fake *FakeOperatorV1beta1
+}
+
+func newFakeVMAlertmanagerSilences(fake *FakeOperatorV1beta1, namespace string) operatorv1beta1.VMAlertmanagerSilenceInterface {
+	return &fakeVMAlertmanagerSilences{
+		gentype.NewFakeClientWithList[*v1beta1.VMAlertmanagerSilence, *v1beta1.VMAlertmanagerSilenceList](
+			fake.Fake,
+			namespace,
+			v1beta1.SchemeGroupVersion.WithResource("vmalertmanagersilences"),
+			v1beta1.SchemeGroupVersion.WithKind("VMAlertmanagerSilence"),
+			func() *v1beta1.VMAlertmanagerSilence { return &v1beta1.VMAlertmanagerSilence{} },
+			func() *v1beta1.VMAlertmanagerSilenceList { return &v1beta1.VMAlertmanagerSilenceList{} },
+			func(dst, src *v1beta1.VMAlertmanagerSilenceList) { dst.ListMeta = src.ListMeta },
+			func(list *v1beta1.VMAlertmanagerSilenceList) []*v1beta1.VMAlertmanagerSilence {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *v1beta1.VMAlertmanagerSilenceList, items []*v1beta1.VMAlertmanagerSilence) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
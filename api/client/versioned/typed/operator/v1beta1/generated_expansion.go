@@ -27,20 +27,28 @@ type VMAlertmanagerExpansion interface{}
 
 type VMAlertmanagerConfigExpansion interface{}
 
+type VMAlertmanagerSilenceExpansion interface{}
+
 type VMAuthExpansion interface{}
 
 type VMClusterExpansion interface{}
 
+type VMClusterScrapeDefaultsExpansion interface{}
+
 type VMNodeScrapeExpansion interface{}
 
 type VMPodScrapeExpansion interface{}
 
 type VMProbeExpansion interface{}
 
+type VMRolloutPolicyExpansion interface{}
+
 type VMRuleExpansion interface{}
 
 type VMScrapeConfigExpansion interface{}
 
+type VMScrapeDefaultsExpansion interface{}
+
 type VMServiceScrapeExpansion interface{}
 
 type VMSingleExpansion interface{}
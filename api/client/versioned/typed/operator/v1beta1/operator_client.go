@@ -32,13 +32,17 @@ type OperatorV1beta1Interface interface {
 	VMAlertsGetter
 	VMAlertmanagersGetter
 	VMAlertmanagerConfigsGetter
+	VMAlertmanagerSilencesGetter
 	VMAuthsGetter
 	VMClustersGetter
+	VMClusterScrapeDefaultsGetter
 	VMNodeScrapesGetter
 	VMPodScrapesGetter
 	VMProbesGetter
+	VMRolloutPoliciesGetter
 	VMRulesGetter
 	VMScrapeConfigsGetter
+	VMScrapeDefaultsGetter
 	VMServiceScrapesGetter
 	VMSinglesGetter
 	VMStaticScrapesGetter
@@ -70,6 +74,10 @@ func (c *OperatorV1beta1Client) VMAlertmanagerConfigs(namespace string) VMAlertm
 	return newVMAlertmanagerConfigs(c, namespace)
 }
 
+func (c *OperatorV1beta1Client) VMAlertmanagerSilences(namespace string) VMAlertmanagerSilenceInterface {
+	return newVMAlertmanagerSilences(c, namespace)
+}
+
 func (c *OperatorV1beta1Client) VMAuths(namespace string) VMAuthInterface {
 	return newVMAuths(c, namespace)
 }
@@ -78,6 +86,10 @@ func (c *OperatorV1beta1Client) VMClusters(namespace string) VMClusterInterface
 	return newVMClusters(c, namespace)
 }
 
+func (c *OperatorV1beta1Client) VMClusterScrapeDefaults() VMClusterScrapeDefaultsInterface {
+	return newVMClusterScrapeDefaults(c)
+}
+
 func (c *OperatorV1beta1Client) VMNodeScrapes(namespace string) VMNodeScrapeInterface {
 	return newVMNodeScrapes(c, namespace)
 }
@@ -90,6 +102,10 @@ func (c *OperatorV1beta1Client) VMProbes(namespace string) VMProbeInterface {
 	return newVMProbes(c, namespace)
 }
 
+func (c *OperatorV1beta1Client) VMRolloutPolicies(namespace string) VMRolloutPolicyInterface {
+	return newVMRolloutPolicies(c, namespace)
+}
+
 func (c *OperatorV1beta1Client) VMRules(namespace string) VMRuleInterface {
 	return newVMRules(c, namespace)
 }
@@ -98,6 +114,10 @@ func (c *OperatorV1beta1Client) VMScrapeConfigs(namespace string) VMScrapeConfig
 	return newVMScrapeConfigs(c, namespace)
 }
 
+func (c *OperatorV1beta1Client) VMScrapeDefaults(namespace string) VMScrapeDefaultsInterface {
+	return newVMScrapeDefaults(c, namespace)
+}
+
 func (c *OperatorV1beta1Client) VMServiceScrapes(namespace string) VMServiceScrapeInterface {
 	return newVMServiceScrapes(c, namespace)
 }
@@ -0,0 +1,158 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+)
+
+// ConversionDataAnnotation holds v1beta1 fields, which have no v1 equivalent
+// it allows to round-trip objects between versions without loss
+const ConversionDataAnnotation = "operator.victoriametrics.com/v1beta1-conversion-data"
+
+var _ conversion.Convertible = (*VMRule)(nil)
+
+// vmRuleConversionData contains deprecated v1beta1 VMRule fields
+type vmRuleConversionData struct {
+	// ExtraFilterLabels by group name
+	ExtraFilterLabels map[string]map[string]string `json:"extraFilterLabels,omitempty"`
+}
+
+// ConvertTo converts VMRule to the hub version
+func (cr *VMRule) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*vmv1beta1.VMRule)
+	if !ok {
+		return fmt.Errorf("BUG: unexpected conversion hub type: %T", dstRaw)
+	}
+	var data vmRuleConversionData
+	if v, ok := cr.Annotations[ConversionDataAnnotation]; ok {
+		if err := json.Unmarshal([]byte(v), &data); err != nil {
+			return fmt.Errorf("cannot parse annotation=%q: %w", ConversionDataAnnotation, err)
+		}
+	}
+	dst.ObjectMeta = *cr.ObjectMeta.DeepCopy()
+	delete(dst.Annotations, ConversionDataAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+	dst.Spec.Groups = nil
+	for _, g := range cr.Spec.Groups {
+		dg := vmv1beta1.RuleGroup{
+			Name:              g.Name,
+			Interval:          g.Interval,
+			Limit:             g.Limit,
+			EvalOffset:        g.EvalOffset,
+			EvalDelay:         g.EvalDelay,
+			EvalAlignment:     clonePtr(g.EvalAlignment),
+			Concurrency:       g.Concurrency,
+			Labels:            maps.Clone(g.Labels),
+			ExtraFilterLabels: maps.Clone(data.ExtraFilterLabels[g.Name]),
+			Tenant:            g.Tenant,
+			Params:            cloneValues(g.Params),
+			Type:              g.Type,
+			Headers:           slices.Clone(g.Headers),
+			NotifierHeaders:   slices.Clone(g.NotifierHeaders),
+		}
+		for _, r := range g.Rules {
+			dg.Rules = append(dg.Rules, vmv1beta1.Rule{
+				Record:             r.Record,
+				Alert:              r.Alert,
+				Expr:               r.Expr,
+				Debug:              clonePtr(r.Debug),
+				For:                r.For,
+				KeepFiringFor:      r.KeepFiringFor,
+				Labels:             maps.Clone(r.Labels),
+				Annotations:        maps.Clone(r.Annotations),
+				UpdateEntriesLimit: clonePtr(r.UpdateEntriesLimit),
+			})
+		}
+		dst.Spec.Groups = append(dst.Spec.Groups, dg)
+	}
+	dst.Status.StatusMetadata = *cr.Status.StatusMetadata.DeepCopy()
+	return nil
+}
+
+// ConvertFrom converts VMRule from the hub version
+func (cr *VMRule) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*vmv1beta1.VMRule)
+	if !ok {
+		return fmt.Errorf("BUG: unexpected conversion hub type: %T", srcRaw)
+	}
+	var data vmRuleConversionData
+	cr.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	cr.Spec.Groups = nil
+	for _, g := range src.Spec.Groups {
+		if len(g.ExtraFilterLabels) > 0 {
+			if data.ExtraFilterLabels == nil {
+				data.ExtraFilterLabels = make(map[string]map[string]string)
+			}
+			data.ExtraFilterLabels[g.Name] = maps.Clone(g.ExtraFilterLabels)
+		}
+		dg := RuleGroup{
+			Name:            g.Name,
+			Interval:        g.Interval,
+			Limit:           g.Limit,
+			EvalOffset:      g.EvalOffset,
+			EvalDelay:       g.EvalDelay,
+			EvalAlignment:   clonePtr(g.EvalAlignment),
+			Concurrency:     g.Concurrency,
+			Labels:          maps.Clone(g.Labels),
+			Tenant:          g.Tenant,
+			Params:          cloneValues(g.Params),
+			Type:            g.Type,
+			Headers:         slices.Clone(g.Headers),
+			NotifierHeaders: slices.Clone(g.NotifierHeaders),
+		}
+		for _, r := range g.Rules {
+			dg.Rules = append(dg.Rules, Rule{
+				Record:             r.Record,
+				Alert:              r.Alert,
+				Expr:               r.Expr,
+				Debug:              clonePtr(r.Debug),
+				For:                r.For,
+				KeepFiringFor:      r.KeepFiringFor,
+				Labels:             maps.Clone(r.Labels),
+				Annotations:        maps.Clone(r.Annotations),
+				UpdateEntriesLimit: clonePtr(r.UpdateEntriesLimit),
+			})
+		}
+		cr.Spec.Groups = append(cr.Spec.Groups, dg)
+	}
+	if data.ExtraFilterLabels != nil {
+		v, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("cannot marshal conversion data: %w", err)
+		}
+		if cr.Annotations == nil {
+			cr.Annotations = make(map[string]string)
+		}
+		cr.Annotations[ConversionDataAnnotation] = string(v)
+	}
+	cr.Status.StatusMetadata = *src.Status.StatusMetadata.DeepCopy()
+	return nil
+}
+
+func clonePtr[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+func cloneValues(v url.Values) url.Values {
+	if v == nil {
+		return nil
+	}
+	c := make(url.Values, len(v))
+	for k, vs := range v {
+		c[k] = slices.Clone(vs)
+	}
+	return c
+}
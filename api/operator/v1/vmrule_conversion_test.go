@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+)
+
+func TestVMRuleConversionRoundTrip(t *testing.T) {
+	f := func(src *vmv1beta1.VMRule) {
+		t.Helper()
+		var spoke VMRule
+		assert.NoError(t, spoke.ConvertFrom(src.DeepCopy()))
+		var got vmv1beta1.VMRule
+		assert.NoError(t, spoke.ConvertTo(&got))
+		assert.Equal(t, src, &got)
+	}
+
+	// empty object
+	f(&vmv1beta1.VMRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: "default"},
+	})
+
+	// all fields
+	f(&vmv1beta1.VMRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "full",
+			Namespace:   "default",
+			Annotations: map[string]string{"team": "infra"},
+			Generation:  2,
+		},
+		Spec: vmv1beta1.VMRuleSpec{Groups: []vmv1beta1.RuleGroup{
+			{
+				Name:              "group-1",
+				Interval:          "1m",
+				Limit:             10,
+				EvalOffset:        "10s",
+				EvalDelay:         "30s",
+				EvalAlignment:     ptr.To(false),
+				Concurrency:       2,
+				Labels:            map[string]string{"env": "prod"},
+				ExtraFilterLabels: map[string]string{"job": "vmagent"},
+				Tenant:            "1:2",
+				Params:            url.Values{"nocache": {"1"}},
+				Type:              "prometheus",
+				Headers:           []string{"X-Scope: 1"},
+				NotifierHeaders:   []string{"X-Notifier: 1"},
+				Rules: []vmv1beta1.Rule{
+					{
+						Alert:              "TooManyRestarts",
+						Expr:               "changes(process_start_time_seconds[15m]) > 2",
+						Debug:              ptr.To(true),
+						For:                "5m",
+						KeepFiringFor:      "10m",
+						Labels:             map[string]string{"severity": "critical"},
+						Annotations:        map[string]string{"summary": "restarts"},
+						UpdateEntriesLimit: ptr.To(5),
+					},
+					{
+						Record: "job:up:sum",
+						Expr:   "sum(up) by (job)",
+					},
+				},
+			},
+			{
+				Name:  "group-2",
+				Rules: []vmv1beta1.Rule{{Record: "up:count", Expr: "count(up)"}},
+			},
+		}},
+		Status: vmv1beta1.VMRuleStatus{StatusMetadata: vmv1beta1.StatusMetadata{
+			UpdateStatus:       vmv1beta1.UpdateStatusOperational,
+			ObservedGeneration: 2,
+		}},
+	})
+}
+
+func TestVMRuleConvertFrom(t *testing.T) {
+	src := &vmv1beta1.VMRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "rule", Namespace: "default"},
+		Spec: vmv1beta1.VMRuleSpec{Groups: []vmv1beta1.RuleGroup{{
+			Name:              "group",
+			EvalOffset:        "10s",
+			ExtraFilterLabels: map[string]string{"job": "vmagent"},
+			Rules:             []vmv1beta1.Rule{{Alert: "Down", Expr: "up == 0", KeepFiringFor: "1m"}},
+		}}},
+	}
+	var got VMRule
+	assert.NoError(t, got.ConvertFrom(src))
+	assert.Equal(t, "10s", got.Spec.Groups[0].EvalOffset)
+	assert.Equal(t, "1m", got.Spec.Groups[0].Rules[0].KeepFiringFor)
+	assert.Equal(t, `{"extraFilterLabels":{"group":{"job":"vmagent"}}}`, got.Annotations[ConversionDataAnnotation])
+	// source object must not be changed
+	assert.Nil(t, src.Annotations)
+
+	// incorrect conversion data
+	got.Annotations[ConversionDataAnnotation] = "{"
+	var dst vmv1beta1.VMRule
+	assert.Error(t, got.ConvertTo(&dst))
+}
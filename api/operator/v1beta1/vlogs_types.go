@@ -43,6 +43,13 @@ type VLogsSpec struct {
 	// created by operator for the given CustomResource
 	ManagedMetadata *ManagedObjectsMetadata `json:"managedMetadata,omitempty"`
 
+	// MaintenanceWindows defines time ranges, when disruptive changes are allowed:
+	// Rolling update of Deployment pods and PVC expansion.
+	// Such changes are deferred until the next window and listed at PendingMaintenance status condition.
+	// Other changes are applied as usual. Changes are applied immediately if windows are not defined.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
 	CommonDefaultableParams           `json:",inline,omitempty"`
 	CommonApplicationDeploymentParams `json:",inline,omitempty"`
 
@@ -210,7 +217,7 @@ func (cr *VLogs) ProbeNeedLiveness() bool {
 
 func (cr *VLogs) AnnotationsFiltered() map[string]string {
 	// TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
-	dst := filterMapKeysByPrefixes(cr.ObjectMeta.Annotations, annotationFilterPrefixes)
+	dst := filterMapKeysByPrefixes(cr.ObjectMeta.Annotations, getAnnotationFilterPrefixes())
 	if cr.Spec.ManagedMetadata != nil {
 		if dst == nil {
 			dst = make(map[string]string)
@@ -248,7 +255,7 @@ func (cr *VLogs) AllLabels() map[string]string {
 	var result map[string]string
 	// TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
 	if cr.ObjectMeta.Labels != nil {
-		result = filterMapKeysByPrefixes(cr.ObjectMeta.Labels, labelFilterPrefixes)
+		result = filterMapKeysByPrefixes(cr.ObjectMeta.Labels, getLabelFilterPrefixes())
 	}
 	if cr.Spec.ManagedMetadata != nil {
 		result = labels.Merge(result, cr.Spec.ManagedMetadata.Labels)
@@ -331,6 +338,21 @@ func (cr *VLogs) Paused() bool {
 	return cr.Spec.Paused
 }
 
+// GetStatusMetadata returns metadata for object status
+func (cr *VLogs) GetStatusMetadata() *StatusMetadata {
+	return &cr.Status.StatusMetadata
+}
+
+// IsDriftReportOnly checks if child objects modified outside of operator must not be reverted
+func (cr *VLogs) IsDriftReportOnly() bool {
+	return cr.Annotations[DriftPolicyAnnotation] == DriftPolicyReportOnly
+}
+
+// GetMaintenanceWindows returns time ranges, when disruptive changes are allowed
+func (cr *VLogs) GetMaintenanceWindows() []MaintenanceWindow {
+	return cr.Spec.MaintenanceWindows
+}
+
 // SetStatusTo changes update status with optional reason of fail
 func (cr *VLogs) SetUpdateStatusTo(ctx context.Context, c client.Client, status UpdateStatus, maybeErr error) error {
 	return updateObjectStatus(ctx, c, &patchStatusOpts[*VLogs, *VLogsStatus]{
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
 
@@ -238,6 +239,12 @@ type VMAgentSpec struct {
 	// see [here](https://docs.victoriametrics.com/vmagent/#scraping-big-number-of-targets)
 	// +optional
 	ShardCount *int `json:"shardCount,omitempty"`
+	// ConfigCanary enables canary rollout for scrape configuration changes.
+	// New configuration is applied to the canary shard first and promoted to all shards
+	// only if canary stays healthy during soak duration.
+	// Requires shardCount > 1
+	// +optional
+	ConfigCanary *VMAgentConfigCanary `json:"configCanary,omitempty"`
 
 	// UpdateStrategy - overrides default update strategy.
 	// works only for deployments, statefulset always use OnDelete.
@@ -298,6 +305,12 @@ type VMAgentSpec struct {
 	// set it to RollingUpdate for disabling operator statefulSet rollingUpdate
 	// +optional
 	StatefulRollingUpdateStrategy appsv1.StatefulSetUpdateStrategyType `json:"statefulRollingUpdateStrategy,omitempty"`
+	// MaintenanceWindows defines time ranges, when disruptive changes are allowed:
+	// StatefulSet recreation, rolling update of StatefulSet, Deployment and DaemonSet pods and PVC expansion.
+	// Such changes are deferred until the next window and listed at PendingMaintenance status condition.
+	// Other changes are applied as usual. Changes are applied immediately if windows are not defined.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 
 	// ClaimTemplates allows adding additional VolumeClaimTemplates for VMAgent in StatefulMode
 	ClaimTemplates []v1.PersistentVolumeClaim `json:"claimTemplates,omitempty"`
@@ -356,8 +369,8 @@ func (cr *VMAgent) Validate() error {
 		}
 	}
 	for idx, rw := range cr.Spec.RemoteWrite {
-		if rw.URL == "" {
-			return fmt.Errorf("remoteWrite.url cannot be empty at idx: %d", idx)
+		if err := validateRemoteURL(rw.URL, rw.TargetRef); err != nil {
+			return fmt.Errorf("remoteWrite at idx: %d: %w", idx, err)
 		}
 		if len(rw.InlineUrlRelabelConfig) > 0 {
 			if err := checkRelabelConfigs(rw.InlineUrlRelabelConfig); err != nil {
@@ -368,6 +381,19 @@ func (cr *VMAgent) Validate() error {
 	if cr.Spec.DaemonSetMode && cr.Spec.StatefulMode {
 		return fmt.Errorf("daemonSetMode and statefulMode cannot be used in the same time")
 	}
+	if cc := cr.Spec.ConfigCanary; cc != nil {
+		if cr.Spec.DaemonSetMode || cr.Spec.ShardCount == nil || *cr.Spec.ShardCount < 2 {
+			return fmt.Errorf("configCanary requires shardCount > 1 and cannot be used with daemonSetMode")
+		}
+		if cc.GetShardNum() >= *cr.Spec.ShardCount {
+			return fmt.Errorf("configCanary.shardNum=%d must be less than shardCount=%d", cc.GetShardNum(), *cr.Spec.ShardCount)
+		}
+		if cc.SoakDuration != "" {
+			if _, err := time.ParseDuration(cc.SoakDuration); err != nil {
+				return fmt.Errorf("cannot parse configCanary.soakDuration: %w", err)
+			}
+		}
+	}
 	if cr.Spec.DaemonSetMode {
 		if cr.Spec.PodDisruptionBudget != nil {
 			return fmt.Errorf("podDisruptionBudget cannot be used with daemonSetMode")
@@ -443,7 +469,12 @@ type VMAgentRemoteWriteSettings struct {
 // +k8s:openapi-gen=true
 type VMAgentRemoteWriteSpec struct {
 	// URL of the endpoint to send samples to.
-	URL string `json:"url"`
+	// Either url or targetRef must be set
+	// +optional
+	URL string `json:"url,omitempty"`
+	// TargetRef references VMSingle, VMCluster or VMAuth object, which url is used for remote write
+	// +optional
+	TargetRef *RemoteTargetRef `json:"targetRef,omitempty"`
 	// BasicAuth allow an endpoint to authenticate over basic authentication
 	// +optional
 	BasicAuth *BasicAuth `json:"basicAuth,omitempty"`
@@ -504,6 +535,92 @@ func (*VMAgentRemoteWriteSpec) AsConfigMapKey(idx int, suffix string) string {
 	return fmt.Sprintf("RWS_%d-CM-%s", idx, strings.ToUpper(suffix))
 }
 
+// VMAgentConfigCanary defines canary rollout settings for scrape configuration
+type VMAgentConfigCanary struct {
+	// ShardNum defines number of the shard, which receives new configuration first
+	// By default, shard 0 is used
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ShardNum *int `json:"shardNum,omitempty"`
+	// SoakDuration defines how long canary shard must be healthy with new configuration
+	// before promotion to all shards. By default, 5m
+	// +kubebuilder:validation:Pattern:="[0-9]+(ms|s|m|h)"
+	// +optional
+	SoakDuration string `json:"soakDuration,omitempty"`
+	// MaxScrapeErrorsPercent defines max percent of down scrape targets at canary shard.
+	// By default, 10
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxScrapeErrorsPercent *int32 `json:"maxScrapeErrorsPercent,omitempty"`
+	// MaxDroppedSamples defines max number of samples, which canary shard could drop during soak.
+	// By default, 0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxDroppedSamples *int64 `json:"maxDroppedSamples,omitempty"`
+}
+
+// GetShardNum returns number of canary shard
+func (cc *VMAgentConfigCanary) GetShardNum() int {
+	if cc.ShardNum == nil {
+		return 0
+	}
+	return *cc.ShardNum
+}
+
+// GetSoakDuration returns soak duration for canary
+func (cc *VMAgentConfigCanary) GetSoakDuration() time.Duration {
+	d, err := time.ParseDuration(cc.SoakDuration)
+	if err != nil || d <= 0 {
+		return 5 * time.Minute
+	}
+	return d
+}
+
+// GetMaxScrapeErrorsPercent returns allowed percent of down targets for canary
+func (cc *VMAgentConfigCanary) GetMaxScrapeErrorsPercent() int32 {
+	if cc.MaxScrapeErrorsPercent == nil {
+		return 10
+	}
+	return *cc.MaxScrapeErrorsPercent
+}
+
+// GetMaxDroppedSamples returns allowed number of dropped samples for canary
+func (cc *VMAgentConfigCanary) GetMaxDroppedSamples() int64 {
+	if cc.MaxDroppedSamples == nil {
+		return 0
+	}
+	return *cc.MaxDroppedSamples
+}
+
+// ConfigCanaryPhase defines state of configuration canary rollout
+type ConfigCanaryPhase string
+
+const (
+	ConfigCanaryInProgress ConfigCanaryPhase = "InProgress"
+	ConfigCanaryPromoted   ConfigCanaryPhase = "Promoted"
+	ConfigCanaryRolledBack ConfigCanaryPhase = "RolledBack"
+	ConfigCanaryCancelled  ConfigCanaryPhase = "Cancelled"
+)
+
+// ConditionConfigCanary defines condition type for VMAgent configuration canary rollout
+const ConditionConfigCanary = "ConfigCanary"
+
+// VMAgentConfigCanaryStatus defines state of the configuration canary rollout
+type VMAgentConfigCanaryStatus struct {
+	// Phase of the canary rollout
+	Phase ConfigCanaryPhase `json:"phase,omitempty"`
+	// ConfigHash is a hash of configuration at canary
+	ConfigHash string `json:"configHash,omitempty"`
+	// StartedAt defines time of the canary rollout start
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// DroppedSamplesBaseline holds number of dropped samples at canary shard
+	// observed after configuration reload
+	DroppedSamplesBaseline *int64 `json:"droppedSamplesBaseline,omitempty"`
+	// Message contains human readable reason of the last phase change
+	Message string `json:"message,omitempty"`
+}
+
 // VMAgentStatus defines the observed state of VMAgent
 // +k8s:openapi-gen=true
 type VMAgentStatus struct {
@@ -512,7 +629,10 @@ type VMAgentStatus struct {
 	// Selector string form of label value set for autoscaling
 	Selector string `json:"selector,omitempty"`
 	// ReplicaCount Total number of pods targeted by this VMAgent
-	Replicas       int32 `json:"replicas,omitempty"`
+	Replicas int32 `json:"replicas,omitempty"`
+	// ConfigCanary defines state of configuration canary rollout
+	// +optional
+	ConfigCanary   *VMAgentConfigCanaryStatus `json:"configCanary,omitempty"`
 	StatusMetadata `json:",inline"`
 }
 
@@ -585,7 +705,7 @@ func (cr *VMAgent) PodAnnotations() map[string]string {
 
 func (cr *VMAgent) AnnotationsFiltered() map[string]string {
 	// TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
-	dst := filterMapKeysByPrefixes(cr.ObjectMeta.Annotations, annotationFilterPrefixes)
+	dst := filterMapKeysByPrefixes(cr.ObjectMeta.Annotations, getAnnotationFilterPrefixes())
 	if cr.Spec.ManagedMetadata != nil {
 		if dst == nil {
 			dst = make(map[string]string)
@@ -625,7 +745,7 @@ func (cr *VMAgent) AllLabels() map[string]string {
 	var result map[string]string
 	// TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
 	if cr.ObjectMeta.Labels != nil {
-		result = filterMapKeysByPrefixes(cr.ObjectMeta.Labels, labelFilterPrefixes)
+		result = filterMapKeysByPrefixes(cr.ObjectMeta.Labels, getLabelFilterPrefixes())
 	}
 	if cr.Spec.ManagedMetadata != nil {
 		result = labels.Merge(result, cr.Spec.ManagedMetadata.Labels)
@@ -637,6 +757,16 @@ func (cr *VMAgent) PrefixedName() string {
 	return fmt.Sprintf("vmagent-%s", cr.Name)
 }
 
+// IsConfigCanaryEnabled checks if scrape configuration changes must be rolled out with canary shard
+func (cr *VMAgent) IsConfigCanaryEnabled() bool {
+	return cr.Spec.ConfigCanary != nil && !cr.Spec.IngestOnlyMode && !cr.Spec.DaemonSetMode && cr.Spec.ShardCount != nil && *cr.Spec.ShardCount > 1
+}
+
+// CanaryConfigSecretName returns name of secret with scrape configuration for canary shard
+func (cr *VMAgent) CanaryConfigSecretName() string {
+	return fmt.Sprintf("vmagent-%s-canary", cr.Name)
+}
+
 func (cr *VMAgent) TLSAssetName() string {
 	return fmt.Sprintf("tls-assets-vmagent-%s", cr.Name)
 }
@@ -819,6 +949,21 @@ func (cr *VMAgent) Paused() bool {
 	return cr.Spec.Paused
 }
 
+// GetStatusMetadata returns metadata for object status
+func (cr *VMAgent) GetStatusMetadata() *StatusMetadata {
+	return &cr.Status.StatusMetadata
+}
+
+// IsDriftReportOnly checks if child objects modified outside of operator must not be reverted
+func (cr *VMAgent) IsDriftReportOnly() bool {
+	return cr.Annotations[DriftPolicyAnnotation] == DriftPolicyReportOnly
+}
+
+// GetMaintenanceWindows returns time ranges, when disruptive changes are allowed
+func (cr *VMAgent) GetMaintenanceWindows() []MaintenanceWindow {
+	return cr.Spec.MaintenanceWindows
+}
+
 // HasAnyRelabellingConfigs checks if vmagent has any defined relabeling rules
 func (cr *VMAgent) HasAnyRelabellingConfigs() bool {
 	if cr.Spec.RelabelConfig != nil || len(cr.Spec.InlineRelabelConfig) > 0 {
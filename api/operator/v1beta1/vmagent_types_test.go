@@ -2,6 +2,8 @@ package v1beta1
 
 import (
 	"testing"
+
+	"k8s.io/utils/ptr"
 )
 
 func TestVMAgent_Validate(t *testing.T) {
@@ -65,6 +67,31 @@ func TestVMAgent_Validate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "config canary without shards",
+			spec: VMAgentSpec{
+				RemoteWrite:  []VMAgentRemoteWriteSpec{{URL: "http://some-rw"}},
+				ConfigCanary: &VMAgentConfigCanary{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "config canary with incorrect shard num",
+			spec: VMAgentSpec{
+				RemoteWrite:  []VMAgentRemoteWriteSpec{{URL: "http://some-rw"}},
+				ShardCount:   ptr.To(2),
+				ConfigCanary: &VMAgentConfigCanary{ShardNum: ptr.To(2)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid config canary",
+			spec: VMAgentSpec{
+				RemoteWrite:  []VMAgentRemoteWriteSpec{{URL: "http://some-rw"}},
+				ShardCount:   ptr.To(2),
+				ConfigCanary: &VMAgentConfigCanary{ShardNum: ptr.To(1), SoakDuration: "10m"},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
@@ -50,6 +51,14 @@ type VMAlertSpec struct {
 	// being created.
 	// +optional
 	EnforcedNamespaceLabel string `json:"enforcedNamespaceLabel,omitempty"`
+	// RulePolicies defines policies for VMRules selected from namespaces matching policy namespaceSelector.
+	// All matching policies are applied in the defined order.
+	// VMRule, which violates policy, is excluded from configuration and violation is reported at its status
+	// +optional
+	RulePolicies []VMAlertRulePolicy `json:"rulePolicies,omitempty"`
+	// RuleDependencies configures evaluation ordering of groups, which use results of recording rules from other groups
+	// +optional
+	RuleDependencies *VMAlertRuleDependencies `json:"ruleDependencies,omitempty"`
 	// SelectAllByDefault changes default behavior for empty CRD selectors, such RuleSelector.
 	// with selectAllByDefault: true and empty serviceScrapeSelector and RuleNamespaceSelector
 	// Operator selects all exist serviceScrapes
@@ -104,6 +113,11 @@ type VMAlertSpec struct {
 	// +optional
 	RemoteRead *VMAlertRemoteReadSpec `json:"remoteRead,omitempty"`
 
+	// StatePersistence configures persistence of alerts state at datasource storage,
+	// which allows to restore active alerts after vmalert restarts
+	// +optional
+	StatePersistence *VMAlertStatePersistence `json:"statePersistence,omitempty"`
+
 	// RulePath to the file with alert rules.
 	// Supports patterns. Flag can be specified multiple times.
 	// Examples:
@@ -148,6 +162,13 @@ type VMAlertSpec struct {
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 
+	// MaintenanceWindows defines time ranges, when disruptive changes are allowed:
+	// Rolling update of Deployment pods.
+	// Such changes are deferred until the next window and listed at PendingMaintenance status condition.
+	// Other changes are applied as usual. Changes are applied immediately if windows are not defined.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
 	CommonDefaultableParams           `json:",inline,omitempty"`
 	CommonConfigReloaderParams        `json:",inline,omitempty"`
 	CommonApplicationDeploymentParams `json:",inline,omitempty"`
@@ -183,8 +204,13 @@ func (cr *VMAlertSpec) UnmarshalJSON(src []byte) error {
 // VMAlertDatasourceSpec defines the remote storage configuration for VmAlert to read alerts from
 // +k8s:openapi-gen=true
 type VMAlertDatasourceSpec struct {
-	// Victoria Metrics or VMSelect url. Required parameter. E.g. http://127.0.0.1:8428
-	URL string `json:"url"`
+	// Victoria Metrics or VMSelect url. E.g. http://127.0.0.1:8428
+	// Either url or targetRef must be set
+	// +optional
+	URL string `json:"url,omitempty"`
+	// TargetRef references VMSingle, VMCluster or VMAuth object, which url is used as datasource
+	// +optional
+	TargetRef *RemoteTargetRef `json:"targetRef,omitempty"`
 	// HTTPAuth generic auth methods
 	HTTPAuth `json:",inline,omitempty"`
 }
@@ -213,7 +239,12 @@ func (cr *VMAlert) NotifierAsMapKey(i int) string {
 // +k8s:openapi-gen=true
 type VMAlertRemoteReadSpec struct {
 	// URL of the endpoint to send samples to.
-	URL string `json:"url"`
+	// Either url or targetRef must be set
+	// +optional
+	URL string `json:"url,omitempty"`
+	// TargetRef references VMSingle, VMCluster or VMAuth object, which url is used for remote read
+	// +optional
+	TargetRef *RemoteTargetRef `json:"targetRef,omitempty"`
 	// Lookback defines how far to look into past for alerts timeseries. For example, if lookback=1h then range from now() to now()-1h will be scanned. (default 1h0m0s)
 	// Applied only to RemoteReadSpec
 	// +optional
@@ -226,7 +257,12 @@ type VMAlertRemoteReadSpec struct {
 // +k8s:openapi-gen=true
 type VMAlertRemoteWriteSpec struct {
 	// URL of the endpoint to send samples to.
-	URL string `json:"url"`
+	// Either url or targetRef must be set
+	// +optional
+	URL string `json:"url,omitempty"`
+	// TargetRef references VMSingle, VMCluster or VMAuth object, which url is used for remote write
+	// +optional
+	TargetRef *RemoteTargetRef `json:"targetRef,omitempty"`
 	// Defines number of readers that concurrently write into remote storage (default 1)
 	// +optional
 	Concurrency *int32 `json:"concurrency,omitempty"`
@@ -244,10 +280,94 @@ type VMAlertRemoteWriteSpec struct {
 	HTTPAuth `json:",inline,omitempty"`
 }
 
+// VMAlertRulePolicy defines policy for VMRules from namespaces matching namespaceSelector
+type VMAlertRulePolicy struct {
+	// Name of the policy
+	Name string `json:"name"`
+	// NamespaceSelector selects namespaces of VMRules the policy applies to.
+	// Empty selector matches all namespaces
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Tenant is set for every group of VMRule, can be used only with enterprise version of vmalert.
+	// See more details [here](https://docs.victoriametrics.com/vmalert#multitenancy).
+	// +optional
+	Tenant string `json:"tenant,omitempty"`
+	// ExtraFilterLabels are added to extra_filter_labels of every group of VMRule.
+	// They override labels with the same names defined at VMRule
+	// +optional
+	ExtraFilterLabels map[string]string `json:"extraFilterLabels,omitempty"`
+	// Labels are added to every rule of VMRule.
+	// They override labels with the same names defined at VMRule
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// MinInterval defines minimal allowed evaluation interval of group
+	// +optional
+	// +kubebuilder:validation:Pattern:="[0-9]+(ms|s|m|h)"
+	MinInterval string `json:"minInterval,omitempty"`
+	// MaxConcurrency defines maximal allowed concurrency of group
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrency int `json:"maxConcurrency,omitempty"`
+}
+
+// VMAlertRuleDependencies defines evaluation ordering of dependent rule groups
+type VMAlertRuleDependencies struct {
+	// AutoEvalOffset assigns eval_offset to groups without explicit eval_offset,
+	// so groups with recording rules are evaluated before groups, which use their results
+	// +optional
+	AutoEvalOffset bool `json:"autoEvalOffset,omitempty"`
+}
+
+// IsAutoEvalOffsetEnabled checks if eval_offset must be assigned to dependent rule groups
+func (cr *VMAlert) IsAutoEvalOffsetEnabled() bool {
+	return cr.Spec.RuleDependencies != nil && cr.Spec.RuleDependencies.AutoEvalOffset
+}
+
+// VMAlertStatePersistence defines persistence of vmalert alerts state
+type VMAlertStatePersistence struct {
+	// Enabled configures remoteWrite and remoteRead with the datasource storage,
+	// if they are not defined explicitly.
+	// remoteRead.lookback is set to the longest `for` of selected alerting rules,
+	// if it's not defined explicitly.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// IsStatePersistenceEnabled checks if alerts state persistence is enabled
+func (cr *VMAlert) IsStatePersistenceEnabled() bool {
+	return cr.Spec.StatePersistence != nil && cr.Spec.StatePersistence.Enabled
+}
+
+// ValidateStatePersistence checks if alerts state could be persisted at datasource storage
+// vmselect doesn't accept remote write requests and vminsert address cannot be derived from its url
+func (cr *VMAlert) ValidateStatePersistence() error {
+	if !cr.IsStatePersistenceEnabled() || cr.Spec.RemoteWrite != nil {
+		return nil
+	}
+	ds := cr.Spec.Datasource
+	if ds.TargetRef == nil && strings.Contains(ds.URL, "/select/") {
+		return fmt.Errorf("spec.statePersistence: datasource url=%q points to vmselect, which doesn't accept remote write, define spec.remoteWrite or use spec.datasource.targetRef", ds.URL)
+	}
+	return nil
+}
+
 // VMAlertStatus defines the observed state of VMAlert
 // +k8s:openapi-gen=true
 type VMAlertStatus struct {
 	StatusMetadata `json:",inline"`
+	// StatePersistence reports alerts state persistence configuration
+	// +optional
+	StatePersistence *VMAlertStatePersistenceStatus `json:"statePersistence,omitempty"`
+}
+
+// VMAlertStatePersistenceStatus defines observed state of alerts state persistence
+type VMAlertStatePersistenceStatus struct {
+	// Lookback is the longest `for` of selected alerting rules
+	// +optional
+	Lookback string `json:"lookback,omitempty"`
+	// Warning describes why alerts state cannot be restored after restart
+	// +optional
+	Warning string `json:"warning,omitempty"`
 }
 
 // GetStatusMetadata returns metadata for object status
@@ -335,7 +455,7 @@ func (cr *VMAlert) PodAnnotations() map[string]string {
 
 func (cr *VMAlert) AnnotationsFiltered() map[string]string {
 	// TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
-	dst := filterMapKeysByPrefixes(cr.ObjectMeta.Annotations, annotationFilterPrefixes)
+	dst := filterMapKeysByPrefixes(cr.ObjectMeta.Annotations, getAnnotationFilterPrefixes())
 	if cr.Spec.ManagedMetadata != nil {
 		if dst == nil {
 			dst = make(map[string]string)
@@ -355,8 +475,44 @@ func (cr *VMAlert) Validate() error {
 	if cr.Spec.ServiceSpec != nil && cr.Spec.ServiceSpec.Name == cr.PrefixedName() {
 		return fmt.Errorf("spec.serviceSpec.Name cannot be equal to prefixed name=%q", cr.PrefixedName())
 	}
-	if cr.Spec.Datasource.URL == "" {
-		return fmt.Errorf("spec.datasource.url cannot be empty")
+	if err := validateRemoteURL(cr.Spec.Datasource.URL, cr.Spec.Datasource.TargetRef); err != nil {
+		return fmt.Errorf("spec.datasource: %w", err)
+	}
+	if cr.Spec.RemoteWrite != nil {
+		if err := validateRemoteURL(cr.Spec.RemoteWrite.URL, cr.Spec.RemoteWrite.TargetRef); err != nil {
+			return fmt.Errorf("spec.remoteWrite: %w", err)
+		}
+	}
+	if cr.Spec.RemoteRead != nil {
+		if err := validateRemoteURL(cr.Spec.RemoteRead.URL, cr.Spec.RemoteRead.TargetRef); err != nil {
+			return fmt.Errorf("spec.remoteRead: %w", err)
+		}
+	}
+	if err := cr.ValidateStatePersistence(); err != nil {
+		return err
+	}
+	uniqPolicies := make(map[string]struct{}, len(cr.Spec.RulePolicies))
+	for idx, policy := range cr.Spec.RulePolicies {
+		if policy.Name == "" {
+			return fmt.Errorf("spec.rulePolicies at idx=%d: name cannot be empty", idx)
+		}
+		if _, ok := uniqPolicies[policy.Name]; ok {
+			return fmt.Errorf("spec.rulePolicies at idx=%d: duplicate name=%q", idx, policy.Name)
+		}
+		uniqPolicies[policy.Name] = struct{}{}
+		if _, err := metav1.LabelSelectorAsSelector(policy.NamespaceSelector); err != nil {
+			return fmt.Errorf("spec.rulePolicies at idx=%d: cannot parse namespaceSelector: %w", idx, err)
+		}
+		if policy.Tenant != "" {
+			if err := validateRuleGroupTenantID(policy.Tenant); err != nil {
+				return fmt.Errorf("spec.rulePolicies at idx=%d: bad tenant=%q: %w", idx, policy.Tenant, err)
+			}
+		}
+		if policy.MinInterval != "" {
+			if _, err := time.ParseDuration(policy.MinInterval); err != nil {
+				return fmt.Errorf("spec.rulePolicies at idx=%d: cannot parse minInterval=%q: %w", idx, policy.MinInterval, err)
+			}
+		}
 	}
 	if cr.Spec.Notifier != nil {
 		if cr.Spec.Notifier.URL == "" && cr.Spec.Notifier.Selector == nil {
@@ -402,7 +558,7 @@ func (cr *VMAlert) AllLabels() map[string]string {
 	var result map[string]string
 	// TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
 	if cr.ObjectMeta.Labels != nil {
-		result = filterMapKeysByPrefixes(cr.ObjectMeta.Labels, labelFilterPrefixes)
+		result = filterMapKeysByPrefixes(cr.ObjectMeta.Labels, getLabelFilterPrefixes())
 	}
 	if cr.Spec.ManagedMetadata != nil {
 		result = labels.Merge(result, cr.Spec.ManagedMetadata.Labels)
@@ -514,6 +670,21 @@ func (cr *VMAlert) Paused() bool {
 	return cr.Spec.Paused
 }
 
+// GetStatusMetadata returns metadata for object status
+func (cr *VMAlert) GetStatusMetadata() *StatusMetadata {
+	return &cr.Status.StatusMetadata
+}
+
+// IsDriftReportOnly checks if child objects modified outside of operator must not be reverted
+func (cr *VMAlert) IsDriftReportOnly() bool {
+	return cr.Annotations[DriftPolicyAnnotation] == DriftPolicyReportOnly
+}
+
+// GetMaintenanceWindows returns time ranges, when disruptive changes are allowed
+func (cr *VMAlert) GetMaintenanceWindows() []MaintenanceWindow {
+	return cr.Spec.MaintenanceWindows
+}
+
 // SetStatusTo changes update status with optional reason of fail
 func (cr *VMAlert) SetUpdateStatusTo(ctx context.Context, c client.Client, status UpdateStatus, maybeErr error) error {
 	return updateObjectStatus(ctx, c, &patchStatusOpts[*VMAlert, *VMAlertStatus]{
@@ -37,6 +37,96 @@ func TestVMAlert_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "with datasource targetRef",
+			spec: VMAlertSpec{
+				Datasource: VMAlertDatasourceSpec{TargetRef: &RemoteTargetRef{Kind: "VMCluster", Name: "main", TenantID: "1"}},
+				CommonApplicationDeploymentParams: CommonApplicationDeploymentParams{
+					ExtraArgs: map[string]string{"notifier.blackhole": "true"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "with datasource url and targetRef",
+			spec: VMAlertSpec{
+				Datasource: VMAlertDatasourceSpec{URL: "http://some-url", TargetRef: &RemoteTargetRef{Kind: "VMSingle", Name: "main"}},
+				CommonApplicationDeploymentParams: CommonApplicationDeploymentParams{
+					ExtraArgs: map[string]string{"notifier.blackhole": "true"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "with tenant for remoteWrite VMSingle targetRef",
+			spec: VMAlertSpec{
+				Datasource:  VMAlertDatasourceSpec{URL: "http://some-url"},
+				RemoteWrite: &VMAlertRemoteWriteSpec{TargetRef: &RemoteTargetRef{Kind: "VMSingle", Name: "main", TenantID: "1"}},
+				CommonApplicationDeploymentParams: CommonApplicationDeploymentParams{
+					ExtraArgs: map[string]string{"notifier.blackhole": "true"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "with rule policies",
+			spec: VMAlertSpec{
+				Datasource: VMAlertDatasourceSpec{URL: "http://some-url"},
+				RulePolicies: []VMAlertRulePolicy{
+					{Name: "team-a", Tenant: "1:0", MinInterval: "30s", MaxConcurrency: 2},
+					{Name: "all", Labels: map[string]string{"team": "unknown"}},
+				},
+				CommonApplicationDeploymentParams: CommonApplicationDeploymentParams{
+					ExtraArgs: map[string]string{"notifier.blackhole": "true"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "with duplicate rule policies",
+			spec: VMAlertSpec{
+				Datasource:   VMAlertDatasourceSpec{URL: "http://some-url"},
+				RulePolicies: []VMAlertRulePolicy{{Name: "team-a"}, {Name: "team-a"}},
+				CommonApplicationDeploymentParams: CommonApplicationDeploymentParams{
+					ExtraArgs: map[string]string{"notifier.blackhole": "true"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "with bad rule policy tenant",
+			spec: VMAlertSpec{
+				Datasource:   VMAlertDatasourceSpec{URL: "http://some-url"},
+				RulePolicies: []VMAlertRulePolicy{{Name: "team-a", Tenant: "team-a"}},
+				CommonApplicationDeploymentParams: CommonApplicationDeploymentParams{
+					ExtraArgs: map[string]string{"notifier.blackhole": "true"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "with state persistence at vmselect",
+			spec: VMAlertSpec{
+				Datasource:       VMAlertDatasourceSpec{URL: "http://vmselect:8481/select/0/prometheus"},
+				StatePersistence: &VMAlertStatePersistence{Enabled: true},
+				CommonApplicationDeploymentParams: CommonApplicationDeploymentParams{
+					ExtraArgs: map[string]string{"notifier.blackhole": "true"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "with state persistence at vmselect and explicit remoteWrite",
+			spec: VMAlertSpec{
+				Datasource:       VMAlertDatasourceSpec{URL: "http://vmselect:8481/select/0/prometheus"},
+				RemoteWrite:      &VMAlertRemoteWriteSpec{URL: "http://vminsert:8480/insert/0/prometheus"},
+				StatePersistence: &VMAlertStatePersistence{Enabled: true},
+				CommonApplicationDeploymentParams: CommonApplicationDeploymentParams{
+					ExtraArgs: map[string]string{"notifier.blackhole": "true"},
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -121,6 +121,11 @@ type VMAlertmanagerSpec struct {
 	ListenLocal bool `json:"listenLocal,omitempty"`
 	// AdditionalPeers allows injecting a set of additional Alertmanagers to peer with to form a highly available cluster.
 	AdditionalPeers []string `json:"additionalPeers,omitempty"`
+	// PeerDiscovery defines discovery of additional peers from Services or DNS SRV records,
+	// e.g. for Alertmanager cluster spanning multiple Kubernetes clusters.
+	// Discovered peers are added to AdditionalPeers
+	// +optional
+	PeerDiscovery *AlertmanagerPeerDiscovery `json:"peerDiscovery,omitempty"`
 	// ClusterAdvertiseAddress is the explicit address to advertise in cluster.
 	// Needs to be provided for non RFC1918 [1] (public) addresses.
 	// [1] RFC1918: https://tools.ietf.org/html/rfc1918
@@ -181,6 +186,12 @@ type VMAlertmanagerSpec struct {
 	// Can be changed for RollingUpdate
 	// +optional
 	RollingUpdateStrategy appsv1.StatefulSetUpdateStrategyType `json:"rollingUpdateStrategy,omitempty"`
+	// MaintenanceWindows defines time ranges, when disruptive changes are allowed:
+	// StatefulSet recreation, rolling update of StatefulSet pods and PVC expansion.
+	// Such changes are deferred until the next window and listed at PendingMaintenance status condition.
+	// Other changes are applied as usual. Changes are applied immediately if windows are not defined.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 	// ClaimTemplates allows adding additional VolumeClaimTemplates for StatefulSet
 	ClaimTemplates []v1.PersistentVolumeClaim `json:"claimTemplates,omitempty"`
 
@@ -241,10 +252,37 @@ type VMAlertmanagerList struct {
 	Items []VMAlertmanager `json:"items"`
 }
 
+// ConditionInvalidTemplates defines condition type for VMAlertmanager templates, which cannot be parsed
+const ConditionInvalidTemplates = "InvalidTemplates"
+
 // VMAlertmanagerStatus is the most recent observed status of the VMAlertmanager cluster
 // Operator API itself. More info:
 type VMAlertmanagerStatus struct {
 	StatusMetadata `json:",inline"`
+	// Peers contains reachability of additional and discovered cluster peers
+	// +optional
+	Peers []AlertmanagerPeerStatus `json:"peers,omitempty"`
+}
+
+// AlertmanagerPeerStatus defines reachability of Alertmanager cluster peer
+type AlertmanagerPeerStatus struct {
+	// Address of peer in form of host:port
+	// it's empty if discovery of peer source failed and there are no previously known peers for it
+	// +optional
+	Address string `json:"address,omitempty"`
+	// Source defines origin of peer: additionalPeers, service/NAME, serviceSelector or dns/NAME
+	Source string `json:"source"`
+	// Reachable indicates that gossip port of peer accepts TCP connections from operator
+	Reachable bool `json:"reachable"`
+	// Error defines reason of unreachable peer
+	// +optional
+	Error string `json:"error,omitempty"`
+	// DiscoveryError defines reason of the last failed discovery of peer source
+	// peer address is taken from the previous status in this case
+	// +optional
+	DiscoveryError string `json:"discoveryError,omitempty"`
+	// LastTransitionTime defines time of the last reachability change
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
 }
 
 // GetStatusMetadata returns metadata for object status
@@ -278,7 +316,7 @@ func (cr *VMAlertmanager) PodAnnotations() map[string]string {
 
 func (cr *VMAlertmanager) AnnotationsFiltered() map[string]string {
 	// TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
-	dst := filterMapKeysByPrefixes(cr.ObjectMeta.Annotations, annotationFilterPrefixes)
+	dst := filterMapKeysByPrefixes(cr.ObjectMeta.Annotations, getAnnotationFilterPrefixes())
 	if cr.Spec.ManagedMetadata != nil {
 		if dst == nil {
 			dst = make(map[string]string)
@@ -316,7 +354,7 @@ func (cr *VMAlertmanager) AllLabels() map[string]string {
 	var result map[string]string
 	// TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
 	if cr.ObjectMeta.Labels != nil {
-		result = filterMapKeysByPrefixes(cr.ObjectMeta.Labels, labelFilterPrefixes)
+		result = filterMapKeysByPrefixes(cr.ObjectMeta.Labels, getLabelFilterPrefixes())
 	}
 	if cr.Spec.ManagedMetadata != nil {
 		result = labels.Merge(result, cr.Spec.ManagedMetadata.Labels)
@@ -484,6 +522,21 @@ func (cr *VMAlertmanager) Paused() bool {
 	return cr.Spec.Paused
 }
 
+// GetStatusMetadata returns metadata for object status
+func (cr *VMAlertmanager) GetStatusMetadata() *StatusMetadata {
+	return &cr.Status.StatusMetadata
+}
+
+// IsDriftReportOnly checks if child objects modified outside of operator must not be reverted
+func (cr *VMAlertmanager) IsDriftReportOnly() bool {
+	return cr.Annotations[DriftPolicyAnnotation] == DriftPolicyReportOnly
+}
+
+// GetMaintenanceWindows returns time ranges, when disruptive changes are allowed
+func (cr *VMAlertmanager) GetMaintenanceWindows() []MaintenanceWindow {
+	return cr.Spec.MaintenanceWindows
+}
+
 // SetStatusTo changes update status with optional reason of fail
 func (cr *VMAlertmanager) SetUpdateStatusTo(ctx context.Context, c client.Client, status UpdateStatus, maybeErr error) error {
 	return updateObjectStatus(ctx, c, &patchStatusOpts[*VMAlertmanager, *VMAlertmanagerStatus]{
@@ -535,6 +588,16 @@ func (cr *VMAlertmanager) Validate() error {
 		}
 	}
 
+	if pd := cr.Spec.PeerDiscovery; pd != nil {
+		if pd.ServiceSelector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(pd.ServiceSelector); err != nil {
+				return fmt.Errorf("incorrect spec.peerDiscovery.serviceSelector: %w", err)
+			}
+		}
+		if pd.ServiceSelector == nil && len(pd.DNSSRVNames) == 0 {
+			return fmt.Errorf("spec.peerDiscovery must define serviceSelector or dnsSRVNames")
+		}
+	}
 	if cr.Spec.GossipConfig != nil {
 		if cr.Spec.GossipConfig.TLSServerConfig != nil {
 			tc := cr.Spec.GossipConfig.TLSServerConfig
@@ -563,6 +626,22 @@ func (cr *VMAlertmanager) Validate() error {
 	return nil
 }
 
+// AlertmanagerPeerDiscovery defines discovery of additional Alertmanager cluster peers
+type AlertmanagerPeerDiscovery struct {
+	// ServiceSelector selects Services at VMAlertmanager namespace, which expose gossip port of peers.
+	// Ingress addresses are used for LoadBalancer Services, external name for ExternalName Services
+	// and Endpoints addresses for other Services
+	// +optional
+	ServiceSelector *metav1.LabelSelector `json:"serviceSelector,omitempty"`
+	// PortName defines name of Service port with gossip protocol.
+	// By default, port with number 9094 or the first port of Service is used
+	// +optional
+	PortName string `json:"portName,omitempty"`
+	// DNSSRVNames defines DNS SRV records, which targets are used as peers
+	// +optional
+	DNSSRVNames []string `json:"dnsSRVNames,omitempty"`
+}
+
 // AlertmanagerGossipConfig defines Gossip TLS configuration for alertmanager
 type AlertmanagerGossipConfig struct {
 	// TLSServerConfig defines server TLS configuration for alertmanager
@@ -43,6 +43,13 @@ global:
           `
 			Expect(am.Validate()).To(Succeed())
 		})
+
+		It("Should deny peer discovery without sources", func() {
+			am.Spec.PeerDiscovery = &AlertmanagerPeerDiscovery{PortName: "mesh"}
+			Expect(am.Validate()).NotTo(Succeed())
+			am.Spec.PeerDiscovery.DNSSRVNames = []string{"_mesh._tcp.alertmanager.example.com"}
+			Expect(am.Validate()).To(Succeed())
+		})
 	})
 
 	Context("When creating VMAlertmanager under Conversion Webhook", func() {
@@ -22,6 +22,7 @@ import (
 	"html/template"
 	"net"
 	"net/url"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
@@ -34,6 +35,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ReceiverTestAnnotation requests delivery of test notification
+// through VMAlertmanagerConfig receiver with name defined at annotation value
+const ReceiverTestAnnotation = "operator.victoriametrics.com/receiver-test"
+
 // VMAlertmanagerConfigSpec defines configuration for VMAlertmanagerConfig
 // it must reference only locally defined objects
 type VMAlertmanagerConfigSpec struct {
@@ -50,10 +55,30 @@ type VMAlertmanagerConfigSpec struct {
 	// See https://prometheus.io/docs/alerting/latest/configuration/#time_interval
 	// +optional
 	TimeIntervals []TimeIntervals `json:"time_intervals,omitempty" yaml:"time_intervals,omitempty"`
+	// Templates defines notification templates for receivers of this config.
+	// Names of templates defined with `define` action are prefixed with namespace and name of VMAlertmanagerConfig
+	// in order to avoid clashes with templates of other configs. Receivers reference templates by original names.
+	// +optional
+	Templates []NotificationTemplate `json:"templates,omitempty" yaml:"templates,omitempty"`
 	// ParsingError contents error with context if operator was failed to parse json object from kubernetes api server
 	ParsingError string `json:"-" yaml:"-"`
 }
 
+// NotificationTemplate defines alertmanager notification template
+// either with inline content or with ConfigMap key reference
+type NotificationTemplate struct {
+	// Name of template
+	// +required
+	Name string `json:"name"`
+	// Content defines inline template content
+	// +optional
+	Content string `json:"content,omitempty"`
+	// ConfigMap defines ConfigMap key with template content
+	// ConfigMap must be in the same namespace as VMAlertmanagerConfig
+	// +optional
+	ConfigMap *v1.ConfigMapKeySelector `json:"configMap,omitempty"`
+}
+
 // TimeIntervals for alerts
 type TimeIntervals struct {
 	// Name of interval
@@ -104,6 +129,40 @@ func (r *VMAlertmanagerConfig) GetStatusMetadata() *StatusMetadata {
 	return &r.Status.StatusMetadata
 }
 
+// SetUnreachableRoutes replaces unreachable routes of the given VMAlertmanager in form of namespace/name
+// and removes entries of VMAlertmanagers, which are not present at parents, if it's not nil
+//
+// returns true if status was changed
+func (r *VMAlertmanagerConfig) SetUnreachableRoutes(alertmanager string, routes []UnreachableRoute, parents map[string]struct{}) bool {
+	var result []UnreachableRoute
+	for _, ur := range r.Status.UnreachableRoutes {
+		if ur.Alertmanager == alertmanager {
+			continue
+		}
+		if _, ok := parents[ur.Alertmanager]; parents != nil && !ok {
+			continue
+		}
+		result = append(result, ur)
+	}
+	result = append(result, routes...)
+	if reflect.DeepEqual(result, r.Status.UnreachableRoutes) {
+		return false
+	}
+	r.Status.UnreachableRoutes = result
+	return true
+}
+
+// GetUnreachableRoutes returns unreachable routes of the given VMAlertmanager in form of namespace/name
+func (r *VMAlertmanagerConfig) GetUnreachableRoutes(alertmanager string) []UnreachableRoute {
+	var result []UnreachableRoute
+	for _, ur := range r.Status.UnreachableRoutes {
+		if ur.Alertmanager == alertmanager {
+			result = append(result, ur)
+		}
+	}
+	return result
+}
+
 func (r *VMAlertmanagerConfig) Validate() error {
 	if mustSkipValidation(r) {
 		return nil
@@ -158,7 +217,30 @@ func (r *VMAlertmanagerConfig) Validate() error {
 			return fmt.Errorf("subRoute=%d is not valid: %w", idx, err)
 		}
 	}
+	if err := validateNotificationTemplates(r.Spec.Templates); err != nil {
+		return err
+	}
+
+	return nil
+}
 
+func validateNotificationTemplates(templates []NotificationTemplate) error {
+	names := make(map[string]struct{}, len(templates))
+	for idx, t := range templates {
+		if t.Name == "" {
+			return fmt.Errorf("template at idx=%d must have name", idx)
+		}
+		if _, ok := names[t.Name]; ok {
+			return fmt.Errorf("template name %q is not unique", t.Name)
+		}
+		names[t.Name] = struct{}{}
+		if (t.Content == "") == (t.ConfigMap == nil) {
+			return fmt.Errorf("template=%q must have exactly one of content or configMap", t.Name)
+		}
+		if t.ConfigMap != nil && (t.ConfigMap.Name == "" || t.ConfigMap.Key == "") {
+			return fmt.Errorf("template=%q must have configMap name and key", t.Name)
+		}
+	}
 	return nil
 }
 
@@ -168,6 +250,56 @@ type VMAlertmanagerConfigStatus struct {
 	// reconcile
 	StatusMetadata                  `json:",inline"`
 	LastErrorParentAlertmanagerName string `json:"lastErrorParentAlertmanagerName,omitempty"`
+	// UnreachableRoutes contains sample label sets built from route matchers,
+	// which do not reach the corresponding route at the merged VMAlertmanager route tree
+	// +optional
+	UnreachableRoutes []UnreachableRoute `json:"unreachableRoutes,omitempty"`
+	// ReceiverTest contains result of the latest test notification delivery
+	// requested with operator.victoriametrics.com/receiver-test annotation
+	// +optional
+	ReceiverTest *ReceiverTestStatus `json:"receiverTest,omitempty"`
+}
+
+// UnreachableRoute describes route of VMAlertmanagerConfig shadowed by other routes
+type UnreachableRoute struct {
+	// Alertmanager defines VMAlertmanager in form of namespace/name
+	Alertmanager string `json:"alertmanager"`
+	// Route defines path of route at VMAlertmanagerConfig, e.g. route.routes[1]
+	Route string `json:"route"`
+	// Labels defines sample label set, which matches route matchers
+	Labels map[string]string `json:"labels"`
+	// Receivers defines receivers, which actually get alert with sample labels
+	// +optional
+	Receivers []string `json:"receivers,omitempty"`
+}
+
+// ReceiverTestStatus describes result of test notification delivery
+type ReceiverTestStatus struct {
+	// Receiver defines name of tested receiver
+	Receiver string `json:"receiver"`
+	// Alertmanager defines VMAlertmanager in form of namespace/name,
+	// which configuration was used for receiver rendering
+	// +optional
+	Alertmanager string `json:"alertmanager,omitempty"`
+	// LastTestTime defines time of the test
+	LastTestTime metav1.Time `json:"lastTestTime"`
+	// Error defines reason, why receiver cannot be tested
+	// +optional
+	Error string `json:"error,omitempty"`
+	// Integrations contains delivery results for each receiver integration
+	// +optional
+	Integrations []ReceiverTestIntegration `json:"integrations,omitempty"`
+}
+
+// ReceiverTestIntegration describes result of test notification delivery for receiver integration
+type ReceiverTestIntegration struct {
+	// Name defines integration in form of type[index], e.g. slack_configs[0]
+	Name string `json:"name"`
+	// Success indicates that notification was accepted by integration endpoint
+	Success bool `json:"success"`
+	// Error defines delivery error, e.g. HTTP status code and response body
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // VMAlertmanagerConfig is the Schema for the vmalertmanagerconfigs API
@@ -350,6 +482,8 @@ type Receiver struct {
 	RocketchatConfigs []RocketchatConfig `json:"rocketchat_configs,omitempty" yaml:"rocketchat_configs,omitempty"`
 	// +optional
 	MSTeamsV2Configs []MSTeamsV2Config `json:"msteamsv2_configs,omitempty" yaml:"msteamsv2_configs,omitempty"`
+	// +optional
+	IncidentIOConfigs []IncidentIOConfig `json:"incidentio_configs,omitempty" yaml:"incidentio_configs,omitempty"`
 }
 
 // TelegramConfig configures notification via telegram
@@ -1131,6 +1265,42 @@ type MSTeamsV2Config struct {
 	HTTPConfig *HTTPConfig `json:"http_config,omitempty" yaml:"http_config,omitempty"`
 }
 
+// IncidentIOConfig configures notifications via incident.io alert source.
+// https://prometheus.io/docs/alerting/latest/configuration/#incidentio_config
+// available from v0.56.0 operator version
+// and v0.29.0 alertmanager version
+type IncidentIOConfig struct {
+	// SendResolved controls notify about resolved alerts.
+	// +optional
+	SendResolved *bool `json:"send_resolved,omitempty" yaml:"send_resolved,omitempty"`
+	// URL of incident.io alert source HTTP endpoint
+	// one of `url` and `url_secret` must be defined.
+	// +optional
+	URL *string `json:"url,omitempty" yaml:"url,omitempty"`
+	// URLSecret defines secret name and key at the CRD namespace.
+	// It must contain the alert source URL.
+	// one of `url` and `url_secret` must be defined.
+	// +optional
+	URLSecret *v1.SecretKeySelector `json:"url_secret,omitempty" yaml:"url_secret,omitempty"`
+	// AlertSourceToken defines secret name and key at the CRD namespace.
+	// It must contain the alert source token.
+	// +optional
+	AlertSourceToken *v1.SecretKeySelector `json:"alert_source_token,omitempty" yaml:"alert_source_token,omitempty"`
+	// Maximum number of alerts to be sent per incident.io message. When 0, all alerts are included.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxAlerts int32 `json:"max_alerts,omitempty" yaml:"max_alerts,omitempty"`
+	// Timeout is the maximum time allowed to invoke incident.io endpoint
+	// +optional
+	// +kubebuilder:validation:Pattern:="^(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?$"
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	HTTPConfig *HTTPConfig `json:"http_config,omitempty" yaml:"http_config,omitempty"`
+}
+
 // HTTPConfig defines a client HTTP configuration for VMAlertmanagerConfig objects
 // See https://prometheus.io/docs/alerting/latest/configuration/#http_config
 type HTTPConfig struct {
@@ -1821,6 +1991,27 @@ func validateReceiver(recv Receiver) error {
 		}
 	}
 
+	for idx, cfg := range recv.IncidentIOConfigs {
+		if cfg.URL == nil && cfg.URLSecret == nil {
+			return fmt.Errorf("at idx=%d for incidentio_configs of url or url_secret must be configured", idx)
+		}
+		if cfg.URL != nil && cfg.URLSecret != nil {
+			return fmt.Errorf("at idx=%d for incidentio_configs at most one of url or url_secret must be configured", idx)
+		}
+		if cfg.URL != nil {
+			if _, err := url.Parse(*cfg.URL); err != nil {
+				return fmt.Errorf("at idx=%d for incidentio_configs has invalid url=%q", idx, *cfg.URL)
+			}
+		}
+		if cfg.AlertSourceToken != nil && cfg.HTTPConfig != nil &&
+			(cfg.HTTPConfig.Authorization != nil || cfg.HTTPConfig.BearerTokenSecret != nil || len(cfg.HTTPConfig.BearerTokenFile) > 0) {
+			return fmt.Errorf("at idx=%d for incidentio_configs at most one of alert_source_token or http_config authorization must be configured", idx)
+		}
+		if err := cfg.HTTPConfig.validate(); err != nil {
+			return fmt.Errorf("at idx=%d for incidentio_configs incorrect http_config: %w", idx, err)
+		}
+	}
+
 	return nil
 }
 
@@ -50,6 +50,39 @@ var _ = Describe("VMAlertmanagerConfig Webhook", func() {
               - matcher: [nested=env]
                 receiver: non-exist
         `, `subRoute=0 is not valid: nested route=0: undefined receiver "non-exist" used in route`),
+			Entry("template without content", `
+        apiVersion: v1 
+        kind: VMAlertmanagerConfig
+        metadata:
+          name: test-fail
+        spec:
+          receivers:
+          - name: blackhole
+          route:
+            receiver: blackhole
+          templates:
+          - name: slack
+            content: '{{ define "slack.text" }}text{{ end }}'
+          - name: empty
+        `, `template="empty" must have exactly one of content or configMap`),
+			Entry("duplicate template name", `
+        apiVersion: v1 
+        kind: VMAlertmanagerConfig
+        metadata:
+          name: test-fail
+        spec:
+          receivers:
+          - name: blackhole
+          route:
+            receiver: blackhole
+          templates:
+          - name: slack
+            content: '{{ define "slack.text" }}text{{ end }}'
+          - name: slack
+            configMap:
+              name: templates
+              key: slack.tmpl
+        `, `template name "slack" is not unique`),
 			Entry("missing receiver at root", `
         apiVersion: v1 
         kind: VMAlertmanagerConfig
@@ -185,6 +218,47 @@ var _ = Describe("VMAlertmanagerConfig Webhook", func() {
         `,
 				`receiver at idx=0 is invalid: at idx=0 for msteamsv2_configs of webhook_url or webhook_url_secret must be configured`,
 			),
+			Entry("incidentio-both-urls", `
+        apiVersion: v1
+        kind: VMAlertmanagerConfig
+        metadata:
+          name: incidentio
+        spec:
+          receivers:
+          - name: incidentio
+            incidentio_configs:
+            - url: https://api.incident.io/v2/alert_events/http/id
+              url_secret:
+                name: incidentio
+                key: URL
+          route:
+            receiver: incidentio
+        `,
+				`receiver at idx=0 is invalid: at idx=0 for incidentio_configs at most one of url or url_secret must be configured`,
+			),
+			Entry("incidentio-token-with-authorization", `
+        apiVersion: v1
+        kind: VMAlertmanagerConfig
+        metadata:
+          name: incidentio
+        spec:
+          receivers:
+          - name: incidentio
+            incidentio_configs:
+            - url: https://api.incident.io/v2/alert_events/http/id
+              alert_source_token:
+                name: incidentio
+                key: TOKEN
+              http_config:
+               authorization:
+                credentials:
+                  name: incidentio
+                  key: TOKEN
+          route:
+            receiver: incidentio
+        `,
+				`receiver at idx=0 is invalid: at idx=0 for incidentio_configs at most one of alert_source_token or http_config authorization must be configured`,
+			),
 		)
 		DescribeTable("should pass validation",
 			func(srcYAML string) {
@@ -535,6 +609,26 @@ var _ = Describe("VMAlertmanagerConfig Webhook", func() {
               text: alert notification
           route:
             receiver: teams
+        `),
+			Entry("incidentio", `
+        apiVersion: v1
+        kind: VMAlertmanagerConfig
+        metadata:
+          name: incidentio
+        spec:
+          receivers:
+          - name: incidentio
+            incidentio_configs:
+            - url_secret:
+                name: incidentio
+                key: URL
+              alert_source_token:
+                name: incidentio
+                key: TOKEN
+              max_alerts: 10
+              timeout: 30s
+          route:
+            receiver: incidentio
         `),
 		)
 	})
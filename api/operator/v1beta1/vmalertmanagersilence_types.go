@@ -0,0 +1,143 @@
+package v1beta1
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VMAlertmanagerSilenceSpec defines silence, which is created at selected VMAlertmanagers
+type VMAlertmanagerSilenceSpec struct {
+	// Matchers defines label matchers of alerts muted by silence
+	// https://prometheus.io/docs/alerting/latest/configuration/#matcher
+	// +kubebuilder:validation:MinItems:=1
+	Matchers []string `json:"matchers"`
+	// StartsAt defines time, when silence becomes active.
+	// Silence starts at object creation time if not set
+	// +optional
+	StartsAt *metav1.Time `json:"startsAt,omitempty"`
+	// EndsAt defines time, when silence expires
+	EndsAt metav1.Time `json:"endsAt"`
+	// Comment describes silence purpose
+	Comment string `json:"comment"`
+	// CreatedBy defines author of silence
+	CreatedBy string `json:"createdBy"`
+}
+
+// Silence states reported by Alertmanager
+const (
+	SilenceStatePending = "pending"
+	SilenceStateActive  = "active"
+	SilenceStateExpired = "expired"
+)
+
+// VMAlertmanagerSilenceStatus defines the observed state of VMAlertmanagerSilence
+type VMAlertmanagerSilenceStatus struct {
+	StatusMetadata `json:",inline"`
+	// State defines current state of silence - pending, active or expired
+	// +optional
+	State string `json:"state,omitempty"`
+	// Silences contains silences created at VMAlertmanager pods
+	// +optional
+	Silences []AlertmanagerPodSilence `json:"silences,omitempty"`
+}
+
+// AlertmanagerPodSilence describes silence created at Alertmanager pod
+type AlertmanagerPodSilence struct {
+	// Alertmanager defines VMAlertmanager in form of namespace/name
+	Alertmanager string `json:"alertmanager"`
+	// Pod defines name of VMAlertmanager pod
+	Pod string `json:"pod"`
+	// ID of silence at Alertmanager
+	ID string `json:"id"`
+	// State of silence reported by Alertmanager
+	// +optional
+	State string `json:"state,omitempty"`
+}
+
+// VMAlertmanagerSilence is the Schema for the vmalertmanagersilences API
+// Silence is synced to all pods of VMAlertmanagers, which select it with configSelector and configNamespaceSelector
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="VMAlertmanagerSilence"
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=vmalertmanagersilences,scope=Namespaced
+// +kubebuilder:printcolumn:name="Ends At",type="date",JSONPath=".spec.endsAt"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.updateStatus"
+// +kubebuilder:printcolumn:name="Sync Error",type="string",JSONPath=".status.reason"
+// +genclient
+type VMAlertmanagerSilence struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VMAlertmanagerSilenceSpec   `json:"spec,omitempty"`
+	Status VMAlertmanagerSilenceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VMAlertmanagerSilenceList contains a list of VMAlertmanagerSilence
+type VMAlertmanagerSilenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VMAlertmanagerSilence `json:"items"`
+}
+
+// GetStatusMetadata implements reconcile.objectWithStatus interface
+func (cr *VMAlertmanagerSilence) GetStatusMetadata() *StatusMetadata {
+	return &cr.Status.StatusMetadata
+}
+
+// GetStartsAt returns start time of silence
+func (cr *VMAlertmanagerSilence) GetStartsAt() time.Time {
+	if cr.Spec.StartsAt != nil {
+		return cr.Spec.StartsAt.Time
+	}
+	return cr.CreationTimestamp.Time
+}
+
+// StateAt returns state of silence at the given time
+func (cr *VMAlertmanagerSilence) StateAt(t time.Time) string {
+	switch {
+	case !t.Before(cr.Spec.EndsAt.Time):
+		return SilenceStateExpired
+	case t.Before(cr.GetStartsAt()):
+		return SilenceStatePending
+	default:
+		return SilenceStateActive
+	}
+}
+
+// Validate performs semantic validation of object
+func (cr *VMAlertmanagerSilence) Validate() error {
+	if mustSkipValidation(cr) {
+		return nil
+	}
+	if len(cr.Spec.Matchers) == 0 {
+		return fmt.Errorf("matchers cannot be empty")
+	}
+	for idx, m := range cr.Spec.Matchers {
+		if _, err := labels.ParseMatcher(m); err != nil {
+			return fmt.Errorf("cannot parse matcher=%q idx=%d: %w", m, idx, err)
+		}
+	}
+	if cr.Spec.EndsAt.IsZero() {
+		return fmt.Errorf("endsAt cannot be empty")
+	}
+	if cr.Spec.StartsAt != nil && !cr.Spec.StartsAt.Before(&cr.Spec.EndsAt) {
+		return fmt.Errorf("endsAt=%s must be after startsAt=%s", cr.Spec.EndsAt.Format(time.RFC3339), cr.Spec.StartsAt.Format(time.RFC3339))
+	}
+	if cr.Spec.CreatedBy == "" {
+		return fmt.Errorf("createdBy cannot be empty")
+	}
+	if cr.Spec.Comment == "" {
+		return fmt.Errorf("comment cannot be empty")
+	}
+	return nil
+}
+
+func init() {
+	SchemeBuilder.Register(&VMAlertmanagerSilence{}, &VMAlertmanagerSilenceList{})
+}
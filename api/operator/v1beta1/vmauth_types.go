@@ -111,6 +111,13 @@ type VMAuthSpec struct {
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty" yaml:"serviceAccountName,omitempty"`
 
+	// MaintenanceWindows defines time ranges, when disruptive changes are allowed:
+	// Rolling update of Deployment pods.
+	// Such changes are deferred until the next window and listed at PendingMaintenance status condition.
+	// Other changes are applied as usual. Changes are applied immediately if windows are not defined.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty" yaml:"maintenanceWindows,omitempty"`
+
 	CommonDefaultableParams           `json:",inline,omitempty" yaml:",inline"`
 	CommonConfigReloaderParams        `json:",inline,omitempty" yaml:",inline"`
 	CommonApplicationDeploymentParams `json:",inline,omitempty" yaml:",inline"`
@@ -547,7 +554,7 @@ func (cr *VMAuth) PodAnnotations() map[string]string {
 
 func (cr *VMAuth) AnnotationsFiltered() map[string]string {
 	// TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
-	dst := filterMapKeysByPrefixes(cr.ObjectMeta.Annotations, annotationFilterPrefixes)
+	dst := filterMapKeysByPrefixes(cr.ObjectMeta.Annotations, getAnnotationFilterPrefixes())
 	if cr.Spec.ManagedMetadata != nil {
 		if dst == nil {
 			dst = make(map[string]string)
@@ -585,7 +592,7 @@ func (cr *VMAuth) AllLabels() map[string]string {
 	var result map[string]string
 	// TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
 	if cr.ObjectMeta.Labels != nil {
-		result = filterMapKeysByPrefixes(cr.ObjectMeta.Labels, labelFilterPrefixes)
+		result = filterMapKeysByPrefixes(cr.ObjectMeta.Labels, getLabelFilterPrefixes())
 	}
 	if cr.Spec.ManagedMetadata != nil {
 		result = labels.Merge(result, cr.Spec.ManagedMetadata.Labels)
@@ -606,6 +613,23 @@ func (cr *VMAuth) GetMetricPath() string {
 	return buildPathWithPrefixFlag(cr.Spec.ExtraArgs, metricPath)
 }
 
+// AsURL returns url for accessing vmauth service
+func (cr *VMAuth) AsURL() string {
+	port := cr.Spec.Port
+	if port == "" {
+		port = "8427"
+	}
+	if cr.Spec.ServiceSpec != nil && cr.Spec.ServiceSpec.UseAsDefault {
+		for _, svcPort := range cr.Spec.ServiceSpec.Spec.Ports {
+			if svcPort.Name == "http" {
+				port = fmt.Sprintf("%d", svcPort.Port)
+				break
+			}
+		}
+	}
+	return fmt.Sprintf("%s://%s.%s.svc:%s", protoFromFlags(cr.Spec.ExtraArgs), cr.PrefixedName(), cr.Namespace, port)
+}
+
 // GetExtraArgs returns additionally configured command-line arguments
 func (cr *VMAuth) GetExtraArgs() map[string]string {
 	return cr.Spec.ExtraArgs
@@ -658,6 +682,21 @@ func (cr *VMAuth) Paused() bool {
 	return cr.Spec.Paused
 }
 
+// GetStatusMetadata returns metadata for object status
+func (cr *VMAuth) GetStatusMetadata() *StatusMetadata {
+	return &cr.Status.StatusMetadata
+}
+
+// IsDriftReportOnly checks if child objects modified outside of operator must not be reverted
+func (cr *VMAuth) IsDriftReportOnly() bool {
+	return cr.Annotations[DriftPolicyAnnotation] == DriftPolicyReportOnly
+}
+
+// GetMaintenanceWindows returns time ranges, when disruptive changes are allowed
+func (cr *VMAuth) GetMaintenanceWindows() []MaintenanceWindow {
+	return cr.Spec.MaintenanceWindows
+}
+
 // SetStatusTo changes update status with optional reason of fail
 func (cr *VMAuth) SetUpdateStatusTo(ctx context.Context, c client.Client, status UpdateStatus, maybeErr error) error {
 	return updateObjectStatus(ctx, c, &patchStatusOpts[*VMAuth, *VMAuthStatus]{
@@ -68,6 +68,12 @@ type VMClusterSpec struct {
 	// going to be performed, except for delete actions.
 	// +optional
 	Paused bool `json:"paused,omitempty"`
+	// MaintenanceWindows defines time ranges, when disruptive changes are allowed:
+	// StatefulSet recreation, rolling update of StatefulSet and Deployment pods and PVC expansion.
+	// Such changes are deferred until the next window and listed at PendingMaintenance status condition.
+	// Other changes are applied as usual. Changes are applied immediately if windows are not defined.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 	// UseStrictSecurity enables strict security mode for component
 	// it restricts disk writes access
 	// uses non-root user out of the box
@@ -156,6 +162,9 @@ func (cr *VMClusterSpec) UnmarshalJSON(src []byte) error {
 // +kubebuilder:printcolumn:name="Insert Count",type="string",JSONPath=".spec.vminsert.replicaCount",description="replicas of VMInsert"
 // +kubebuilder:printcolumn:name="Storage Count",type="string",JSONPath=".spec.vmstorage.replicaCount",description="replicas of VMStorage"
 // +kubebuilder:printcolumn:name="Select Count",type="string",JSONPath=".spec.vmselect.replicaCount",description="replicas of VMSelect"
+// +kubebuilder:printcolumn:name="Insert Ready",type="string",JSONPath=".status.vminsert.ready",description="ready replicas of VMInsert"
+// +kubebuilder:printcolumn:name="Storage Ready",type="string",JSONPath=".status.vmstorage.ready",description="ready replicas of VMStorage"
+// +kubebuilder:printcolumn:name="Select Ready",type="string",JSONPath=".status.vmselect.ready",description="ready replicas of VMSelect"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.updateStatus",description="Current status of cluster"
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -190,6 +199,57 @@ type VMClusterStatus struct {
 	StatusMetadata `json:",inline"`
 	// LegacyStatus is deprecated and will be removed at v0.52.0 version
 	LegacyStatus UpdateStatus `json:"clusterStatus,omitempty"`
+	// VMSelect defines status of vmselect component
+	// +optional
+	VMSelect *VMClusterComponentStatus `json:"vmselect,omitempty"`
+	// VMInsert defines status of vminsert component
+	// +optional
+	VMInsert *VMClusterComponentStatus `json:"vminsert,omitempty"`
+	// VMStorage defines status of vmstorage component
+	// +optional
+	VMStorage *VMClusterComponentStatus `json:"vmstorage,omitempty"`
+	// RequestsLoadBalancer defines status of requests load balancer component
+	// +optional
+	RequestsLoadBalancer *VMClusterComponentStatus `json:"requestsLoadBalancer,omitempty"`
+}
+
+// ClusterComponentPhase defines rollout phase of cluster component
+type ClusterComponentPhase string
+
+const (
+	ClusterComponentPending     ClusterComponentPhase = "Pending"
+	ClusterComponentProgressing ClusterComponentPhase = "Progressing"
+	ClusterComponentReady       ClusterComponentPhase = "Ready"
+	ClusterComponentFailed      ClusterComponentPhase = "Failed"
+)
+
+// Condition types for VMCluster components readiness
+const (
+	ConditionVMSelectReady             = "VMSelectReady"
+	ConditionVMInsertReady             = "VMInsertReady"
+	ConditionVMStorageReady            = "VMStorageReady"
+	ConditionRequestsLoadBalancerReady = "RequestsLoadBalancerReady"
+)
+
+// VMClusterComponentStatus defines the observed state of cluster component
+// it's populated from the state of component StatefulSet or Deployment
+type VMClusterComponentStatus struct {
+	// Replicas is the desired number of component pods
+	Replicas int32 `json:"replicas"`
+	// ReadyReplicas is the number of ready component pods
+	ReadyReplicas int32 `json:"readyReplicas"`
+	// UpdatedReplicas is the number of component pods updated to the current revision
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+	// Ready is ready and desired pods in human readable form, e.g. 4/5
+	Ready string `json:"ready,omitempty"`
+	// Image is the current image of component
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Phase defines rollout phase of component
+	Phase ClusterComponentPhase `json:"phase,omitempty"`
+	// LastError contains the last reconcile error of component
+	// +optional
+	LastError string `json:"lastError,omitempty"`
 }
 
 // GetStatusMetadata returns metadata for object status
@@ -711,7 +771,7 @@ func (cr *VMCluster) FinalLabels(selectorLabels map[string]string) map[string]st
 	var result map[string]string
 	// TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
 	if cr.ObjectMeta.Labels != nil {
-		result = filterMapKeysByPrefixes(cr.ObjectMeta.Labels, labelFilterPrefixes)
+		result = filterMapKeysByPrefixes(cr.ObjectMeta.Labels, getLabelFilterPrefixes())
 	}
 	if cr.Spec.ManagedMetadata != nil {
 		result = labels.Merge(result, cr.Spec.ManagedMetadata.Labels)
@@ -746,7 +806,7 @@ func (cr *VMCluster) VMStoragePodAnnotations() map[string]string {
 // AnnotationsFiltered returns global annotations to be applied by objects generate for vmcluster
 func (cr *VMCluster) AnnotationsFiltered() map[string]string {
 	// TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
-	dst := filterMapKeysByPrefixes(cr.ObjectMeta.Annotations, annotationFilterPrefixes)
+	dst := filterMapKeysByPrefixes(cr.ObjectMeta.Annotations, getAnnotationFilterPrefixes())
 	if cr.Spec.ManagedMetadata != nil {
 		if dst == nil {
 			dst = make(map[string]string)
@@ -773,6 +833,21 @@ func (cr *VMCluster) Paused() bool {
 	return cr.Spec.Paused
 }
 
+// GetStatusMetadata returns metadata for object status
+func (cr *VMCluster) GetStatusMetadata() *StatusMetadata {
+	return &cr.Status.StatusMetadata
+}
+
+// IsDriftReportOnly checks if child objects modified outside of operator must not be reverted
+func (cr *VMCluster) IsDriftReportOnly() bool {
+	return cr.Annotations[DriftPolicyAnnotation] == DriftPolicyReportOnly
+}
+
+// GetMaintenanceWindows returns time ranges, when disruptive changes are allowed
+func (cr *VMCluster) GetMaintenanceWindows() []MaintenanceWindow {
+	return cr.Spec.MaintenanceWindows
+}
+
 // GetMetricPath returns prefixed path for metric requests
 func (cr *VMSelect) GetMetricPath() string {
 	if cr == nil {
@@ -0,0 +1,52 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VMClusterScrapeDefaults is cluster-scoped variant of VMScrapeDefaults.
+// It defines default scrape settings for scrape objects at namespaces selected by namespaceSelector.
+// NamespaceSelector nil or empty - objects at any namespace.
+// It's ignored if operator watches only a subset of namespaces.
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="VMClusterScrapeDefaults"
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=vmclusterscrapedefaults,scope=Cluster
+// +kubebuilder:printcolumn:name="Priority",type="integer",JSONPath=".spec.priority"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.updateStatus"
+// +kubebuilder:printcolumn:name="Sync Error",type="string",JSONPath=".status.reason"
+// +genclient
+// +genclient:nonNamespaced
+type VMClusterScrapeDefaults struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VMScrapeDefaultsSpec `json:"spec,omitempty"`
+	Status ScrapeObjectStatus   `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VMClusterScrapeDefaultsList contains a list of VMClusterScrapeDefaults
+type VMClusterScrapeDefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VMClusterScrapeDefaults `json:"items"`
+}
+
+// AsKey returns name of object
+//
+// it cannot clash with namespace/name key of VMScrapeDefaults
+func (cr *VMClusterScrapeDefaults) AsKey() string {
+	return cr.Name
+}
+
+// GetStatusMetadata implements reconcile.objectWithStatus interface
+func (cr *VMClusterScrapeDefaults) GetStatusMetadata() *StatusMetadata {
+	return &cr.Status.StatusMetadata
+}
+
+func init() {
+	SchemeBuilder.Register(&VMClusterScrapeDefaults{}, &VMClusterScrapeDefaultsList{})
+}
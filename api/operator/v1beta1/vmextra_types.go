@@ -8,8 +8,10 @@ import (
 	"path"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	"gopkg.in/yaml.v2"
 
@@ -52,7 +54,10 @@ const (
 	SkipValidationValue      = "true"
 	AdditionalServiceLabel   = "operator.victoriametrics.com/additional-service"
 	// PVCExpandableLabel controls checks for storageClass
-	PVCExpandableLabel            = "operator.victoriametrics.com/pvc-allow-volume-expansion"
+	PVCExpandableLabel = "operator.victoriametrics.com/pvc-allow-volume-expansion"
+	// ConfigSecretPartOfLabel marks secrets with configuration parts, value is a name of the main config secret
+	// config-reloader uses it for parts discovery
+	ConfigSecretPartOfLabel       = "operator.victoriametrics.com/config-secret-part-of"
 	lastAppliedSpecAnnotationName = "operator.victoriametrics/last-applied-spec"
 )
 
@@ -69,25 +74,68 @@ const (
 	ConditionParsingReason = "ConfigParsedAndApplied"
 	// ConditionDomainTypeAppliedSuffix defines type suffix for ConditionParsingReason reason
 	ConditionDomainTypeAppliedSuffix = ".victoriametrics.com/Applied"
+	// ConditionDriftCorrected defines condition type for child objects modified outside of operator
+	ConditionDriftCorrected = "DriftCorrected"
+	// ConditionPendingMaintenance defines condition type for disruptive changes deferred until maintenance window
+	ConditionPendingMaintenance = "PendingMaintenance"
+)
+
+const (
+	// DriftPolicyAnnotation defines how operator handles child objects modified outside of operator
+	DriftPolicyAnnotation = "operator.victoriametrics.com/drift-policy"
+	// DriftPolicyReportOnly reports modified child objects without reverting them
+	DriftPolicyReportOnly = "report-only"
+	// RolloutPriorityAnnotation defines order of object update during defaults rollout
+	// objects with higher integer value are updated first
+	RolloutPriorityAnnotation = "operator.victoriametrics.com/rollout-priority"
+	// MaintenanceForceAnnotation allows to apply disruptive changes outside of maintenance windows
+	// if set to true
+	MaintenanceForceAnnotation = "operator.victoriametrics.com/maintenance-force"
+	// ReconcilePriorityAnnotation defines priority class of parent object, such as VMAgent, for reconcile rate limiting
+	// supported values are high, normal and low
+	ReconcilePriorityAnnotation = "operator.victoriametrics.com/reconcile-priority"
+	// ReconcilePriorityHigh allows object to bypass per-object rate limit
+	ReconcilePriorityHigh = "high"
+	// ReconcilePriorityNormal is the default priority class
+	ReconcilePriorityNormal = "normal"
+	// ReconcilePriorityLow allows object to use only the half of controller rate limit budget
+	ReconcilePriorityLow = "low"
 )
 
 // SchemeGroupVersion is group version used to register these objects
 var SchemeGroupVersion = schema.GroupVersion{Group: "operator.victoriametrics.com", Version: "v1beta1"}
 
+// default ignored annotations
+var defaultAnnotationFilterPrefixes = []string{"kubectl.kubernetes.io/", "operator.victoriametrics.com/", "operator.victoriametrics/"}
+
 var (
+	filterPrefixesMu sync.RWMutex
 	// TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
 	labelFilterPrefixes []string
-	// default ignored annotations
 	// TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
-	annotationFilterPrefixes = []string{"kubectl.kubernetes.io/", "operator.victoriametrics.com/", "operator.victoriametrics/"}
+	annotationFilterPrefixes = defaultAnnotationFilterPrefixes
 )
 
 // SetLabelAndAnnotationPrefixes configures global filtering for child labels and annotations
-// cannot be used concurrently and should be called only once at lib init
+// it replaces previously configured prefixes and could be called at runtime
 // TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
 func SetLabelAndAnnotationPrefixes(labelPrefixes, annotationPrefixes []string) {
+	filterPrefixesMu.Lock()
+	defer filterPrefixesMu.Unlock()
 	labelFilterPrefixes = labelPrefixes
-	annotationFilterPrefixes = append(annotationFilterPrefixes, annotationPrefixes...)
+	annotationFilterPrefixes = append(slices.Clip(defaultAnnotationFilterPrefixes), annotationPrefixes...)
+}
+
+func getLabelFilterPrefixes() []string {
+	filterPrefixesMu.RLock()
+	defer filterPrefixesMu.RUnlock()
+	return labelFilterPrefixes
+}
+
+func getAnnotationFilterPrefixes() []string {
+	filterPrefixesMu.RLock()
+	defer filterPrefixesMu.RUnlock()
+	return annotationFilterPrefixes
 }
 
 // TODO: @f41gh7 deprecated at will be removed at v0.52.0 release
@@ -294,6 +342,76 @@ type EmbeddedPersistentVolumeClaim struct {
 	Status v1.PersistentVolumeClaimStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
 }
 
+// RemoteTargetRef references VictoriaMetrics CRD object, which is used as a remote endpoint instead of raw url.
+// Operator resolves url of the referenced object and tracks its changes.
+type RemoteTargetRef struct {
+	// Kind of the referenced object
+	// +kubebuilder:validation:Enum=VMSingle;VMCluster;VMAuth
+	Kind string `json:"kind"`
+	// Name of the referenced object
+	Name string `json:"name"`
+	// Namespace of the referenced object, if empty - namespace of the current object is used
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// TenantID for VMCluster kind in form of accountID[:projectID] or multitenant.
+	// By default 0 tenant is used
+	// +optional
+	TenantID string `json:"tenantID,omitempty"`
+	// User defines name of VMUser at the namespace of the current object for VMAuth kind.
+	// Its credentials are used for vmauth access, if no other auth method is defined
+	// +optional
+	User string `json:"user,omitempty"`
+}
+
+// Validate performs syntax check for ref
+func (r *RemoteTargetRef) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	switch r.Kind {
+	case "VMSingle", "VMCluster", "VMAuth":
+	default:
+		return fmt.Errorf("unsupported kind=%q, want one of VMSingle,VMCluster,VMAuth", r.Kind)
+	}
+	if r.TenantID != "" && r.Kind != "VMCluster" {
+		return fmt.Errorf("tenantID can be used only with VMCluster kind")
+	}
+	if r.User != "" && r.Kind != "VMAuth" {
+		return fmt.Errorf("user can be used only with VMAuth kind")
+	}
+	return nil
+}
+
+func validateRemoteURL(url string, ref *RemoteTargetRef) error {
+	switch {
+	case url == "" && ref == nil:
+		return fmt.Errorf("url or targetRef must be set")
+	case url != "" && ref != nil:
+		return fmt.Errorf("url and targetRef cannot be used at the same time")
+	case ref != nil:
+		if err := ref.Validate(); err != nil {
+			return fmt.Errorf("incorrect targetRef: %w", err)
+		}
+	}
+	return nil
+}
+
+// NamespaceOrDefault returns namespace of the referenced object
+func (r *RemoteTargetRef) NamespaceOrDefault(defaultNamespace string) string {
+	if r.Namespace != "" {
+		return r.Namespace
+	}
+	return defaultNamespace
+}
+
+// Tenant returns tenant for VMCluster url path
+func (r *RemoteTargetRef) Tenant() string {
+	if r.TenantID != "" {
+		return r.TenantID
+	}
+	return "0"
+}
+
 // HTTPAuth generic auth used with http protocols
 type HTTPAuth struct {
 	// +optional
@@ -1075,6 +1193,9 @@ type TLSClientConfig struct {
 // ScrapeObjectStatus defines the observed state of ScrapeObjects
 type ScrapeObjectStatus struct {
 	StatusMetadata `json:",inline"`
+	// AppliedDefaults lists VMScrapeDefaults and VMClusterScrapeDefaults objects, which supplied settings to the object
+	// +optional
+	AppliedDefaults []AppliedScrapeDefaults `json:"appliedDefaults,omitempty"`
 }
 
 type objectWithLastAppliedState[T, ST any] interface {
@@ -1491,6 +1612,20 @@ type Image struct {
 	PullPolicy v1.PullPolicy `json:"pullPolicy,omitempty"`
 }
 
+// MaintenanceWindow defines time range, when disruptive changes are allowed
+type MaintenanceWindow struct {
+	// Schedule defines start of window in cron format, e.g. "0 2 * * 6" - every Saturday at 02:00
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+	// Duration defines how long window lasts after start, e.g. 2h
+	// +kubebuilder:validation:Pattern:="[0-9]+(ms|s|m|h)"
+	Duration string `json:"duration"`
+	// TimeZone defines IANA time zone name for schedule, e.g. Europe/Berlin
+	// By default, UTC is used
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
 // Condition defines status condition of the resource
 type Condition struct {
 	// Type of condition in CamelCase or in name.namespace.resource.victoriametrics.com/CamelCase.
@@ -132,6 +132,11 @@ func (cr *VMProbe) GetStatusMetadata() *StatusMetadata {
 	return &cr.Status.StatusMetadata
 }
 
+// GetScrapeObjectStatus implements reconcile.objectWithScrapeDefaults interface
+func (cr *VMProbe) GetScrapeObjectStatus() *ScrapeObjectStatus {
+	return &cr.Status
+}
+
 func init() {
 	SchemeBuilder.Register(&VMProbe{}, &VMProbeList{})
 }
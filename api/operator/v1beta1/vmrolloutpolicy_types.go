@@ -0,0 +1,135 @@
+package v1beta1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RolloutPhase defines state of defaults rollout
+type RolloutPhase string
+
+const (
+	// RolloutPhaseIdle - there were no rollouts since operator start
+	RolloutPhaseIdle RolloutPhase = "Idle"
+	// RolloutPhaseProgressing - objects are updated in batches
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+	// RolloutPhasePaused - new batches are not started
+	RolloutPhasePaused RolloutPhase = "Paused"
+	// RolloutPhaseAborted - objects, which are not updated yet, keep previous configuration
+	RolloutPhaseAborted RolloutPhase = "Aborted"
+	// RolloutPhaseCompleted - all objects are updated
+	RolloutPhaseCompleted RolloutPhase = "Completed"
+	// RolloutPhaseFailed - number of objects failed to reach operational state exceeded maxFailures,
+	// new batches are not started until policy spec is changed
+	RolloutPhaseFailed RolloutPhase = "Failed"
+)
+
+const defaultProgressDeadline = 10 * time.Minute
+
+// VMRolloutPolicySpec defines how objects are updated after change of operator defaults
+type VMRolloutPolicySpec struct {
+	// MaxConcurrent defines maximum number of objects updated at the same batch
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrent int32 `json:"maxConcurrent,omitempty"`
+	// OrderByLabel defines label key, objects are updated in ascending order of its value.
+	// Objects without label are updated last.
+	// Object priority defined with operator.victoriametrics.com/rollout-priority annotation takes precedence over label order.
+	// +optional
+	OrderByLabel string `json:"orderByLabel,omitempty"`
+	// Paused stops start of new batches, objects of the current batch are updated
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+	// Abort stops rollout. Objects, which are not updated yet, keep previous configuration
+	// until abort is disabled or object spec is changed
+	// +optional
+	Abort bool `json:"abort,omitempty"`
+	// ProgressDeadline defines how long object of the current batch could reach operational state.
+	// Object, which doesn't reach it in time, is marked as failed and removed from the batch.
+	// Default value is 10m
+	// +optional
+	// +kubebuilder:validation:Pattern:="[0-9]+(ms|s|m|h)"
+	ProgressDeadline string `json:"progressDeadline,omitempty"`
+	// MaxFailures defines number of failed objects, which stops rollout.
+	// Stopped rollout is resumed after change of policy spec.
+	// By default, the first failed object stops rollout
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxFailures int32 `json:"maxFailures,omitempty"`
+}
+
+// VMRolloutPolicyStatus defines progress of defaults rollout
+type VMRolloutPolicyStatus struct {
+	// Phase of the current rollout
+	Phase RolloutPhase `json:"phase,omitempty"`
+	// Total number of objects at the current rollout
+	Total int32 `json:"total,omitempty"`
+	// Updated number of objects, which reached operational state with new defaults
+	Updated int32 `json:"updated,omitempty"`
+	// Pending number of objects waiting for update
+	Pending int32 `json:"pending,omitempty"`
+	// Updating lists objects of the current batch in kind/namespace/name format
+	// +optional
+	Updating []string `json:"updating,omitempty"`
+	// Failed lists objects, which didn't reach operational state within progressDeadline, in kind/namespace/name format
+	// +optional
+	Failed []string `json:"failed,omitempty"`
+	// LastUpdateTime defines time of the last status change
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// VMRolloutPolicy controls fleet-wide rollout of VMAgent, VMAlert, VMAlertmanager, VMAuth, VMCluster, VMSingle and VLogs objects
+// after change of operator defaults, such as default image versions or resources.
+// Operator uses the object configured with -defaultsRollout.policy flag.
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="VMRolloutPolicy"
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=vmrolloutpolicies,scope=Namespaced
+// +kubebuilder:printcolumn:name="Max Concurrent",type="integer",JSONPath=".spec.maxConcurrent"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Updated",type="integer",JSONPath=".status.updated"
+// +kubebuilder:printcolumn:name="Total",type="integer",JSONPath=".status.total"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +genclient
+type VMRolloutPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VMRolloutPolicySpec   `json:"spec,omitempty"`
+	Status VMRolloutPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VMRolloutPolicyList contains a list of VMRolloutPolicy
+type VMRolloutPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VMRolloutPolicy `json:"items"`
+}
+
+// GetMaxConcurrent returns maximum number of objects updated at the same batch
+func (cr *VMRolloutPolicy) GetMaxConcurrent() int {
+	if cr.Spec.MaxConcurrent <= 0 {
+		return 1
+	}
+	return int(cr.Spec.MaxConcurrent)
+}
+
+// GetProgressDeadline returns time for object of the current batch to reach operational state
+func (cr *VMRolloutPolicy) GetProgressDeadline() time.Duration {
+	if cr.Spec.ProgressDeadline == "" {
+		return defaultProgressDeadline
+	}
+	d, err := time.ParseDuration(cr.Spec.ProgressDeadline)
+	if err != nil || d <= 0 {
+		return defaultProgressDeadline
+	}
+	return d
+}
+
+func init() {
+	SchemeBuilder.Register(&VMRolloutPolicy{}, &VMRolloutPolicyList{})
+}
@@ -144,6 +144,9 @@ func (cr *VMRule) GetStatusMetadata() *StatusMetadata {
 	return &cr.Status.StatusMetadata
 }
 
+// Hub marks VMRule as a conversion hub, other API versions are converted to and from it
+func (*VMRule) Hub() {}
+
 // Validate performs symantic validation of object
 func (cr *VMRule) Validate() error {
 	if mustSkipValidation(cr) {
@@ -217,6 +220,7 @@ func validateRuleGroupTenantID(id string) error {
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=vmrules,scope=Namespaced
+// +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.updateStatus"
 // +kubebuilder:printcolumn:name="Sync Error",type="string",JSONPath=".status.reason"
@@ -0,0 +1,83 @@
+package v1beta1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VMScrapeDefaultsSpec defines default scrape settings for scrape objects
+type VMScrapeDefaultsSpec struct {
+	// NamespaceSelector defines namespaces of scrape objects, which receive defaults.
+	// NamespaceSelector nil - only objects at VMScrapeDefaults namespace.
+	// NamespaceSelector empty - objects at any namespace.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Priority defines merge order if multiple VMScrapeDefaults match the same scrape object.
+	// Objects with higher priority are applied first and their settings take precedence.
+	// Objects with the same priority are applied in namespace/name order.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// Relabelings are applied only if endpoint doesn't define own relabelConfigs or metricRelabelConfigs.
+	EndpointRelabelings `json:",inline"`
+	// Auth is applied only if endpoint doesn't define any authorization method.
+	// tlsConfig is applied separately, if endpoint doesn't have own tlsConfig.
+	// Secrets and ConfigMaps are referenced at the namespace of scrape object.
+	EndpointAuth `json:",inline"`
+	// ScrapeParams are applied per field, if endpoint doesn't set given field.
+	EndpointScrapeParams `json:",inline"`
+}
+
+// VMScrapeDefaults defines default scrape settings for VMServiceScrape, VMPodScrape,
+// VMStaticScrape, VMNodeScrape, VMProbe and VMScrapeConfig objects at namespaces selected by namespaceSelector.
+// Settings defined at scrape object always have priority over defaults.
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="VMScrapeDefaults"
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=vmscrapedefaults,scope=Namespaced
+// +kubebuilder:printcolumn:name="Priority",type="integer",JSONPath=".spec.priority"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.updateStatus"
+// +kubebuilder:printcolumn:name="Sync Error",type="string",JSONPath=".status.reason"
+// +genclient
+type VMScrapeDefaults struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VMScrapeDefaultsSpec `json:"spec,omitempty"`
+	Status ScrapeObjectStatus   `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VMScrapeDefaultsList contains a list of VMScrapeDefaults
+type VMScrapeDefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VMScrapeDefaults `json:"items"`
+}
+
+// AppliedScrapeDefaults describes settings of scrape object supplied by VMScrapeDefaults
+type AppliedScrapeDefaults struct {
+	// Name of VMScrapeDefaults object in form of namespace/name
+	// or name of VMClusterScrapeDefaults object
+	Name string `json:"name"`
+	// Fields lists paths of scrape object fields with values taken from VMScrapeDefaults
+	// +optional
+	Fields []string `json:"fields,omitempty"`
+}
+
+// AsKey returns namespace/name key of object
+func (cr *VMScrapeDefaults) AsKey() string {
+	return fmt.Sprintf("%s/%s", cr.Namespace, cr.Name)
+}
+
+// GetStatusMetadata implements reconcile.objectWithStatus interface
+func (cr *VMScrapeDefaults) GetStatusMetadata() *StatusMetadata {
+	return &cr.Status.StatusMetadata
+}
+
+func init() {
+	SchemeBuilder.Register(&VMScrapeDefaults{}, &VMScrapeDefaultsList{})
+}
@@ -145,6 +145,11 @@ func (cr *VMServiceScrape) GetStatusMetadata() *StatusMetadata {
 	return &cr.Status.StatusMetadata
 }
 
+// GetScrapeObjectStatus implements reconcile.objectWithScrapeDefaults interface
+func (cr *VMServiceScrape) GetScrapeObjectStatus() *ScrapeObjectStatus {
+	return &cr.Status
+}
+
 func init() {
 	SchemeBuilder.Register(&VMServiceScrape{}, &VMServiceScrapeList{})
 }
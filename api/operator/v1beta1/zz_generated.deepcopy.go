@@ -126,6 +126,62 @@ func (in *AlertmanagerHTTPConfig) DeepCopy() *AlertmanagerHTTPConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertmanagerPeerDiscovery) DeepCopyInto(out *AlertmanagerPeerDiscovery) {
+	*out = *in
+	if in.ServiceSelector != nil {
+		in, out := &in.ServiceSelector, &out.ServiceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DNSSRVNames != nil {
+		in, out := &in.DNSSRVNames, &out.DNSSRVNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerPeerDiscovery.
+func (in *AlertmanagerPeerDiscovery) DeepCopy() *AlertmanagerPeerDiscovery {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerPeerDiscovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertmanagerPeerStatus) DeepCopyInto(out *AlertmanagerPeerStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerPeerStatus.
+func (in *AlertmanagerPeerStatus) DeepCopy() *AlertmanagerPeerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerPeerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertmanagerPodSilence) DeepCopyInto(out *AlertmanagerPodSilence) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerPodSilence.
+func (in *AlertmanagerPodSilence) DeepCopy() *AlertmanagerPodSilence {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerPodSilence)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertmanagerWebConfig) DeepCopyInto(out *AlertmanagerWebConfig) {
 	*out = *in
@@ -158,6 +214,26 @@ func (in *AlertmanagerWebConfig) DeepCopy() *AlertmanagerWebConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedScrapeDefaults) DeepCopyInto(out *AppliedScrapeDefaults) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedScrapeDefaults.
+func (in *AppliedScrapeDefaults) DeepCopy() *AppliedScrapeDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedScrapeDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArbitraryFSAccessThroughSMsConfig) DeepCopyInto(out *ArbitraryFSAccessThroughSMsConfig) {
 	*out = *in
@@ -1546,6 +1622,46 @@ func (in *ImageConfig) DeepCopy() *ImageConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IncidentIOConfig) DeepCopyInto(out *IncidentIOConfig) {
+	*out = *in
+	if in.SendResolved != nil {
+		in, out := &in.SendResolved, &out.SendResolved
+		*out = new(bool)
+		**out = **in
+	}
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(string)
+		**out = **in
+	}
+	if in.URLSecret != nil {
+		in, out := &in.URLSecret, &out.URLSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AlertSourceToken != nil {
+		in, out := &in.AlertSourceToken, &out.AlertSourceToken
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPConfig != nil {
+		in, out := &in.HTTPConfig, &out.HTTPConfig
+		*out = new(HTTPConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncidentIOConfig.
+func (in *IncidentIOConfig) DeepCopy() *IncidentIOConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(IncidentIOConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InhibitRule) DeepCopyInto(out *InhibitRule) {
 	*out = *in
@@ -1834,6 +1950,21 @@ func (in *MSTeamsV2Config) DeepCopy() *MSTeamsV2Config {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedObjectsMetadata) DeepCopyInto(out *ManagedObjectsMetadata) {
 	*out = *in
@@ -1908,6 +2039,26 @@ func (in *NamespaceSelector) DeepCopy() *NamespaceSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTemplate) DeepCopyInto(out *NotificationTemplate) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTemplate.
+func (in *NotificationTemplate) DeepCopy() *NotificationTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OAuth2) DeepCopyInto(out *OAuth2) {
 	*out = *in
@@ -2392,6 +2543,13 @@ func (in *Receiver) DeepCopyInto(out *Receiver) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.IncidentIOConfigs != nil {
+		in, out := &in.IncidentIOConfigs, &out.IncidentIOConfigs
+		*out = make([]IncidentIOConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Receiver.
@@ -2404,6 +2562,42 @@ func (in *Receiver) DeepCopy() *Receiver {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReceiverTestIntegration) DeepCopyInto(out *ReceiverTestIntegration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReceiverTestIntegration.
+func (in *ReceiverTestIntegration) DeepCopy() *ReceiverTestIntegration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReceiverTestIntegration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReceiverTestStatus) DeepCopyInto(out *ReceiverTestStatus) {
+	*out = *in
+	in.LastTestTime.DeepCopyInto(&out.LastTestTime)
+	if in.Integrations != nil {
+		in, out := &in.Integrations, &out.Integrations
+		*out = make([]ReceiverTestIntegration, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReceiverTestStatus.
+func (in *ReceiverTestStatus) DeepCopy() *ReceiverTestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReceiverTestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RelabelConfig) DeepCopyInto(out *RelabelConfig) {
 	*out = *in
@@ -2456,6 +2650,21 @@ func (in *RelabelConfig) DeepCopy() *RelabelConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteTargetRef) DeepCopyInto(out *RemoteTargetRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteTargetRef.
+func (in *RemoteTargetRef) DeepCopy() *RemoteTargetRef {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteTargetRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RocketchatAttachmentAction) DeepCopyInto(out *RocketchatAttachmentAction) {
 	*out = *in
@@ -2706,6 +2915,13 @@ func (in *RuleGroup) DeepCopy() *RuleGroup {
 func (in *ScrapeObjectStatus) DeepCopyInto(out *ScrapeObjectStatus) {
 	*out = *in
 	in.StatusMetadata.DeepCopyInto(&out.StatusMetadata)
+	if in.AppliedDefaults != nil {
+		in, out := &in.AppliedDefaults, &out.AppliedDefaults
+		*out = make([]AppliedScrapeDefaults, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScrapeObjectStatus.
@@ -3596,6 +3812,33 @@ func (in *UnauthorizedAccessConfigURLMap) DeepCopy() *UnauthorizedAccessConfigUR
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnreachableRoute) DeepCopyInto(out *UnreachableRoute) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Receivers != nil {
+		in, out := &in.Receivers, &out.Receivers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnreachableRoute.
+func (in *UnreachableRoute) DeepCopy() *UnreachableRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(UnreachableRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VLogs) DeepCopyInto(out *VLogs) {
 	*out = *in
@@ -3673,6 +3916,11 @@ func (in *VLogsSpec) DeepCopyInto(out *VLogsSpec) {
 		*out = new(ManagedObjectsMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	in.CommonDefaultableParams.DeepCopyInto(&out.CommonDefaultableParams)
 	in.CommonApplicationDeploymentParams.DeepCopyInto(&out.CommonApplicationDeploymentParams)
 	if in.Storage != nil {
@@ -3756,6 +4004,60 @@ func (in *VMAgent) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMAgentConfigCanary) DeepCopyInto(out *VMAgentConfigCanary) {
+	*out = *in
+	if in.ShardNum != nil {
+		in, out := &in.ShardNum, &out.ShardNum
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxScrapeErrorsPercent != nil {
+		in, out := &in.MaxScrapeErrorsPercent, &out.MaxScrapeErrorsPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxDroppedSamples != nil {
+		in, out := &in.MaxDroppedSamples, &out.MaxDroppedSamples
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMAgentConfigCanary.
+func (in *VMAgentConfigCanary) DeepCopy() *VMAgentConfigCanary {
+	if in == nil {
+		return nil
+	}
+	out := new(VMAgentConfigCanary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMAgentConfigCanaryStatus) DeepCopyInto(out *VMAgentConfigCanaryStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.DroppedSamplesBaseline != nil {
+		in, out := &in.DroppedSamplesBaseline, &out.DroppedSamplesBaseline
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMAgentConfigCanaryStatus.
+func (in *VMAgentConfigCanaryStatus) DeepCopy() *VMAgentConfigCanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMAgentConfigCanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMAgentList) DeepCopyInto(out *VMAgentList) {
 	*out = *in
@@ -3843,6 +4145,11 @@ func (in *VMAgentRemoteWriteSettings) DeepCopy() *VMAgentRemoteWriteSettings {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMAgentRemoteWriteSpec) DeepCopyInto(out *VMAgentRemoteWriteSpec) {
 	*out = *in
+	if in.TargetRef != nil {
+		in, out := &in.TargetRef, &out.TargetRef
+		*out = new(RemoteTargetRef)
+		**out = **in
+	}
 	if in.BasicAuth != nil {
 		in, out := &in.BasicAuth, &out.BasicAuth
 		*out = new(BasicAuth)
@@ -4072,6 +4379,11 @@ func (in *VMAgentSpec) DeepCopyInto(out *VMAgentSpec) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.ConfigCanary != nil {
+		in, out := &in.ConfigCanary, &out.ConfigCanary
+		*out = new(VMAgentConfigCanary)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.UpdateStrategy != nil {
 		in, out := &in.UpdateStrategy, &out.UpdateStrategy
 		*out = new(appsv1.DeploymentStrategyType)
@@ -4173,6 +4485,11 @@ func (in *VMAgentSpec) DeepCopyInto(out *VMAgentSpec) {
 		*out = new(StorageSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	if in.ClaimTemplates != nil {
 		in, out := &in.ClaimTemplates, &out.ClaimTemplates
 		*out = make([]v1.PersistentVolumeClaim, len(*in))
@@ -4204,6 +4521,11 @@ func (in *VMAgentSpec) DeepCopy() *VMAgentSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMAgentStatus) DeepCopyInto(out *VMAgentStatus) {
 	*out = *in
+	if in.ConfigCanary != nil {
+		in, out := &in.ConfigCanary, &out.ConfigCanary
+		*out = new(VMAgentConfigCanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	in.StatusMetadata.DeepCopyInto(&out.StatusMetadata)
 }
 
@@ -4252,6 +4574,11 @@ func (in *VMAlert) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMAlertDatasourceSpec) DeepCopyInto(out *VMAlertDatasourceSpec) {
 	*out = *in
+	if in.TargetRef != nil {
+		in, out := &in.TargetRef, &out.TargetRef
+		*out = new(RemoteTargetRef)
+		**out = **in
+	}
 	in.HTTPAuth.DeepCopyInto(&out.HTTPAuth)
 }
 
@@ -4321,6 +4648,11 @@ func (in *VMAlertNotifierSpec) DeepCopy() *VMAlertNotifierSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMAlertRemoteReadSpec) DeepCopyInto(out *VMAlertRemoteReadSpec) {
 	*out = *in
+	if in.TargetRef != nil {
+		in, out := &in.TargetRef, &out.TargetRef
+		*out = new(RemoteTargetRef)
+		**out = **in
+	}
 	if in.Lookback != nil {
 		in, out := &in.Lookback, &out.Lookback
 		*out = new(string)
@@ -4342,6 +4674,11 @@ func (in *VMAlertRemoteReadSpec) DeepCopy() *VMAlertRemoteReadSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMAlertRemoteWriteSpec) DeepCopyInto(out *VMAlertRemoteWriteSpec) {
 	*out = *in
+	if in.TargetRef != nil {
+		in, out := &in.TargetRef, &out.TargetRef
+		*out = new(RemoteTargetRef)
+		**out = **in
+	}
 	if in.Concurrency != nil {
 		in, out := &in.Concurrency, &out.Concurrency
 		*out = new(int32)
@@ -4376,20 +4713,81 @@ func (in *VMAlertRemoteWriteSpec) DeepCopy() *VMAlertRemoteWriteSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VMAlertSpec) DeepCopyInto(out *VMAlertSpec) {
+func (in *VMAlertRuleDependencies) DeepCopyInto(out *VMAlertRuleDependencies) {
 	*out = *in
-	if in.PodMetadata != nil {
-		in, out := &in.PodMetadata, &out.PodMetadata
-		*out = new(EmbeddedObjectMetadata)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ManagedMetadata != nil {
-		in, out := &in.ManagedMetadata, &out.ManagedMetadata
-		*out = new(ManagedObjectsMetadata)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMAlertRuleDependencies.
+func (in *VMAlertRuleDependencies) DeepCopy() *VMAlertRuleDependencies {
+	if in == nil {
+		return nil
 	}
-	if in.RuleSelector != nil {
-		in, out := &in.RuleSelector, &out.RuleSelector
+	out := new(VMAlertRuleDependencies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMAlertRulePolicy) DeepCopyInto(out *VMAlertRulePolicy) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraFilterLabels != nil {
+		in, out := &in.ExtraFilterLabels, &out.ExtraFilterLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMAlertRulePolicy.
+func (in *VMAlertRulePolicy) DeepCopy() *VMAlertRulePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(VMAlertRulePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMAlertSpec) DeepCopyInto(out *VMAlertSpec) {
+	*out = *in
+	if in.PodMetadata != nil {
+		in, out := &in.PodMetadata, &out.PodMetadata
+		*out = new(EmbeddedObjectMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagedMetadata != nil {
+		in, out := &in.ManagedMetadata, &out.ManagedMetadata
+		*out = new(ManagedObjectsMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RulePolicies != nil {
+		in, out := &in.RulePolicies, &out.RulePolicies
+		*out = make([]VMAlertRulePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RuleDependencies != nil {
+		in, out := &in.RuleDependencies, &out.RuleDependencies
+		*out = new(VMAlertRuleDependencies)
+		**out = **in
+	}
+	if in.RuleSelector != nil {
+		in, out := &in.RuleSelector, &out.RuleSelector
 		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
@@ -4425,6 +4823,11 @@ func (in *VMAlertSpec) DeepCopyInto(out *VMAlertSpec) {
 		*out = new(VMAlertRemoteReadSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.StatePersistence != nil {
+		in, out := &in.StatePersistence, &out.StatePersistence
+		*out = new(VMAlertStatePersistence)
+		**out = **in
+	}
 	if in.RulePath != nil {
 		in, out := &in.RulePath, &out.RulePath
 		*out = make([]string, len(*in))
@@ -4473,6 +4876,11 @@ func (in *VMAlertSpec) DeepCopyInto(out *VMAlertSpec) {
 		*out = new(License)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	in.CommonDefaultableParams.DeepCopyInto(&out.CommonDefaultableParams)
 	in.CommonConfigReloaderParams.DeepCopyInto(&out.CommonConfigReloaderParams)
 	in.CommonApplicationDeploymentParams.DeepCopyInto(&out.CommonApplicationDeploymentParams)
@@ -4488,10 +4896,45 @@ func (in *VMAlertSpec) DeepCopy() *VMAlertSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMAlertStatePersistence) DeepCopyInto(out *VMAlertStatePersistence) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMAlertStatePersistence.
+func (in *VMAlertStatePersistence) DeepCopy() *VMAlertStatePersistence {
+	if in == nil {
+		return nil
+	}
+	out := new(VMAlertStatePersistence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMAlertStatePersistenceStatus) DeepCopyInto(out *VMAlertStatePersistenceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMAlertStatePersistenceStatus.
+func (in *VMAlertStatePersistenceStatus) DeepCopy() *VMAlertStatePersistenceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMAlertStatePersistenceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMAlertStatus) DeepCopyInto(out *VMAlertStatus) {
 	*out = *in
 	in.StatusMetadata.DeepCopyInto(&out.StatusMetadata)
+	if in.StatePersistence != nil {
+		in, out := &in.StatePersistence, &out.StatePersistence
+		*out = new(VMAlertStatePersistenceStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMAlertStatus.
@@ -4624,6 +5067,13 @@ func (in *VMAlertmanagerConfigSpec) DeepCopyInto(out *VMAlertmanagerConfigSpec)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]NotificationTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMAlertmanagerConfigSpec.
@@ -4640,6 +5090,18 @@ func (in *VMAlertmanagerConfigSpec) DeepCopy() *VMAlertmanagerConfigSpec {
 func (in *VMAlertmanagerConfigStatus) DeepCopyInto(out *VMAlertmanagerConfigStatus) {
 	*out = *in
 	in.StatusMetadata.DeepCopyInto(&out.StatusMetadata)
+	if in.UnreachableRoutes != nil {
+		in, out := &in.UnreachableRoutes, &out.UnreachableRoutes
+		*out = make([]UnreachableRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReceiverTest != nil {
+		in, out := &in.ReceiverTest, &out.ReceiverTest
+		*out = new(ReceiverTestStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMAlertmanagerConfigStatus.
@@ -4684,6 +5146,111 @@ func (in *VMAlertmanagerList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMAlertmanagerSilence) DeepCopyInto(out *VMAlertmanagerSilence) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMAlertmanagerSilence.
+func (in *VMAlertmanagerSilence) DeepCopy() *VMAlertmanagerSilence {
+	if in == nil {
+		return nil
+	}
+	out := new(VMAlertmanagerSilence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VMAlertmanagerSilence) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMAlertmanagerSilenceList) DeepCopyInto(out *VMAlertmanagerSilenceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VMAlertmanagerSilence, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMAlertmanagerSilenceList.
+func (in *VMAlertmanagerSilenceList) DeepCopy() *VMAlertmanagerSilenceList {
+	if in == nil {
+		return nil
+	}
+	out := new(VMAlertmanagerSilenceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VMAlertmanagerSilenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMAlertmanagerSilenceSpec) DeepCopyInto(out *VMAlertmanagerSilenceSpec) {
+	*out = *in
+	if in.Matchers != nil {
+		in, out := &in.Matchers, &out.Matchers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartsAt != nil {
+		in, out := &in.StartsAt, &out.StartsAt
+		*out = (*in).DeepCopy()
+	}
+	in.EndsAt.DeepCopyInto(&out.EndsAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMAlertmanagerSilenceSpec.
+func (in *VMAlertmanagerSilenceSpec) DeepCopy() *VMAlertmanagerSilenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VMAlertmanagerSilenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMAlertmanagerSilenceStatus) DeepCopyInto(out *VMAlertmanagerSilenceStatus) {
+	*out = *in
+	in.StatusMetadata.DeepCopyInto(&out.StatusMetadata)
+	if in.Silences != nil {
+		in, out := &in.Silences, &out.Silences
+		*out = make([]AlertmanagerPodSilence, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMAlertmanagerSilenceStatus.
+func (in *VMAlertmanagerSilenceStatus) DeepCopy() *VMAlertmanagerSilenceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMAlertmanagerSilenceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMAlertmanagerSpec) DeepCopyInto(out *VMAlertmanagerSpec) {
 	*out = *in
@@ -4712,6 +5279,11 @@ func (in *VMAlertmanagerSpec) DeepCopyInto(out *VMAlertmanagerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PeerDiscovery != nil {
+		in, out := &in.PeerDiscovery, &out.PeerDiscovery
+		*out = new(AlertmanagerPeerDiscovery)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ServiceSpec != nil {
 		in, out := &in.ServiceSpec, &out.ServiceSpec
 		*out = new(AdditionalServiceSpec)
@@ -4747,6 +5319,11 @@ func (in *VMAlertmanagerSpec) DeepCopyInto(out *VMAlertmanagerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	if in.ClaimTemplates != nil {
 		in, out := &in.ClaimTemplates, &out.ClaimTemplates
 		*out = make([]v1.PersistentVolumeClaim, len(*in))
@@ -4783,6 +5360,13 @@ func (in *VMAlertmanagerSpec) DeepCopy() *VMAlertmanagerSpec {
 func (in *VMAlertmanagerStatus) DeepCopyInto(out *VMAlertmanagerStatus) {
 	*out = *in
 	in.StatusMetadata.DeepCopyInto(&out.StatusMetadata)
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = make([]AlertmanagerPeerStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMAlertmanagerStatus.
@@ -4984,6 +5568,11 @@ func (in *VMAuthSpec) DeepCopyInto(out *VMAuthSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.ExternalConfig.DeepCopyInto(&out.ExternalConfig)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	in.CommonDefaultableParams.DeepCopyInto(&out.CommonDefaultableParams)
 	in.CommonConfigReloaderParams.DeepCopyInto(&out.CommonConfigReloaderParams)
 	in.CommonApplicationDeploymentParams.DeepCopyInto(&out.CommonApplicationDeploymentParams)
@@ -5177,6 +5766,21 @@ func (in *VMCluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMClusterComponentStatus) DeepCopyInto(out *VMClusterComponentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMClusterComponentStatus.
+func (in *VMClusterComponentStatus) DeepCopy() *VMClusterComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMClusterComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMClusterList) DeepCopyInto(out *VMClusterList) {
 	*out = *in
@@ -5209,6 +5813,65 @@ func (in *VMClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMClusterScrapeDefaults) DeepCopyInto(out *VMClusterScrapeDefaults) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMClusterScrapeDefaults.
+func (in *VMClusterScrapeDefaults) DeepCopy() *VMClusterScrapeDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(VMClusterScrapeDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VMClusterScrapeDefaults) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMClusterScrapeDefaultsList) DeepCopyInto(out *VMClusterScrapeDefaultsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VMClusterScrapeDefaults, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMClusterScrapeDefaultsList.
+func (in *VMClusterScrapeDefaultsList) DeepCopy() *VMClusterScrapeDefaultsList {
+	if in == nil {
+		return nil
+	}
+	out := new(VMClusterScrapeDefaultsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VMClusterScrapeDefaultsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMClusterSpec) DeepCopyInto(out *VMClusterSpec) {
 	*out = *in
@@ -5242,6 +5905,11 @@ func (in *VMClusterSpec) DeepCopyInto(out *VMClusterSpec) {
 		*out = new(VMStorage)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	if in.UseStrictSecurity != nil {
 		in, out := &in.UseStrictSecurity, &out.UseStrictSecurity
 		*out = new(bool)
@@ -5269,6 +5937,26 @@ func (in *VMClusterSpec) DeepCopy() *VMClusterSpec {
 func (in *VMClusterStatus) DeepCopyInto(out *VMClusterStatus) {
 	*out = *in
 	in.StatusMetadata.DeepCopyInto(&out.StatusMetadata)
+	if in.VMSelect != nil {
+		in, out := &in.VMSelect, &out.VMSelect
+		*out = new(VMClusterComponentStatus)
+		**out = **in
+	}
+	if in.VMInsert != nil {
+		in, out := &in.VMInsert, &out.VMInsert
+		*out = new(VMClusterComponentStatus)
+		**out = **in
+	}
+	if in.VMStorage != nil {
+		in, out := &in.VMStorage, &out.VMStorage
+		*out = new(VMClusterComponentStatus)
+		**out = **in
+	}
+	if in.RequestsLoadBalancer != nil {
+		in, out := &in.RequestsLoadBalancer, &out.RequestsLoadBalancer
+		*out = new(VMClusterComponentStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMClusterStatus.
@@ -5717,6 +6405,109 @@ func (in *VMRestoreOnStartConfig) DeepCopy() *VMRestoreOnStartConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMRolloutPolicy) DeepCopyInto(out *VMRolloutPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMRolloutPolicy.
+func (in *VMRolloutPolicy) DeepCopy() *VMRolloutPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(VMRolloutPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VMRolloutPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMRolloutPolicyList) DeepCopyInto(out *VMRolloutPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VMRolloutPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMRolloutPolicyList.
+func (in *VMRolloutPolicyList) DeepCopy() *VMRolloutPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(VMRolloutPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VMRolloutPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMRolloutPolicySpec) DeepCopyInto(out *VMRolloutPolicySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMRolloutPolicySpec.
+func (in *VMRolloutPolicySpec) DeepCopy() *VMRolloutPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VMRolloutPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMRolloutPolicyStatus) DeepCopyInto(out *VMRolloutPolicyStatus) {
+	*out = *in
+	if in.Updating != nil {
+		in, out := &in.Updating, &out.Updating
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Failed != nil {
+		in, out := &in.Failed, &out.Failed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMRolloutPolicyStatus.
+func (in *VMRolloutPolicyStatus) DeepCopy() *VMRolloutPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMRolloutPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMRule) DeepCopyInto(out *VMRule) {
 	*out = *in
@@ -5968,6 +6759,88 @@ func (in *VMScrapeConfigSpec) DeepCopy() *VMScrapeConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMScrapeDefaults) DeepCopyInto(out *VMScrapeDefaults) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMScrapeDefaults.
+func (in *VMScrapeDefaults) DeepCopy() *VMScrapeDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(VMScrapeDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VMScrapeDefaults) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMScrapeDefaultsList) DeepCopyInto(out *VMScrapeDefaultsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VMScrapeDefaults, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMScrapeDefaultsList.
+func (in *VMScrapeDefaultsList) DeepCopy() *VMScrapeDefaultsList {
+	if in == nil {
+		return nil
+	}
+	out := new(VMScrapeDefaultsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VMScrapeDefaultsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMScrapeDefaultsSpec) DeepCopyInto(out *VMScrapeDefaultsSpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.EndpointRelabelings.DeepCopyInto(&out.EndpointRelabelings)
+	in.EndpointAuth.DeepCopyInto(&out.EndpointAuth)
+	in.EndpointScrapeParams.DeepCopyInto(&out.EndpointScrapeParams)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMScrapeDefaultsSpec.
+func (in *VMScrapeDefaultsSpec) DeepCopy() *VMScrapeDefaultsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VMScrapeDefaultsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMScrapeParams) DeepCopyInto(out *VMScrapeParams) {
 	*out = *in
@@ -6299,6 +7172,11 @@ func (in *VMSingleSpec) DeepCopyInto(out *VMSingleSpec) {
 		*out = new(StreamAggrConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	in.CommonDefaultableParams.DeepCopyInto(&out.CommonDefaultableParams)
 	in.CommonApplicationDeploymentParams.DeepCopyInto(&out.CommonApplicationDeploymentParams)
 }
@@ -35,6 +35,38 @@ type syncEvent struct {
 }
 
 func newKubernetesWatcher(ctx context.Context, secretName, namespace string) (*k8sWatcher, error) {
+	c, err := newKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+	listOpts := &client.ListOptions{
+		Namespace:     namespace,
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", secretName),
+	}
+	inf := newSecretsInformer(ctx, c, listOpts)
+
+	syncChan := make(chan syncEvent, 10)
+	if _, err := inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			s := obj.(*corev1.Secret)
+			syncChan <- syncEvent{op: "create", obj: s}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			s := newObj.(*corev1.Secret)
+			syncChan <- syncEvent{op: "update", obj: s}
+		},
+		DeleteFunc: func(obj interface{}) {
+			s := obj.(*corev1.Secret)
+			syncChan <- syncEvent{op: "delete", obj: s}
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("cannot build eventHandler: %w", err)
+	}
+
+	return &k8sWatcher{inf: inf, c: c, events: syncChan, namespace: namespace, secretName: secretName}, nil
+}
+
+func newKubernetesClient() (client.WithWatch, error) {
 	lr := clientcmd.NewDefaultClientConfigLoadingRules()
 
 	cfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(lr, &clientcmd.ConfigOverrides{})
@@ -46,11 +78,11 @@ func newKubernetesWatcher(ctx context.Context, secretName, namespace string) (*k
 	if err != nil {
 		return nil, fmt.Errorf("cannot start watch for secret: %w", err)
 	}
-	listOpts := &client.ListOptions{
-		Namespace:     namespace,
-		FieldSelector: fields.OneTermEqualSelector("metadata.name", secretName),
-	}
-	inf := cache.NewSharedIndexInformer(&cache.ListWatch{
+	return c, nil
+}
+
+func newSecretsInformer(ctx context.Context, c client.WithWatch, listOpts *client.ListOptions) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(&cache.ListWatch{
 		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
 			var s corev1.SecretList
 			if err := c.List(ctx, &s, listOpts); err != nil {
@@ -68,26 +100,6 @@ func newKubernetesWatcher(ctx context.Context, secretName, namespace string) (*k
 			return wi, err
 		},
 	}, &corev1.Secret{}, 0, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
-
-	syncChan := make(chan syncEvent, 10)
-	if _, err := inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: func(obj interface{}) {
-			s := obj.(*corev1.Secret)
-			syncChan <- syncEvent{op: "create", obj: s}
-		},
-		UpdateFunc: func(oldObj, newObj interface{}) {
-			s := newObj.(*corev1.Secret)
-			syncChan <- syncEvent{op: "update", obj: s}
-		},
-		DeleteFunc: func(obj interface{}) {
-			s := obj.(*corev1.Secret)
-			syncChan <- syncEvent{op: "delete", obj: s}
-		},
-	}); err != nil {
-		return nil, fmt.Errorf("cannot build eventHandler: %w", err)
-	}
-
-	return &k8sWatcher{inf: inf, c: c, events: syncChan, namespace: namespace, secretName: secretName}, nil
 }
 
 var errNotModified = fmt.Errorf("file content not modified")
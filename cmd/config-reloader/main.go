@@ -91,14 +91,23 @@ func main() {
 	if err != nil {
 		logger.Fatalf("cannot create configWatcher: %s", err)
 	}
+	partsWatcher, err := newConfigPartsWatcher(ctx)
+	if err != nil {
+		logger.Fatalf("cannot create config parts watcher: %s", err)
+	}
 
-	err = configWatcher.startWatch(ctx, updatesChan)
+	// parts must be written before the main config, which includes them
+	err = partsWatcher.startWatch(ctx, updatesChan)
+	if err == nil {
+		err = configWatcher.startWatch(ctx, updatesChan)
+	}
 	if *onlyInitConfig {
 		if err != nil {
 			logger.Fatalf("failed to init config: %v", err)
 		}
 		logger.Infof("config initiation succeed, exit now")
 		cancel()
+		partsWatcher.close()
 		configWatcher.close()
 		return
 	}
@@ -125,6 +134,7 @@ func main() {
 	cancel()
 	watcher.close()
 	configWatcher.close()
+	partsWatcher.close()
 	dw.close()
 	logger.Infof("config-reloader stopped")
 }
@@ -290,17 +300,14 @@ func newConfigWatcher(ctx context.Context) (watcher, error) {
 		w = fw
 	}
 	if *configSecretName != "" {
-		secretNamespaced := *configSecretName
 		if *configSecretKey == "" {
 			return nil, fmt.Errorf("config-secret-key cannot be empty")
 		}
-		logger.Infof("starting kubernetes watcher with secret: %s", secretNamespaced)
-		idx := strings.IndexByte(secretNamespaced, '/')
-		if idx <= 0 {
-			return nil, fmt.Errorf("bad configSecretName: %s, it must be in form namespace/secret-name", secretNamespaced)
+		logger.Infof("starting kubernetes watcher with secret: %s", *configSecretName)
+		namespace, secretName, err := parseConfigSecretName()
+		if err != nil {
+			return nil, err
 		}
-		namespace := secretNamespaced[:idx]
-		secretName := secretNamespaced[idx+1:]
 		logger.Infof("starting watch for secret: %s at namespace: %s", secretName, namespace)
 		kw, err := newKubernetesWatcher(ctx, secretName, namespace)
 		if err != nil {
@@ -312,6 +319,14 @@ func newConfigWatcher(ctx context.Context) (watcher, error) {
 	return w, nil
 }
 
+func parseConfigSecretName() (string, string, error) {
+	idx := strings.IndexByte(*configSecretName, '/')
+	if idx <= 0 {
+		return "", "", fmt.Errorf("bad configSecretName: %s, it must be in form namespace/secret-name", *configSecretName)
+	}
+	return (*configSecretName)[:idx], (*configSecretName)[idx+1:], nil
+}
+
 var firstGzipBytes = []byte{0x1f, 0x8b, 0x08}
 
 func writeNewContent(data []byte) error {
@@ -319,23 +334,36 @@ func writeNewContent(data []byte) error {
 	if *configFileDst == "" {
 		return nil
 	}
-	if len(data) > 3 && bytes.Equal(data[0:3], firstGzipBytes) {
-		// its gzipped data
-		gz, err := gzip.NewReader(bytes.NewReader(data))
-		if err != nil {
-			return fmt.Errorf("cannot create gzip reader: %w", err)
-		}
-		defer gz.Close()
-		data, err = io.ReadAll(gz)
-		if err != nil {
-			return fmt.Errorf("cannot ungzip data: %w", err)
-		}
+	data, err := ungzipIfNeeded(data)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(*configFileDst, data)
+}
+
+func ungzipIfNeeded(data []byte) ([]byte, error) {
+	if len(data) <= 3 || !bytes.Equal(data[0:3], firstGzipBytes) {
+		return data, nil
 	}
-	tmpDst := *configFileDst + ".tmp"
+	// its gzipped data
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create gzip reader: %w", err)
+	}
+	defer gz.Close()
+	data, err = io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("cannot ungzip data: %w", err)
+	}
+	return data, nil
+}
+
+func writeFileAtomic(dst string, data []byte) error {
+	tmpDst := dst + ".tmp"
 	if err := os.WriteFile(tmpDst, data, 0644); err != nil {
-		return fmt.Errorf("cannot write file: %s to the disk: %w", *configFileDst, err)
+		return fmt.Errorf("cannot write file: %s to the disk: %w", dst, err)
 	}
-	if err := os.Rename(tmpDst, *configFileDst); err != nil {
+	if err := os.Rename(tmpDst, dst); err != nil {
 		return fmt.Errorf("cannot rename tmp file: %w", err)
 	}
 	return nil
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// configPartOfLabel must be in sync with operator ConfigSecretPartOfLabel
+	configPartOfLabel = "operator.victoriametrics.com/config-secret-part-of"
+	// configPartsDir is a sub-directory of config-envsubst-file directory, where config parts are written
+	configPartsDir = "scrape_configs"
+)
+
+// partsWatcher assembles configuration parts stored at secrets labeled with the name of config-secret-name
+//
+// each part is written into a separate file at configPartsDir,
+// the main configuration is expected to include them.
+type partsWatcher struct {
+	c        client.WithWatch
+	inf      cache.SharedIndexInformer
+	events   chan struct{}
+	listOpts *client.ListOptions
+	dir      string
+	wg       sync.WaitGroup
+}
+
+func newConfigPartsWatcher(ctx context.Context) (watcher, error) {
+	if *configSecretName == "" || *configFileDst == "" {
+		return &emptyWatcher{}, nil
+	}
+	namespace, secretName, err := parseConfigSecretName()
+	if err != nil {
+		return nil, err
+	}
+	c, err := newKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+	listOpts := &client.ListOptions{
+		Namespace:     namespace,
+		LabelSelector: labels.SelectorFromSet(labels.Set{configPartOfLabel: secretName}),
+	}
+	inf := newSecretsInformer(ctx, c, listOpts)
+	events := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case events <- struct{}{}:
+		default:
+		}
+	}
+	if _, err := inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(_ interface{}) { notify() },
+		UpdateFunc: func(_, _ interface{}) { notify() },
+		DeleteFunc: func(_ interface{}) { notify() },
+	}); err != nil {
+		return nil, fmt.Errorf("cannot build eventHandler: %w", err)
+	}
+	return &partsWatcher{
+		c:        c,
+		inf:      inf,
+		events:   events,
+		listOpts: listOpts,
+		dir:      filepath.Join(filepath.Dir(*configFileDst), configPartsDir),
+	}, nil
+}
+
+func (pw *partsWatcher) startWatch(ctx context.Context, updates chan struct{}) error {
+	initParts := func() error {
+		var l corev1.SecretList
+		if err := pw.c.List(ctx, &l, pw.listOpts); err != nil {
+			return fmt.Errorf("cannot list config parts: %w", err)
+		}
+		secrets := make([]*corev1.Secret, 0, len(l.Items))
+		for i := range l.Items {
+			secrets = append(secrets, &l.Items[i])
+		}
+		_, err := writeConfigParts(pw.dir, secrets)
+		return err
+	}
+	if err := initParts(); err != nil {
+		if *onlyInitConfig {
+			return err
+		}
+		contentUpdateErrosTotal.Inc()
+		logger.Errorf("cannot write config parts: %s", err)
+	}
+	if *onlyInitConfig {
+		return nil
+	}
+	go pw.inf.Run(ctx.Done())
+
+	pw.wg.Add(1)
+	go func() {
+		defer pw.wg.Done()
+		for {
+			select {
+			case <-pw.events:
+				var secrets []*corev1.Secret
+				for _, obj := range pw.inf.GetStore().List() {
+					secrets = append(secrets, obj.(*corev1.Secret))
+				}
+				updated, err := writeConfigParts(pw.dir, secrets)
+				if err != nil {
+					contentUpdateErrosTotal.Inc()
+					logger.Errorf("cannot sync config parts: %s", err)
+					continue
+				}
+				if !updated {
+					continue
+				}
+				select {
+				case updates <- struct{}{}:
+				default:
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (pw *partsWatcher) close() {
+	pw.wg.Wait()
+}
+
+// writeConfigParts writes content of the given secrets into dir and removes files of missing secrets
+//
+// it returns true if any file at dir was changed
+func writeConfigParts(dir string, secrets []*corev1.Secret) (bool, error) {
+	if len(secrets) == 0 {
+		// fast path, config wasn't split
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			return false, nil
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, fmt.Errorf("cannot create dir for config parts: %w", err)
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].Name < secrets[j].Name
+	})
+	var updated bool
+	wantFiles := make(map[string]struct{}, len(secrets))
+	for _, s := range secrets {
+		data, ok := s.Data[*configSecretKey]
+		if !ok {
+			return updated, fmt.Errorf("key=%q with content not found at secret=%q", *configSecretKey, s.Name)
+		}
+		data, err := ungzipIfNeeded(data)
+		if err != nil {
+			return updated, fmt.Errorf("cannot read content of secret=%q: %w", s.Name, err)
+		}
+		fileName := s.Name + ".yaml"
+		wantFiles[fileName] = struct{}{}
+		dst := filepath.Join(dir, fileName)
+		prevData, err := os.ReadFile(dst)
+		if err == nil && bytes.Equal(prevData, data) {
+			continue
+		}
+		logger.Infof("updating config part file: %s", dst)
+		if err := writeFileAtomic(dst, data); err != nil {
+			return updated, err
+		}
+		updated = true
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return updated, fmt.Errorf("cannot read config parts dir: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".yaml") {
+			continue
+		}
+		if _, ok := wantFiles[e.Name()]; ok {
+			continue
+		}
+		logger.Infof("removing stale config part file: %s", e.Name())
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			return updated, fmt.Errorf("cannot remove stale config part: %w", err)
+		}
+		updated = true
+	}
+	return updated, nil
+}
@@ -18,6 +18,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -38,6 +39,14 @@ func main() {
 		cancel()
 	}()
 
+	if len(os.Args) > 1 && os.Args[1] == manager.AlertmanagerRouteTestCmd {
+		if err := manager.RunAlertmanagerRouteTest(ctx, os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	err := manager.RunManager(ctx)
 	if err != nil {
 		setupLog.Error(err, "cannot setup manager")
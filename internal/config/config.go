@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"text/tabwriter"
 	"time"
 
@@ -16,7 +17,10 @@ import (
 )
 
 var (
-	opConf   *BaseOperatorConf
+	opConf atomic.Pointer[BaseOperatorConf]
+	// envConf holds configuration loaded from env variables
+	// it's used as base for configuration loaded from ConfigMap
+	envConf  *BaseOperatorConf
 	initConf sync.Once
 
 	opNamespace   []string
@@ -501,6 +505,8 @@ func (boc BaseOperatorConf) PrintDefaults(format string) error {
 }
 
 // MustGetBaseConfig returns operator configuration with default values populated from env variables
+//
+// returned value must not be modified, it could be replaced with SetBaseConfig at any time
 func MustGetBaseConfig() *BaseOperatorConf {
 	initConf.Do(func() {
 		c := &BaseOperatorConf{}
@@ -515,9 +521,10 @@ func MustGetBaseConfig() *BaseOperatorConf {
 		if err := parseAndSetCustomerConfigReloadImageVersion(c); err != nil {
 			panic(err)
 		}
-		opConf = c
+		envConf = c
+		opConf.Store(c)
 	})
-	return opConf
+	return opConf.Load()
 }
 
 var validNamespaceRegex = regexp.MustCompile(`[a-z0-9]([-a-z0-9]*[a-z0-9])?`)
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// LoadFromData returns operator configuration with values from env variables
+// overridden by the given data.
+// Data keys must match env variable names printed by -printDefaults, e.g. VM_VMAGENTDEFAULT_VERSION
+func LoadFromData(data map[string]string) (*BaseOperatorConf, error) {
+	MustGetBaseConfig()
+	c := *envConf
+	fields, err := configFields(&c)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		f, ok := fields[k]
+		if !ok {
+			return nil, fmt.Errorf("unknown config key=%q", k)
+		}
+		if err := setFieldValue(f, strings.TrimSpace(data[k])); err != nil {
+			return nil, fmt.Errorf("cannot parse value of config key=%q: %w", k, err)
+		}
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if err := parseAndSetCustomerConfigReloadImageVersion(&c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// SetBaseConfig replaces operator configuration returned by MustGetBaseConfig
+func SetBaseConfig(c *BaseOperatorConf) {
+	MustGetBaseConfig()
+	opConf.Store(c)
+}
+
+// ChangedKeys returns sorted list of config keys with different values at prev and next configurations
+func ChangedKeys(prev, next *BaseOperatorConf) ([]string, error) {
+	prevFields, err := configFields(prev)
+	if err != nil {
+		return nil, err
+	}
+	nextFields, err := configFields(next)
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	for k, pf := range prevFields {
+		if !reflect.DeepEqual(pf.Interface(), nextFields[k].Interface()) {
+			changed = append(changed, k)
+		}
+	}
+	slices.Sort(changed)
+	return changed, nil
+}
+
+// configFields returns struct fields of the given config by env variable names
+func configFields(c *BaseOperatorConf) (map[string]reflect.Value, error) {
+	fields := make(map[string]reflect.Value)
+	tmpl, err := template.New("fields").Funcs(template.FuncMap{
+		"collect": func(key string, f reflect.Value) string {
+			fields[key] = f
+			return ""
+		},
+	}).Parse(`{{range .}}{{collect .Key .Field}}{{end}}`)
+	if err != nil {
+		return nil, fmt.Errorf("BUG: cannot parse config fields template: %w", err)
+	}
+	if err := envconfig.Usaget(prefixVar, c, io.Discard, tmpl); err != nil {
+		return nil, fmt.Errorf("cannot collect config fields: %w", err)
+	}
+	return fields, nil
+}
+
+// setFieldValue parses value in the same way as envconfig does
+func setFieldValue(f reflect.Value, value string) error {
+	switch {
+	case f.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(d))
+	case f.Kind() == reflect.String:
+		f.SetString(value)
+	case f.Kind() == reflect.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		f.SetBool(v)
+	case f.Kind() == reflect.Slice && f.Type().Elem().Kind() == reflect.String:
+		var items []string
+		if value != "" {
+			items = strings.Split(value, ",")
+		}
+		f.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type=%s", f.Type())
+	}
+	return nil
+}
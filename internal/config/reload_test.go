@@ -0,0 +1,63 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFromData(t *testing.T) {
+	f := func(data map[string]string, wantErr bool, check func(c *BaseOperatorConf)) {
+		t.Helper()
+		c, err := LoadFromData(data)
+		if wantErr {
+			assert.Error(t, err)
+			return
+		}
+		assert.NoError(t, err)
+		check(c)
+	}
+	// values from env
+	f(nil, false, func(c *BaseOperatorConf) {
+		assert.Equal(t, MustGetBaseConfig().VMAgentDefault.Version, c.VMAgentDefault.Version)
+		assert.NotNil(t, c.CustomConfigReloaderImageVersion())
+	})
+	f(map[string]string{
+		"VM_VMAGENTDEFAULT_VERSION":       "v1.110.0",
+		"VM_FORCERESYNCINTERVAL":          " 5m",
+		"VM_FILTERCHILDLABELPREFIXES":     "app.kubernetes.io/,team",
+		"VM_ENABLESTRICTSECURITY":         "true",
+		"VM_CONFIG_RELOADER_LIMIT_MEMORY": "128Mi",
+	}, false, func(c *BaseOperatorConf) {
+		assert.Equal(t, "v1.110.0", c.VMAgentDefault.Version)
+		assert.Equal(t, 5*time.Minute, c.ForceResyncInterval)
+		assert.Equal(t, []string{"app.kubernetes.io/", "team"}, c.FilterChildLabelPrefixes)
+		assert.True(t, c.EnableStrictSecurity)
+		assert.Equal(t, "128Mi", c.ConfigReloaderLimitMemory)
+		// operator config isn't modified
+		assert.NotEqual(t, "v1.110.0", MustGetBaseConfig().VMAgentDefault.Version)
+	})
+	f(map[string]string{"VM_UNKNOWN_KEY": "value"}, true, nil)
+	f(map[string]string{"VM_FORCERESYNCINTERVAL": "1 minute"}, true, nil)
+	f(map[string]string{"VM_ENABLESTRICTSECURITY": "yes"}, true, nil)
+	// validation error
+	f(map[string]string{"VM_VMAGENTDEFAULT_RESOURCE_LIMIT_MEM": "1 gigabyte"}, true, nil)
+	f(map[string]string{"VM_CUSTOMCONFIGRELOADERIMAGE": "config-reloader"}, true, nil)
+}
+
+func TestChangedKeys(t *testing.T) {
+	prev := MustGetBaseConfig()
+	next, err := LoadFromData(map[string]string{
+		"VM_VMSINGLEDEFAULT_VERSION":  "v1.110.0",
+		"VM_FILTERCHILDLABELPREFIXES": "team",
+	})
+	assert.NoError(t, err)
+	changed, err := ChangedKeys(prev, next)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"VM_FILTERCHILDLABELPREFIXES", "VM_VMSINGLEDEFAULT_VERSION"}, changed)
+
+	changed, err = ChangedKeys(prev, prev)
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+}
@@ -6,33 +6,52 @@ import (
 	"flag"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	"github.com/VictoriaMetrics/operator/internal/config"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/limiter"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
 	operatorreconcile "github.com/VictoriaMetrics/operator/internal/controller/operator/factory/reconcile"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/rollout"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/tracing"
 )
 
 // BindFlags binds package flags to the given flagSet
 func BindFlags(f *flag.FlagSet) {
 	cacheSyncTimeout = f.Duration("controller.cacheSyncTimeout", *cacheSyncTimeout, "controls timeout for caches to be synced.")
 	maxConcurrency = f.Int("controller.maxConcurrentReconciles", *maxConcurrency, "Configures number of concurrent reconciles. It should improve performance for clusters with many objects.")
+	f.Func("controller.rateLimits", "Configures token bucket rate limits for reconciles triggered by child objects. "+
+		"Format: controller:rate=2.5,burst=5,objectRate=0.5,objectBurst=2;controller2:rate=1. Supported controllers: vmagent,vmalert,vmalertmanager,vmauth. "+
+		"rate and burst are shared by all objects of controller, objectRate and objectBurst are applied to a single object", limiter.SetConfigs)
 }
 
 var (
@@ -243,16 +262,23 @@ type objectWithStatusTrack interface {
 	LastAppliedSpecAsPatch() (client.Patch, error)
 	SetUpdateStatusTo(ctx context.Context, r client.Client, status vmv1beta1.UpdateStatus, maybeReason error) error
 	Paused() bool
+	GetStatusMetadata() *vmv1beta1.StatusMetadata
+	IsDriftReportOnly() bool
 }
 
 func createGenericEventForObject(ctx context.Context, c client.Client, object client.Object, message string) error {
+	return createEventForObject(ctx, c, object, corev1.EventTypeNormal, "ReconcileEvent", message)
+}
+
+// createEventForObject creates event of the given type and reason for the object
+func createEventForObject(ctx context.Context, c client.Client, object client.Object, eventType, reason, message string) error {
 	ev := &corev1.Event{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "victoria-metrics-operator-" + uuid.New().String(),
 			Namespace: object.GetNamespace(),
 		},
-		Type:    corev1.EventTypeNormal,
-		Reason:  "ReconcileEvent",
+		Type:    eventType,
+		Reason:  reason,
 		Message: message,
 		Source: corev1.EventSource{
 			Component: "victoria-metrics-operator",
@@ -267,7 +293,7 @@ func createGenericEventForObject(ctx context.Context, c client.Client, object cl
 		},
 	}
 	if err := c.Create(ctx, ev); err != nil {
-		return fmt.Errorf("cannot create generic event at k8s api for object: %q: %w", object.GetObjectKind().GroupVersionKind().GroupKind(), err)
+		return fmt.Errorf("cannot create %s event at k8s api for object: %q: %w", reason, object.GetObjectKind().GroupVersionKind().GroupKind(), err)
 	}
 	return nil
 }
@@ -275,7 +301,24 @@ func createGenericEventForObject(ctx context.Context, c client.Client, object cl
 // TODO :@f41gh7 replace object with generic type
 // it allows to use DeepClone method to prevent hidden object updates
 // made by controller-runtime client
-func reconcileAndTrackStatus(ctx context.Context, c client.Client, object objectWithStatusTrack, cb func() (ctrl.Result, error)) (result ctrl.Result, resultErr error) {
+//
+// cb receives context with DriftRecorder, child objects modified outside of operator
+// are reported with events and DriftCorrected status condition
+func reconcileAndTrackStatus(ctx context.Context, c client.Client, object objectWithStatusTrack, cb func(ctx context.Context) (ctrl.Result, error)) (result ctrl.Result, resultErr error) {
+	controllerName := "unknown"
+	if gvk, err := apiutil.GVKForObject(object, c.Scheme()); err == nil {
+		controllerName = strings.ToLower(gvk.Kind)
+	}
+	ctx, span := tracing.Start(ctx, "reconcile "+controllerName,
+		attribute.String("k8s.namespace.name", object.GetNamespace()),
+		attribute.String("k8s.object.name", object.GetName()),
+		attribute.Int64("k8s.object.generation", object.GetGeneration()))
+	defer func() {
+		tracing.End(span, resultErr)
+	}()
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		ctx = logger.AddToContext(ctx, logger.WithContext(ctx).WithValues("trace_id", traceID))
+	}
 	if object.Paused() {
 		if err := object.SetUpdateStatusTo(ctx, c, vmv1beta1.UpdateStatusPaused, nil); err != nil {
 			resultErr = fmt.Errorf("failed to update object status: %w", err)
@@ -288,6 +331,17 @@ func reconcileAndTrackStatus(ctx context.Context, c client.Client, object object
 		resultErr = fmt.Errorf("cannot parse exist spec changes")
 		return
 	}
+	maintenance, err := newMaintenanceGate(object)
+	if err != nil {
+		resultErr = &parsingError{err.Error(), controllerName}
+		return
+	}
+	// spec changes of already observed generation are made by operator defaults
+	// such changes are applied in batches if rollout policy is configured
+	if specChanged && object.GetStatusMetadata().ObservedGeneration == object.GetGeneration() && rollout.MustWait(controllerName, object) {
+		logger.WithContext(ctx).Info("object update with new defaults is postponed by rollout policy")
+		return ctrl.Result{RequeueAfter: rollout.WaitInterval}, nil
+	}
 	var diffPatch client.Patch
 	if specChanged {
 		// TODO: @f41gh7 replace error prone patch
@@ -317,7 +371,10 @@ func reconcileAndTrackStatus(ctx context.Context, c client.Client, object object
 		logger.WithContext(ctx).Info("object has changes with previous state, applying changes")
 	}
 
-	result, err = cb()
+	drifts := operatorreconcile.NewDriftRecorder(controllerName, object.IsDriftReportOnly())
+	result, err = cb(operatorreconcile.WithMaintenanceGate(operatorreconcile.WithDriftRecorder(ctx, drifts), maintenance))
+	reportChildDrifts(ctx, c, object, drifts)
+	reportPendingMaintenance(ctx, c, object, maintenance)
 	if err != nil {
 		// do not change status on conflict to failed
 		// it should be retried on the next loop
@@ -328,7 +385,8 @@ func reconcileAndTrackStatus(ctx context.Context, c client.Client, object object
 		if operatorreconcile.IsErrorWaitTimeout(err) {
 			desiredStatus = vmv1beta1.UpdateStatusExpanding
 		}
-		if updateErr := object.SetUpdateStatusTo(ctx, c, desiredStatus, err); updateErr != nil {
+		statusErr := errors.New(tracing.WithTraceID(ctx, err.Error(), object.GetStatusMetadata().Reason))
+		if updateErr := object.SetUpdateStatusTo(ctx, c, desiredStatus, statusErr); updateErr != nil {
 			resultErr = fmt.Errorf("failed to update object status: %q, origin err: %w", updateErr, err)
 			return
 		}
@@ -345,6 +403,176 @@ func reconcileAndTrackStatus(ctx context.Context, c client.Client, object object
 		resultErr = fmt.Errorf("failed to update object status: %w", err)
 		return
 	}
+	rollout.Release(controllerName, object)
+	// retry deferred changes at the next maintenance window
+	if len(maintenance.PendingActions()) > 0 {
+		if untilOpen := time.Until(maintenance.NextOpen()) + time.Second; result.RequeueAfter == 0 || untilOpen < result.RequeueAfter {
+			result.RequeueAfter = untilOpen
+		}
+	}
 
 	return result, nil
 }
+
+// newMaintenanceGate returns gate for disruptive changes of the object
+// gate is always open for objects without maintenance windows
+func newMaintenanceGate(object client.Object) (*operatorreconcile.MaintenanceGate, error) {
+	var windows []vmv1beta1.MaintenanceWindow
+	if o, ok := object.(interface {
+		GetMaintenanceWindows() []vmv1beta1.MaintenanceWindow
+	}); ok {
+		windows = o.GetMaintenanceWindows()
+	}
+	force := object.GetAnnotations()[vmv1beta1.MaintenanceForceAnnotation] == "true"
+	return operatorreconcile.NewMaintenanceGate(windows, force, time.Now())
+}
+
+// reportPendingMaintenance updates PendingMaintenance status condition with disruptive changes deferred until maintenance window
+func reportPendingMaintenance(ctx context.Context, c client.Client, object objectWithStatusTrack, mg *operatorreconcile.MaintenanceGate) {
+	prevObject := object.DeepCopyObject().(objectWithStatusTrack)
+	mg.WriteCondition(object.GetStatusMetadata(), object.GetGeneration())
+	if equality.Semantic.DeepEqual(prevObject.GetStatusMetadata().Conditions, object.GetStatusMetadata().Conditions) {
+		return
+	}
+	// make a deep copy, patch reloads state of the object from API server
+	objectToUpdate := object.DeepCopyObject().(client.Object)
+	if err := c.Status().Patch(ctx, objectToUpdate, client.MergeFrom(prevObject)); err != nil {
+		logger.WithContext(ctx).Error(err, "cannot update PendingMaintenance status condition")
+		return
+	}
+	object.SetResourceVersion(objectToUpdate.GetResourceVersion())
+}
+
+// withResyncInterval sets periodic resync interval to the given reconcile result
+// shorter requeue interval requested by reconcile is preserved
+func withResyncInterval(result ctrl.Result) ctrl.Result {
+	resync := config.MustGetBaseConfig().ResyncAfterDuration()
+	if result.RequeueAfter == 0 || (resync > 0 && resync < result.RequeueAfter) {
+		result.RequeueAfter = resync
+	}
+	return result
+}
+
+// reportChildDrifts creates events for child objects modified outside of operator
+// and sets DriftCorrected status condition
+//
+// in report-only mode events are created only if set of drifts or their actors changed
+func reportChildDrifts(ctx context.Context, c client.Client, object objectWithStatusTrack, dr *operatorreconcile.DriftRecorder) {
+	prevObject := object.DeepCopyObject().(client.Object)
+	if !dr.WriteCondition(object.GetStatusMetadata(), object.GetGeneration()) {
+		return
+	}
+	action := "reverted"
+	if dr.ReportOnly() {
+		action = fmt.Sprintf("not reverted due to %s=%s annotation", vmv1beta1.DriftPolicyAnnotation, vmv1beta1.DriftPolicyReportOnly)
+	}
+	for _, cd := range dr.Drifts() {
+		if err := createEventForObject(ctx, c, object, corev1.EventTypeWarning, "ChildDrift", fmt.Sprintf("%s, changes are %s", cd, action)); err != nil {
+			logger.WithContext(ctx).Error(err, "cannot create child drift event")
+		}
+	}
+	// status is patched explicitly, since SetUpdateStatusTo skips requests without UpdateStatus changes
+	// make a deep copy, patch reloads state of the object from API server
+	objectToUpdate := object.DeepCopyObject().(client.Object)
+	if err := c.Status().Patch(ctx, objectToUpdate, client.MergeFrom(prevObject)); err != nil {
+		logger.WithContext(ctx).Error(err, "cannot update DriftCorrected status condition")
+		return
+	}
+	object.SetResourceVersion(objectToUpdate.GetResourceVersion())
+}
+
+// remoteTargetKinds defines kinds of objects, which could be referenced by RemoteTargetRef
+var remoteTargetKinds = []struct {
+	kind string
+	obj  client.Object
+}{
+	{"VMSingle", &vmv1beta1.VMSingle{}},
+	{"VMCluster", &vmv1beta1.VMCluster{}},
+	{"VMAuth", &vmv1beta1.VMAuth{}},
+	{"VMUser", &vmv1beta1.VMUser{}},
+}
+
+// isRemoteTargetRefMatch checks if given ref defined at namespace references the object of kind
+func isRemoteTargetRefMatch(ref *vmv1beta1.RemoteTargetRef, namespace, kind string, obj client.Object) bool {
+	if ref == nil {
+		return false
+	}
+	if kind == "VMUser" {
+		return ref.Kind == "VMAuth" && ref.User == obj.GetName() && namespace == obj.GetNamespace()
+	}
+	return ref.Kind == kind && ref.Name == obj.GetName() && ref.NamespaceOrDefault(namespace) == obj.GetNamespace()
+}
+
+// watchShardRebalance enqueues parent objects owned by the current shard on shard membership change
+// it's no-op if sharding is disabled
+func watchShardRebalance[T any, PT interface {
+	*T
+	client.ObjectList
+}](b *builder.Builder, rclient client.Client, controllerName string) *builder.Builder {
+	ch := sharding.Subscribe()
+	if ch == nil {
+		return b
+	}
+	return b.WatchesRawSource(source.Channel(ch, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, _ client.Object) []reconcile.Request {
+		var requests []reconcile.Request
+		if err := k8stools.ListObjectsByNamespace(ctx, rclient, config.MustGetWatchNamespaces(), func(dst PT) {
+			_ = meta.EachListItem(dst, func(o runtime.Object) error {
+				nsn := client.ObjectKeyFromObject(o.(client.Object))
+				if sharding.IsOwned(nsn) {
+					requests = append(requests, reconcile.Request{NamespacedName: nsn})
+				}
+				return nil
+			})
+		}); err != nil {
+			logger.WithContext(ctx).Error(err, fmt.Sprintf("cannot list %s objects for shard rebalance", controllerName))
+			return nil
+		}
+		sharding.SetOwnedObjects(controllerName, len(requests))
+		return requests
+	})))
+}
+
+// watchRemoteTargets adds watches for objects referenced by RemoteTargetRef
+// and enqueues objects returned by findRequests on its changes
+func watchRemoteTargets(b *builder.Builder, findRequests func(ctx context.Context, kind string, obj client.Object) []reconcile.Request) *builder.Builder {
+	for _, rtk := range remoteTargetKinds {
+		kind := rtk.kind
+		b = b.Watches(rtk.obj, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+			return findRequests(ctx, kind, obj)
+		}), builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+	}
+	return b
+}
+
+// watchNamespaceLabels enqueues objects returned by findRequests on namespace labels change
+// it's no-op if operator watches only a subset of namespaces, since namespace selectors are ignored in this case
+func watchNamespaceLabels(b *builder.Builder, findRequests func(ctx context.Context, oldLabels, newLabels labels.Set) []reconcile.Request) *builder.Builder {
+	if len(config.MustGetWatchNamespaces()) > 0 {
+		return b
+	}
+	return b.Watches(&corev1.Namespace{}, handler.Funcs{
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			oldLabels, newLabels := labels.Set(e.ObjectOld.GetLabels()), labels.Set(e.ObjectNew.GetLabels())
+			if labels.Equals(oldLabels, newLabels) {
+				return
+			}
+			for _, req := range findRequests(ctx, oldLabels, newLabels) {
+				q.Add(req)
+			}
+		},
+	})
+}
+
+// isNamespaceSelectorChanged checks if namespace labels change affects the result of namespaceSelector
+//
+// nil or empty selector doesn't depend on namespace labels
+func isNamespaceSelectorChanged(nsSelector *metav1.LabelSelector, oldLabels, newLabels labels.Set) bool {
+	if nsSelector == nil || (len(nsSelector.MatchLabels) == 0 && len(nsSelector.MatchExpressions) == 0) {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(nsSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(oldLabels) != selector.Matches(newLabels)
+}
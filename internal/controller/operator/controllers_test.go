@@ -3,14 +3,19 @@ package operator
 import (
 	"context"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+	operatorreconcile "github.com/VictoriaMetrics/operator/internal/controller/operator/factory/reconcile"
 )
 
 func TestIsSelectorsMatchesTargetCRD(t *testing.T) {
@@ -323,3 +328,115 @@ func TestIsSelectorsMatchesTargetCRD(t *testing.T) {
 		})
 	}
 }
+
+func TestReportChildDrifts(t *testing.T) {
+	ctx := context.Background()
+	ts := metav1.Now()
+	cr := &vmv1beta1.VMAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "example",
+			Namespace:   "default",
+			Annotations: map[string]string{vmv1beta1.DriftPolicyAnnotation: vmv1beta1.DriftPolicyReportOnly},
+		},
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vmagent-example",
+			Namespace: "default",
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &ts},
+			},
+		},
+		Data: map[string]string{"key": "edited"},
+	}
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{cr, cm})
+	dr := operatorreconcile.NewDriftRecorder("vmagent", cr.IsDriftReportOnly())
+	newCM := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "vmagent-example", Namespace: "default"},
+		Data:       map[string]string{"key": "rendered"},
+	}
+	if err := operatorreconcile.ConfigMap(operatorreconcile.WithDriftRecorder(ctx, dr), fclient, newCM, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	reportChildDrifts(ctx, fclient, cr, dr)
+
+	var events corev1.EventList
+	if err := fclient.List(ctx, &events, client.InNamespace("default")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(events.Items) != 1 || events.Items[0].Reason != "ChildDrift" || events.Items[0].Type != corev1.EventTypeWarning {
+		t.Fatalf("expected single ChildDrift event, got: %v", events.Items)
+	}
+	var got vmv1beta1.VMAgent
+	if err := fclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "example"}, &got); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got.Status.Conditions) != 1 {
+		t.Fatalf("expected single status condition, got: %v", got.Status.Conditions)
+	}
+	cond := got.Status.Conditions[0]
+	if cond.Type != vmv1beta1.ConditionDriftCorrected || cond.Status != metav1.ConditionFalse || cond.Reason != "DriftReportOnly" {
+		t.Fatalf("unexpected condition: %v", cond)
+	}
+
+	// the same drift is detected at the next reconcile, but it isn't reported again
+	dr = operatorreconcile.NewDriftRecorder("vmagent", cr.IsDriftReportOnly())
+	if err := operatorreconcile.ConfigMap(operatorreconcile.WithDriftRecorder(ctx, dr), fclient, newCM.DeepCopy(), nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(dr.Drifts()) != 1 {
+		t.Fatalf("expected drift to be detected, got: %v", dr.Drifts())
+	}
+	reportChildDrifts(ctx, fclient, cr, dr)
+	if err := fclient.List(ctx, &events, client.InNamespace("default")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(events.Items) != 1 {
+		t.Fatalf("expected no new events for already reported drift, got: %v", events.Items)
+	}
+}
+
+func TestWithResyncInterval(t *testing.T) {
+	f := func(requeueAfter time.Duration, wantResync bool) {
+		t.Helper()
+		got := withResyncInterval(ctrl.Result{RequeueAfter: requeueAfter})
+		if !wantResync {
+			if got.RequeueAfter != requeueAfter {
+				t.Fatalf("unexpected requeue interval, got: %s, want: %s", got.RequeueAfter, requeueAfter)
+			}
+			return
+		}
+		// resync interval has up to 10s jitter
+		if got.RequeueAfter < time.Minute || got.RequeueAfter > time.Minute+10*time.Second {
+			t.Fatalf("unexpected resync interval: %s", got.RequeueAfter)
+		}
+	}
+	f(0, true)
+	f(10*time.Second, false)
+	f(time.Hour, true)
+}
+
+func TestIsNamespaceSelectorChanged(t *testing.T) {
+	f := func(nsSelector *metav1.LabelSelector, oldLabels, newLabels labels.Set, want bool) {
+		t.Helper()
+		if got := isNamespaceSelectorChanged(nsSelector, oldLabels, newLabels); got != want {
+			t.Fatalf("unexpected result, got: %v, want: %v", got, want)
+		}
+	}
+	teamA := &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+
+	// selector doesn't depend on namespace labels
+	f(nil, labels.Set{}, labels.Set{"team": "a"}, false)
+	f(&metav1.LabelSelector{}, labels.Set{}, labels.Set{"team": "a"}, false)
+
+	// namespace starts or stops matching selector
+	f(teamA, labels.Set{}, labels.Set{"team": "a"}, true)
+	f(teamA, labels.Set{"team": "a"}, labels.Set{"team": "b"}, true)
+
+	// selector result isn't changed
+	f(teamA, labels.Set{"team": "a"}, labels.Set{"team": "a", "env": "prod"}, false)
+	f(teamA, labels.Set{"env": "dev"}, labels.Set{"env": "prod"}, false)
+
+	// invalid selector
+	f(&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "unknown"}}}, labels.Set{}, labels.Set{"team": "a"}, false)
+}
@@ -78,15 +78,16 @@ func CreateOrUpdateAlertManager(ctx context.Context, cr *vmv1beta1.VMAlertmanage
 			return err
 		}
 	}
+	peers := discoverPeers(ctx, rclient, cr)
 	var prevSts *appsv1.StatefulSet
 	if prevCR != nil {
 		var err error
-		prevSts, err = newStsForAlertManager(prevCR)
+		prevSts, err = newStsForAlertManager(prevCR, peers)
 		if err != nil {
 			return fmt.Errorf("cannot generate prev alertmanager sts, name: %s,err: %w", cr.Name, err)
 		}
 	}
-	newSts, err := newStsForAlertManager(cr)
+	newSts, err := newStsForAlertManager(cr, peers)
 	if err != nil {
 		return fmt.Errorf("cannot generate alertmanager sts, name: %s,err: %w", cr.Name, err)
 	}
@@ -95,7 +96,14 @@ func CreateOrUpdateAlertManager(ctx context.Context, cr *vmv1beta1.VMAlertmanage
 		HasClaim:       len(newSts.Spec.VolumeClaimTemplates) > 0,
 		SelectorLabels: cr.SelectorLabels,
 	}
-	return reconcile.HandleSTSUpdate(ctx, rclient, stsOpts, newSts, prevSts)
+	if err := reconcile.HandleSTSUpdate(ctx, rclient, stsOpts, newSts, prevSts); err != nil {
+		return err
+	}
+	var peersStatus []vmv1beta1.AlertmanagerPeerStatus
+	if len(peers) > 0 {
+		peersStatus = probePeers(ctx, peers, cr.Status.Peers)
+	}
+	return updatePeersStatus(ctx, rclient, cr, peersStatus)
 }
 
 func deletePrevStateResources(ctx context.Context, cr *vmv1beta1.VMAlertmanager, rclient client.Client) error {
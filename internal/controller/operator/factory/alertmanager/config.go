@@ -9,8 +9,11 @@ import (
 	"strings"
 
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/config"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/build"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	version "github.com/hashicorp/go-version"
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -87,9 +90,23 @@ OUTER:
 			result.brokenAMCfgs = append(result.brokenAMCfgs, amcKey)
 			continue
 		}
+		cts, err := loadConfigTemplates(ctx, rclient, amcKey, configmapCache)
+		if err != nil {
+			result.brokenAMCfgs = append(result.brokenAMCfgs, amcKey)
+			amcKey.Status.CurrentSyncError = err.Error()
+			continue
+		}
 		var receiverCfgs []yaml.MapSlice
 		for _, receiver := range amcKey.Spec.Receivers {
+			if err := checkReceiverSupported(alertmanagerCR, receiver); err != nil {
+				result.brokenAMCfgs = append(result.brokenAMCfgs, amcKey)
+				amcKey.Status.CurrentSyncError = err.Error()
+				continue OUTER
+			}
 			receiverCfg, err := buildReceiver(ctx, rclient, amcKey, receiver, &globalConfigOpts, secretCache, configmapCache, tlsAssets)
+			if err == nil {
+				receiverCfg, err = cts.applyToReceiver(receiverCfg)
+			}
 			if err != nil {
 				// skip broken configs
 				result.brokenAMCfgs = append(result.brokenAMCfgs, amcKey)
@@ -117,6 +134,11 @@ OUTER:
 		}
 
 		baseYAMlCfg.Receivers = append(baseYAMlCfg.Receivers, receiverCfgs...)
+		if len(cts.content) > 0 {
+			assetKey := cts.assetKey(amcKey)
+			tlsAssets[assetKey] = cts.content
+			baseYAMlCfg.Templates = append(baseYAMlCfg.Templates, path.Join(tlsAssetsDir, assetKey))
+		}
 		for _, rule := range amcKey.Spec.InhibitRules {
 			baseYAMlCfg.InhibitRules = append(baseYAMlCfg.InhibitRules, buildInhibitRule(amcKey.Namespace, rule, !alertmanagerCR.Spec.DisableNamespaceMatcher))
 		}
@@ -129,6 +151,7 @@ OUTER:
 	}
 	amcfgs = amcfgs[:cnt]
 
+	baseRoutesCount := len(baseYAMlCfg.Route.Routes)
 	if len(subRoutes) > 0 {
 		baseYAMlCfg.Route.Routes = append(baseYAMlCfg.Route.Routes, subRoutes...)
 	}
@@ -142,9 +165,27 @@ OUTER:
 	}
 	result.amcfgs = amcfgs
 	result.data = data
+	setUnreachableRoutes(ctx, alertmanagerCR, &result, baseRoutesCount)
 	return &result, nil
 }
 
+// setUnreachableRoutes evaluates routes of each VMAlertmanagerConfig against merged route tree
+// and reports routes, which cannot be reached, at status
+func setUnreachableRoutes(ctx context.Context, alertmanagerCR *vmv1beta1.VMAlertmanager, result *parsedConfig, baseRoutesCount int) {
+	amKey := alertmanagerCR.Namespace + "/" + alertmanagerCR.Name
+	for _, amc := range result.brokenAMCfgs {
+		amc.SetUnreachableRoutes(amKey, nil, nil)
+	}
+	tree, err := parseRoutingTree(result.data)
+	if err != nil {
+		logger.WithContext(ctx).Error(err, "cannot evaluate routes of generated config")
+		return
+	}
+	for idx, amc := range result.amcfgs {
+		amc.SetUnreachableRoutes(amKey, tree.findUnreachableRoutes(baseRoutesCount+idx, amKey), nil)
+	}
+}
+
 // addConfigTemplates adds external templates to the given based configuration
 func addConfigTemplates(baseCfg []byte, templates []string) ([]byte, error) {
 	if len(templates) == 0 {
@@ -361,6 +402,32 @@ type route struct {
 	ActiveTimeIntervals []string          `yaml:"active_time_intervals,omitempty" json:"active_time_intervals,omitempty"`
 }
 
+// incidentIOMinVersion is the first alertmanager version, which supports incidentio_configs
+var incidentIOMinVersion = version.Must(version.NewVersion("v0.29.0"))
+
+// checkReceiverSupported checks that receiver integrations are supported by alertmanager image of given VMAlertmanager
+//
+// older alertmanager versions fail to load configuration with unknown integrations,
+// so config with such receiver must be excluded from configuration
+func checkReceiverSupported(am *vmv1beta1.VMAlertmanager, receiver vmv1beta1.Receiver) error {
+	if len(receiver.IncidentIOConfigs) == 0 {
+		return nil
+	}
+	tag := am.Spec.Image.Tag
+	if tag == "" {
+		tag = config.MustGetBaseConfig().VMAlertManager.AlertManagerVersion
+	}
+	ver, err := version.NewVersion(tag)
+	if err != nil {
+		// version of custom tags, like latest, cannot be checked
+		return nil
+	}
+	if ver.LessThan(incidentIOMinVersion) {
+		return fmt.Errorf("receiver=%q: incidentio_configs requires alertmanager %s or newer, got image tag=%q", receiver.Name, incidentIOMinVersion.Original(), tag)
+	}
+	return nil
+}
+
 func buildReceiver(
 	ctx context.Context,
 	rclient client.Client,
@@ -536,6 +603,13 @@ func (cb *configBuilder) buildCfg(receiver vmv1beta1.Receiver) error {
 	}
 	cb.finalizeSection("msteamsv2_configs")
 
+	for _, iioCfg := range receiver.IncidentIOConfigs {
+		if err := cb.buildIncidentIO(iioCfg); err != nil {
+			return err
+		}
+	}
+	cb.finalizeSection("incidentio_configs")
+
 	return nil
 }
 
@@ -1087,6 +1161,53 @@ func (cb *configBuilder) buildTeamsV2(mstCfg vmv1beta1.MSTeamsV2Config) error {
 	return nil
 }
 
+func (cb *configBuilder) buildIncidentIO(iio vmv1beta1.IncidentIOConfig) error {
+	if iio.URL == nil && iio.URLSecret == nil {
+		return fmt.Errorf("one of required fields 'url' or 'url_secret' are not set")
+	}
+	var temp yaml.MapSlice
+	if iio.HTTPConfig != nil {
+		h, err := cb.buildHTTPConfig(iio.HTTPConfig)
+		if err != nil {
+			return err
+		}
+		temp = append(temp, yaml.MapItem{Key: "http_config", Value: h})
+	}
+	if iio.SendResolved != nil {
+		temp = append(temp, yaml.MapItem{Key: "send_resolved", Value: *iio.SendResolved})
+	}
+	var iioURL string
+	switch {
+	case iio.URLSecret != nil:
+		sv, err := cb.fetchSecretValue(iio.URLSecret)
+		if err != nil {
+			return err
+		}
+		iioURL = sv
+	case iio.URL != nil:
+		iioURL = *iio.URL
+	}
+	if err := parseURL(iioURL); err != nil {
+		return fmt.Errorf("failed to parse incidentio url: %w", err)
+	}
+	temp = append(temp, yaml.MapItem{Key: "url", Value: iioURL})
+	if iio.AlertSourceToken != nil {
+		token, err := cb.fetchSecretValue(iio.AlertSourceToken)
+		if err != nil {
+			return err
+		}
+		temp = append(temp, yaml.MapItem{Key: "alert_source_token", Value: token})
+	}
+	if iio.MaxAlerts != 0 {
+		temp = append(temp, yaml.MapItem{Key: "max_alerts", Value: iio.MaxAlerts})
+	}
+	if iio.Timeout != "" {
+		temp = append(temp, yaml.MapItem{Key: "timeout", Value: iio.Timeout})
+	}
+	cb.currentYaml = append(cb.currentYaml, temp)
+	return nil
+}
+
 func (cb *configBuilder) buildWebhook(wh vmv1beta1.WebhookConfig) error {
 	var temp yaml.MapSlice
 	if wh.HTTPConfig != nil {
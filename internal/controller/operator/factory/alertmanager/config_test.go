@@ -1260,6 +1260,156 @@ receivers:
     text: some other text
     title: team 2
 templates: []
+`,
+		},
+		{
+			name: "incidentio",
+			args: args{
+				amCR: &vmv1beta1.VMAlertmanager{
+					Spec: vmv1beta1.VMAlertmanagerSpec{
+						CommonDefaultableParams: vmv1beta1.CommonDefaultableParams{
+							Image: vmv1beta1.Image{Tag: "v0.29.0"},
+						},
+					},
+				},
+				amcfgs: []*vmv1beta1.VMAlertmanagerConfig{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "incidentio",
+							Namespace: "default",
+						},
+						Spec: vmv1beta1.VMAlertmanagerConfigSpec{
+							Route: &vmv1beta1.Route{
+								Receiver: "iio",
+							},
+							Receivers: []vmv1beta1.Receiver{
+								{
+									Name: "iio",
+									IncidentIOConfigs: []vmv1beta1.IncidentIOConfig{
+										{
+											URLSecret: &corev1.SecretKeySelector{
+												Key: "URL",
+												LocalObjectReference: corev1.LocalObjectReference{
+													Name: "incidentio-access",
+												},
+											},
+											AlertSourceToken: &corev1.SecretKeySelector{
+												Key: "TOKEN",
+												LocalObjectReference: corev1.LocalObjectReference{
+													Name: "incidentio-access",
+												},
+											},
+											SendResolved: ptr.To(true),
+											MaxAlerts:    10,
+											Timeout:      "30s",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			predefinedObjects: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "incidentio-access",
+						Namespace: "default",
+					},
+					Data: map[string][]byte{
+						"TOKEN": []byte(`token value`),
+						"URL":   []byte(`https://api.incident.io/v2/alert_events/http/source-id`),
+					},
+				},
+			},
+			want: `route:
+  receiver: blackhole
+  routes:
+  - matchers:
+    - namespace = "default"
+    receiver: default-incidentio-iio
+    continue: true
+receivers:
+- name: blackhole
+- name: default-incidentio-iio
+  incidentio_configs:
+  - send_resolved: true
+    url: https://api.incident.io/v2/alert_events/http/source-id
+    alert_source_token: token value
+    max_alerts: 10
+    timeout: 30s
+templates: []
+`,
+		},
+		{
+			name: "incidentio with unsupported alertmanager version",
+			args: args{
+				amCR: &vmv1beta1.VMAlertmanager{
+					Spec: vmv1beta1.VMAlertmanagerSpec{
+						CommonDefaultableParams: vmv1beta1.CommonDefaultableParams{
+							Image: vmv1beta1.Image{Tag: "v0.27.0"},
+						},
+					},
+				},
+				amcfgs: []*vmv1beta1.VMAlertmanagerConfig{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "incidentio",
+							Namespace: "default",
+						},
+						Spec: vmv1beta1.VMAlertmanagerConfigSpec{
+							Route: &vmv1beta1.Route{
+								Receiver: "iio",
+							},
+							Receivers: []vmv1beta1.Receiver{
+								{
+									Name: "iio",
+									IncidentIOConfigs: []vmv1beta1.IncidentIOConfig{
+										{
+											URL: ptr.To("https://api.incident.io/v2/alert_events/http/source-id"),
+										},
+									},
+								},
+							},
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "webhook",
+							Namespace: "default",
+						},
+						Spec: vmv1beta1.VMAlertmanagerConfigSpec{
+							Route: &vmv1beta1.Route{
+								Receiver: "wh",
+							},
+							Receivers: []vmv1beta1.Receiver{
+								{
+									Name: "wh",
+									WebhookConfigs: []vmv1beta1.WebhookConfig{
+										{
+											URL: ptr.To("http://example.com/webhook"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			parseError: `receiver="iio": incidentio_configs requires alertmanager v0.29.0 or newer, got image tag="v0.27.0"`,
+			want: `route:
+  receiver: blackhole
+  routes:
+  - matchers:
+    - namespace = "default"
+    receiver: default-webhook-wh
+    continue: true
+receivers:
+- name: blackhole
+- name: default-webhook-wh
+  webhook_configs:
+  - url: http://example.com/webhook
+templates: []
 `,
 		},
 	}
@@ -0,0 +1,273 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+)
+
+const (
+	// PeerDiscoveryInterval defines interval between peer discovery runs
+	PeerDiscoveryInterval = time.Minute
+	peerProbeTimeout      = 2 * time.Second
+	gossipPort            = 9094
+	peerSourceStatic      = "additionalPeers"
+	// peerSourceServiceSelector is reported for failed services listing
+	peerSourceServiceSelector = "serviceSelector"
+	peerSourceServicePrefix   = "service/"
+)
+
+// lookupSRV is used for DNS SRV records resolution, it's replaced at tests
+var lookupSRV = net.DefaultResolver.LookupSRV
+
+// dialPeer checks gossip port reachability, it's replaced at tests
+var dialPeer = func(ctx context.Context, addr string) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+type clusterPeer struct {
+	address        string
+	source         string
+	discoveryError string
+}
+
+// discoverPeers returns additional peers of alertmanager cluster
+// static peers go first, discovered peers are sorted by address in order to keep stable statefulset args
+//
+// if discovery of some source fails, last known peers of this source are taken from status
+// in order to not drop peers from statefulset args on transient errors.
+// Source without known peers is returned with empty address, it's only reported at status
+func discoverPeers(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlertmanager) []clusterPeer {
+	peers := make([]clusterPeer, 0, len(cr.Spec.AdditionalPeers))
+	seen := make(map[string]struct{})
+	add := func(address, source, discoveryError string) {
+		if _, ok := seen[address]; ok {
+			return
+		}
+		seen[address] = struct{}{}
+		peers = append(peers, clusterPeer{address: address, source: source, discoveryError: discoveryError})
+	}
+	var failed []clusterPeer
+	fallback := func(source string, matchSource func(string) bool, err error) {
+		logger.WithContext(ctx).Error(err, fmt.Sprintf("cannot discover alertmanager peers of source=%s, using last known peers", source))
+		var found bool
+		for _, st := range cr.Status.Peers {
+			if st.Address != "" && matchSource(st.Source) {
+				add(st.Address, st.Source, err.Error())
+				found = true
+			}
+		}
+		if !found {
+			failed = append(failed, clusterPeer{source: source, discoveryError: err.Error()})
+		}
+	}
+	for _, peer := range cr.Spec.AdditionalPeers {
+		add(peer, peerSourceStatic, "")
+	}
+	pd := cr.Spec.PeerDiscovery
+	if pd == nil {
+		return peers
+	}
+	staticCount := len(peers)
+	if pd.ServiceSelector != nil {
+		if err := discoverServicePeers(ctx, rclient, cr, add, fallback); err != nil {
+			fallback(peerSourceServiceSelector, func(source string) bool {
+				return strings.HasPrefix(source, peerSourceServicePrefix)
+			}, err)
+		}
+	}
+	for _, name := range pd.DNSSRVNames {
+		source := "dns/" + name
+		_, srvs, err := lookupSRV(ctx, "", "", name)
+		if err != nil {
+			fallback(source, func(s string) bool { return s == source }, fmt.Errorf("cannot resolve DNS SRV record=%q: %w", name, err))
+			continue
+		}
+		for _, srv := range srvs {
+			add(net.JoinHostPort(strings.TrimSuffix(srv.Target, "."), strconv.Itoa(int(srv.Port))), source, "")
+		}
+	}
+	discovered := peers[staticCount:]
+	slices.SortFunc(discovered, func(a, b clusterPeer) int {
+		return strings.Compare(a.address, b.address)
+	})
+	return append(peers, failed...)
+}
+
+// discoverServicePeers adds peers of services selected by peerDiscovery.serviceSelector
+func discoverServicePeers(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlertmanager,
+	add func(address, source, discoveryError string), fallback func(source string, matchSource func(string) bool, err error)) error {
+	pd := cr.Spec.PeerDiscovery
+	selector, err := metav1.LabelSelectorAsSelector(pd.ServiceSelector)
+	if err != nil {
+		return fmt.Errorf("cannot parse serviceSelector: %w", err)
+	}
+	var svcs corev1.ServiceList
+	if err := rclient.List(ctx, &svcs, &client.ListOptions{Namespace: cr.Namespace, LabelSelector: selector}); err != nil {
+		return fmt.Errorf("cannot list services for peer discovery: %w", err)
+	}
+	for i := range svcs.Items {
+		svc := &svcs.Items[i]
+		if svc.Name == cr.PrefixedName() {
+			continue
+		}
+		source := peerSourceServicePrefix + svc.Name
+		addrs, err := serviceAddresses(ctx, rclient, svc, pd.PortName)
+		if err != nil {
+			fallback(source, func(s string) bool { return s == source }, err)
+			continue
+		}
+		for _, addr := range addrs {
+			add(addr, source, "")
+		}
+	}
+	return nil
+}
+
+// serviceAddresses returns gossip addresses of the given service
+func serviceAddresses(ctx context.Context, rclient client.Client, svc *corev1.Service, portName string) ([]string, error) {
+	var port *corev1.ServicePort
+	for i := range svc.Spec.Ports {
+		p := &svc.Spec.Ports[i]
+		if portName != "" {
+			if p.Name == portName {
+				port = p
+				break
+			}
+			continue
+		}
+		if p.Port == gossipPort {
+			port = p
+			break
+		}
+		if port == nil {
+			port = p
+		}
+	}
+	if port == nil {
+		if svc.Spec.Type == corev1.ServiceTypeExternalName && portName == "" {
+			return []string{net.JoinHostPort(svc.Spec.ExternalName, strconv.Itoa(gossipPort))}, nil
+		}
+		return nil, nil
+	}
+	servicePort := strconv.Itoa(int(port.Port))
+	var addrs []string
+	switch svc.Spec.Type {
+	case corev1.ServiceTypeLoadBalancer:
+		for _, ing := range svc.Status.LoadBalancer.Ingress {
+			host := ing.Hostname
+			if ing.IP != "" {
+				host = ing.IP
+			}
+			if host != "" {
+				addrs = append(addrs, net.JoinHostPort(host, servicePort))
+			}
+		}
+	case corev1.ServiceTypeExternalName:
+		addrs = append(addrs, net.JoinHostPort(svc.Spec.ExternalName, servicePort))
+	default:
+		var eps corev1.Endpoints
+		if err := rclient.Get(ctx, types.NamespacedName{Namespace: svc.Namespace, Name: svc.Name}, &eps); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("cannot get endpoints of service=%s for peer discovery: %w", svc.Name, err)
+		}
+		for _, subset := range eps.Subsets {
+			for _, ep := range subset.Ports {
+				// endpoints port name matches service port name
+				if ep.Name != port.Name {
+					continue
+				}
+				for _, addr := range subset.Addresses {
+					addrs = append(addrs, net.JoinHostPort(addr.IP, strconv.Itoa(int(ep.Port))))
+				}
+			}
+		}
+	}
+	return addrs, nil
+}
+
+// probePeers checks reachability of peers gossip port
+func probePeers(ctx context.Context, peers []clusterPeer, prev []vmv1beta1.AlertmanagerPeerStatus) []vmv1beta1.AlertmanagerPeerStatus {
+	result := make([]vmv1beta1.AlertmanagerPeerStatus, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(i int, peer clusterPeer) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, peerProbeTimeout)
+			defer cancel()
+			st := vmv1beta1.AlertmanagerPeerStatus{
+				Address:        peer.address,
+				Source:         peer.source,
+				Reachable:      true,
+				DiscoveryError: peer.discoveryError,
+			}
+			if peer.address == "" {
+				st.Reachable = false
+			} else if err := dialPeer(ctx, peer.address); err != nil {
+				st.Reachable = false
+				st.Error = err.Error()
+			}
+			result[i] = st
+		}(i, peer)
+	}
+	wg.Wait()
+	now := metav1.Now()
+	for i := range result {
+		st := &result[i]
+		st.LastTransitionTime = now
+		idx := slices.IndexFunc(prev, func(p vmv1beta1.AlertmanagerPeerStatus) bool {
+			return p.Address == st.Address && p.Source == st.Source
+		})
+		if idx >= 0 && prev[idx].Reachable == st.Reachable {
+			st.LastTransitionTime = prev[idx].LastTransitionTime
+		}
+	}
+	return result
+}
+
+// updatePeersStatus patches status with the given peers reachability
+func updatePeersStatus(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlertmanager, peers []vmv1beta1.AlertmanagerPeerStatus) error {
+	if equality.Semantic.DeepEqual(cr.Status.Peers, peers) {
+		return nil
+	}
+	type patch struct {
+		Status struct {
+			Peers []vmv1beta1.AlertmanagerPeerStatus `json:"peers"`
+		} `json:"status"`
+	}
+	var p patch
+	p.Status.Peers = peers
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("BUG: cannot marshal peers status: %w", err)
+	}
+	if err := rclient.Status().Patch(ctx, cr.DeepCopy(), client.RawPatch(types.MergePatchType, data)); err != nil {
+		return fmt.Errorf("cannot update peers status of vmalertmanager=%s/%s: %w", cr.Namespace, cr.Name, err)
+	}
+	cr.Status.Peers = peers
+	return nil
+}
@@ -0,0 +1,198 @@
+package alertmanager
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func TestDiscoverPeers(t *testing.T) {
+	origLookupSRV := lookupSRV
+	defer func() { lookupSRV = origLookupSRV }()
+	lookupSRV = func(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+		if name != "_mesh._tcp.alertmanager.cluster-b.example.com" {
+			return "", nil, fmt.Errorf("no such host")
+		}
+		return "", []*net.SRV{
+			{Target: "am-1.cluster-b.example.com.", Port: 9094},
+			{Target: "am-0.cluster-b.example.com.", Port: 9094},
+		}, nil
+	}
+	peerLabels := map[string]string{"alertmanager-peer": "true"}
+	predefined := []runtime.Object{
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-c", Namespace: "monitoring", Labels: peerLabels},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeLoadBalancer,
+				Ports: []corev1.ServicePort{{Name: "http", Port: 9093}, {Name: "mesh", Port: 9094}},
+			},
+			Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.2"}, {Hostname: "lb.cluster-c.example.com"}},
+			}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-d", Namespace: "monitoring", Labels: peerLabels},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Name: "mesh", Port: 19094}},
+			},
+		},
+		&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-d", Namespace: "monitoring"},
+			Subsets: []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{{IP: "192.168.0.1"}},
+				Ports:     []corev1.EndpointPort{{Name: "mesh", Port: 9094}},
+			}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-e", Namespace: "monitoring", Labels: peerLabels},
+			Spec: corev1.ServiceSpec{
+				Type:         corev1.ServiceTypeExternalName,
+				ExternalName: "am.cluster-e.example.com",
+			},
+		},
+		// not selected
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "monitoring"},
+			Spec: corev1.ServiceSpec{
+				Type:         corev1.ServiceTypeExternalName,
+				ExternalName: "other.example.com",
+			},
+		},
+	}
+	f := func(spec vmv1beta1.VMAlertmanagerSpec, prevPeers []vmv1beta1.AlertmanagerPeerStatus, want []clusterPeer) {
+		t.Helper()
+		cr := &vmv1beta1.VMAlertmanager{
+			ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"},
+			Spec:       spec,
+			Status:     vmv1beta1.VMAlertmanagerStatus{Peers: prevPeers},
+		}
+		fclient := k8stools.GetTestClientWithObjects(predefined)
+		got := discoverPeers(context.Background(), fclient, cr)
+		assert.Equal(t, want, got)
+	}
+
+	// static peers only
+	f(vmv1beta1.VMAlertmanagerSpec{AdditionalPeers: []string{"am.example.com:9094"}}, nil, []clusterPeer{
+		{address: "am.example.com:9094", source: peerSourceStatic},
+	})
+
+	// services and dns
+	f(vmv1beta1.VMAlertmanagerSpec{
+		AdditionalPeers: []string{"am-0.cluster-b.example.com:9094"},
+		PeerDiscovery: &vmv1beta1.AlertmanagerPeerDiscovery{
+			ServiceSelector: &metav1.LabelSelector{MatchLabels: peerLabels},
+			DNSSRVNames:     []string{"_mesh._tcp.alertmanager.cluster-b.example.com"},
+		},
+	}, nil, []clusterPeer{
+		{address: "am-0.cluster-b.example.com:9094", source: peerSourceStatic},
+		{address: "10.0.0.2:9094", source: "service/cluster-c"},
+		{address: "192.168.0.1:9094", source: "service/cluster-d"},
+		{address: "am-1.cluster-b.example.com:9094", source: "dns/_mesh._tcp.alertmanager.cluster-b.example.com"},
+		{address: "am.cluster-e.example.com:9094", source: "service/cluster-e"},
+		{address: "lb.cluster-c.example.com:9094", source: "service/cluster-c"},
+	})
+
+	// port name
+	f(vmv1beta1.VMAlertmanagerSpec{
+		PeerDiscovery: &vmv1beta1.AlertmanagerPeerDiscovery{
+			ServiceSelector: &metav1.LabelSelector{MatchLabels: peerLabels},
+			PortName:        "http",
+		},
+	}, nil, []clusterPeer{
+		{address: "10.0.0.2:9093", source: "service/cluster-c"},
+		{address: "lb.cluster-c.example.com:9093", source: "service/cluster-c"},
+	})
+
+	// dns error without known peers
+	dnsErr := `cannot resolve DNS SRV record="_mesh._tcp.missing.example.com": no such host`
+	f(vmv1beta1.VMAlertmanagerSpec{
+		PeerDiscovery: &vmv1beta1.AlertmanagerPeerDiscovery{
+			DNSSRVNames: []string{"_mesh._tcp.missing.example.com"},
+		},
+	}, nil, []clusterPeer{
+		{source: "dns/_mesh._tcp.missing.example.com", discoveryError: dnsErr},
+	})
+
+	// dns error falls back to last known peers of the same source
+	f(vmv1beta1.VMAlertmanagerSpec{
+		AdditionalPeers: []string{"am.example.com:9094"},
+		PeerDiscovery: &vmv1beta1.AlertmanagerPeerDiscovery{
+			DNSSRVNames: []string{"_mesh._tcp.missing.example.com"},
+		},
+	}, []vmv1beta1.AlertmanagerPeerStatus{
+		{Address: "am.example.com:9094", Source: peerSourceStatic, Reachable: true},
+		{Address: "am-1.missing.example.com:9094", Source: "dns/_mesh._tcp.missing.example.com", Reachable: true},
+		{Address: "am-0.missing.example.com:9094", Source: "dns/_mesh._tcp.missing.example.com", Reachable: true},
+		{Address: "10.0.0.5:9094", Source: "service/removed", Reachable: true},
+	}, []clusterPeer{
+		{address: "am.example.com:9094", source: peerSourceStatic},
+		{address: "am-0.missing.example.com:9094", source: "dns/_mesh._tcp.missing.example.com", discoveryError: dnsErr},
+		{address: "am-1.missing.example.com:9094", source: "dns/_mesh._tcp.missing.example.com", discoveryError: dnsErr},
+	})
+}
+
+func TestProbePeers(t *testing.T) {
+	origDialPeer := dialPeer
+	defer func() { dialPeer = origDialPeer }()
+	dialPeer = func(_ context.Context, addr string) error {
+		if addr == "10.0.0.2:9094" {
+			return fmt.Errorf("connection refused")
+		}
+		return nil
+	}
+	ctx := context.Background()
+	cr := &vmv1beta1.VMAlertmanager{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"},
+		Spec: vmv1beta1.VMAlertmanagerSpec{
+			CommonApplicationDeploymentParams: vmv1beta1.CommonApplicationDeploymentParams{
+				ReplicaCount: ptr.To(int32(2)),
+			},
+		},
+	}
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{cr})
+
+	prevTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	prev := []vmv1beta1.AlertmanagerPeerStatus{
+		{Address: "am.example.com:9094", Source: peerSourceStatic, Reachable: true, LastTransitionTime: prevTime},
+		{Address: "10.0.0.2:9094", Source: "service/cluster-c", Reachable: true, LastTransitionTime: prevTime},
+	}
+	peers := []clusterPeer{
+		{address: "am.example.com:9094", source: peerSourceStatic},
+		{address: "10.0.0.2:9094", source: "service/cluster-c"},
+		{source: "dns/_mesh._tcp.missing.example.com", discoveryError: "no such host"},
+	}
+	got := probePeers(ctx, peers, prev)
+	assert.Len(t, got, 3)
+	assert.False(t, got[2].Reachable)
+	assert.Equal(t, "no such host", got[2].DiscoveryError)
+	assert.True(t, got[0].Reachable)
+	assert.Equal(t, prevTime, got[0].LastTransitionTime)
+	assert.False(t, got[1].Reachable)
+	assert.Equal(t, "connection refused", got[1].Error)
+	assert.True(t, got[1].LastTransitionTime.After(prevTime.Time))
+
+	assert.NoError(t, updatePeersStatus(ctx, fclient, cr, got))
+	var updated vmv1beta1.VMAlertmanager
+	assert.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}, &updated))
+	assert.Len(t, updated.Status.Peers, 3)
+	assert.Equal(t, "10.0.0.2:9094", updated.Status.Peers[1].Address)
+
+	// discovered peers are added to statefulset args
+	spec, err := makeStatefulSetSpec(cr, peers)
+	assert.NoError(t, err)
+	assert.Contains(t, spec.Template.Spec.Containers[0].Args, "--cluster.peer=10.0.0.2:9094")
+	assert.Contains(t, spec.Template.Spec.Containers[0].Args, "--cluster.peer=am.example.com:9094")
+	assert.NotContains(t, spec.Template.Spec.Containers[0].Args, "--cluster.peer=")
+}
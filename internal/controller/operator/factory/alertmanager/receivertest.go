@@ -0,0 +1,360 @@
+package alertmanager
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	amcfg "github.com/prometheus/alertmanager/config"
+	amreceiver "github.com/prometheus/alertmanager/config/receiver"
+	"github.com/prometheus/alertmanager/notify"
+	amtemplate "github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+)
+
+const (
+	receiverTestTimeout   = 10 * time.Second
+	receiverTestAlertname = "VMAlertmanagerReceiverTest"
+)
+
+// receiverTestResolvedIntegrations contains integrations, which create incidents
+// test incidents are resolved right after creation in order to not page on-call engineers
+var receiverTestResolvedIntegrations = map[string]struct{}{
+	"pagerduty": {},
+	"opsgenie":  {},
+	"victorops": {},
+}
+
+// IsReceiverTestRequested checks if receiver test must be performed or its status must be reset
+func IsReceiverTestRequested(amc *vmv1beta1.VMAlertmanagerConfig) bool {
+	receiver := amc.Annotations[vmv1beta1.ReceiverTestAnnotation]
+	prev := amc.Status.ReceiverTest
+	switch {
+	case !amc.DeletionTimestamp.IsZero():
+		return false
+	case receiver == "" && prev == nil:
+		return false
+	case receiver != "" && prev != nil && prev.Receiver == receiver:
+		return false
+	}
+	return true
+}
+
+// TestReceiver sends test notification through receiver requested with ReceiverTestAnnotation
+// and records delivery results at VMAlertmanagerConfig status
+//
+// test is performed once per annotation value, removal of annotation resets test status
+func TestReceiver(ctx context.Context, rclient client.Client, amc *vmv1beta1.VMAlertmanagerConfig) error {
+	if !IsReceiverTestRequested(amc) {
+		return nil
+	}
+	var st *vmv1beta1.ReceiverTestStatus
+	if receiver := amc.Annotations[vmv1beta1.ReceiverTestAnnotation]; receiver != "" {
+		st = runReceiverTest(ctx, rclient, amc, receiver)
+		logger.WithContext(ctx).Info(fmt.Sprintf("tested receiver=%q, results: %s", receiver, receiverTestSummary(st)))
+	}
+	return updateReceiverTestStatus(ctx, rclient, amc, st)
+}
+
+func runReceiverTest(ctx context.Context, rclient client.Client, amc *vmv1beta1.VMAlertmanagerConfig, receiver string) *vmv1beta1.ReceiverTestStatus {
+	st := &vmv1beta1.ReceiverTestStatus{
+		Receiver:     receiver,
+		LastTestTime: metav1.Now(),
+	}
+	am, err := findSelectingAlertmanager(ctx, rclient, amc)
+	if err != nil {
+		st.Error = err.Error()
+		return st
+	}
+	st.Alertmanager = fmt.Sprintf("%s/%s", am.Namespace, am.Name)
+	tlsAssets := map[string]string{}
+	cfg, recv, err := renderReceiver(ctx, rclient, am, amc, receiver, tlsAssets)
+	if err != nil {
+		st.Error = err.Error()
+		return st
+	}
+	tmpl, err := buildReceiverTestTemplate(ctx, rclient, am, cfg, tlsAssets)
+	if err != nil {
+		st.Error = err.Error()
+		return st
+	}
+	inlineReceiverTLSAssets(recv, tlsAssets)
+	integrations, err := amreceiver.BuildReceiverIntegrations(*recv, tmpl, nil)
+	if err != nil {
+		st.Error = fmt.Sprintf("cannot build receiver integrations: %s", err)
+		return st
+	}
+	st.Integrations = sendTestNotifications(ctx, integrations, newTestNotification(amc, recv.Name))
+	return st
+}
+
+func receiverTestSummary(st *vmv1beta1.ReceiverTestStatus) string {
+	if st.Error != "" {
+		return st.Error
+	}
+	results := make([]string, 0, len(st.Integrations))
+	for _, integration := range st.Integrations {
+		result := "success"
+		if !integration.Success {
+			result = integration.Error
+		}
+		results = append(results, fmt.Sprintf("%s: %s", integration.Name, result))
+	}
+	return strings.Join(results, ", ")
+}
+
+// findSelectingAlertmanager returns the first VMAlertmanager, which selects given config
+func findSelectingAlertmanager(ctx context.Context, rclient client.Client, amc *vmv1beta1.VMAlertmanagerConfig) (*vmv1beta1.VMAlertmanager, error) {
+	var ams vmv1beta1.VMAlertmanagerList
+	if err := k8stools.ListObjectsByNamespace(ctx, rclient, nil, func(l *vmv1beta1.VMAlertmanagerList) {
+		ams.Items = append(ams.Items, l.Items...)
+	}); err != nil {
+		return nil, fmt.Errorf("cannot list vmalertmanagers: %w", err)
+	}
+	slices.SortFunc(ams.Items, func(a, b vmv1beta1.VMAlertmanager) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+	for i := range ams.Items {
+		am := &ams.Items[i]
+		if !am.DeletionTimestamp.IsZero() || am.Spec.ParsingError != "" {
+			continue
+		}
+		ok, err := k8stools.IsObjectSelected(ctx, rclient, am.Spec.ConfigNamespaceSelector, am.Spec.ConfigSelector, am.Namespace, am.Spec.SelectAllByDefault, amc)
+		if err != nil {
+			return nil, fmt.Errorf("cannot check selectors of vmalertmanager=%s/%s: %w", am.Namespace, am.Name, err)
+		}
+		if ok {
+			return am, nil
+		}
+	}
+	return nil, fmt.Errorf("config isn't selected by any vmalertmanager")
+}
+
+// renderReceiver builds merged configuration of the given VMAlertmanager
+// and returns configuration and receiver of config loaded by alertmanager config parser
+func renderReceiver(ctx context.Context, rclient client.Client, am *vmv1beta1.VMAlertmanager, amc *vmv1beta1.VMAlertmanagerConfig, receiver string, tlsAssets map[string]string) (*amcfg.Config, *amcfg.Receiver, error) {
+	if !slices.ContainsFunc(amc.Spec.Receivers, func(r vmv1beta1.Receiver) bool { return r.Name == receiver }) {
+		return nil, nil, fmt.Errorf("receiver=%q is not defined at config", receiver)
+	}
+	baseCfg, err := getBaseConfig(ctx, rclient, am)
+	if err != nil {
+		return nil, nil, err
+	}
+	amCfgs, _, _, err := selectConfigs(ctx, rclient, am)
+	if err != nil {
+		return nil, nil, err
+	}
+	// replace selected copy of config in order to detect build errors of tested config
+	cfgs := make([]*vmv1beta1.VMAlertmanagerConfig, 0, len(amCfgs)+1)
+	for _, cfg := range amCfgs {
+		if cfg.Namespace == amc.Namespace && cfg.Name == amc.Name {
+			continue
+		}
+		cfgs = append(cfgs, cfg)
+	}
+	target := amc.DeepCopy()
+	cfgs = append(cfgs, target)
+	parsedCfg, err := buildConfig(ctx, rclient, am, baseCfg, cfgs, tlsAssets)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot build config: %w", err)
+	}
+	if slices.Contains(parsedCfg.brokenAMCfgs, target) {
+		return nil, nil, fmt.Errorf("cannot build config: %s", target.Status.CurrentSyncError)
+	}
+	data, err := addCRTemplates(am, parsedCfg.data)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg, err := loadConfig(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot load merged configuration: %w", err)
+	}
+	name := buildCRPrefixedName(amc, receiver)
+	for _, recv := range cfg.Receivers {
+		if recv.Name == name {
+			return cfg, &recv, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("BUG: receiver=%q is missing at merged configuration", name)
+}
+
+// buildReceiverTestTemplate builds notification template with the same templates as alertmanager uses
+//
+// templates of VMAlertmanagerConfig are taken from tlsAssets and templates of VMAlertmanager from ConfigMaps,
+// other template files are located at alertmanager container and cannot be loaded by operator
+func buildReceiverTestTemplate(ctx context.Context, rclient client.Client, am *vmv1beta1.VMAlertmanager, cfg *amcfg.Config, tlsAssets map[string]string) (*amtemplate.Template, error) {
+	tmpl, err := amtemplate.FromGlobs(nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load default templates: %w", err)
+	}
+	cmCache := make(map[string]*corev1.ConfigMap)
+	for _, tp := range cfg.Templates {
+		var content string
+		switch {
+		case strings.HasPrefix(tp, tlsAssetsDir+"/"):
+			content = tlsAssets[path.Base(tp)]
+		case strings.HasPrefix(tp, templatesDir+"/"):
+			cmName, key := path.Split(strings.TrimPrefix(tp, templatesDir+"/"))
+			cmName = strings.TrimSuffix(cmName, "/")
+			sel := corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: cmName}, Key: key}
+			content, err = k8stools.GetCredFromConfigMap(ctx, rclient, am.Namespace, sel, fmt.Sprintf("%s/%s", am.Namespace, cmName), cmCache)
+			if err != nil {
+				return nil, fmt.Errorf("cannot load template=%q: %w", tp, err)
+			}
+		default:
+			continue
+		}
+		if err := tmpl.Parse(strings.NewReader(content)); err != nil {
+			return nil, fmt.Errorf("cannot parse template=%q: %w", tp, err)
+		}
+	}
+	externalURL := am.Spec.ExternalURL
+	if externalURL == "" {
+		externalURL = am.AsURL()
+	}
+	if tmpl.ExternalURL, err = url.Parse(externalURL); err != nil {
+		return nil, fmt.Errorf("cannot parse external url=%q: %w", externalURL, err)
+	}
+	return tmpl, nil
+}
+
+var httpClientConfigType = reflect.TypeOf(&commoncfg.HTTPClientConfig{})
+
+// inlineReceiverTLSAssets replaces TLS files of receiver http configurations with content of tlsAssets
+//
+// TLS files of alertmanager configuration point to the config secret mount, which isn't available for operator
+func inlineReceiverTLSAssets(recv *amcfg.Receiver, tlsAssets map[string]string) {
+	inlineFile := func(file *string, dst func(string)) {
+		if !strings.HasPrefix(*file, tlsAssetsDir+"/") {
+			return
+		}
+		if content, ok := tlsAssets[path.Base(*file)]; ok {
+			dst(content)
+			*file = ""
+		}
+	}
+	rv := reflect.ValueOf(recv).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		configs := rv.Field(i)
+		if configs.Kind() != reflect.Slice {
+			continue
+		}
+		for j := 0; j < configs.Len(); j++ {
+			c := configs.Index(j)
+			if c.Kind() != reflect.Pointer || c.IsNil() || c.Elem().Kind() != reflect.Struct {
+				continue
+			}
+			f := c.Elem().FieldByName("HTTPConfig")
+			if !f.IsValid() || f.Type() != httpClientConfigType || f.IsNil() {
+				continue
+			}
+			// make a copy, http config could be shared with global configuration
+			httpCfg := *f.Interface().(*commoncfg.HTTPClientConfig)
+			tc := &httpCfg.TLSConfig
+			inlineFile(&tc.CAFile, func(s string) { tc.CA = s })
+			inlineFile(&tc.CertFile, func(s string) { tc.Cert = s })
+			inlineFile(&tc.KeyFile, func(s string) { tc.Key = commoncfg.Secret(s) })
+			f.Set(reflect.ValueOf(&httpCfg))
+		}
+	}
+}
+
+// testNotification contains synthetic alert, which is sent to receivers
+type testNotification struct {
+	receiver string
+	alert    *types.Alert
+}
+
+func newTestNotification(amc *vmv1beta1.VMAlertmanagerConfig, receiver string) *testNotification {
+	now := time.Now()
+	return &testNotification{
+		receiver: receiver,
+		alert: &types.Alert{
+			Alert: model.Alert{
+				Labels: model.LabelSet{
+					model.AlertNameLabel: receiverTestAlertname,
+					"namespace":          model.LabelValue(amc.Namespace),
+					"severity":           "none",
+				},
+				Annotations: model.LabelSet{
+					"summary": model.LabelValue(fmt.Sprintf("Test notification sent by VictoriaMetrics operator through receiver=%q of VMAlertmanagerConfig=%s/%s", receiver, amc.Namespace, amc.Name)),
+				},
+				StartsAt: now,
+				EndsAt:   now.Add(time.Hour),
+			},
+			UpdatedAt: now,
+		},
+	}
+}
+
+// notify sends test notification to the given integration
+// resolved notification is sent right after firing one for integrations, which create incidents
+func (tn *testNotification) notify(ctx context.Context, integration notify.Integration) error {
+	ctx, cancel := context.WithTimeout(ctx, receiverTestTimeout)
+	defer cancel()
+	now := time.Now()
+	ctx = notify.WithReceiverName(ctx, tn.receiver)
+	ctx = notify.WithGroupKey(ctx, fmt.Sprintf("%s/%s/%d", tn.alert.Labels["namespace"], tn.receiver, tn.alert.StartsAt.UnixNano()))
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{model.AlertNameLabel: receiverTestAlertname})
+	ctx = notify.WithNow(ctx, now)
+	ctx = notify.WithRepeatInterval(ctx, time.Hour)
+	if _, err := integration.Notify(ctx, tn.alert); err != nil {
+		return err
+	}
+	if _, ok := receiverTestResolvedIntegrations[integration.Name()]; !ok {
+		return nil
+	}
+	resolved := *tn.alert
+	resolved.EndsAt = now
+	_, err := integration.Notify(ctx, &resolved)
+	return err
+}
+
+// sendTestNotifications sends test notification to each integration of receiver
+func sendTestNotifications(ctx context.Context, integrations []notify.Integration, tn *testNotification) []vmv1beta1.ReceiverTestIntegration {
+	results := make([]vmv1beta1.ReceiverTestIntegration, 0, len(integrations))
+	for _, integration := range integrations {
+		result := vmv1beta1.ReceiverTestIntegration{
+			Name:    fmt.Sprintf("%s_configs[%d]", integration.Name(), integration.Index()),
+			Success: true,
+		}
+		if err := tn.notify(ctx, integration); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func updateReceiverTestStatus(ctx context.Context, rclient client.Client, amc *vmv1beta1.VMAlertmanagerConfig, st *vmv1beta1.ReceiverTestStatus) error {
+	nsn := k8stypes.NamespacedName{Namespace: amc.Namespace, Name: amc.Name}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var dst vmv1beta1.VMAlertmanagerConfig
+		if err := rclient.Get(ctx, nsn, &dst); err != nil {
+			return err
+		}
+		dst.Status.ReceiverTest = st
+		if err := rclient.Status().Update(ctx, &dst); err != nil {
+			return fmt.Errorf("cannot update receiver test status of vmalertmanagerconfig=%s: %w", nsn, err)
+		}
+		amc.Status.ReceiverTest = st
+		return nil
+	})
+}
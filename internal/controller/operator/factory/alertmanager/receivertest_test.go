@@ -0,0 +1,145 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func TestTestReceiver(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	requests := map[string][]map[string]any{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var msg map[string]any
+		assert.NoError(t, json.Unmarshal(data, &msg))
+		mu.Lock()
+		requests[r.URL.Path] = append(requests[r.URL.Path], msg)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/slack":
+			w.Write([]byte(`ok`))
+		case "/slack-api":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`invalid_token`))
+		case "/broken":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`bad token`))
+		}
+	}))
+	defer srv.Close()
+
+	am := &vmv1beta1.VMAlertmanager{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"},
+		Spec:       vmv1beta1.VMAlertmanagerSpec{SelectAllByDefault: true},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "receivers", Namespace: "default"},
+		Data: map[string][]byte{
+			"slack":       []byte(srv.URL + "/slack"),
+			"slack-api":   []byte(srv.URL + "/slack-api"),
+			"routing-key": []byte("secret-key"),
+		},
+	}
+	secretKey := func(key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "receivers"}, Key: key}
+	}
+	amc := &vmv1beta1.VMAlertmanagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "default"},
+		Spec: vmv1beta1.VMAlertmanagerConfigSpec{
+			Route: &vmv1beta1.Route{Receiver: "oncall"},
+			Templates: []vmv1beta1.NotificationTemplate{
+				{Name: "slack", Content: `{{ define "slack.text" }}{{ .CommonLabels.alertname }} at {{ .ExternalURL }}{{ end }}`},
+			},
+			Receivers: []vmv1beta1.Receiver{
+				{
+					Name: "oncall",
+					WebhookConfigs: []vmv1beta1.WebhookConfig{
+						{URL: ptr.To(srv.URL + "/webhook")},
+						{URL: ptr.To(srv.URL + "/broken")},
+					},
+					SlackConfigs: []vmv1beta1.SlackConfig{
+						{APIURL: secretKey("slack"), Channel: "#alerts", Text: `{{ template "slack.text" . }}`},
+						{APIURL: secretKey("slack-api")},
+					},
+					PagerDutyConfigs: []vmv1beta1.PagerDutyConfig{
+						{RoutingKey: secretKey("routing-key"), URL: srv.URL + "/pagerduty"},
+					},
+				},
+				{Name: "other"},
+			},
+		},
+	}
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{am, secret, amc})
+	getStatus := func() *vmv1beta1.ReceiverTestStatus {
+		t.Helper()
+		var got vmv1beta1.VMAlertmanagerConfig
+		assert.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: amc.Namespace, Name: amc.Name}, &got))
+		return got.Status.ReceiverTest
+	}
+
+	// no annotation
+	assert.NoError(t, TestReceiver(ctx, fclient, amc))
+	assert.Nil(t, getStatus())
+	assert.Empty(t, requests)
+
+	amc.Annotations = map[string]string{vmv1beta1.ReceiverTestAnnotation: "oncall"}
+	assert.NoError(t, TestReceiver(ctx, fclient, amc))
+	st := getStatus()
+	assert.Equal(t, "oncall", st.Receiver)
+	assert.Equal(t, "monitoring/main", st.Alertmanager)
+	assert.Empty(t, st.Error)
+	names := make([]string, 0, len(st.Integrations))
+	for _, r := range st.Integrations {
+		names = append(names, r.Name)
+	}
+	assert.Equal(t, []string{"webhook_configs[0]", "webhook_configs[1]", "pagerduty_configs[0]", "slack_configs[0]", "slack_configs[1]"}, names)
+	assert.True(t, st.Integrations[0].Success)
+	assert.False(t, st.Integrations[1].Success)
+	assert.Contains(t, st.Integrations[1].Error, "unexpected status code 401: bad token")
+	assert.True(t, st.Integrations[2].Success)
+	assert.True(t, st.Integrations[3].Success)
+	assert.Contains(t, st.Integrations[4].Error, "unexpected status code 403")
+
+	assert.Len(t, requests["/webhook"], 1)
+	assert.Equal(t, "default-team-oncall", requests["/webhook"][0]["receiver"])
+	assert.Equal(t, "#alerts", requests["/slack"][0]["channel"])
+	// templates of config are rendered by alertmanager template engine
+	attachments := requests["/slack"][0]["attachments"].([]any)
+	assert.Equal(t, "VMAlertmanagerReceiverTest at http://vmalertmanager-main.monitoring.svc:9093", attachments[0].(map[string]any)["text"])
+	// pagerduty incident is resolved
+	assert.Len(t, requests["/pagerduty"], 2)
+	assert.Equal(t, "secret-key", requests["/pagerduty"][0]["routing_key"])
+	assert.Equal(t, "trigger", requests["/pagerduty"][0]["event_action"])
+	assert.Equal(t, "resolve", requests["/pagerduty"][1]["event_action"])
+
+	// test isn't repeated for the same annotation value
+	amc.Status.ReceiverTest = st
+	assert.NoError(t, TestReceiver(ctx, fclient, amc))
+	assert.Len(t, requests["/webhook"], 1)
+
+	// unknown receiver
+	amc.Annotations[vmv1beta1.ReceiverTestAnnotation] = "missing"
+	assert.NoError(t, TestReceiver(ctx, fclient, amc))
+	assert.Equal(t, `receiver="missing" is not defined at config`, getStatus().Error)
+
+	// annotation removal resets status
+	delete(amc.Annotations, vmv1beta1.ReceiverTestAnnotation)
+	assert.NoError(t, TestReceiver(ctx, fclient, amc))
+	assert.Nil(t, getStatus())
+}
@@ -0,0 +1,312 @@
+package alertmanager
+
+import (
+	"context"
+	"fmt"
+	"regexp/syntax"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/inhibit"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+)
+
+// maxUnreachableRoutes limits number of unreachable routes reported at VMAlertmanagerConfig status
+const maxUnreachableRoutes = 10
+
+// RoutingResult describes how alertmanager routes alert with the given labels
+type RoutingResult struct {
+	Labels       map[string]string    `json:"labels"`
+	Routes       []MatchedRoute       `json:"routes"`
+	InhibitRules []MatchedInhibitRule `json:"inhibitRules,omitempty"`
+}
+
+// Receivers returns unique receivers of matched routes
+func (rr *RoutingResult) Receivers() []string {
+	var receivers []string
+	seen := make(map[string]struct{})
+	for _, r := range rr.Routes {
+		if _, ok := seen[r.Receiver]; ok {
+			continue
+		}
+		seen[r.Receiver] = struct{}{}
+		receivers = append(receivers, r.Receiver)
+	}
+	return receivers
+}
+
+// MatchedRoute describes route, which matched alert
+// with settings inherited from parent routes
+type MatchedRoute struct {
+	Path                string   `json:"path"`
+	Matchers            []string `json:"matchers,omitempty"`
+	Receiver            string   `json:"receiver"`
+	GroupBy             []string `json:"groupBy,omitempty"`
+	GroupWait           string   `json:"groupWait"`
+	GroupInterval       string   `json:"groupInterval"`
+	RepeatInterval      string   `json:"repeatInterval"`
+	MuteTimeIntervals   []string `json:"muteTimeIntervals,omitempty"`
+	ActiveTimeIntervals []string `json:"activeTimeIntervals,omitempty"`
+	Continue            bool     `json:"continue"`
+}
+
+// MatchedInhibitRule describes inhibit rule, which could mute alert
+// if any alert matching source matchers with the same equal labels is firing
+type MatchedInhibitRule struct {
+	Index          int      `json:"index"`
+	SourceMatchers []string `json:"sourceMatchers"`
+	Equal          []string `json:"equal,omitempty"`
+}
+
+// routingNode is a route of alertmanager routing tree with its location at config
+type routingNode struct {
+	*dispatch.Route
+	path string
+	// pathMatchers contains matchers of route and all its parents
+	pathMatchers labels.Matchers
+	routes       []*routingNode
+}
+
+type routingInhibitRule struct {
+	index int
+	*inhibit.InhibitRule
+}
+
+// routingTree is a parsed alertmanager routing configuration
+type routingTree struct {
+	root         *routingNode
+	nodes        map[*dispatch.Route]*routingNode
+	inhibitRules []routingInhibitRule
+}
+
+// EvaluateRouting evaluates route tree and inhibit rules of the given alertmanager configuration for the given labels
+func EvaluateRouting(cfg []byte, lset map[string]string) (*RoutingResult, error) {
+	tree, err := parseRoutingTree(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return tree.evaluate(lset), nil
+}
+
+// EvaluateAlertmanagerRouting evaluates route tree and inhibit rules
+// of configuration generated by operator for the given VMAlertmanager
+func EvaluateAlertmanagerRouting(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlertmanager, lset map[string]string) (*RoutingResult, error) {
+	var s corev1.Secret
+	if err := rclient.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: cr.ConfigSecretName()}, &s); err != nil {
+		return nil, fmt.Errorf("cannot fetch config secret for vmalertmanager=%s/%s: %w", cr.Namespace, cr.Name, err)
+	}
+	data, ok := s.Data[alertmanagerSecretConfigKey]
+	if !ok {
+		return nil, fmt.Errorf("cannot find alertmanager config key: %q at secret: %q", alertmanagerSecretConfigKey, s.Name)
+	}
+	return EvaluateRouting(data, lset)
+}
+
+// routingConfig contains only alertmanager config sections required for alert routing
+//
+// receivers aren't parsed, since operator could generate receiver fields unknown to the alertmanager version used for parsing
+type routingConfig struct {
+	Route        *config.Route        `yaml:"route"`
+	InhibitRules []config.InhibitRule `yaml:"inhibit_rules"`
+}
+
+// parseRoutingTree parses routing tree the same way as alertmanager does
+func parseRoutingTree(cfg []byte) (*routingTree, error) {
+	var rc routingConfig
+	if err := yaml.Unmarshal(cfg, &rc); err != nil {
+		return nil, fmt.Errorf("cannot parse alertmanager config: %w", err)
+	}
+	if rc.Route == nil {
+		return nil, fmt.Errorf("alertmanager config has no route")
+	}
+	tree := &routingTree{nodes: make(map[*dispatch.Route]*routingNode)}
+	tree.root = tree.addNode(dispatch.NewRoute(rc.Route, nil), "route", nil)
+	for idx, ir := range rc.InhibitRules {
+		tree.inhibitRules = append(tree.inhibitRules, routingInhibitRule{index: idx, InhibitRule: inhibit.NewInhibitRule(ir)})
+	}
+	return tree, nil
+}
+
+func (t *routingTree) addNode(r *dispatch.Route, path string, parentMatchers labels.Matchers) *routingNode {
+	n := &routingNode{
+		Route:        r,
+		path:         path,
+		pathMatchers: append(parentMatchers[:len(parentMatchers):len(parentMatchers)], r.Matchers...),
+	}
+	t.nodes[r] = n
+	for idx, child := range r.Routes {
+		n.routes = append(n.routes, t.addNode(child, fmt.Sprintf("%s.routes[%d]", path, idx), n.pathMatchers))
+	}
+	return n
+}
+
+func toLabelSet(lset map[string]string) model.LabelSet {
+	ls := make(model.LabelSet, len(lset))
+	for k, v := range lset {
+		ls[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return ls
+}
+
+func matchersToStrings(ms labels.Matchers) []string {
+	result := make([]string, 0, len(ms))
+	for _, m := range ms {
+		result = append(result, m.String())
+	}
+	return result
+}
+
+func groupByToStrings(opts *dispatch.RouteOpts) []string {
+	if opts.GroupByAll {
+		return []string{"..."}
+	}
+	result := make([]string, 0, len(opts.GroupBy))
+	for ln := range opts.GroupBy {
+		result = append(result, string(ln))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (t *routingTree) evaluate(lset map[string]string) *RoutingResult {
+	result := &RoutingResult{Labels: lset}
+	ls := toLabelSet(lset)
+	for _, r := range t.root.Match(ls) {
+		n := t.nodes[r]
+		result.Routes = append(result.Routes, MatchedRoute{
+			Path:                n.path,
+			Matchers:            matchersToStrings(n.pathMatchers),
+			Receiver:            r.RouteOpts.Receiver,
+			GroupBy:             groupByToStrings(&r.RouteOpts),
+			GroupWait:           model.Duration(r.RouteOpts.GroupWait).String(),
+			GroupInterval:       model.Duration(r.RouteOpts.GroupInterval).String(),
+			RepeatInterval:      model.Duration(r.RouteOpts.RepeatInterval).String(),
+			MuteTimeIntervals:   r.RouteOpts.MuteTimeIntervals,
+			ActiveTimeIntervals: r.RouteOpts.ActiveTimeIntervals,
+			Continue:            r.Continue,
+		})
+	}
+	for _, ir := range t.inhibitRules {
+		if !ir.TargetMatchers.Matches(ls) {
+			continue
+		}
+		equal := make([]string, 0, len(ir.Equal))
+		for ln := range ir.Equal {
+			equal = append(equal, string(ln))
+		}
+		sort.Strings(equal)
+		result.InhibitRules = append(result.InhibitRules, MatchedInhibitRule{
+			Index:          ir.index,
+			SourceMatchers: matchersToStrings(ir.SourceMatchers),
+			Equal:          equal,
+		})
+	}
+	return result
+}
+
+// findUnreachableRoutes checks that sample labels built from matchers of leaf routes
+// of the given top level route reach these routes at the merged tree
+func (t *routingTree) findUnreachableRoutes(topRouteIdx int, alertmanager string) []vmv1beta1.UnreachableRoute {
+	if topRouteIdx >= len(t.root.routes) {
+		return nil
+	}
+	top := t.root.routes[topRouteIdx]
+	var result []vmv1beta1.UnreachableRoute
+	var walk func(n *routingNode)
+	walk = func(n *routingNode) {
+		if len(result) >= maxUnreachableRoutes {
+			return
+		}
+		if len(n.routes) > 0 {
+			for _, child := range n.routes {
+				walk(child)
+			}
+			return
+		}
+		lset, ok := sampleLabels(n.pathMatchers)
+		if !ok {
+			return
+		}
+		rr := t.evaluate(lset)
+		for _, r := range rr.Routes {
+			if r.Path == n.path {
+				return
+			}
+		}
+		result = append(result, vmv1beta1.UnreachableRoute{
+			Alertmanager: alertmanager,
+			Route:        "route" + strings.TrimPrefix(n.path, top.path),
+			Labels:       lset,
+			Receivers:    rr.Receivers(),
+		})
+	}
+	walk(top)
+	return result
+}
+
+// sampleLabels builds label set, which matches all given matchers
+//
+// returns false if such label set cannot be built
+func sampleLabels(ms labels.Matchers) (map[string]string, bool) {
+	lset := make(map[string]string)
+	for _, m := range ms {
+		if _, ok := lset[m.Name]; ok {
+			continue
+		}
+		switch m.Type {
+		case labels.MatchEqual:
+			lset[m.Name] = m.Value
+		case labels.MatchRegexp:
+			re, err := syntax.Parse(m.Value, syntax.Perl)
+			if err != nil {
+				return nil, false
+			}
+			lset[m.Name] = sampleRegexpValue(re.Simplify())
+		}
+	}
+	for k, v := range lset {
+		if v == "" {
+			delete(lset, k)
+		}
+	}
+	if !ms.Matches(toLabelSet(lset)) {
+		return nil, false
+	}
+	return lset, true
+}
+
+// sampleRegexpValue returns the shortest obvious string matching the given regexp
+func sampleRegexpValue(re *syntax.Regexp) string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return string(re.Rune)
+	case syntax.OpCharClass:
+		if len(re.Rune) > 0 {
+			return string(re.Rune[0])
+		}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return "x"
+	case syntax.OpCapture, syntax.OpPlus:
+		return sampleRegexpValue(re.Sub[0])
+	case syntax.OpRepeat:
+		return strings.Repeat(sampleRegexpValue(re.Sub[0]), re.Min)
+	case syntax.OpAlternate:
+		return sampleRegexpValue(re.Sub[0])
+	case syntax.OpConcat:
+		var sb strings.Builder
+		for _, sub := range re.Sub {
+			sb.WriteString(sampleRegexpValue(sub))
+		}
+		return sb.String()
+	}
+	return ""
+}
@@ -0,0 +1,180 @@
+package alertmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func TestEvaluateRouting(t *testing.T) {
+	cfg := `
+route:
+  receiver: default
+  group_by: [alertname]
+  routes:
+  - matchers: ['namespace = "team-a"', 'env = "prod"']
+    receiver: team-a-pager
+    group_wait: 10s
+    continue: true
+    routes:
+    - match:
+        severity: info
+      receiver: team-a-chat
+      mute_time_intervals: [nights]
+  - match_re:
+      namespace: team-.*
+    receiver: teams
+    group_by: [alertname, job]
+  - receiver: catch-all
+inhibit_rules:
+- source_matchers: ['severity = "critical"']
+  target_matchers: ['severity = "warning"']
+  equal: [alertname]
+receivers:
+- name: default
+- name: team-a-pager
+- name: team-a-chat
+- name: teams
+- name: catch-all
+time_intervals:
+- name: nights
+`
+	f := func(lset map[string]string, wantPaths, wantReceivers []string) *RoutingResult {
+		t.Helper()
+		got, err := EvaluateRouting([]byte(cfg), lset)
+		assert.NoError(t, err)
+		var paths []string
+		for _, r := range got.Routes {
+			paths = append(paths, r.Path)
+		}
+		assert.Equal(t, wantPaths, paths)
+		assert.Equal(t, wantReceivers, got.Receivers())
+		return got
+	}
+
+	// continue at the first route
+	got := f(map[string]string{"namespace": "team-a", "env": "prod"},
+		[]string{"route.routes[0]", "route.routes[1]"},
+		[]string{"team-a-pager", "teams"})
+	assert.Equal(t, MatchedRoute{
+		Path:           "route.routes[0]",
+		Matchers:       []string{`env="prod"`, `namespace="team-a"`},
+		Receiver:       "team-a-pager",
+		GroupBy:        []string{"alertname"},
+		GroupWait:      "10s",
+		GroupInterval:  "5m",
+		RepeatInterval: "4h",
+		Continue:       true,
+	}, got.Routes[0])
+	assert.Equal(t, []string{"alertname", "job"}, got.Routes[1].GroupBy)
+	assert.Equal(t, "30s", got.Routes[1].GroupWait)
+	assert.Empty(t, got.InhibitRules)
+
+	// nested route inherits settings and mute intervals aren't inherited
+	got = f(map[string]string{"namespace": "team-a", "env": "prod", "severity": "info"},
+		[]string{"route.routes[0].routes[0]", "route.routes[1]"},
+		[]string{"team-a-chat", "teams"})
+	assert.Equal(t, "10s", got.Routes[0].GroupWait)
+	assert.Equal(t, []string{"nights"}, got.Routes[0].MuteTimeIntervals)
+	assert.Empty(t, got.Routes[1].MuteTimeIntervals)
+
+	// match_re is anchored
+	f(map[string]string{"namespace": "my-team-b"},
+		[]string{"route.routes[2]"},
+		[]string{"catch-all"})
+
+	// inhibit rules matching alert as target
+	got = f(map[string]string{"namespace": "other", "severity": "warning"},
+		[]string{"route.routes[2]"},
+		[]string{"catch-all"})
+	assert.Equal(t, []MatchedInhibitRule{
+		{Index: 0, SourceMatchers: []string{`severity="critical"`}, Equal: []string{"alertname"}},
+	}, got.InhibitRules)
+
+	// root route and group by all labels
+	cfg = `
+route:
+  receiver: blackhole
+  group_by: ['...']
+receivers:
+- name: blackhole
+`
+	got = f(map[string]string{"alertname": "Down"}, []string{"route"}, []string{"blackhole"})
+	assert.Equal(t, []string{"..."}, got.Routes[0].GroupBy)
+
+	// invalid matcher
+	_, err := EvaluateRouting([]byte(`{route: {receiver: r, routes: [{matchers: ['bad matcher']}]}, receivers: [{name: r}]}`), nil)
+	assert.Error(t, err)
+
+}
+
+func TestSampleLabels(t *testing.T) {
+	f := func(matchers []string, want map[string]string, wantOK bool) {
+		t.Helper()
+		var ms labels.Matchers
+		for _, s := range matchers {
+			m, err := labels.ParseMatcher(s)
+			assert.NoError(t, err)
+			ms = append(ms, m)
+		}
+		got, ok := sampleLabels(ms)
+		assert.Equal(t, wantOK, ok)
+		assert.Equal(t, want, got)
+	}
+	f([]string{`job="api"`, `env!="dev"`}, map[string]string{"job": "api"}, true)
+	f([]string{`severity=~"critical|warning"`}, map[string]string{"severity": "critical"}, true)
+	f([]string{`instance=~"db-[0-9]+"`}, map[string]string{"instance": "db-0"}, true)
+	f([]string{`instance=~".+"`}, map[string]string{"instance": "x"}, true)
+	f([]string{`job!~".*"`}, nil, false)
+	f([]string{`job="a"`, `job="b"`}, nil, false)
+}
+
+func TestBuildConfigUnreachableRoutes(t *testing.T) {
+	ctx := context.Background()
+	am := &vmv1beta1.VMAlertmanager{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"},
+		Spec: vmv1beta1.VMAlertmanagerSpec{
+			EnforcedTopRouteMatchers: []string{`cluster="prod"`},
+		},
+	}
+	amcfg := &vmv1beta1.VMAlertmanagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "default"},
+		Spec: vmv1beta1.VMAlertmanagerConfigSpec{
+			Route: &vmv1beta1.Route{
+				Receiver: "default",
+				Routes: []*vmv1beta1.SubRoute{
+					{Receiver: "warnings", Matchers: []string{`severity=~"warning|critical"`}},
+					{Receiver: "critical", Matchers: []string{`severity="critical"`}},
+					{Receiver: "databases", Matchers: []string{`job="db"`}},
+				},
+			},
+			Receivers: []vmv1beta1.Receiver{{Name: "default"}, {Name: "warnings"}, {Name: "critical"}, {Name: "databases"}},
+		},
+		Status: vmv1beta1.VMAlertmanagerConfigStatus{
+			UnreachableRoutes: []vmv1beta1.UnreachableRoute{
+				{Alertmanager: "monitoring/other", Route: "route.routes[0]"},
+				{Alertmanager: "monitoring/main", Route: "route.routes[2]"},
+			},
+		},
+	}
+	fclient := k8stools.GetTestClientWithObjects(nil)
+	got, err := buildConfig(ctx, fclient, am, nil, []*vmv1beta1.VMAlertmanagerConfig{amcfg}, map[string]string{})
+	assert.NoError(t, err)
+	assert.Len(t, got.amcfgs, 1)
+	assert.Equal(t, []vmv1beta1.UnreachableRoute{
+		{
+			Alertmanager: "monitoring/main",
+			Route:        "route.routes[1]",
+			Labels:       map[string]string{"namespace": "default", "cluster": "prod", "severity": "critical"},
+			Receivers:    []string{"default-team-warnings"},
+		},
+	}, got.amcfgs[0].GetUnreachableRoutes("monitoring/main"))
+	// entries of other alertmanagers are kept
+	assert.Len(t, got.amcfgs[0].GetUnreachableRoutes("monitoring/other"), 1)
+}
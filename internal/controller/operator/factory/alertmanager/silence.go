@@ -0,0 +1,455 @@
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/build"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+)
+
+// silenceSyncInterval defines interval between checks of silences at alertmanager pods
+// it allows to recreate silences lost on pod restart or expired manually
+const silenceSyncInterval = time.Minute
+
+const silenceHTTPTimeout = 5 * time.Second
+
+// apiSilence represents silence of Alertmanager v2 API
+type apiSilence struct {
+	ID        string           `json:"id,omitempty"`
+	Matchers  []apiMatcher     `json:"matchers"`
+	StartsAt  time.Time        `json:"startsAt"`
+	EndsAt    time.Time        `json:"endsAt"`
+	CreatedBy string           `json:"createdBy"`
+	Comment   string           `json:"comment"`
+	Status    *apiSilenceState `json:"status,omitempty"`
+}
+
+type apiMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+type apiSilenceState struct {
+	State string `json:"state"`
+}
+
+func (s *apiSilence) state() string {
+	if s.Status == nil {
+		return ""
+	}
+	return s.Status.State
+}
+
+func (s *apiSilence) isExpired() bool {
+	return s.state() == vmv1beta1.SilenceStateExpired
+}
+
+// SilenceRequeueAfter returns interval for the next silence check
+// or zero if silence is expired
+func SilenceRequeueAfter(cr *vmv1beta1.VMAlertmanagerSilence) time.Duration {
+	now := time.Now()
+	if cr.StateAt(now) == vmv1beta1.SilenceStateExpired {
+		return 0
+	}
+	next := silenceSyncInterval
+	if startsIn := cr.GetStartsAt().Sub(now); startsIn > 0 {
+		next = min(next, startsIn)
+	}
+	return min(next, cr.Spec.EndsAt.Sub(now))
+}
+
+// buildSilence converts VMAlertmanagerSilence into Alertmanager API silence for the given alertmanager
+//
+// namespace matcher is enforced in the same way as for VMAlertmanagerConfig routes
+func buildSilence(cr *vmv1beta1.VMAlertmanagerSilence, am *vmv1beta1.VMAlertmanager) (*apiSilence, error) {
+	s := &apiSilence{
+		StartsAt:  cr.GetStartsAt(),
+		EndsAt:    cr.Spec.EndsAt.Time,
+		CreatedBy: cr.Spec.CreatedBy,
+		Comment:   cr.Spec.Comment,
+	}
+	addMatcher := func(m apiMatcher) {
+		if !slices.Contains(s.Matchers, m) {
+			s.Matchers = append(s.Matchers, m)
+		}
+	}
+	for _, src := range cr.Spec.Matchers {
+		m, err := labels.ParseMatcher(src)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse matcher=%q: %w", src, err)
+		}
+		addMatcher(apiMatcher{
+			Name:    m.Name,
+			Value:   m.Value,
+			IsRegex: m.Type == labels.MatchRegexp || m.Type == labels.MatchNotRegexp,
+			IsEqual: m.Type == labels.MatchEqual || m.Type == labels.MatchRegexp,
+		})
+	}
+	if !am.Spec.DisableNamespaceMatcher {
+		addMatcher(apiMatcher{Name: "namespace", Value: cr.Namespace, IsEqual: true})
+	}
+	return s, nil
+}
+
+// isSameSilence checks if existing silence matches desired one
+//
+// Alertmanager resets startsAt in the past to the silence creation time,
+// so it's compared only for pending silences
+func isSameSilence(existing, desired *apiSilence, now time.Time) bool {
+	if existing.CreatedBy != desired.CreatedBy || existing.Comment != desired.Comment || !existing.EndsAt.Equal(desired.EndsAt) {
+		return false
+	}
+	if desired.StartsAt.After(now) {
+		if !existing.StartsAt.Equal(desired.StartsAt) {
+			return false
+		}
+	} else if existing.StartsAt.After(now) {
+		return false
+	}
+	if len(existing.Matchers) != len(desired.Matchers) {
+		return false
+	}
+	for _, m := range desired.Matchers {
+		if !slices.Contains(existing.Matchers, m) {
+			return false
+		}
+	}
+	return true
+}
+
+// newSilenceHTTPClient returns http client for API of the given VMAlertmanager
+//
+// webConfig defines only server side settings, so pods are accessed with the same tls and auth settings as self-scrape,
+// which could be changed with serviceScrapeSpec, for instance to provide client certificate for mTLS or credentials.
+func newSilenceHTTPClient(ctx context.Context, rclient client.Client, am *vmv1beta1.VMAlertmanager) (*http.Client, error) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: am.PrefixedName(), Namespace: am.Namespace},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http"}}},
+	}
+	var auth vmv1beta1.EndpointAuth
+	for _, ep := range build.VMServiceScrapeForAlertmanager(svc, am).Spec.Endpoints {
+		if ep.Port == "http" {
+			auth = ep.EndpointAuth
+			break
+		}
+	}
+	hc, err := k8stools.NewHTTPClient(ctx, rclient, am.Namespace, &auth, silenceHTTPTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build http client for vmalertmanager=%s/%s: %w", am.Namespace, am.Name, err)
+	}
+	return hc, nil
+}
+
+func silencesURL(am *vmv1beta1.VMAlertmanager, pod *corev1.Pod) string {
+	return fmt.Sprintf("%s://%s:%s%s", strings.ToLower(am.ProbeScheme()), pod.Status.PodIP, am.Port(), path.Join("/", am.Spec.RoutePrefix, "/api/v2"))
+}
+
+func doSilenceRequest(ctx context.Context, hc *http.Client, method, url string, body any, dst any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("cannot marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("cannot read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &silenceAPIError{statusCode: resp.StatusCode, msg: strings.TrimSpace(string(data))}
+	}
+	if dst == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("cannot parse response: %w", err)
+	}
+	return nil
+}
+
+type silenceAPIError struct {
+	statusCode int
+	msg        string
+}
+
+// Error implements error interface
+func (e *silenceAPIError) Error() string {
+	return fmt.Sprintf("unexpected status code=%d: %s", e.statusCode, e.msg)
+}
+
+func listSilences(ctx context.Context, hc *http.Client, baseURL string) ([]apiSilence, error) {
+	var silences []apiSilence
+	if err := doSilenceRequest(ctx, hc, http.MethodGet, baseURL+"/silences", nil, &silences); err != nil {
+		return nil, fmt.Errorf("cannot list silences: %w", err)
+	}
+	return silences, nil
+}
+
+func postSilence(ctx context.Context, hc *http.Client, baseURL string, s *apiSilence) (string, error) {
+	var resp struct {
+		SilenceID string `json:"silenceID"`
+	}
+	if err := doSilenceRequest(ctx, hc, http.MethodPost, baseURL+"/silences", s, &resp); err != nil {
+		return "", fmt.Errorf("cannot post silence: %w", err)
+	}
+	return resp.SilenceID, nil
+}
+
+func expireSilence(ctx context.Context, hc *http.Client, baseURL, id string) error {
+	err := doSilenceRequest(ctx, hc, http.MethodDelete, baseURL+"/silence/"+id, nil, nil)
+	var ae *silenceAPIError
+	if errors.As(err, &ae) && ae.statusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot expire silence=%q: %w", id, err)
+	}
+	return nil
+}
+
+// syncPodSilence ensures that desired silence exists at alertmanager pod
+//
+// it reuses silence with knownID or any other silence with the same content, for instance replicated by peer,
+// and recreates silence if it was lost or expired before endsAt
+func syncPodSilence(ctx context.Context, hc *http.Client, baseURL string, desired *apiSilence, knownID string, now time.Time) (string, string, error) {
+	silences, err := listSilences(ctx, hc, baseURL)
+	if err != nil {
+		return knownID, "", err
+	}
+	var known, same *apiSilence
+	for i := range silences {
+		s := &silences[i]
+		if knownID != "" && s.ID == knownID {
+			known = s
+		}
+		if same == nil && !s.isExpired() && isSameSilence(s, desired, now) {
+			same = s
+		}
+	}
+	isKnownActive := known != nil && !known.isExpired()
+	desiredState := vmv1beta1.SilenceStateActive
+	if desired.StartsAt.After(now) {
+		desiredState = vmv1beta1.SilenceStatePending
+	}
+	switch {
+	case !desired.EndsAt.After(now):
+		// silence must be already expired
+		if isKnownActive {
+			if err := expireSilence(ctx, hc, baseURL, known.ID); err != nil {
+				return known.ID, known.state(), err
+			}
+		}
+		return knownID, vmv1beta1.SilenceStateExpired, nil
+	case same != nil:
+		if isKnownActive && known.ID != same.ID {
+			if err := expireSilence(ctx, hc, baseURL, known.ID); err != nil {
+				return known.ID, known.state(), err
+			}
+		}
+		return same.ID, same.state(), nil
+	case isKnownActive:
+		// silence was changed, Alertmanager either updates it in-place or replaces with a new one
+		toUpdate := *desired
+		toUpdate.ID = known.ID
+		id, err := postSilence(ctx, hc, baseURL, &toUpdate)
+		if err != nil {
+			return known.ID, known.state(), err
+		}
+		return id, desiredState, nil
+	default:
+		if knownID != "" {
+			logger.WithContext(ctx).Info(fmt.Sprintf("silence=%q is missing or expired at %s, recreating it", knownID, baseURL))
+		}
+		id, err := postSilence(ctx, hc, baseURL, desired)
+		if err != nil {
+			return knownID, "", err
+		}
+		return id, desiredState, nil
+	}
+}
+
+func listAlertmanagerPods(ctx context.Context, rclient client.Client, am *vmv1beta1.VMAlertmanager) ([]corev1.Pod, error) {
+	var pods corev1.PodList
+	if err := rclient.List(ctx, &pods, client.InNamespace(am.Namespace), client.MatchingLabels(am.SelectorLabels())); err != nil {
+		return nil, fmt.Errorf("cannot list pods of vmalertmanager=%s/%s: %w", am.Namespace, am.Name, err)
+	}
+	slices.SortFunc(pods.Items, func(a, b corev1.Pod) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return pods.Items, nil
+}
+
+func isPodAvailable(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "" && pod.DeletionTimestamp.IsZero()
+}
+
+// SyncSilence creates silence at all pods of the given VMAlertmanagers
+// and expires it at pods of VMAlertmanagers, which no longer select it
+//
+// result of sync is written into silence status
+func SyncSilence(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlertmanagerSilence, ams []*vmv1beta1.VMAlertmanager) error {
+	now := time.Now()
+	prevByPod := make(map[string]vmv1beta1.AlertmanagerPodSilence, len(cr.Status.Silences))
+	for _, ps := range cr.Status.Silences {
+		prevByPod[ps.Alertmanager+"/"+ps.Pod] = ps
+	}
+	var errs []error
+	var silences []vmv1beta1.AlertmanagerPodSilence
+	selected := make(map[string]struct{}, len(ams))
+	for _, am := range ams {
+		amKey := am.Namespace + "/" + am.Name
+		selected[amKey] = struct{}{}
+		desired, err := buildSilence(cr, am)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		pods, err := listAlertmanagerPods(ctx, rclient, am)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		hc, err := newSilenceHTTPClient(ctx, rclient, am)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for i := range pods {
+			pod := &pods[i]
+			prev, hasPrev := prevByPod[amKey+"/"+pod.Name]
+			if !isPodAvailable(pod) {
+				// keep silence id until pod is started
+				if hasPrev {
+					silences = append(silences, prev)
+				}
+				continue
+			}
+			id, state, err := syncPodSilence(ctx, hc, silencesURL(am, pod), desired, prev.ID, now)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("cannot sync silence at pod=%s of vmalertmanager=%s: %w", pod.Name, amKey, err))
+			}
+			if id == "" {
+				continue
+			}
+			silences = append(silences, vmv1beta1.AlertmanagerPodSilence{
+				Alertmanager: amKey,
+				Pod:          pod.Name,
+				ID:           id,
+				State:        state,
+			})
+		}
+	}
+	for _, prev := range cr.Status.Silences {
+		if _, ok := selected[prev.Alertmanager]; ok {
+			continue
+		}
+		if err := expirePodSilence(ctx, rclient, prev); err != nil {
+			errs = append(errs, err)
+			silences = append(silences, prev)
+		}
+	}
+	return updateSilenceStatus(ctx, rclient, cr, cr.StateAt(now), silences, errors.Join(errs...))
+}
+
+// DeleteSilence expires silence at all alertmanager pods, where it was created
+func DeleteSilence(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlertmanagerSilence) error {
+	var errs []error
+	for _, ps := range cr.Status.Silences {
+		if err := expirePodSilence(ctx, rclient, ps); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// expirePodSilence expires silence at alertmanager pod
+// missing alertmanager or pod is ignored, since silences are stored in memory of the pod
+func expirePodSilence(ctx context.Context, rclient client.Client, ps vmv1beta1.AlertmanagerPodSilence) error {
+	if ps.State == vmv1beta1.SilenceStateExpired {
+		return nil
+	}
+	namespace, name, ok := strings.Cut(ps.Alertmanager, "/")
+	if !ok {
+		return nil
+	}
+	var am vmv1beta1.VMAlertmanager
+	if err := rclient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &am); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("cannot get vmalertmanager=%s: %w", ps.Alertmanager, err)
+	}
+	var pod corev1.Pod
+	if err := rclient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ps.Pod}, &pod); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("cannot get pod=%s of vmalertmanager=%s: %w", ps.Pod, ps.Alertmanager, err)
+	}
+	if !isPodAvailable(&pod) {
+		return nil
+	}
+	hc, err := newSilenceHTTPClient(ctx, rclient, &am)
+	if err != nil {
+		return err
+	}
+	if err := expireSilence(ctx, hc, silencesURL(&am, &pod), ps.ID); err != nil {
+		return fmt.Errorf("cannot expire silence at pod=%s of vmalertmanager=%s: %w", ps.Pod, ps.Alertmanager, err)
+	}
+	return nil
+}
+
+func updateSilenceStatus(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlertmanagerSilence, state string, silences []vmv1beta1.AlertmanagerPodSilence, syncErr error) error {
+	prevStatus := cr.Status.DeepCopy()
+	cr.Status.State = state
+	cr.Status.Silences = silences
+	cr.Status.ObservedGeneration = cr.Generation
+	cr.Status.UpdateStatus = vmv1beta1.UpdateStatusOperational
+	cr.Status.Reason = ""
+	if syncErr != nil {
+		cr.Status.UpdateStatus = vmv1beta1.UpdateStatusFailed
+		cr.Status.Reason = syncErr.Error()
+	}
+	if !reflect.DeepEqual(prevStatus, &cr.Status) {
+		if err := rclient.Status().Update(ctx, cr); err != nil {
+			return fmt.Errorf("cannot update status of vmalertmanagersilence=%s/%s: %w", cr.Namespace, cr.Name, err)
+		}
+	}
+	return syncErr
+}
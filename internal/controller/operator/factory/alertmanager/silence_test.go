@@ -0,0 +1,259 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+// fakeSilencesAPI implements subset of Alertmanager v2 silences API
+type fakeSilencesAPI struct {
+	mu       sync.Mutex
+	silences map[string]*apiSilence
+	nextID   int
+}
+
+func (f *fakeSilencesAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v2/silences":
+		list := make([]apiSilence, 0, len(f.silences))
+		for _, s := range f.silences {
+			st := vmv1beta1.SilenceStateActive
+			switch {
+			case !s.EndsAt.After(now):
+				st = vmv1beta1.SilenceStateExpired
+			case s.StartsAt.After(now):
+				st = vmv1beta1.SilenceStatePending
+			}
+			item := *s
+			item.Status = &apiSilenceState{State: st}
+			list = append(list, item)
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v2/silences":
+		var s apiSilence
+		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if s.ID != "" {
+			prev, ok := f.silences[s.ID]
+			if !ok {
+				http.Error(w, "silence not found", http.StatusNotFound)
+				return
+			}
+			prev.EndsAt = now
+		}
+		if s.StartsAt.Before(now) {
+			s.StartsAt = now
+		}
+		f.nextID++
+		s.ID = fmt.Sprintf("silence-%d", f.nextID)
+		f.silences[s.ID] = &s
+		_ = json.NewEncoder(w).Encode(map[string]string{"silenceID": s.ID})
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/v2/silence/"):
+		s, ok := f.silences[strings.TrimPrefix(r.URL.Path, "/api/v2/silence/")]
+		if !ok {
+			http.Error(w, "silence not found", http.StatusNotFound)
+			return
+		}
+		s.EndsAt = now
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func (f *fakeSilencesAPI) active() []*apiSilence {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var active []*apiSilence
+	for _, s := range f.silences {
+		if s.EndsAt.After(time.Now()) {
+			active = append(active, s)
+		}
+	}
+	return active
+}
+
+func TestSyncSilence(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeSilencesAPI{silences: map[string]*apiSilence{}}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+	srvURL, err := url.Parse(srv.URL)
+	assert.NoError(t, err)
+
+	am := &vmv1beta1.VMAlertmanager{
+		ObjectMeta: metav1.ObjectMeta{Name: "am", Namespace: "monitoring"},
+		Spec: vmv1beta1.VMAlertmanagerSpec{
+			CommonDefaultableParams: vmv1beta1.CommonDefaultableParams{Port: srvURL.Port()},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "vmalertmanager-am-0", Namespace: "monitoring", Labels: am.SelectorLabels()},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: srvURL.Hostname()},
+	}
+	cr := &vmv1beta1.VMAlertmanagerSilence{
+		ObjectMeta: metav1.ObjectMeta{Name: "maintenance", Namespace: "team-a", CreationTimestamp: metav1.Now()},
+		Spec: vmv1beta1.VMAlertmanagerSilenceSpec{
+			Matchers:  []string{`alertname="HighLatency"`, `instance=~"db-.+"`},
+			EndsAt:    metav1.NewTime(time.Now().Add(time.Hour).Truncate(time.Second)),
+			Comment:   "database maintenance",
+			CreatedBy: "team-a",
+		},
+	}
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{am, pod, cr})
+
+	// silence is created with enforced namespace matcher
+	assert.NoError(t, SyncSilence(ctx, fclient, cr, []*vmv1beta1.VMAlertmanager{am}))
+	active := api.active()
+	assert.Len(t, active, 1)
+	assert.Equal(t, []apiMatcher{
+		{Name: "alertname", Value: "HighLatency", IsEqual: true},
+		{Name: "instance", Value: "db-.+", IsRegex: true, IsEqual: true},
+		{Name: "namespace", Value: "team-a", IsEqual: true},
+	}, active[0].Matchers)
+	assert.Equal(t, vmv1beta1.SilenceStateActive, cr.Status.State)
+	assert.Equal(t, []vmv1beta1.AlertmanagerPodSilence{
+		{Alertmanager: "monitoring/am", Pod: "vmalertmanager-am-0", ID: active[0].ID, State: vmv1beta1.SilenceStateActive},
+	}, cr.Status.Silences)
+	firstID := active[0].ID
+
+	// existing silence is reused
+	assert.NoError(t, SyncSilence(ctx, fclient, cr, []*vmv1beta1.VMAlertmanager{am}))
+	assert.Len(t, api.silences, 1)
+	assert.Equal(t, firstID, cr.Status.Silences[0].ID)
+
+	// silence expired before endsAt is recreated
+	assert.NoError(t, expireSilence(ctx, http.DefaultClient, silencesURL(am, pod), firstID))
+	assert.NoError(t, SyncSilence(ctx, fclient, cr, []*vmv1beta1.VMAlertmanager{am}))
+	active = api.active()
+	assert.Len(t, active, 1)
+	assert.NotEqual(t, firstID, active[0].ID)
+	assert.Equal(t, active[0].ID, cr.Status.Silences[0].ID)
+
+	// silence lost on restart is recreated
+	api.mu.Lock()
+	api.silences = map[string]*apiSilence{}
+	api.mu.Unlock()
+	assert.NoError(t, SyncSilence(ctx, fclient, cr, []*vmv1beta1.VMAlertmanager{am}))
+	assert.Len(t, api.active(), 1)
+
+	// changed silence replaces previous one
+	cr.Spec.Comment = "extended maintenance"
+	assert.NoError(t, SyncSilence(ctx, fclient, cr, []*vmv1beta1.VMAlertmanager{am}))
+	active = api.active()
+	assert.Len(t, active, 1)
+	assert.Equal(t, "extended maintenance", active[0].Comment)
+	assert.Equal(t, active[0].ID, cr.Status.Silences[0].ID)
+
+	// silence is expired at alertmanager, which no longer selects it
+	assert.NoError(t, SyncSilence(ctx, fclient, cr, nil))
+	assert.Empty(t, api.active())
+	assert.Empty(t, cr.Status.Silences)
+
+	// namespace matcher isn't enforced if disabled
+	am.Spec.DisableNamespaceMatcher = true
+	assert.NoError(t, SyncSilence(ctx, fclient, cr, []*vmv1beta1.VMAlertmanager{am}))
+	active = api.active()
+	assert.Len(t, active, 1)
+	assert.Len(t, active[0].Matchers, 2)
+
+	// deleted silence is expired
+	assert.NoError(t, DeleteSilence(ctx, fclient, cr))
+	assert.Empty(t, api.active())
+}
+
+func TestBuildSilence(t *testing.T) {
+	f := func(matchers []string, disableNamespaceMatcher bool, want []apiMatcher) {
+		t.Helper()
+		cr := &vmv1beta1.VMAlertmanagerSilence{
+			ObjectMeta: metav1.ObjectMeta{Name: "silence", Namespace: "default"},
+			Spec:       vmv1beta1.VMAlertmanagerSilenceSpec{Matchers: matchers},
+		}
+		am := &vmv1beta1.VMAlertmanager{Spec: vmv1beta1.VMAlertmanagerSpec{DisableNamespaceMatcher: disableNamespaceMatcher}}
+		got, err := buildSilence(cr, am)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got.Matchers)
+	}
+	f([]string{`job!="test"`, `instance!~"host-.*"`}, true, []apiMatcher{
+		{Name: "job", Value: "test"},
+		{Name: "instance", Value: "host-.*", IsRegex: true},
+	})
+	// duplicate namespace matcher isn't added
+	f([]string{`namespace="default"`}, false, []apiMatcher{
+		{Name: "namespace", Value: "default", IsEqual: true},
+	})
+	// namespace matcher can't be overridden by user
+	f([]string{`namespace=~".+"`}, false, []apiMatcher{
+		{Name: "namespace", Value: ".+", IsRegex: true, IsEqual: true},
+		{Name: "namespace", Value: "default", IsEqual: true},
+	})
+}
+
+func TestNewSilenceHTTPClient(t *testing.T) {
+	var gotUser, gotPassword string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, gotPassword, _ = r.BasicAuth()
+	}))
+	defer srv.Close()
+
+	am := &vmv1beta1.VMAlertmanager{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"},
+		Spec: vmv1beta1.VMAlertmanagerSpec{
+			WebConfig: &vmv1beta1.AlertmanagerWebConfig{TLSServerConfig: &vmv1beta1.TLSServerConfig{}},
+		},
+	}
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "am-auth", Namespace: "default"},
+			Data:       map[string][]byte{"user": []byte("operator"), "password": []byte("secret")},
+		},
+	})
+	ctx := context.Background()
+
+	// tls is enabled by webConfig
+	hc, err := newSilenceHTTPClient(ctx, fclient, am)
+	assert.NoError(t, err)
+	resp, err := hc.Get(srv.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Empty(t, gotUser)
+
+	// credentials are taken from serviceScrapeSpec
+	am.Spec.ServiceScrapeSpec = &vmv1beta1.VMServiceScrapeSpec{Endpoints: []vmv1beta1.Endpoint{{
+		Port: "http",
+		EndpointAuth: vmv1beta1.EndpointAuth{
+			TLSConfig: &vmv1beta1.TLSConfig{InsecureSkipVerify: true},
+			BasicAuth: &vmv1beta1.BasicAuth{
+				Username: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "am-auth"}, Key: "user"},
+				Password: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "am-auth"}, Key: "password"},
+			},
+		},
+	}}}
+	hc, err = newSilenceHTTPClient(ctx, fclient, am)
+	assert.NoError(t, err)
+	resp, err = hc.Get(srv.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "operator", gotUser)
+	assert.Equal(t, "secret", gotPassword)
+}
@@ -46,12 +46,12 @@ receivers:
 `
 )
 
-func newStsForAlertManager(cr *vmv1beta1.VMAlertmanager) (*appsv1.StatefulSet, error) {
+func newStsForAlertManager(cr *vmv1beta1.VMAlertmanager, peers []clusterPeer) (*appsv1.StatefulSet, error) {
 	if cr.Spec.Retention == "" {
 		cr.Spec.Retention = defaultRetention
 	}
 
-	spec, err := makeStatefulSetSpec(cr)
+	spec, err := makeStatefulSetSpec(cr, peers)
 	if err != nil {
 		return nil, err
 	}
@@ -144,7 +144,7 @@ func createOrUpdateAlertManagerService(ctx context.Context, rclient client.Clien
 	return newService, nil
 }
 
-func makeStatefulSetSpec(cr *vmv1beta1.VMAlertmanager) (*appsv1.StatefulSetSpec, error) {
+func makeStatefulSetSpec(cr *vmv1beta1.VMAlertmanager, peers []clusterPeer) (*appsv1.StatefulSetSpec, error) {
 
 	useVMConfigReloader := ptr.Deref(cr.Spec.UseVMConfigReloader, false)
 	image := fmt.Sprintf("%s:%s", cr.Spec.Image.Repository, cr.Spec.Image.Tag)
@@ -212,8 +212,11 @@ func makeStatefulSetSpec(cr *vmv1beta1.VMAlertmanager) (*appsv1.StatefulSetSpec,
 		amArgs = append(amArgs, fmt.Sprintf("--cluster.peer=%s-%d.%s:9094", cr.PrefixedName(), i, clusterPeerDomain))
 	}
 
-	for _, peer := range cr.Spec.AdditionalPeers {
-		amArgs = append(amArgs, fmt.Sprintf("--cluster.peer=%s", peer))
+	for _, peer := range peers {
+		if peer.address == "" {
+			continue
+		}
+		amArgs = append(amArgs, fmt.Sprintf("--cluster.peer=%s", peer.address))
 	}
 
 	ports := []corev1.ContainerPort{
@@ -446,7 +449,6 @@ func makeStatefulSetSpec(cr *vmv1beta1.VMAlertmanager) (*appsv1.StatefulSetSpec,
 // CreateOrUpdateConfig - check if secret with config exist,
 // if not create with predefined or user value.
 func CreateOrUpdateConfig(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlertmanager, childCR *vmv1beta1.VMAlertmanagerConfig) error {
-	l := logger.WithContext(ctx)
 	var prevCR *vmv1beta1.VMAlertmanager
 	if cr.ParsedLastAppliedSpec != nil {
 		prevCR = cr.DeepCopy()
@@ -457,26 +459,9 @@ func CreateOrUpdateConfig(ctx context.Context, rclient client.Client, cr *vmv1be
 	// e.g. namespace_secret_name_secret_key
 	tlsAssets := make(map[string]string)
 
-	var alertmananagerConfig []byte
-	switch {
-	// fetch content from user defined secret
-	case cr.Spec.ConfigSecret != "":
-		if cr.Spec.ConfigSecret == cr.ConfigSecretName() {
-			l.Info("ignoring content of ConfigSecret, "+
-				"since it has the same name as secreted created by operator for config",
-				"secretName", cr.Spec.ConfigSecret)
-		} else {
-			// retrieve content
-			secretContent, err := getSecretContentForAlertmanager(ctx, rclient, cr.Spec.ConfigSecret, cr.Namespace)
-			if err != nil {
-				return fmt.Errorf("cannot fetch secret content for alertmanager config secret, err: %w", err)
-			}
-			alertmananagerConfig = secretContent
-
-		}
-		// use in-line config
-	case cr.Spec.ConfigRawYaml != "":
-		alertmananagerConfig = []byte(cr.Spec.ConfigRawYaml)
+	alertmananagerConfig, err := getBaseConfig(ctx, rclient, cr)
+	if err != nil {
+		return err
 	}
 	mergedCfg, err := buildAlertmanagerConfigWithCRDs(ctx, rclient, cr, alertmananagerConfig, tlsAssets)
 	if err != nil {
@@ -498,17 +483,16 @@ func CreateOrUpdateConfig(ctx context.Context, rclient client.Client, cr *vmv1be
 		return fmt.Errorf("cannot build gossip config: %w", err)
 	}
 
-	// add templates from CR to alermanager config
-	if len(cr.Spec.Templates) > 0 {
-		templatePaths := make([]string, 0, len(cr.Spec.Templates))
-		for _, template := range cr.Spec.Templates {
-			templatePaths = append(templatePaths, path.Join(templatesDir, template.Name, template.Key))
-		}
-		mergedCfg, err := addConfigTemplates(alertmananagerConfig, templatePaths)
-		if err != nil {
-			return fmt.Errorf("cannot build alertmanager config with templates, err: %w", err)
-		}
-		alertmananagerConfig = mergedCfg
+	// broken templates are reported at status and do not block reconcile
+	// alertmanager keeps running with previous configuration until templates are fixed
+	templatesErr := ValidateTemplates(ctx, rclient, cr)
+	if templatesErr != nil {
+		logger.WithContext(ctx).Error(templatesErr, "VMAlertmanager templates are invalid")
+	}
+	writeTemplatesCondition(cr, templatesErr)
+	alertmananagerConfig, err = addCRTemplates(cr, alertmananagerConfig)
+	if err != nil {
+		return err
 	}
 
 	newAMSecretConfig := &corev1.Secret{
@@ -661,6 +645,46 @@ func buildVMAlertmanagerConfigReloader(cr *vmv1beta1.VMAlertmanager, crVolumeMou
 	return configReloaderContainer
 }
 
+// getBaseConfig returns user defined configuration of VMAlertmanager
+func getBaseConfig(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlertmanager) ([]byte, error) {
+	switch {
+	// fetch content from user defined secret
+	case cr.Spec.ConfigSecret != "":
+		if cr.Spec.ConfigSecret == cr.ConfigSecretName() {
+			logger.WithContext(ctx).Info("ignoring content of ConfigSecret, "+
+				"since it has the same name as secreted created by operator for config",
+				"secretName", cr.Spec.ConfigSecret)
+			return nil, nil
+		}
+		// retrieve content
+		secretContent, err := getSecretContentForAlertmanager(ctx, rclient, cr.Spec.ConfigSecret, cr.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("cannot fetch secret content for alertmanager config secret, err: %w", err)
+		}
+		return secretContent, nil
+		// use in-line config
+	case cr.Spec.ConfigRawYaml != "":
+		return []byte(cr.Spec.ConfigRawYaml), nil
+	}
+	return nil, nil
+}
+
+// addCRTemplates adds templates from CR to alermanager config
+func addCRTemplates(cr *vmv1beta1.VMAlertmanager, cfg []byte) ([]byte, error) {
+	if len(cr.Spec.Templates) == 0 {
+		return cfg, nil
+	}
+	templatePaths := make([]string, 0, len(cr.Spec.Templates))
+	for _, template := range cr.Spec.Templates {
+		templatePaths = append(templatePaths, path.Join(templatesDir, template.Name, template.Key))
+	}
+	mergedCfg, err := addConfigTemplates(cfg, templatePaths)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build alertmanager config with templates, err: %w", err)
+	}
+	return mergedCfg, nil
+}
+
 func getSecretContentForAlertmanager(ctx context.Context, rclient client.Client, secretName, ns string) ([]byte, error) {
 	var s corev1.Secret
 	if err := rclient.Get(ctx, types.NamespacedName{Namespace: ns, Name: secretName}, &s); err != nil {
@@ -678,6 +702,23 @@ func getSecretContentForAlertmanager(ctx context.Context, rclient client.Client,
 }
 
 func buildAlertmanagerConfigWithCRDs(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlertmanager, originConfig []byte, tlsAssets map[string]string) (*parsedConfig, error) {
+	amCfgs, badCfgs, namespacedNames, err := selectConfigs(ctx, rclient, cr)
+	if err != nil {
+		return nil, err
+	}
+	parsedCfg, err := buildConfig(ctx, rclient, cr, originConfig, amCfgs, tlsAssets)
+	if err != nil {
+		return nil, err
+	}
+	parsedCfg.brokenAMCfgs = append(parsedCfg.brokenAMCfgs, badCfgs...)
+	logger.SelectedObjects(ctx, "VMAlertmanagerConfigs", len(parsedCfg.amcfgs), len(parsedCfg.brokenAMCfgs), namespacedNames)
+	badConfigsTotal.Add(float64(len(badCfgs)))
+	return parsedCfg, nil
+}
+
+// selectConfigs returns VMAlertmanagerConfigs selected by VMAlertmanager
+// configs, which cannot be parsed or validated, are returned as bad configs
+func selectConfigs(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlertmanager) ([]*vmv1beta1.VMAlertmanagerConfig, []*vmv1beta1.VMAlertmanagerConfig, []string, error) {
 	var amCfgs []*vmv1beta1.VMAlertmanagerConfig
 	var badCfgs []*vmv1beta1.VMAlertmanagerConfig
 	var namespacedNames []string
@@ -706,17 +747,9 @@ func buildAlertmanagerConfigWithCRDs(ctx context.Context, rclient client.Client,
 				amCfgs = append(amCfgs, &item)
 			}
 		}); err != nil {
-		return nil, fmt.Errorf("cannot select alertmanager configs: %w", err)
-	}
-
-	parsedCfg, err := buildConfig(ctx, rclient, cr, originConfig, amCfgs, tlsAssets)
-	if err != nil {
-		return nil, err
+		return nil, nil, nil, fmt.Errorf("cannot select alertmanager configs: %w", err)
 	}
-	parsedCfg.brokenAMCfgs = append(parsedCfg.brokenAMCfgs, badCfgs...)
-	logger.SelectedObjects(ctx, "VMAlertmanagerConfigs", len(parsedCfg.amcfgs), len(parsedCfg.brokenAMCfgs), namespacedNames)
-	badConfigsTotal.Add(float64(len(badCfgs)))
-	return parsedCfg, nil
+	return amCfgs, badCfgs, namespacedNames, nil
 }
 
 func subPathForStorage(s *vmv1beta1.StorageSpec) string {
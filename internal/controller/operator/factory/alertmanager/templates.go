@@ -0,0 +1,217 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	htmltemplate "html/template"
+	"path"
+	"regexp"
+	"strings"
+	texttemplate "text/template"
+
+	amtemplate "github.com/prometheus/alertmanager/template"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+// templateNameRe matches template names at define, template and block actions
+var templateNameRe = regexp.MustCompile(`(\{\{-?\s*(?:define|template|block)\s+)"([^"]+)"`)
+
+// parseTemplate parses given text with alertmanager template engine
+// and returns text template with defined templates
+func parseTemplate(text string) (*texttemplate.Template, error) {
+	var tt *texttemplate.Template
+	t, err := amtemplate.New(func(text *texttemplate.Template, _ *htmltemplate.Template) {
+		tt = text
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Parse(strings.NewReader(text)); err != nil {
+		return nil, err
+	}
+	return tt, nil
+}
+
+// configTemplates contains notification templates of VMAlertmanagerConfig
+// with names prefixed by namespace and name of config
+type configTemplates struct {
+	prefix  func(string) string
+	names   map[string]struct{}
+	content string
+}
+
+// loadConfigTemplates fetches and parses templates of the given VMAlertmanagerConfig
+func loadConfigTemplates(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlertmanagerConfig, cmCache map[string]*corev1.ConfigMap) (*configTemplates, error) {
+	ct := &configTemplates{
+		prefix: func(name string) string { return buildCRPrefixedName(cr, name) },
+		names:  map[string]struct{}{},
+	}
+	if len(cr.Spec.Templates) == 0 {
+		return ct, nil
+	}
+	contents := make([]string, 0, len(cr.Spec.Templates))
+	for _, t := range cr.Spec.Templates {
+		content := t.Content
+		if t.ConfigMap != nil {
+			var err error
+			content, err = k8stools.GetCredFromConfigMap(ctx, rclient, cr.Namespace, *t.ConfigMap, fmt.Sprintf("%s/%s", cr.Namespace, t.ConfigMap.Name), cmCache)
+			if err != nil {
+				return nil, fmt.Errorf("cannot load template=%q: %w", t.Name, err)
+			}
+		}
+		tt, err := parseTemplate(content)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse template=%q: %w", t.Name, err)
+		}
+		for _, dt := range tt.Templates() {
+			if dt.Name() == "" {
+				continue
+			}
+			ct.names[dt.Name()] = struct{}{}
+		}
+		contents = append(contents, content)
+	}
+	ct.content = ct.rename(strings.Join(contents, "\n"))
+	// check for clashes between templates of the same config
+	if _, err := parseTemplate(ct.content); err != nil {
+		return nil, fmt.Errorf("cannot parse templates: %w", err)
+	}
+	return ct, nil
+}
+
+// rename adds prefix to names of templates defined by config
+func (ct *configTemplates) rename(text string) string {
+	if len(ct.names) == 0 || !strings.Contains(text, "{{") {
+		return text
+	}
+	return templateNameRe.ReplaceAllStringFunc(text, func(s string) string {
+		m := templateNameRe.FindStringSubmatch(s)
+		if _, ok := ct.names[m[2]]; !ok {
+			return s
+		}
+		return m[1] + `"` + ct.prefix(m[2]) + `"`
+	})
+}
+
+// applyToReceiver renames references to config templates at receiver fields
+// and checks that templated fields could be parsed
+func (ct *configTemplates) applyToReceiver(receiver yaml.MapSlice) (yaml.MapSlice, error) {
+	var err error
+	var walk func(v any) any
+	walk = func(v any) any {
+		switch v := v.(type) {
+		case string:
+			if !strings.Contains(v, "{{") {
+				return v
+			}
+			v = ct.rename(v)
+			if _, perr := parseTemplate(v); perr != nil && err == nil {
+				err = perr
+			}
+			return v
+		case yaml.MapSlice:
+			for i := range v {
+				v[i].Value = walk(v[i].Value)
+			}
+			return v
+		case []yaml.MapSlice:
+			for i := range v {
+				v[i] = walk(v[i]).(yaml.MapSlice)
+			}
+			return v
+		case []any:
+			for i := range v {
+				v[i] = walk(v[i])
+			}
+			return v
+		case []string:
+			for i := range v {
+				v[i] = walk(v[i]).(string)
+			}
+			return v
+		case map[string]string:
+			for k, s := range v {
+				v[k] = walk(s).(string)
+			}
+			return v
+		}
+		return v
+	}
+	receiver = walk(receiver).(yaml.MapSlice)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse template at receiver fields: %w", err)
+	}
+	return receiver, nil
+}
+
+// assetKey returns name of config secret key for templates
+// content hash changes alertmanager configuration in order to trigger config reload
+func (ct *configTemplates) assetKey(cr *vmv1beta1.VMAlertmanagerConfig) string {
+	h := fnv.New32a()
+	h.Write([]byte(ct.content))
+	return fmt.Sprintf("templates_%s_%s_%x.tmpl", cr.Namespace, cr.Name, h.Sum32())
+}
+
+// ErrTemplateNotLoaded is returned by ValidateTemplates if template content cannot be fetched
+var ErrTemplateNotLoaded = errors.New("cannot load template")
+
+// ValidateTemplates checks that templates referenced by VMAlertmanager could be parsed
+func ValidateTemplates(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlertmanager) error {
+	cmCache := make(map[string]*corev1.ConfigMap)
+	for _, t := range cr.Spec.Templates {
+		sel := corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: t.Name}, Key: t.Key}
+		content, err := k8stools.GetCredFromConfigMap(ctx, rclient, cr.Namespace, sel, fmt.Sprintf("%s/%s", cr.Namespace, t.Name), cmCache)
+		if err != nil {
+			return fmt.Errorf("%w=%q: %w", ErrTemplateNotLoaded, path.Join(t.Name, t.Key), err)
+		}
+		if _, err := parseTemplate(content); err != nil {
+			return fmt.Errorf("cannot parse template=%q: %w", path.Join(t.Name, t.Key), err)
+		}
+	}
+	return nil
+}
+
+// writeTemplatesCondition reports result of templates validation at InvalidTemplates status condition
+//
+// condition is added only for invalid templates and is set to False after templates are fixed
+func writeTemplatesCondition(cr *vmv1beta1.VMAlertmanager, templatesErr error) {
+	now := metav1.Now()
+	cond := vmv1beta1.Condition{
+		Type:               vmv1beta1.ConditionInvalidTemplates,
+		Status:             metav1.ConditionTrue,
+		Reason:             "TemplatesParseFailed",
+		ObservedGeneration: cr.Generation,
+		LastTransitionTime: now,
+		LastUpdateTime:     now,
+	}
+	if templatesErr != nil {
+		cond.Message = templatesErr.Error()
+	} else {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "TemplatesParsed"
+	}
+	for i, c := range cr.Status.Conditions {
+		if c.Type != cond.Type {
+			continue
+		}
+		if c.Status == cond.Status && c.Message == cond.Message && c.ObservedGeneration == cond.ObservedGeneration {
+			return
+		}
+		if c.Status == cond.Status {
+			cond.LastTransitionTime = c.LastTransitionTime
+		}
+		cr.Status.Conditions[i] = cond
+		return
+	}
+	if templatesErr != nil {
+		cr.Status.Conditions = append(cr.Status.Conditions, cond)
+	}
+}
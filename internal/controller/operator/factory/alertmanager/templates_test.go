@@ -0,0 +1,167 @@
+package alertmanager
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func TestBuildConfigWithTemplates(t *testing.T) {
+	ctx := context.Background()
+	newConfig := func(templates []vmv1beta1.NotificationTemplate, text string) *vmv1beta1.VMAlertmanagerConfig {
+		return &vmv1beta1.VMAlertmanagerConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "team-a"},
+			Spec: vmv1beta1.VMAlertmanagerConfigSpec{
+				Route: &vmv1beta1.Route{Receiver: "slack"},
+				Receivers: []vmv1beta1.Receiver{{
+					Name: "slack",
+					SlackConfigs: []vmv1beta1.SlackConfig{{
+						APIURL: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "slack"},
+							Key:                  "url",
+						},
+						Text:  text,
+						Title: `{{ template "slack.default.title" . }}`,
+					}},
+				}},
+				Templates: templates,
+			},
+		}
+	}
+	predefined := []runtime.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "team-a"},
+			Data:       map[string][]byte{"url": []byte("http://slack.example.com")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "templates", Namespace: "team-a"},
+			Data:       map[string]string{"footer.tmpl": `{{ define "footer" }}team-a{{ end }}`},
+		},
+	}
+	f := func(amc *vmv1beta1.VMAlertmanagerConfig, wantText string, wantTemplates map[string]string, wantErr string) {
+		t.Helper()
+		fclient := k8stools.GetTestClientWithObjects(predefined)
+		tlsAssets := map[string]string{}
+		got, err := buildConfig(ctx, fclient, &vmv1beta1.VMAlertmanager{}, nil, []*vmv1beta1.VMAlertmanagerConfig{amc}, tlsAssets)
+		assert.NoError(t, err)
+		if wantErr != "" {
+			assert.Len(t, got.brokenAMCfgs, 1)
+			assert.Contains(t, amc.Status.CurrentSyncError, wantErr)
+			return
+		}
+		assert.Empty(t, got.brokenAMCfgs)
+		var cfg struct {
+			Templates []string `yaml:"templates"`
+			Receivers []struct {
+				SlackConfigs []struct {
+					Text  string `yaml:"text"`
+					Title string `yaml:"title"`
+				} `yaml:"slack_configs"`
+			} `yaml:"receivers"`
+		}
+		assert.NoError(t, yaml.Unmarshal(got.data, &cfg))
+		assert.Equal(t, wantText, cfg.Receivers[1].SlackConfigs[0].Text)
+		// references to global templates are kept as is
+		assert.Equal(t, `{{ template "slack.default.title" . }}`, cfg.Receivers[1].SlackConfigs[0].Title)
+		var wantPaths []string
+		for key, content := range wantTemplates {
+			wantPaths = append(wantPaths, tlsAssetsDir+"/"+key)
+			assert.Equal(t, content, tlsAssets[key])
+		}
+		assert.ElementsMatch(t, wantPaths, cfg.Templates)
+	}
+
+	// without templates
+	f(newConfig(nil, `{{ template "text" . }}`), `{{ template "text" . }}`, nil, "")
+
+	// inline and configmap templates are prefixed
+	f(newConfig([]vmv1beta1.NotificationTemplate{
+		{Name: "text", Content: `{{ define "text" }}{{ .CommonLabels.alertname }} {{ template "footer" . }}{{ end }}`},
+		{Name: "footer", ConfigMap: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "templates"},
+			Key:                  "footer.tmpl",
+		}},
+	}, `{{ template "text" . }}`), `{{ template "team-a-slack-text" . }}`, map[string]string{
+		"templates_team-a_slack_e1d0119b.tmpl": `{{ define "team-a-slack-text" }}{{ .CommonLabels.alertname }} {{ template "team-a-slack-footer" . }}{{ end }}
+{{ define "team-a-slack-footer" }}team-a{{ end }}`,
+	}, "")
+
+	// broken template
+	f(newConfig([]vmv1beta1.NotificationTemplate{
+		{Name: "text", Content: `{{ define "text" }}{{ .CommonLabels.alertname }}`},
+	}, `{{ template "text" . }}`), "", nil, `cannot parse template="text"`)
+
+	// unknown function
+	f(newConfig([]vmv1beta1.NotificationTemplate{
+		{Name: "text", Content: `{{ define "text" }}{{ .CommonLabels.alertname | unknownFunc }}{{ end }}`},
+	}, `{{ template "text" . }}`), "", nil, `function "unknownFunc" not defined`)
+
+	// broken receiver field
+	f(newConfig(nil, `{{ .CommonLabels.alertname`), "", nil, "cannot parse template at receiver fields")
+
+	// missing configmap key
+	f(newConfig([]vmv1beta1.NotificationTemplate{
+		{Name: "missing", ConfigMap: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "templates"},
+			Key:                  "missing.tmpl",
+		}},
+	}, "text"), "", nil, `cannot load template="missing"`)
+}
+
+func TestValidateTemplates(t *testing.T) {
+	f := func(content string, wantErr bool) {
+		t.Helper()
+		cr := &vmv1beta1.VMAlertmanager{
+			ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"},
+			Spec: vmv1beta1.VMAlertmanagerSpec{
+				Templates: []vmv1beta1.ConfigMapKeyReference{{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "templates"},
+					Key:                  "main.tmpl",
+				}},
+			},
+		}
+		fclient := k8stools.GetTestClientWithObjects([]runtime.Object{&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "templates", Namespace: "default"},
+			Data:       map[string]string{"main.tmpl": content},
+		}})
+		err := ValidateTemplates(context.Background(), fclient, cr)
+		if wantErr {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+	f(`{{ define "main" }}{{ .Status | toUpper }}{{ end }}`, false)
+	f(`{{ define "main" }}{{ .CommonLabels.Values | join ", " | reReplaceAll "\\s+" " " }}{{ 3600 | humanizeDuration }}{{ end }}`, false)
+	f(`{{ define "main" }}{{ .Status | toUpper }}`, true)
+	f(`{{ define "main" }}{{ .Status | toJSON }}{{ end }}`, true)
+}
+
+func TestWriteTemplatesCondition(t *testing.T) {
+	cr := &vmv1beta1.VMAlertmanager{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+
+	// valid templates do not add condition
+	writeTemplatesCondition(cr, nil)
+	assert.Empty(t, cr.Status.Conditions)
+
+	writeTemplatesCondition(cr, fmt.Errorf("cannot parse template"))
+	assert.Len(t, cr.Status.Conditions, 1)
+	assert.Equal(t, vmv1beta1.ConditionInvalidTemplates, cr.Status.Conditions[0].Type)
+	assert.Equal(t, metav1.ConditionTrue, cr.Status.Conditions[0].Status)
+	assert.Equal(t, "cannot parse template", cr.Status.Conditions[0].Message)
+
+	// fixed templates reset condition
+	writeTemplatesCondition(cr, nil)
+	assert.Len(t, cr.Status.Conditions, 1)
+	assert.Equal(t, metav1.ConditionFalse, cr.Status.Conditions[0].Status)
+	assert.Empty(t, cr.Status.Conditions[0].Message)
+}
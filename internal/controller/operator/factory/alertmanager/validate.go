@@ -0,0 +1,266 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	amcfg "github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+)
+
+const (
+	configValidationCacheTTL  = time.Minute
+	configValidationCacheSize = 1000
+)
+
+var configValidationCacheRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "operator_vmalertmanagerconfig_validation_cache_requests_total",
+	Help: "Number of lookups of VMAlertmanagerConfig full configuration validation results at cache",
+}, []string{"result"})
+
+func init() {
+	metrics.Registry.MustRegister(configValidationCacheRequestsTotal)
+}
+
+// configValidationCache holds results of merged configuration validation
+//
+// it allows to skip config parsing for repeated admission requests,
+// which are common for GitOps tools and dry-run requests
+var configValidationCache = &validationCache{entries: map[uint64]validationResult{}}
+
+type validationCache struct {
+	mu      sync.Mutex
+	entries map[uint64]validationResult
+}
+
+type validationResult struct {
+	err       error
+	expiresAt time.Time
+}
+
+func (vc *validationCache) get(key uint64) (validationResult, bool) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	r, ok := vc.entries[key]
+	if !ok || time.Now().After(r.expiresAt) {
+		configValidationCacheRequestsTotal.WithLabelValues("miss").Inc()
+		return r, false
+	}
+	configValidationCacheRequestsTotal.WithLabelValues("hit").Inc()
+	return r, true
+}
+
+func (vc *validationCache) set(key uint64, err error) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	now := time.Now()
+	if len(vc.entries) >= configValidationCacheSize {
+		for k, r := range vc.entries {
+			if now.After(r.expiresAt) {
+				delete(vc.entries, k)
+			}
+		}
+		// drop all entries if there are no expired entries, cache will be filled again
+		if len(vc.entries) >= configValidationCacheSize {
+			vc.entries = map[uint64]validationResult{}
+		}
+	}
+	vc.entries[key] = validationResult{err: err, expiresAt: now.Add(configValidationCacheTTL)}
+}
+
+// ValidateConfigForAlertmanagers renders given VMAlertmanagerConfig into merged configuration
+// of each VMAlertmanager, which selects it, and loads result with alertmanager config parser
+func ValidateConfigForAlertmanagers(ctx context.Context, rclient client.Client, amc *vmv1beta1.VMAlertmanagerConfig) error {
+	var ams vmv1beta1.VMAlertmanagerList
+	if err := k8stools.ListObjectsByNamespace(ctx, rclient, nil, func(l *vmv1beta1.VMAlertmanagerList) {
+		ams.Items = append(ams.Items, l.Items...)
+	}); err != nil {
+		return fmt.Errorf("cannot list vmalertmanagers: %w", err)
+	}
+	for i := range ams.Items {
+		am := &ams.Items[i]
+		if !am.DeletionTimestamp.IsZero() || am.Spec.ParsingError != "" {
+			continue
+		}
+		ok, err := k8stools.IsObjectSelected(ctx, rclient, am.Spec.ConfigNamespaceSelector, am.Spec.ConfigSelector, am.Namespace, am.Spec.SelectAllByDefault, amc)
+		if err != nil {
+			return fmt.Errorf("cannot check selectors of vmalertmanager=%s/%s: %w", am.Namespace, am.Name, err)
+		}
+		if !ok {
+			continue
+		}
+		if err := validateConfigForAlertmanager(ctx, rclient, am, amc); err != nil {
+			return fmt.Errorf("config breaks vmalertmanager=%s/%s: %w", am.Namespace, am.Name, err)
+		}
+	}
+	return nil
+}
+
+func validateConfigForAlertmanager(ctx context.Context, rclient client.Client, am *vmv1beta1.VMAlertmanager, amc *vmv1beta1.VMAlertmanagerConfig) error {
+	baseCfg, err := getBaseConfig(ctx, rclient, am)
+	if err != nil {
+		return err
+	}
+	amCfgs, _, _, err := selectConfigs(ctx, rclient, am)
+	if err != nil {
+		return err
+	}
+	// replace stored version of config with validated one
+	cfgs := make([]*vmv1beta1.VMAlertmanagerConfig, 0, len(amCfgs)+1)
+	for _, cfg := range amCfgs {
+		if cfg.Namespace == amc.Namespace && cfg.Name == amc.Name {
+			continue
+		}
+		cfgs = append(cfgs, cfg)
+	}
+	target := amc.DeepCopy()
+	cfgs = append(cfgs, target)
+	return renderAndLoadConfig(ctx, rclient, am, baseCfg, target, cfgs)
+}
+
+// renderAndLoadConfig builds merged configuration with target config and loads it with alertmanager config parser
+//
+// if merged configuration cannot be loaded, configuration without target is loaded as well
+// and error is returned only if target introduces it, e.g. baseline configuration has no error or error is different. It allows to update configs, while configuration
+// is already broken by VMAlertmanager spec or other configs.
+func renderAndLoadConfig(ctx context.Context, rclient client.Client, am *vmv1beta1.VMAlertmanager, baseCfg []byte, target *vmv1beta1.VMAlertmanagerConfig, cfgs []*vmv1beta1.VMAlertmanagerConfig) error {
+	baselineCfgs := make([]*vmv1beta1.VMAlertmanagerConfig, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg != target {
+			baselineCfgs = append(baselineCfgs, cfg.DeepCopy())
+		}
+	}
+	parsedCfg, err := buildConfig(ctx, rclient, am, baseCfg, cfgs, map[string]string{})
+	if err != nil {
+		return fmt.Errorf("cannot build config: %w", err)
+	}
+	for _, broken := range parsedCfg.brokenAMCfgs {
+		if broken == target {
+			return fmt.Errorf("cannot build config: %s", target.Status.CurrentSyncError)
+		}
+	}
+	// rendered configuration includes values of referenced secrets and all selected configs
+	key, err := validationCacheKey(am, target, parsedCfg.data)
+	if err != nil {
+		return err
+	}
+	if r, ok := configValidationCache.get(key); ok {
+		return r.err
+	}
+	err = loadAndCompareWithBaseline(ctx, rclient, am, baseCfg, target, baselineCfgs, parsedCfg.data)
+	configValidationCache.set(key, err)
+	return err
+}
+
+func loadAndCompareWithBaseline(ctx context.Context, rclient client.Client, am *vmv1beta1.VMAlertmanager, baseCfg []byte, target *vmv1beta1.VMAlertmanagerConfig, baselineCfgs []*vmv1beta1.VMAlertmanagerConfig, data []byte) error {
+	loadErr := loadMergedConfig(am, data)
+	if loadErr == nil {
+		return nil
+	}
+	baselineCfg, err := buildConfig(ctx, rclient, am, baseCfg, baselineCfgs, map[string]string{})
+	if err != nil {
+		return fmt.Errorf("merged configuration is invalid: %w", loadErr)
+	}
+	if err := loadMergedConfig(am, baselineCfg.data); err != nil && err.Error() == loadErr.Error() {
+		logger.WithContext(ctx).Info(fmt.Sprintf("skipping validation of VMAlertmanagerConfig=%s, configuration of vmalertmanager=%s/%s is already invalid: %s", target.AsKey(), am.Namespace, am.Name, err))
+		return nil
+	}
+	return fmt.Errorf("merged configuration is invalid: %w", loadErr)
+}
+
+func loadMergedConfig(am *vmv1beta1.VMAlertmanager, data []byte) error {
+	data, err := addCRTemplates(am, data)
+	if err != nil {
+		return err
+	}
+	_, err = loadConfig(data)
+	return err
+}
+
+// receiverSectionsUnknownToParser contains receiver integrations,
+// which are supported by newer alertmanager versions, but not by the alertmanager config parser used by operator
+var receiverSectionsUnknownToParser = map[string]struct{}{
+	"incidentio_configs": {},
+}
+
+// loadConfig parses given alertmanager configuration
+// integrations unknown to the parser are skipped, they're validated by CRD webhook only
+func loadConfig(data []byte) (*amcfg.Config, error) {
+	var cfg yaml.MapSlice
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	var stripped bool
+	for i, item := range cfg {
+		if item.Key != "receivers" {
+			continue
+		}
+		receivers, ok := item.Value.([]any)
+		if !ok {
+			break
+		}
+		for j, recv := range receivers {
+			recvCfg, ok := recv.(yaml.MapSlice)
+			if !ok {
+				continue
+			}
+			filtered := recvCfg[:0:0]
+			for _, section := range recvCfg {
+				if key, ok := section.Key.(string); ok {
+					if _, ok := receiverSectionsUnknownToParser[key]; ok {
+						stripped = true
+						continue
+					}
+				}
+				filtered = append(filtered, section)
+			}
+			receivers[j] = filtered
+		}
+		cfg[i].Value = receivers
+	}
+	if stripped {
+		var err error
+		data, err = yaml.Marshal(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("BUG: cannot marshal config: %w", err)
+		}
+	}
+	return amcfg.Load(string(data))
+}
+
+// validationCacheKey builds cache key from VMAlertmanager spec, validated config spec and rendered configuration
+//
+// rendered configuration reflects values of referenced secrets and configs selected by current namespace labels,
+// so changes of them invalidate cached result
+func validationCacheKey(am *vmv1beta1.VMAlertmanager, target *vmv1beta1.VMAlertmanagerConfig, renderedCfg []byte) (uint64, error) {
+	data, err := json.Marshal(struct {
+		Alertmanager string
+		Spec         *vmv1beta1.VMAlertmanagerSpec
+		Target       string
+		TargetSpec   *vmv1beta1.VMAlertmanagerConfigSpec
+		Rendered     []byte
+	}{
+		Alertmanager: am.Namespace + "/" + am.Name,
+		Spec:         &am.Spec,
+		Target:       target.AsKey(),
+		TargetSpec:   &target.Spec,
+		Rendered:     renderedCfg,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cannot build validation cache key: %w", err)
+	}
+	h := fnv.New64a()
+	h.Write(data)
+	return h.Sum64(), nil
+}
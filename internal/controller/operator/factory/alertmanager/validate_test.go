@@ -0,0 +1,102 @@
+package alertmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func TestValidateConfigForAlertmanagers(t *testing.T) {
+	ctx := context.Background()
+	newConfig := func(name, receiver string) *vmv1beta1.VMAlertmanagerConfig {
+		return &vmv1beta1.VMAlertmanagerConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team", Generation: 1},
+			Spec: vmv1beta1.VMAlertmanagerConfigSpec{
+				Route: &vmv1beta1.Route{Receiver: receiver},
+				Receivers: []vmv1beta1.Receiver{{
+					Name: receiver,
+					WebhookConfigs: []vmv1beta1.WebhookConfig{{
+						URL: ptr.To("http://example.com"),
+					}},
+				}},
+			},
+		}
+	}
+	am := &vmv1beta1.VMAlertmanager{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"},
+		Spec:       vmv1beta1.VMAlertmanagerSpec{SelectAllByDefault: true},
+	}
+	notSelecting := &vmv1beta1.VMAlertmanager{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "monitoring"},
+	}
+	// name team-a-b-c collides with receiver of validated config
+	existing := newConfig("a-b", "c")
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{am, notSelecting, existing})
+
+	assert.NoError(t, ValidateConfigForAlertmanagers(ctx, fclient, newConfig("a", "receiver")))
+	// update of existing config replaces stored version
+	assert.NoError(t, ValidateConfigForAlertmanagers(ctx, fclient, newConfig("a-b", "c")))
+
+	err := ValidateConfigForAlertmanagers(ctx, fclient, newConfig("a", "b-c"))
+	assert.ErrorContains(t, err, "vmalertmanager=monitoring/main")
+	assert.ErrorContains(t, err, `notification config name "team-a-b-c" is not unique`)
+
+	// cached result is returned
+	entries := len(configValidationCache.entries)
+	assert.Error(t, ValidateConfigForAlertmanagers(ctx, fclient, newConfig("a", "b-c")))
+	assert.Len(t, configValidationCache.entries, entries)
+
+	// changes of rendered configuration invalidate cache
+	k1, err := validationCacheKey(am, newConfig("a", "b-c"), []byte(`receivers: [{name: a}]`))
+	assert.NoError(t, err)
+	k2, err := validationCacheKey(am, newConfig("a", "b-c"), []byte(`receivers: [{name: b}]`))
+	assert.NoError(t, err)
+	assert.NotEqual(t, k1, k2)
+
+	// configuration is already broken by other configs
+	brokenClient := k8stools.GetTestClientWithObjects([]runtime.Object{am, existing, newConfig("a", "b-c")})
+	assert.NoError(t, ValidateConfigForAlertmanagers(ctx, brokenClient, newConfig("z", "receiver")))
+	// target is the cause of error
+	assert.Error(t, ValidateConfigForAlertmanagers(ctx, brokenClient, newConfig("a", "b-c")))
+}
+
+func TestLoadConfig(t *testing.T) {
+	f := func(data string, wantReceivers int, wantErr bool) {
+		t.Helper()
+		cfg, err := loadConfig([]byte(data))
+		if wantErr {
+			assert.Error(t, err)
+			return
+		}
+		assert.NoError(t, err)
+		assert.Len(t, cfg.Receivers, wantReceivers)
+	}
+
+	// integrations unknown to parser are skipped
+	f(`route:
+  receiver: blackhole
+receivers:
+- name: blackhole
+- name: incidentio
+  incidentio_configs:
+  - url: https://api.incident.io/v2/alert_events/http/source-id
+  webhook_configs:
+  - url: http://example.com
+`, 2, false)
+
+	// unknown fields are still rejected
+	f(`route:
+  receiver: blackhole
+receivers:
+- name: blackhole
+  unknown_configs:
+  - url: http://example.com
+`, 0, true)
+}
@@ -0,0 +1,132 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+)
+
+// RemoteTarget contains url and credentials resolved from RemoteTargetRef
+type RemoteTarget struct {
+	// URL is a base url of remote target without API path suffix
+	URL               string
+	BasicAuth         *vmv1beta1.BasicAuth
+	BearerTokenSecret *corev1.SecretKeySelector
+	// TLSConfig is set for remote target served with https, if its certificate could be resolved
+	TLSConfig *vmv1beta1.TLSConfig
+}
+
+// TLSConfigFor returns tls config for the remote target
+//
+// tls config defined by user has priority over resolved one.
+// It returns error for https target without resolved certificate,
+// since credentials must not be sent over unverified connection.
+func (rt *RemoteTarget) TLSConfigFor(tlsConfig *vmv1beta1.TLSConfig) (*vmv1beta1.TLSConfig, error) {
+	if tlsConfig != nil {
+		return tlsConfig, nil
+	}
+	if strings.HasPrefix(rt.URL, "https://") && rt.TLSConfig == nil {
+		return nil, fmt.Errorf("cannot resolve certificate of https target=%q, tlsConfig must be defined explicitly", rt.URL)
+	}
+	return rt.TLSConfig, nil
+}
+
+// ResolveRemoteTargetRef fetches object referenced by ref and returns its url for data ingestion if forWrite is set or for querying otherwise
+//
+// VMCluster url points to vminsert or vmselect prometheus API with tenant prefix.
+// VMUser referenced by VMAuth ref must be located at the given namespace,
+// since its credentials Secret is mounted to the current object.
+// TLSConfig is returned for https targets with resolvable certificate, see remoteTargetTLSConfig.
+func ResolveRemoteTargetRef(ctx context.Context, rclient client.Client, ref *vmv1beta1.RemoteTargetRef, namespace string, forWrite bool) (*RemoteTarget, error) {
+	nsn := types.NamespacedName{Namespace: ref.NamespaceOrDefault(namespace), Name: ref.Name}
+	var rt RemoteTarget
+	switch ref.Kind {
+	case "VMSingle":
+		var vmsingle vmv1beta1.VMSingle
+		if err := rclient.Get(ctx, nsn, &vmsingle); err != nil {
+			return nil, fmt.Errorf("cannot get VMSingle=%s: %w", nsn.String(), err)
+		}
+		rt.URL = vmsingle.AsURL()
+		rt.TLSConfig = remoteTargetTLSConfig(rt.URL, &vmsingle.Spec.CommonApplicationDeploymentParams, nsn.Namespace == namespace)
+	case "VMCluster":
+		var vmcluster vmv1beta1.VMCluster
+		if err := rclient.Get(ctx, nsn, &vmcluster); err != nil {
+			return nil, fmt.Errorf("cannot get VMCluster=%s: %w", nsn.String(), err)
+		}
+		if forWrite {
+			if vmcluster.Spec.VMInsert == nil {
+				return nil, fmt.Errorf("VMCluster=%s has no vminsert component", nsn.String())
+			}
+			rt.URL = fmt.Sprintf("%s/insert/%s/prometheus", vmcluster.VMInsertURL(), ref.Tenant())
+			rt.TLSConfig = remoteTargetTLSConfig(rt.URL, &vmcluster.Spec.VMInsert.CommonApplicationDeploymentParams, nsn.Namespace == namespace)
+		} else {
+			if vmcluster.Spec.VMSelect == nil {
+				return nil, fmt.Errorf("VMCluster=%s has no vmselect component", nsn.String())
+			}
+			rt.URL = fmt.Sprintf("%s/select/%s/prometheus", vmcluster.VMSelectURL(), ref.Tenant())
+			rt.TLSConfig = remoteTargetTLSConfig(rt.URL, &vmcluster.Spec.VMSelect.CommonApplicationDeploymentParams, nsn.Namespace == namespace)
+		}
+	case "VMAuth":
+		var vmauth vmv1beta1.VMAuth
+		if err := rclient.Get(ctx, nsn, &vmauth); err != nil {
+			return nil, fmt.Errorf("cannot get VMAuth=%s: %w", nsn.String(), err)
+		}
+		rt.URL = vmauth.AsURL()
+		rt.TLSConfig = remoteTargetTLSConfig(rt.URL, &vmauth.Spec.CommonApplicationDeploymentParams, nsn.Namespace == namespace)
+		if ref.User == "" {
+			break
+		}
+		var vmuser vmv1beta1.VMUser
+		if err := rclient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.User}, &vmuser); err != nil {
+			return nil, fmt.Errorf("cannot get VMUser=%s/%s: %w", namespace, ref.User, err)
+		}
+		secretRef := corev1.LocalObjectReference{Name: vmuser.SecretName()}
+		switch {
+		case vmuser.Spec.TokenRef != nil:
+			rt.BearerTokenSecret = vmuser.Spec.TokenRef.DeepCopy()
+		case vmuser.Spec.BearerToken != nil:
+			rt.BearerTokenSecret = &corev1.SecretKeySelector{LocalObjectReference: secretRef, Key: "bearerToken"}
+		default:
+			rt.BasicAuth = &vmv1beta1.BasicAuth{
+				Username: corev1.SecretKeySelector{LocalObjectReference: secretRef, Key: "username"},
+				Password: corev1.SecretKeySelector{LocalObjectReference: secretRef, Key: "password"},
+			}
+			if vmuser.Spec.PasswordRef != nil {
+				rt.BasicAuth.Password = *vmuser.Spec.PasswordRef.DeepCopy()
+			}
+		}
+	default:
+		return nil, fmt.Errorf("BUG: unsupported kind=%q for remote target ref", ref.Kind)
+	}
+	return &rt, nil
+}
+
+// remoteTargetTLSConfig returns tls config for the given target url if it uses https
+//
+// Target certificate defined with tlsCertFile flag and mounted from spec.secrets is trusted,
+// if target is located at the same namespace as the current object, since Secret must be mounted to it.
+// Otherwise nil is returned and tls config must be defined by user.
+func remoteTargetTLSConfig(url string, params *vmv1beta1.CommonApplicationDeploymentParams, sameNamespace bool) *vmv1beta1.TLSConfig {
+	if !strings.HasPrefix(url, "https://") {
+		return nil
+	}
+	if sameNamespace {
+		rel, ok := strings.CutPrefix(path.Clean(params.ExtraArgs["tlsCertFile"]), vmv1beta1.SecretsDir+"/")
+		if name, key, ok2 := strings.Cut(rel, "/"); ok && ok2 && !strings.Contains(key, "/") && slices.Contains(params.Secrets, name) {
+			return &vmv1beta1.TLSConfig{
+				CA: vmv1beta1.SecretOrConfigMap{
+					Secret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key},
+				},
+			}
+		}
+	}
+	return nil
+}
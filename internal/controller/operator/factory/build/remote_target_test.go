@@ -0,0 +1,196 @@
+package build
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func TestResolveRemoteTargetRef(t *testing.T) {
+	type opts struct {
+		ref               *vmv1beta1.RemoteTargetRef
+		forWrite          bool
+		predefinedObjects []runtime.Object
+		want              *RemoteTarget
+		wantErr           bool
+	}
+	f := func(o opts) {
+		t.Helper()
+		fclient := k8stools.GetTestClientWithObjects(o.predefinedObjects)
+		got, err := ResolveRemoteTargetRef(context.Background(), fclient, o.ref, "default", o.forWrite)
+		if o.wantErr {
+			assert.Error(t, err)
+			return
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, o.want, got)
+	}
+
+	// vmsingle at the same namespace
+	f(opts{
+		ref: &vmv1beta1.RemoteTargetRef{Kind: "VMSingle", Name: "main"},
+		predefinedObjects: []runtime.Object{
+			&vmv1beta1.VMSingle{ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"}},
+		},
+		forWrite: true,
+		want:     &RemoteTarget{URL: "http://vmsingle-main.default.svc:8429"},
+	})
+
+	// missing object
+	f(opts{
+		ref:     &vmv1beta1.RemoteTargetRef{Kind: "VMSingle", Name: "main", Namespace: "monitoring"},
+		wantErr: true,
+	})
+
+	vmcluster := &vmv1beta1.VMCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"},
+		Spec: vmv1beta1.VMClusterSpec{
+			VMInsert: &vmv1beta1.VMInsert{},
+			VMSelect: &vmv1beta1.VMSelect{
+				CommonApplicationDeploymentParams: vmv1beta1.CommonApplicationDeploymentParams{
+					ExtraArgs: map[string]string{"tls": "true"},
+				},
+			},
+		},
+	}
+	// vmcluster write with tenant
+	f(opts{
+		ref:               &vmv1beta1.RemoteTargetRef{Kind: "VMCluster", Name: "main", Namespace: "monitoring", TenantID: "10:5"},
+		predefinedObjects: []runtime.Object{vmcluster},
+		forWrite:          true,
+		want:              &RemoteTarget{URL: "http://vminsert-main.monitoring.svc:8480/insert/10:5/prometheus"},
+	})
+
+	// vmcluster read with default tenant
+	f(opts{
+		ref:               &vmv1beta1.RemoteTargetRef{Kind: "VMCluster", Name: "main", Namespace: "monitoring"},
+		predefinedObjects: []runtime.Object{vmcluster},
+		want:              &RemoteTarget{URL: "https://vmselect-main.monitoring.svc:8481/select/0/prometheus"},
+	})
+
+	// vmcluster without vminsert
+	f(opts{
+		ref: &vmv1beta1.RemoteTargetRef{Kind: "VMCluster", Name: "main"},
+		predefinedObjects: []runtime.Object{
+			&vmv1beta1.VMCluster{ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"}},
+		},
+		forWrite: true,
+		wantErr:  true,
+	})
+
+	// vmauth with vmuser basic auth
+	f(opts{
+		ref: &vmv1beta1.RemoteTargetRef{Kind: "VMAuth", Name: "main", Namespace: "monitoring", User: "agent"},
+		predefinedObjects: []runtime.Object{
+			&vmv1beta1.VMAuth{ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"}},
+			&vmv1beta1.VMUser{
+				ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+				Spec:       vmv1beta1.VMUserSpec{UserName: ptr.To("agent")},
+			},
+		},
+		forWrite: true,
+		want: &RemoteTarget{
+			URL: "http://vmauth-main.monitoring.svc:8427",
+			BasicAuth: &vmv1beta1.BasicAuth{
+				Username: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "vmuser-agent"}, Key: "username"},
+				Password: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "vmuser-agent"}, Key: "password"},
+			},
+		},
+	})
+
+	// vmauth with vmuser bearer token
+	f(opts{
+		ref: &vmv1beta1.RemoteTargetRef{Kind: "VMAuth", Name: "main", User: "agent"},
+		predefinedObjects: []runtime.Object{
+			&vmv1beta1.VMAuth{
+				ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"},
+				Spec:       vmv1beta1.VMAuthSpec{CommonDefaultableParams: vmv1beta1.CommonDefaultableParams{Port: "8080"}},
+			},
+			&vmv1beta1.VMUser{
+				ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+				Spec:       vmv1beta1.VMUserSpec{BearerToken: ptr.To("token")},
+			},
+		},
+		want: &RemoteTarget{
+			URL:               "http://vmauth-main.default.svc:8080",
+			BearerTokenSecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "vmuser-agent"}, Key: "bearerToken"},
+		},
+	})
+
+	// https vmauth with certificate mounted from secrets
+	f(opts{
+		ref: &vmv1beta1.RemoteTargetRef{Kind: "VMAuth", Name: "main"},
+		predefinedObjects: []runtime.Object{
+			&vmv1beta1.VMAuth{
+				ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"},
+				Spec: vmv1beta1.VMAuthSpec{
+					CommonApplicationDeploymentParams: vmv1beta1.CommonApplicationDeploymentParams{
+						Secrets:   []string{"vmauth-tls"},
+						ExtraArgs: map[string]string{"tls": "true", "tlsCertFile": "/etc/vm/secrets/vmauth-tls/tls.crt"},
+					},
+				},
+			},
+		},
+		want: &RemoteTarget{
+			URL: "https://vmauth-main.default.svc:8427",
+			TLSConfig: &vmv1beta1.TLSConfig{CA: vmv1beta1.SecretOrConfigMap{
+				Secret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "vmauth-tls"}, Key: "tls.crt"},
+			}},
+		},
+	})
+
+	// https vmsingle with certificate outside of secrets
+	f(opts{
+		ref: &vmv1beta1.RemoteTargetRef{Kind: "VMSingle", Name: "main"},
+		predefinedObjects: []runtime.Object{
+			&vmv1beta1.VMSingle{
+				ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"},
+				Spec: vmv1beta1.VMSingleSpec{
+					CommonApplicationDeploymentParams: vmv1beta1.CommonApplicationDeploymentParams{
+						ExtraArgs: map[string]string{"tls": "true", "tlsCertFile": "/etc/certs/tls.crt"},
+					},
+				},
+			},
+		},
+		forWrite: true,
+		want:     &RemoteTarget{URL: "https://vmsingle-main.default.svc:8429"},
+	})
+}
+
+func TestRemoteTargetTLSConfigFor(t *testing.T) {
+	f := func(rt *RemoteTarget, tlsConfig, want *vmv1beta1.TLSConfig, wantErr bool) {
+		t.Helper()
+		got, err := rt.TLSConfigFor(tlsConfig)
+		if wantErr {
+			assert.Error(t, err)
+			return
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	ca := &vmv1beta1.TLSConfig{CAFile: "/etc/ca.crt"}
+	resolved := &vmv1beta1.TLSConfig{CA: vmv1beta1.SecretOrConfigMap{
+		Secret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "tls"}, Key: "tls.crt"},
+	}}
+
+	// http target
+	f(&RemoteTarget{URL: "http://vmsingle-main.default.svc:8429"}, nil, nil, false)
+
+	// https target with resolved certificate
+	f(&RemoteTarget{URL: "https://vmsingle-main.default.svc:8429", TLSConfig: resolved}, nil, resolved, false)
+
+	// user defined tls config has priority
+	f(&RemoteTarget{URL: "https://vmsingle-main.default.svc:8429", TLSConfig: resolved}, ca, ca, false)
+	f(&RemoteTarget{URL: "https://vmsingle-main.default.svc:8429"}, ca, ca, false)
+
+	// https target without resolved certificate
+	f(&RemoteTarget{URL: "https://vmsingle-main.default.svc:8429"}, nil, nil, true)
+}
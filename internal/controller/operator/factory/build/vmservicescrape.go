@@ -212,9 +212,11 @@ func VMPodScrapeForObjectWithSpec(psb podScrapeBuilder, serviceScrapeSpec *vmv1b
 	if serviceScrapeSpec != nil {
 		for _, ssEP := range serviceScrapeSpec.Endpoints {
 			if ssEP.Port == *defaultEP.Port {
-				defaultEP.EndpointAuth = ssEP.EndpointAuth
-				defaultEP.EndpointScrapeParams = ssEP.EndpointScrapeParams
-				defaultEP.EndpointRelabelings = ssEP.EndpointRelabelings
+				// default endpoint is already added to the spec
+				ep := &podScrape.Spec.PodMetricsEndpoints[0]
+				ep.EndpointAuth = ssEP.EndpointAuth
+				ep.EndpointScrapeParams = ssEP.EndpointScrapeParams
+				ep.EndpointRelabelings = ssEP.EndpointRelabelings
 				continue
 			}
 			podScrape.Spec.PodMetricsEndpoints = append(podScrape.Spec.PodMetricsEndpoints, vmv1beta1.PodMetricsEndpoint{
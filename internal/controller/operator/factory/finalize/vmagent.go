@@ -45,6 +45,10 @@ func OnVMAgentDelete(ctx context.Context, rclient client.Client, crd *vmv1beta1.
 		return err
 	}
 
+	if err := removeFinalizeObjByName(ctx, rclient, &corev1.Secret{}, crd.CanaryConfigSecretName(), crd.Namespace); err != nil {
+		return err
+	}
+
 	// check secret for tls assests
 	if err := removeFinalizeObjByName(ctx, rclient, &corev1.Secret{}, crd.TLSAssetName(), crd.Namespace); err != nil {
 		return err
@@ -0,0 +1,171 @@
+package k8stools
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+)
+
+// NewHTTPClient returns http client for component API with tls and auth settings of the given endpoint
+//
+// Secrets and ConfigMaps are loaded from the given namespace.
+// File based settings cannot be used, since files are located at component container.
+// Pods are accessed by IP, which usually isn't present at certificate,
+// so if serverName isn't set only certificate chain is verified.
+func NewHTTPClient(ctx context.Context, rclient client.Client, ns string, auth *vmv1beta1.EndpointAuth, timeout time.Duration) (*http.Client, error) {
+	tlsCfg, err := loadTLSConfig(ctx, rclient, ns, auth.TLSConfig)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load tlsConfig: %w", err)
+	}
+	rt := &authRoundTripper{origin: &http.Transport{TLSClientConfig: tlsCfg}}
+	secretCache := make(map[string]*corev1.Secret)
+	switch {
+	case auth.OAuth2 != nil:
+		return nil, fmt.Errorf("oauth2 cannot be used by operator http client")
+	case auth.BearerTokenFile != "":
+		return nil, fmt.Errorf("bearerTokenFile=%q cannot be used by operator http client, use bearerTokenSecret instead", auth.BearerTokenFile)
+	case auth.BearerTokenSecret != nil:
+		token, err := GetCredFromSecret(ctx, rclient, ns, auth.BearerTokenSecret, buildCacheKey(ns, auth.BearerTokenSecret.Name), secretCache)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load bearerTokenSecret: %w", err)
+		}
+		rt.authorization = "Bearer " + token
+	case auth.Authorization != nil:
+		if auth.Authorization.Credentials == nil {
+			return nil, fmt.Errorf("authorization.credentialsFile cannot be used by operator http client, use authorization.credentials instead")
+		}
+		creds, err := GetCredFromSecret(ctx, rclient, ns, auth.Authorization.Credentials, buildCacheKey(ns, auth.Authorization.Credentials.Name), secretCache)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load authorization credentials: %w", err)
+		}
+		authType := auth.Authorization.Type
+		if authType == "" {
+			authType = "Bearer"
+		}
+		rt.authorization = authType + " " + creds
+	case auth.BasicAuth != nil:
+		if auth.BasicAuth.PasswordFile != "" {
+			return nil, fmt.Errorf("basicAuth.passwordFile cannot be used by operator http client, use basicAuth.password instead")
+		}
+		creds, err := LoadBasicAuthSecret(ctx, rclient, ns, auth.BasicAuth, secretCache)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load basicAuth: %w", err)
+		}
+		rt.username, rt.password = creds.Username, creds.Password
+	}
+	return &http.Client{Timeout: timeout, Transport: rt}, nil
+}
+
+func loadTLSConfig(ctx context.Context, rclient client.Client, ns string, cfg *vmv1beta1.TLSConfig) (*tls.Config, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if cfg.CAFile != "" || cfg.CertFile != "" || cfg.KeyFile != "" {
+		return nil, fmt.Errorf("caFile, certFile and keyFile cannot be used by operator http client, use ca, cert and keySecret instead")
+	}
+	tlsCfg := &tls.Config{
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	secretCache := make(map[string]*corev1.Secret)
+	cmCache := make(map[string]*corev1.ConfigMap)
+	if cfg.Cert.PrefixedName() != "" || cfg.KeySecret != nil {
+		if cfg.KeySecret == nil {
+			return nil, fmt.Errorf("keySecret must be set with cert")
+		}
+		cert, err := loadSecretOrConfigMap(ctx, rclient, ns, &cfg.Cert, secretCache, cmCache)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load cert: %w", err)
+		}
+		key, err := GetCredFromSecret(ctx, rclient, ns, cfg.KeySecret, buildCacheKey(ns, cfg.KeySecret.Name), secretCache)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load keySecret: %w", err)
+		}
+		pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{pair}
+	}
+	if cfg.CA.PrefixedName() == "" || cfg.InsecureSkipVerify {
+		return tlsCfg, nil
+	}
+	ca, err := loadSecretOrConfigMap(ctx, rclient, ns, &cfg.CA, secretCache, cmCache)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load ca: %w", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(ca)) {
+		return nil, fmt.Errorf("cannot parse ca certificate")
+	}
+	tlsCfg.RootCAs = roots
+	if cfg.ServerName == "" {
+		// verify only certificate chain
+		tlsCfg.InsecureSkipVerify = true
+		tlsCfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyCertificateChain(rawCerts, roots)
+		}
+	}
+	return tlsCfg, nil
+}
+
+func verifyCertificateChain(rawCerts [][]byte, roots *x509.CertPool) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("server didn't provide certificate")
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("cannot parse server certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(opts)
+	return err
+}
+
+func loadSecretOrConfigMap(ctx context.Context, rclient client.Client, ns string, src *vmv1beta1.SecretOrConfigMap, secretCache map[string]*corev1.Secret, cmCache map[string]*corev1.ConfigMap) (string, error) {
+	if src.Secret != nil {
+		return GetCredFromSecret(ctx, rclient, ns, src.Secret, buildCacheKey(ns, src.Secret.Name), secretCache)
+	}
+	return GetCredFromConfigMap(ctx, rclient, ns, *src.ConfigMap, buildCacheKey(ns, src.ConfigMap.Name), cmCache)
+}
+
+// authRoundTripper adds authorization to requests
+type authRoundTripper struct {
+	origin        http.RoundTripper
+	authorization string
+	username      string
+	password      string
+}
+
+// RoundTrip implements http.RoundTripper interface
+func (rt *authRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rt.authorization == "" && rt.username == "" {
+		return rt.origin.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	switch {
+	case rt.authorization != "":
+		req.Header.Set("Authorization", rt.authorization)
+	default:
+		req.SetBasicAuth(rt.username, rt.password)
+	}
+	return rt.origin.RoundTrip(req)
+}
@@ -0,0 +1,107 @@
+package k8stools
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	// httptest servers share the same certificate, so generate an unrelated one
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "other-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	otherCert, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	otherCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: otherCert})
+
+	predefinedObjects := []runtime.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "auth", Namespace: "default"},
+			Data: map[string][]byte{
+				"ca":       serverCA,
+				"other-ca": otherCA,
+				"user":     []byte("admin"),
+				"password": []byte("secret"),
+				"token":    []byte("token"),
+			},
+		},
+	}
+	secretKey := func(key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "auth"}, Key: key}
+	}
+	f := func(auth *vmv1beta1.EndpointAuth, wantAuth string, wantBuildErr, wantRequestErr bool) {
+		t.Helper()
+		gotAuth = ""
+		fclient := GetTestClientWithObjects(predefinedObjects)
+		hc, err := NewHTTPClient(context.Background(), fclient, "default", auth, time.Second)
+		if wantBuildErr {
+			assert.Error(t, err)
+			return
+		}
+		assert.NoError(t, err)
+		resp, err := hc.Get(srv.URL)
+		if wantRequestErr {
+			assert.Error(t, err)
+			return
+		}
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, wantAuth, gotAuth)
+	}
+
+	// unknown certificate
+	f(&vmv1beta1.EndpointAuth{}, "", false, true)
+
+	// insecure
+	f(&vmv1beta1.EndpointAuth{TLSConfig: &vmv1beta1.TLSConfig{InsecureSkipVerify: true}}, "", false, false)
+
+	// certificate chain is verified with ca
+	f(&vmv1beta1.EndpointAuth{
+		TLSConfig: &vmv1beta1.TLSConfig{CA: vmv1beta1.SecretOrConfigMap{Secret: secretKey("ca")}},
+		BasicAuth: &vmv1beta1.BasicAuth{Username: *secretKey("user"), Password: *secretKey("password")},
+	}, "Basic YWRtaW46c2VjcmV0", false, false)
+	f(&vmv1beta1.EndpointAuth{
+		TLSConfig: &vmv1beta1.TLSConfig{CA: vmv1beta1.SecretOrConfigMap{Secret: secretKey("other-ca")}},
+	}, "", false, true)
+
+	// bearer token
+	f(&vmv1beta1.EndpointAuth{
+		TLSConfig:         &vmv1beta1.TLSConfig{CA: vmv1beta1.SecretOrConfigMap{Secret: secretKey("ca")}},
+		BearerTokenSecret: secretKey("token"),
+	}, "Bearer token", false, false)
+
+	// file based settings
+	f(&vmv1beta1.EndpointAuth{TLSConfig: &vmv1beta1.TLSConfig{CAFile: "/etc/ca.crt"}}, "", true, false)
+	f(&vmv1beta1.EndpointAuth{BearerTokenFile: "/var/run/token"}, "", true, false)
+}
@@ -3,6 +3,7 @@ package k8stools
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/VictoriaMetrics/operator/internal/config"
 	v1 "k8s.io/api/core/v1"
@@ -20,10 +21,51 @@ func VisitObjectsForSelectorsAtNs[T any, PT interface {
 	nsSelector, objectSelector *metav1.LabelSelector,
 	objNamespace string, selectAllByDefault bool, cb func(PT),
 ) error {
+	namespaces, ok, err := namespacesForSelectors(ctx, rclient, nsSelector, objectSelector, objNamespace, selectAllByDefault)
+	if err != nil || !ok {
+		return err
+	}
+	objLabelSelector, err := objectLabelSelector(objectSelector)
+	if err != nil {
+		return err
+	}
+	// namespaces could still be empty if nsSelector&objectSelector are nil and selectAllByDefault=true, and it's ok
+	return ListObjectsByNamespace(ctx, rclient, namespaces, cb, &client.ListOptions{LabelSelector: objLabelSelector})
+}
+
+// IsObjectSelected checks if given object is matched by VisitObjectsForSelectorsAtNs with the same selectors
+//
+// It allows to check objects, which are not persisted yet
+func IsObjectSelected(ctx context.Context, rclient client.Client,
+	nsSelector, objectSelector *metav1.LabelSelector,
+	objNamespace string, selectAllByDefault bool, obj client.Object,
+) (bool, error) {
+	namespaces, ok, err := namespacesForSelectors(ctx, rclient, nsSelector, objectSelector, objNamespace, selectAllByDefault)
+	if err != nil || !ok {
+		return false, err
+	}
+	// empty namespaces means all namespaces
+	if len(namespaces) > 0 && !slices.Contains(namespaces, obj.GetNamespace()) {
+		return false, nil
+	}
+	objLabelSelector, err := objectLabelSelector(objectSelector)
+	if err != nil {
+		return false, err
+	}
+	return objLabelSelector.Matches(labels.Set(obj.GetLabels())), nil
+}
+
+// namespacesForSelectors returns namespaces to select objects from
+//
+// empty namespaces list means all namespaces, false is returned if selectors cannot match any object
+func namespacesForSelectors(ctx context.Context, rclient client.Client,
+	nsSelector, objectSelector *metav1.LabelSelector,
+	objNamespace string, selectAllByDefault bool,
+) ([]string, bool, error) {
 	watchNS := config.MustGetWatchNamespaces()
 	// fast path, empty selectors and cannot select all by default
 	if nsSelector == nil && objectSelector == nil && !selectAllByDefault {
-		return nil
+		return nil, false, nil
 	}
 	var namespaces []string
 	// list namespaces matched by  namespaceselector
@@ -42,28 +84,30 @@ func VisitObjectsForSelectorsAtNs[T any, PT interface {
 		// perform a cluster wide request for namespaces with given filters
 		nsSelector, err := metav1.LabelSelectorAsSelector(nsSelector)
 		if err != nil {
-			return fmt.Errorf("cannot convert selector: %w", err)
+			return nil, false, fmt.Errorf("cannot convert selector: %w", err)
 		}
 		namespaces, err = SelectNamespaces(ctx, rclient, nsSelector)
 		if err != nil {
-			return fmt.Errorf("cannot select namespaces for  match: %w", err)
+			return nil, false, fmt.Errorf("cannot select namespaces for  match: %w", err)
 		}
 		// if nsSelector is specified and no match, return directly
 		if len(namespaces) == 0 {
-			return nil
+			return nil, false, nil
 		}
 	}
+	return namespaces, true, nil
+}
 
+func objectLabelSelector(objectSelector *metav1.LabelSelector) (labels.Selector, error) {
 	// if userSelector is nil, we must set it to catch all values
 	if objectSelector == nil {
 		objectSelector = &metav1.LabelSelector{}
 	}
 	objLabelSelector, err := metav1.LabelSelectorAsSelector(objectSelector)
 	if err != nil {
-		return fmt.Errorf("cannot convert  to Selector: %w", err)
+		return nil, fmt.Errorf("cannot convert  to Selector: %w", err)
 	}
-	// namespaces could still be empty if nsSelector&objectSelector are nil and selectAllByDefault=true, and it's ok
-	return ListObjectsByNamespace(ctx, rclient, namespaces, cb, &client.ListOptions{LabelSelector: objLabelSelector})
+	return objLabelSelector, nil
 }
 
 // SelectNamespaces select namespaces by given label selector
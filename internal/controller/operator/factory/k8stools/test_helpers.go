@@ -9,6 +9,7 @@ import (
 
 	"github.com/go-test/deep"
 	appsv1 "k8s.io/api/apps/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,7 +35,11 @@ func testGetScheme() *runtime.Scheme {
 		&vmv1beta1.VMUserList{},
 		&vmv1beta1.VMAuthList{},
 		&vmv1beta1.VMAlertmanagerConfigList{},
+		&vmv1beta1.VMAlertmanagerSilenceList{},
 		&vmv1beta1.VMScrapeConfigList{},
+		&vmv1beta1.VMScrapeDefaultsList{},
+		&vmv1beta1.VMClusterScrapeDefaultsList{},
+		&vmv1beta1.VMRolloutPolicyList{},
 		&vmv1beta1.VMClusterList{},
 		&vmv1beta1.VLogsList{},
 	)
@@ -53,10 +58,18 @@ func testGetScheme() *runtime.Scheme {
 		&vmv1beta1.VMUser{},
 		&vmv1beta1.VMAuth{},
 		&vmv1beta1.VMAlertmanagerConfig{},
+		&vmv1beta1.VMAlertmanagerSilence{},
 		&vmv1beta1.VMScrapeConfig{},
+		&vmv1beta1.VMScrapeDefaults{},
+		&vmv1beta1.VMClusterScrapeDefaults{},
+		&vmv1beta1.VMRolloutPolicy{},
 		&vmv1beta1.VMCluster{},
 		&vmv1beta1.VLogs{},
 	)
+	s.AddKnownTypes(apiextensionsv1.SchemeGroupVersion,
+		&apiextensionsv1.CustomResourceDefinition{},
+		&apiextensionsv1.CustomResourceDefinitionList{},
+	)
 	return s
 }
 
@@ -82,8 +95,13 @@ func GetTestClientWithObjects(predefinedObjects []runtime.Object) client.Client
 			&vmv1beta1.VMPodScrape{},
 			&vmv1beta1.VMProbe{},
 			&vmv1beta1.VMScrapeConfig{},
+			&vmv1beta1.VMScrapeDefaults{},
+			&vmv1beta1.VMClusterScrapeDefaults{},
+			&vmv1beta1.VMRolloutPolicy{},
+			&vmv1beta1.VMAlertmanagerSilence{},
 			&vmv1beta1.VMStaticScrape{},
 			&vmv1beta1.VMNodeScrape{},
+			&apiextensionsv1.CustomResourceDefinition{},
 		).
 		WithObjects(obj...).Build()
 	withStats := TestClientWithStatsTrack{
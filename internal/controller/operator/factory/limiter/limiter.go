@@ -1,49 +1,310 @@
 package limiter
 
 import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+)
+
+// objects with full per-object bucket and without pending reconcile
+// are removed from limiter state with this interval
+const gcInterval = time.Minute
+
+var (
+	throttledEventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "operator_reconcile_throttled_events_total",
+		Help: "number of throttled reconciliation events",
+	}, []string{"controller", "namespaced_name"})
+
+	registryMu sync.Mutex
+	registry   = map[string]*RateLimiter{}
 )
 
-// RateLimiter limits reconcile callback calls
+func init() {
+	metrics.Registry.MustRegister(throttledEventsTotal)
+}
+
+// Config defines token bucket settings of controller rate limiter
+type Config struct {
+	// Rate is a number of reconciles per second shared by all objects of controller
+	Rate float64
+	// Burst is a max number of reconciles performed at once by all objects of controller
+	Burst float64
+	// ObjectRate is a number of reconciles per second for a single object
+	ObjectRate float64
+	// ObjectBurst is a max number of reconciles performed at once by a single object
+	ObjectBurst float64
+}
+
+// DefaultConfig allows 5 reconciles per 2 seconds for controller
+// and 1 reconcile per 2 seconds for a single object after burst of 2 reconciles
+var DefaultConfig = Config{Rate: 2.5, Burst: 5, ObjectRate: 0.5, ObjectBurst: 2}
+
+func (c Config) validate() error {
+	if c.Rate <= 0 || c.ObjectRate <= 0 {
+		return fmt.Errorf("rate and objectRate must be positive, got rate=%v, objectRate=%v", c.Rate, c.ObjectRate)
+	}
+	if c.Burst < 1 || c.ObjectBurst < 1 {
+		return fmt.Errorf("burst and objectBurst must be greater or equal to 1, got burst=%v, objectBurst=%v", c.Burst, c.ObjectBurst)
+	}
+	return nil
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b *bucket) refill(now time.Time, rate, burst float64) {
+	if b.last.IsZero() {
+		b.tokens = burst
+	} else {
+		b.tokens = min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	}
+	b.last = now
+}
+
+// wait returns duration until bucket has at least n tokens
+func (b *bucket) wait(n, rate float64) time.Duration {
+	if b.tokens >= n {
+		return 0
+	}
+	return time.Duration(math.Ceil((n - b.tokens) / rate * float64(time.Second)))
+}
+
+type objectState struct {
+	bucket
+	pendingUntil time.Time
+}
+
+// RateLimiter limits reconciles of parent objects triggered by changes of child objects
+// with token buckets shared by all parent objects of controller and owned by each parent object.
+// Per-object bucket prevents a single parent object with frequently changed children from consuming the whole controller budget.
 type RateLimiter struct {
-	mu        sync.Mutex
-	budget    int64
-	limit     int64
-	deadline  time.Time
-	throttled prometheus.Counter
-}
-
-// NewRateLimiter returns limiter with limit per 2 seconds
-func NewRateLimiter(limiterName string, limit int64) *RateLimiter {
-	collector := prometheus.NewCounter(prometheus.CounterOpts{
-		Name:        "operator_reconcile_throttled_events_total",
-		Help:        "number of throttled reconciliation events",
-		ConstLabels: map[string]string{"controller": limiterName},
+	name    string
+	mu      sync.Mutex
+	cfg     Config
+	global  bucket
+	objects map[string]*objectState
+	lastGC  time.Time
+	now     func() time.Time
+	// events receives throttled parent objects, if Source is used by parent controller
+	events    chan event.GenericEvent
+	afterFunc func(d time.Duration, f func())
+}
+
+// NewRateLimiter returns limiter with given default settings
+// settings could be changed later with SetConfigs
+func NewRateLimiter(limiterName string, cfg Config) *RateLimiter {
+	if err := cfg.validate(); err != nil {
+		panic(fmt.Sprintf("BUG: incorrect rate limiter config for controller=%s: %s", limiterName, err))
+	}
+	rt := &RateLimiter{
+		name:    limiterName,
+		cfg:     cfg,
+		objects: make(map[string]*objectState),
+		now:     time.Now,
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+	}
+	registryMu.Lock()
+	registry[limiterName] = rt
+	registryMu.Unlock()
+	return rt
+}
+
+// Source returns source of throttled parent objects, it must be watched by parent objects controller.
+//
+// If source is used, throttled parent object is enqueued into parent controller queue once,
+// after its reconcile delay passes. Child controllers must skip throttled parent object,
+// all child object changes made before delayed reconcile are applied by it.
+func (rt *RateLimiter) Source() source.Source {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if rt.events == nil {
+		rt.events = make(chan event.GenericEvent)
+	}
+	return source.Channel(rt.events, &handler.EnqueueRequestForObject{})
+}
+
+// ReconcileDelay registers reconcile event of given parent object, for instance VMAgent for changed VMServiceScrape,
+// and returns zero if reconcile could be performed.
+// Otherwise it returns duration, after which reconcile of parent object will be performed.
+// Throttled events of the same parent object are coalesced into a single delayed reconcile,
+// which is enqueued to parent controller if Source is used and must be retried by caller otherwise.
+func (rt *RateLimiter) ReconcileDelay(obj client.Object) time.Duration {
+	key := obj.GetNamespace() + "/" + obj.GetName()
+	priority := obj.GetAnnotations()[vmv1beta1.ReconcilePriorityAnnotation]
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	now := rt.now()
+	cfg := rt.cfg
+	rt.global.refill(now, cfg.Rate, cfg.Burst)
+	st, ok := rt.objects[key]
+	if !ok {
+		st = &objectState{}
+		rt.objects[key] = st
+	}
+	st.refill(now, cfg.ObjectRate, cfg.ObjectBurst)
+
+	need := 1.0
+	if priority == vmv1beta1.ReconcilePriorityLow {
+		// low priority objects must not drain the shared budget
+		need = max(need, cfg.Burst/2)
+	}
+	delay := rt.global.wait(need, cfg.Rate)
+	if priority != vmv1beta1.ReconcilePriorityHigh {
+		delay = max(delay, st.wait(1, cfg.ObjectRate))
+	}
+	if delay > 0 {
+		if st.pendingUntil.After(now) {
+			delay = st.pendingUntil.Sub(now)
+		} else {
+			st.pendingUntil = now.Add(delay)
+			rt.scheduleReconcile(key, obj, priority, delay)
+		}
+		throttledEventsTotal.WithLabelValues(rt.name, key).Inc()
+		return delay
+	}
+	rt.global.tokens--
+	if priority != vmv1beta1.ReconcilePriorityHigh {
+		st.tokens--
+	}
+	st.pendingUntil = time.Time{}
+	rt.gc(now)
+	return 0
+}
+
+// scheduleReconcile enqueues parent object into parent controller queue after the given delay
+// delayed reconcile consumes tokens the same way as allowed one
+func (rt *RateLimiter) scheduleReconcile(key string, obj client.Object, priority string, delay time.Duration) {
+	if rt.events == nil {
+		return
+	}
+	parent := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Namespace: obj.GetNamespace(), Name: obj.GetName()}}
+	events := rt.events
+	rt.afterFunc(delay, func() {
+		rt.mu.Lock()
+		now := rt.now()
+		cfg := rt.cfg
+		rt.global.refill(now, cfg.Rate, cfg.Burst)
+		rt.global.tokens--
+		if st, ok := rt.objects[key]; ok {
+			st.refill(now, cfg.ObjectRate, cfg.ObjectBurst)
+			if priority != vmv1beta1.ReconcilePriorityHigh {
+				st.tokens--
+			}
+			st.pendingUntil = time.Time{}
+		}
+		rt.mu.Unlock()
+		events <- event.GenericEvent{Object: parent}
 	})
-	r := metrics.Registry
-	r.MustRegister(collector)
-	return &RateLimiter{
-		limit:     limit,
-		throttled: collector,
+}
+
+func (rt *RateLimiter) gc(now time.Time) {
+	if now.Sub(rt.lastGC) < gcInterval {
+		return
 	}
+	rt.lastGC = now
+	for key, st := range rt.objects {
+		st.refill(now, rt.cfg.ObjectRate, rt.cfg.ObjectBurst)
+		if st.tokens >= rt.cfg.ObjectBurst && !st.pendingUntil.After(now) {
+			delete(rt.objects, key)
+			throttledEventsTotal.DeleteLabelValues(rt.name, key)
+		}
+	}
+}
+
+func (rt *RateLimiter) config() Config {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.cfg
 }
 
-// MustThrottleReconcile registers reconcile event and checks if it must be throttled
-func (rt *RateLimiter) MustThrottleReconcile() bool {
+func (rt *RateLimiter) setConfig(cfg Config) {
 	rt.mu.Lock()
 	defer rt.mu.Unlock()
-	if rt.budget <= 0 {
-		if d := time.Until(rt.deadline); d > 0 {
-			rt.throttled.Inc()
-			return true
+	rt.cfg = cfg
+}
+
+// SetConfigs applies rate limiter settings in format:
+//
+//	controller:rate=5,burst=10,objectRate=1,objectBurst=2;controller2:rate=1
+//
+// omitted settings keep the default values of controller
+func SetConfigs(s string) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	cfgs := make(map[string]Config)
+	for _, item := range strings.Split(s, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
 		}
-		rt.deadline = time.Now().Add(time.Second * 2)
-		rt.budget += rt.limit
+		name, params, ok := strings.Cut(item, ":")
+		if !ok {
+			return fmt.Errorf("missing ':' after controller name at %q", item)
+		}
+		name = strings.TrimSpace(name)
+		rt, ok := registry[name]
+		if !ok {
+			return fmt.Errorf("unknown controller=%q, supported controllers: %s", name, strings.Join(registeredNames(), ","))
+		}
+		cfg := rt.config()
+		for _, param := range strings.Split(params, ",") {
+			k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok {
+				return fmt.Errorf("missing '=' at param %q of controller=%q", param, name)
+			}
+			value, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return fmt.Errorf("cannot parse param %q of controller=%q: %w", param, name, err)
+			}
+			switch strings.TrimSpace(k) {
+			case "rate":
+				cfg.Rate = value
+			case "burst":
+				cfg.Burst = value
+			case "objectRate":
+				cfg.ObjectRate = value
+			case "objectBurst":
+				cfg.ObjectBurst = value
+			default:
+				return fmt.Errorf("unknown param %q of controller=%q, supported params: rate,burst,objectRate,objectBurst", k, name)
+			}
+		}
+		if err := cfg.validate(); err != nil {
+			return fmt.Errorf("incorrect config of controller=%q: %w", name, err)
+		}
+		cfgs[name] = cfg
+	}
+	for name, cfg := range cfgs {
+		registry[name].setConfig(cfg)
+	}
+	return nil
+}
+
+func registeredNames() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
 	}
-	rt.budget--
-	return false
+	sort.Strings(names)
+	return names
 }
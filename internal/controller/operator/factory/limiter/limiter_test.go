@@ -0,0 +1,126 @@
+package limiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+)
+
+func newTestObject(name, priority string) *vmv1beta1.VMAgent {
+	obj := &vmv1beta1.VMAgent{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	if priority != "" {
+		obj.Annotations = map[string]string{vmv1beta1.ReconcilePriorityAnnotation: priority}
+	}
+	return obj
+}
+
+func TestRateLimiterReconcileDelay(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	rt := NewRateLimiter("test-reconcile-delay", Config{Rate: 2, Burst: 4, ObjectRate: 1, ObjectBurst: 2})
+	rt.now = func() time.Time { return now }
+
+	hot := newTestObject("hot", "")
+	// object burst
+	assert.Zero(t, rt.ReconcileDelay(hot))
+	assert.Zero(t, rt.ReconcileDelay(hot))
+	// hot object is throttled by its own bucket
+	assert.Equal(t, time.Second, rt.ReconcileDelay(hot))
+	// throttled events are coalesced into the same delayed reconcile
+	now = now.Add(300 * time.Millisecond)
+	assert.Equal(t, 700*time.Millisecond, rt.ReconcileDelay(hot))
+
+	// other objects still have controller budget
+	assert.Zero(t, rt.ReconcileDelay(newTestObject("other", "")))
+	// low priority object cannot use the last half of controller budget
+	assert.Equal(t, 200*time.Millisecond, rt.ReconcileDelay(newTestObject("low", vmv1beta1.ReconcilePriorityLow)))
+	// high priority object bypasses per-object bucket
+	high := newTestObject("high", vmv1beta1.ReconcilePriorityHigh)
+	now = now.Add(200 * time.Millisecond)
+	assert.Zero(t, rt.ReconcileDelay(high))
+	assert.Zero(t, rt.ReconcileDelay(high))
+	// but not the controller bucket
+	assert.Equal(t, 500*time.Millisecond, rt.ReconcileDelay(high))
+
+	// delayed reconcile of hot object is allowed
+	now = now.Add(500 * time.Millisecond)
+	assert.Zero(t, rt.ReconcileDelay(hot))
+}
+
+func TestRateLimiterScheduleReconcile(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	rt := NewRateLimiter("test-schedule-reconcile", Config{Rate: 2, Burst: 4, ObjectRate: 1, ObjectBurst: 1})
+	rt.now = func() time.Time { return now }
+	rt.events = make(chan event.GenericEvent, 2)
+	var scheduled []func()
+	rt.afterFunc = func(d time.Duration, f func()) {
+		assert.Equal(t, time.Second, d)
+		scheduled = append(scheduled, f)
+	}
+
+	hot := newTestObject("hot", "")
+	assert.Zero(t, rt.ReconcileDelay(hot))
+	assert.Equal(t, time.Second, rt.ReconcileDelay(hot))
+	now = now.Add(300 * time.Millisecond)
+	assert.Equal(t, 700*time.Millisecond, rt.ReconcileDelay(hot))
+	// throttled parent object is enqueued once
+	assert.Len(t, scheduled, 1)
+
+	now = now.Add(700 * time.Millisecond)
+	scheduled[0]()
+	e := <-rt.events
+	assert.Equal(t, "default", e.Object.GetNamespace())
+	assert.Equal(t, "hot", e.Object.GetName())
+	// delayed reconcile consumed object token
+	assert.Equal(t, time.Second, rt.ReconcileDelay(hot))
+	assert.Len(t, scheduled, 2)
+
+	// idle object and its metrics are evicted
+	now = now.Add(time.Second)
+	scheduled[1]()
+	<-rt.events
+	now = now.Add(gcInterval + time.Second)
+	rt.gc(now)
+	assert.Empty(t, rt.objects)
+	assert.False(t, throttledEventsTotal.DeleteLabelValues(rt.name, "default/hot"))
+}
+
+func TestSetConfigs(t *testing.T) {
+	rt := NewRateLimiter("test-set-configs", DefaultConfig)
+	f := func(s string, want Config, wantErr bool) {
+		t.Helper()
+		rt.setConfig(DefaultConfig)
+		err := SetConfigs(s)
+		if wantErr {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, want, rt.config())
+	}
+
+	// empty value
+	f("", DefaultConfig, false)
+
+	// partial override
+	f("test-set-configs:rate=10,objectBurst=3", Config{Rate: 10, Burst: 5, ObjectRate: 0.5, ObjectBurst: 3}, false)
+
+	// all settings
+	f(" test-set-configs: rate=1, burst=2, objectRate=0.1, objectBurst=1 ;", Config{Rate: 1, Burst: 2, ObjectRate: 0.1, ObjectBurst: 1}, false)
+
+	// unknown controller
+	f("unknown:rate=1", DefaultConfig, true)
+
+	// unknown param
+	f("test-set-configs:qps=1", DefaultConfig, true)
+
+	// incorrect value
+	f("test-set-configs:rate=0", DefaultConfig, true)
+	f("test-set-configs:burst=0.5", DefaultConfig, true)
+	f("test-set-configs:rate=fast", DefaultConfig, true)
+	f("test-set-configs", DefaultConfig, true)
+}
@@ -0,0 +1,124 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+)
+
+const listPageSize = 500
+
+// StorageVersionMigrator rewrites objects of operator CRDs to the current storage version
+// and removes previous versions from CRD status.storedVersions.
+// It allows to remove deprecated API versions from CRDs.
+type StorageVersionMigrator struct {
+	rclient client.Client
+}
+
+// NewStorageVersionMigrator returns migrator for CRDs of operator API group
+func NewStorageVersionMigrator(rclient client.Client) *StorageVersionMigrator {
+	return &StorageVersionMigrator{rclient: rclient}
+}
+
+// Migrate performs migration of all operator CRDs, which have objects stored with non-storage versions
+func (m *StorageVersionMigrator) Migrate(ctx context.Context) error {
+	var crds apiextensionsv1.CustomResourceDefinitionList
+	if err := m.rclient.List(ctx, &crds); err != nil {
+		return fmt.Errorf("cannot list CRDs: %w", err)
+	}
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		if crd.Spec.Group != vmv1beta1.GroupVersion.Group {
+			continue
+		}
+		if err := m.migrateCRD(ctx, crd); err != nil {
+			return fmt.Errorf("cannot migrate CRD=%s: %w", crd.Name, err)
+		}
+	}
+	return nil
+}
+
+func (m *StorageVersionMigrator) migrateCRD(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) error {
+	var storageVersion string
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			storageVersion = v.Name
+			break
+		}
+	}
+	if storageVersion == "" {
+		return fmt.Errorf("CRD has no storage version")
+	}
+	if slices.Equal(crd.Status.StoredVersions, []string{storageVersion}) {
+		return nil
+	}
+	logger.WithContext(ctx).Info(fmt.Sprintf("migrating objects of CRD=%s from versions=%v to storage version=%s", crd.Name, crd.Status.StoredVersions, storageVersion))
+	gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: storageVersion, Kind: crd.Spec.Names.ListKind}
+	var count int
+	var continueToken string
+	for {
+		var objects unstructured.UnstructuredList
+		objects.SetGroupVersionKind(gvk)
+		if err := m.rclient.List(ctx, &objects, client.Limit(listPageSize), client.Continue(continueToken)); err != nil {
+			return fmt.Errorf("cannot list objects: %w", err)
+		}
+		for i := range objects.Items {
+			if err := m.migrateObject(ctx, &objects.Items[i]); err != nil {
+				return err
+			}
+			count++
+		}
+		continueToken = objects.GetContinue()
+		if continueToken == "" {
+			break
+		}
+	}
+	crd.Status.StoredVersions = []string{storageVersion}
+	if err := m.rclient.Status().Update(ctx, crd); err != nil {
+		return fmt.Errorf("cannot update stored versions: %w", err)
+	}
+	logger.WithContext(ctx).Info(fmt.Sprintf("migrated %d objects of CRD=%s", count, crd.Name))
+	return nil
+}
+
+// migrateObject performs no-op update of object, which makes API server to write it with the storage version
+func (m *StorageVersionMigrator) migrateObject(ctx context.Context, obj *unstructured.Unstructured) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := m.rclient.Update(ctx, obj)
+		if k8serrors.IsConflict(err) {
+			// refresh object for the next attempt
+			if getErr := m.rclient.Get(ctx, client.ObjectKeyFromObject(obj), obj); getErr != nil {
+				return getErr
+			}
+		}
+		return err
+	})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("cannot migrate object=%s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+	return nil
+}
+
+// Start implements manager.Runnable interface
+// migration is performed once at operator start
+func (m *StorageVersionMigrator) Start(ctx context.Context) error {
+	if err := m.Migrate(ctx); err != nil {
+		logger.WithContext(ctx).Error(err, "cannot migrate CRDs storage version")
+	}
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable interface
+func (m *StorageVersionMigrator) NeedLeaderElection() bool {
+	return true
+}
@@ -0,0 +1,60 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func newTestCRD(name, group, kind string, storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: kind, ListKind: kind + "List"},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true},
+				{Name: "v1beta1", Served: true, Storage: true},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+	}
+}
+
+func TestStorageVersionMigratorMigrate(t *testing.T) {
+	f := func(crd *apiextensionsv1.CustomResourceDefinition, wantStoredVersions []string, wantMigrated bool) {
+		t.Helper()
+		rule := &vmv1beta1.VMRule{ObjectMeta: metav1.ObjectMeta{Name: "rule", Namespace: "default"}}
+		rclient := k8stools.GetTestClientWithObjects([]runtime.Object{crd, rule})
+		ctx := context.Background()
+		var prev vmv1beta1.VMRule
+		assert.NoError(t, rclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "rule"}, &prev))
+
+		m := NewStorageVersionMigrator(rclient)
+		assert.NoError(t, m.Migrate(ctx))
+
+		var gotCRD apiextensionsv1.CustomResourceDefinition
+		assert.NoError(t, rclient.Get(ctx, types.NamespacedName{Name: crd.Name}, &gotCRD))
+		assert.Equal(t, wantStoredVersions, gotCRD.Status.StoredVersions)
+		var got vmv1beta1.VMRule
+		assert.NoError(t, rclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "rule"}, &got))
+		assert.Equal(t, wantMigrated, got.ResourceVersion != prev.ResourceVersion)
+	}
+
+	// objects stored with previous version
+	f(newTestCRD("vmrules.operator.victoriametrics.com", "operator.victoriametrics.com", "VMRule", "v1", "v1beta1"), []string{"v1beta1"}, true)
+
+	// already migrated
+	f(newTestCRD("vmrules.operator.victoriametrics.com", "operator.victoriametrics.com", "VMRule", "v1beta1"), []string{"v1beta1"}, false)
+
+	// CRD of another group
+	f(newTestCRD("vmrules.example.com", "example.com", "VMRule", "v1", "v1beta1"), []string{"v1", "v1beta1"}, false)
+}
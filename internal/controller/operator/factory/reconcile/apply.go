@@ -0,0 +1,136 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+)
+
+// FieldManager is a name of field manager used by operator for server-side apply
+const FieldManager = "vm-operator"
+
+// updateFieldManager is a field manager name used by API server for update requests of operator
+// API server uses user agent prefix as a field manager name, if it isn't set explicitly
+var updateFieldManager = strings.Split(rest.DefaultKubernetesUserAgent(), "/")[0]
+
+var useServerSideApply bool
+
+// SetServerSideApply configures reconcile helpers to use server-side apply instead of create and update requests
+//
+// With server-side apply operator owns only fields rendered by it
+// and fields set by other controllers are preserved
+func SetServerSideApply(enabled bool) {
+	useServerSideApply = enabled
+}
+
+// createObject creates the given object
+func createObject(ctx context.Context, rclient client.Client, newObj client.Object) error {
+	if useServerSideApply {
+		return applyObject(ctx, rclient, newObj, nil)
+	}
+	return rclient.Create(ctx, newObj)
+}
+
+// updateObject updates object returned by merge function,
+// which must merge newObj with the current object state.
+//
+// In case of server-side apply merge isn't called and newObj is applied as is,
+// API server merges it with fields owned by other managers
+func updateObject(ctx context.Context, rclient client.Client, newObj, currObj client.Object, merge func() client.Object) error {
+	if useServerSideApply {
+		return applyObject(ctx, rclient, newObj, currObj)
+	}
+	return rclient.Update(ctx, merge())
+}
+
+// applyObject performs server-side apply request for the given object
+// it doesn't force ownership and returns an error on conflict with other field managers
+func applyObject(ctx context.Context, rclient client.Client, newObj, currObj client.Object) error {
+	gvk, err := apiutil.GVKForObject(newObj, rclient.Scheme())
+	if err != nil {
+		return fmt.Errorf("BUG: cannot get GroupVersionKind for object=%s/%s: %w", newObj.GetNamespace(), newObj.GetName(), err)
+	}
+	if currObj != nil {
+		if err := upgradeManagedFields(ctx, rclient, currObj); err != nil {
+			return fmt.Errorf("cannot upgrade managed fields of %s=%s/%s: %w", gvk.Kind, newObj.GetNamespace(), newObj.GetName(), err)
+		}
+	}
+	obj := newObj.DeepCopyObject().(client.Object)
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	// apply request must not contain server generated metadata
+	obj.SetResourceVersion("")
+	obj.SetUID("")
+	obj.SetGeneration(0)
+	obj.SetManagedFields(nil)
+	obj.SetFinalizers(ownedFinalizers(newObj, currObj))
+
+	if err := rclient.Patch(ctx, obj, client.Apply, client.FieldOwner(FieldManager)); err != nil {
+		if k8serrors.IsConflict(err) {
+			return &errApplyConflict{kind: gvk.Kind, namespace: newObj.GetNamespace(), name: newObj.GetName(), origin: err}
+		}
+		return fmt.Errorf("cannot apply %s=%s/%s: %w", gvk.Kind, newObj.GetNamespace(), newObj.GetName(), err)
+	}
+	return nil
+}
+
+// upgradeManagedFields moves fields set by operator with update requests to the server-side apply field manager.
+// Otherwise, apply conflicts with fields previously set by operator, if server-side apply is enabled for existing objects
+func upgradeManagedFields(ctx context.Context, rclient client.Client, currObj client.Object) error {
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(currObj, sets.New(updateFieldManager), FieldManager)
+	if err != nil {
+		return fmt.Errorf("cannot build managed fields patch: %w", err)
+	}
+	if patch == nil {
+		return nil
+	}
+	return rclient.Patch(ctx, currObj.DeepCopyObject().(client.Object), client.RawPatch(types.JSONPatchType, patch))
+}
+
+// ownedFinalizers returns operator finalizer if it's defined at new or current object
+// finalizers of other controllers must not be claimed by operator field manager
+func ownedFinalizers(newObj, currObj client.Object) []string {
+	for _, o := range []client.Object{newObj, currObj} {
+		if o == nil {
+			continue
+		}
+		for _, f := range o.GetFinalizers() {
+			if f == vmv1beta1.FinalizerName {
+				return []string{vmv1beta1.FinalizerName}
+			}
+		}
+	}
+	return nil
+}
+
+// IsErrorApplyConflict determines if the err indicates that fields rendered by operator
+// are owned by other field managers
+func IsErrorApplyConflict(err error) bool {
+	var ec *errApplyConflict
+	return errors.As(err, &ec)
+}
+
+// errApplyConflict doesn't implement Unwrap interface intentionally
+// conflicts with other field managers cannot be resolved by retry
+type errApplyConflict struct {
+	kind      string
+	namespace string
+	name      string
+	origin    error
+}
+
+// Error implements errors.Error interface
+func (err *errApplyConflict) Error() string {
+	return fmt.Sprintf("cannot apply %s=%s/%s, fields are owned by another field manager, remove them from the manager or change CR spec: %s",
+		err.kind, err.namespace, err.name, err.origin)
+}
@@ -0,0 +1,167 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+// applyRecorder records apply requests, since fake client doesn't support server-side apply
+type applyRecorder struct {
+	client.Client
+	applied  []client.Object
+	owners   []string
+	upgrades []string
+	err      error
+}
+
+// Patch implements client.Client interface
+func (ar *applyRecorder) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() == types.JSONPatchType {
+		data, err := patch.Data(obj)
+		if err != nil {
+			return err
+		}
+		ar.upgrades = append(ar.upgrades, string(data))
+		return nil
+	}
+	if patch != client.Apply {
+		return ar.Client.Patch(ctx, obj, patch, opts...)
+	}
+	var po client.PatchOptions
+	po.ApplyOptions(opts)
+	ar.applied = append(ar.applied, obj)
+	ar.owners = append(ar.owners, po.FieldManager)
+	return ar.err
+}
+
+func TestServerSideApply(t *testing.T) {
+	SetServerSideApply(true)
+	defer SetServerSideApply(false)
+	ctx := context.Background()
+
+	current := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "cm",
+			Namespace:       "default",
+			ResourceVersion: "10",
+			Finalizers:      []string{"other.io/finalizer", vmv1beta1.FinalizerName},
+			Annotations:     map[string]string{"injected-by": "mesh"},
+		},
+		Data: map[string]string{"key": "old"},
+	}
+	newCM := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default", Annotations: map[string]string{"owned": "true"}},
+			Data:       map[string]string{"key": "new"},
+		}
+	}
+
+	// create
+	rclient := &applyRecorder{Client: k8stools.GetTestClientWithObjects(nil)}
+	assert.NoError(t, ConfigMap(ctx, rclient, newCM(), nil))
+	assert.Len(t, rclient.applied, 1)
+	assert.Equal(t, []string{FieldManager}, rclient.owners)
+	assert.Equal(t, schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, rclient.applied[0].GetObjectKind().GroupVersionKind())
+
+	// update applies only rendered fields and operator finalizer
+	rclient = &applyRecorder{Client: k8stools.GetTestClientWithObjects([]runtime.Object{current})}
+	assert.NoError(t, ConfigMap(ctx, rclient, newCM(), nil))
+	assert.Len(t, rclient.applied, 1)
+	applied := rclient.applied[0].(*corev1.ConfigMap)
+	assert.Empty(t, applied.ResourceVersion)
+	assert.Equal(t, []string{vmv1beta1.FinalizerName}, applied.Finalizers)
+	assert.Equal(t, map[string]string{"owned": "true"}, applied.Annotations)
+	assert.Equal(t, map[string]string{"key": "new"}, applied.Data)
+
+	// conflicts are reported and not retried
+	conflict := k8serrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "cm", nil)
+	rclient = &applyRecorder{Client: k8stools.GetTestClientWithObjects([]runtime.Object{current}), err: conflict}
+	err := ConfigMap(ctx, rclient, newCM(), nil)
+	assert.Error(t, err)
+	assert.True(t, IsErrorApplyConflict(err))
+	assert.False(t, k8serrors.IsConflict(err))
+	assert.Len(t, rclient.applied, 1)
+}
+
+func TestOwnedFinalizers(t *testing.T) {
+	f := func(newFinalizers, currFinalizers []string, want []string) {
+		t.Helper()
+		newObj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Finalizers: newFinalizers}}
+		currObj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Finalizers: currFinalizers}}
+		assert.Equal(t, want, ownedFinalizers(newObj, currObj))
+	}
+	f(nil, nil, nil)
+	f(nil, []string{"other.io/finalizer"}, nil)
+	f([]string{vmv1beta1.FinalizerName}, nil, []string{vmv1beta1.FinalizerName})
+	f(nil, []string{"other.io/finalizer", vmv1beta1.FinalizerName}, []string{vmv1beta1.FinalizerName})
+}
+
+func TestUpgradeManagedFields(t *testing.T) {
+	f := func(managedFields []metav1.ManagedFieldsEntry, wantUpgrade bool) {
+		t.Helper()
+		current := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default", ResourceVersion: "10", ManagedFields: managedFields},
+		}
+		rclient := &applyRecorder{Client: k8stools.GetTestClientWithObjects(nil)}
+		assert.NoError(t, upgradeManagedFields(context.Background(), rclient, current))
+		if !wantUpgrade {
+			assert.Empty(t, rclient.upgrades)
+			return
+		}
+		assert.Len(t, rclient.upgrades, 1)
+		assert.Contains(t, rclient.upgrades[0], `"manager":"`+FieldManager+`","operation":"Apply"`)
+		assert.NotContains(t, rclient.upgrades[0], `"manager":"`+updateFieldManager+`"`)
+	}
+	fields := &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:key":{}}}`)}
+
+	// fields set by operator update requests
+	f([]metav1.ManagedFieldsEntry{
+		{Manager: updateFieldManager, Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "v1", FieldsType: "FieldsV1", FieldsV1: fields},
+	}, true)
+
+	// fields owned by other managers
+	f([]metav1.ManagedFieldsEntry{
+		{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "v1", FieldsType: "FieldsV1", FieldsV1: fields},
+	}, false)
+
+	// already upgraded
+	f([]metav1.ManagedFieldsEntry{
+		{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply, APIVersion: "v1", FieldsType: "FieldsV1", FieldsV1: fields},
+	}, false)
+}
+
+func TestServerSideApplyPVCExpansion(t *testing.T) {
+	SetServerSideApply(true)
+	defer SetServerSideApply(false)
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-vmstorage-0", Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("10Gi"),
+			}},
+		},
+	}
+	rclient := &applyRecorder{Client: k8stools.GetTestClientWithObjects([]runtime.Object{pvc})}
+	size := resource.MustParse("20Gi")
+	assert.NoError(t, growPVCs(context.Background(), rclient, &size, pvc))
+	assert.Len(t, rclient.applied, 1)
+	applied := rclient.applied[0].(*corev1.PersistentVolumeClaim)
+	// only storage request is owned by operator
+	assert.Equal(t, corev1.PersistentVolumeClaimSpec{
+		Resources: corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: size}},
+	}, applied.Spec)
+}
@@ -21,7 +21,7 @@ func ConfigMap(ctx context.Context, rclient client.Client, newCM *corev1.ConfigM
 	if err := rclient.Get(ctx, types.NamespacedName{Namespace: newCM.Namespace, Name: newCM.Name}, &currentCM); err != nil {
 		if errors.IsNotFound(err) {
 			logger.WithContext(ctx).Info(fmt.Sprintf("creating new ConfigMap %s", newCM.Name))
-			return rclient.Create(ctx, newCM)
+			return createObject(ctx, rclient, newCM)
 		}
 	}
 	var prevAnnotations map[string]string
@@ -34,11 +34,22 @@ func ConfigMap(ctx context.Context, rclient client.Client, newCM *corev1.ConfigM
 		return nil
 	}
 
-	vmv1beta1.AddFinalizer(newCM, &currentCM)
-	cloneSignificantMetadata(newCM, &currentCM)
-	newCM.Annotations = mergeAnnotations(currentCM.Annotations, newCM.Annotations, prevAnnotations)
+	if detectDrift(ctx, "ConfigMap", &currentCM, true, func() []string {
+		fields := metadataDriftFields(newCM, &currentCM, prevAnnotations)
+		if !equality.Semantic.DeepEqual(newCM.Data, currentCM.Data) {
+			fields = append(fields, "data")
+		}
+		return fields
+	}) {
+		return nil
+	}
 
 	logger.WithContext(ctx).Info(fmt.Sprintf("updating ConfigMap %s configuration", newCM.Name))
 
-	return rclient.Update(ctx, newCM)
+	return updateObject(ctx, rclient, newCM, &currentCM, func() client.Object {
+		vmv1beta1.AddFinalizer(newCM, &currentCM)
+		cloneSignificantMetadata(newCM, &currentCM)
+		newCM.Annotations = mergeAnnotations(currentCM.Annotations, newCM.Annotations, prevAnnotations)
+		return newCM
+	})
 }
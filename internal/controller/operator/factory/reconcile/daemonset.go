@@ -37,7 +37,7 @@ func DaemonSet(ctx context.Context, rclient client.Client, newDs, prevDs *appsv1
 		if err != nil {
 			if errors.IsNotFound(err) {
 				logger.WithContext(ctx).Info(fmt.Sprintf("creating new DaemonSet %s", newDs.Name))
-				if err := rclient.Create(ctx, newDs); err != nil {
+				if err := createObject(ctx, rclient, newDs); err != nil {
 					return fmt.Errorf("cannot create new DaemonSet for app: %s, err: %w", newDs.Name, err)
 				}
 				return waitDaemonSetReady(ctx, rclient, newDs, appWaitReadyDeadline)
@@ -53,6 +53,12 @@ func DaemonSet(ctx context.Context, rclient client.Client, newDs, prevDs *appsv1
 			prevAnnotations = prevDs.Annotations
 			prevTemplateAnnotations = prevDs.Spec.Template.Annotations
 		}
+		// kubernetes restarts pods of DaemonSet right after pod template change
+		// keep the current template outside of maintenance window and apply other changes
+		if !equality.Semantic.DeepDerivative(newDs.Spec.Template, currentDs.Spec.Template) &&
+			!allowDisruptive(ctx, fmt.Sprintf("rolling update of DaemonSet=%s", newDs.Name)) {
+			newDs.Spec.Template = *currentDs.Spec.Template.DeepCopy()
+		}
 		isEqual := equality.Semantic.DeepDerivative(newDs.Spec, currentDs.Spec)
 		if isEqual &&
 			isPrevEqual &&
@@ -61,10 +67,12 @@ func DaemonSet(ctx context.Context, rclient client.Client, newDs, prevDs *appsv1
 			return waitDaemonSetReady(ctx, rclient, newDs, appWaitReadyDeadline)
 		}
 
-		vmv1beta1.AddFinalizer(newDs, &currentDs)
-		newDs.Annotations = mergeAnnotations(currentDs.Annotations, newDs.Annotations, prevAnnotations)
-		newDs.Spec.Template.Annotations = mergeAnnotations(currentDs.Spec.Template.Annotations, newDs.Spec.Template.Annotations, prevTemplateAnnotations)
-		cloneSignificantMetadata(newDs, &currentDs)
+		isIntentEqual := isPrevEqual && isPrevMetaEqual(newDs, prevDs)
+		if detectDrift(ctx, "DaemonSet", &currentDs, isIntentEqual, func() []string {
+			return append(metadataDriftFields(newDs, &currentDs, prevAnnotations), specDriftFields("spec", newDs.Spec, currentDs.Spec)...)
+		}) {
+			return waitDaemonSetReady(ctx, rclient, newDs, appWaitReadyDeadline)
+		}
 
 		logMsg := fmt.Sprintf("updating DaemonSet %s configuration"+
 			"is_prev_equal=%v,is_current_equal=%v,is_prev_nil=%v",
@@ -79,7 +87,13 @@ func DaemonSet(ctx context.Context, rclient client.Client, newDs, prevDs *appsv1
 
 		logger.WithContext(ctx).Info(logMsg)
 
-		if err := rclient.Update(ctx, newDs); err != nil {
+		if err := updateObject(ctx, rclient, newDs, &currentDs, func() client.Object {
+			vmv1beta1.AddFinalizer(newDs, &currentDs)
+			newDs.Annotations = mergeAnnotations(currentDs.Annotations, newDs.Annotations, prevAnnotations)
+			newDs.Spec.Template.Annotations = mergeAnnotations(currentDs.Spec.Template.Annotations, newDs.Spec.Template.Annotations, prevTemplateAnnotations)
+			cloneSignificantMetadata(newDs, &currentDs)
+			return newDs
+		}); err != nil {
 			return fmt.Errorf("cannot update DaemonSet for app: %s, err: %w", newDs.Name, err)
 		}
 
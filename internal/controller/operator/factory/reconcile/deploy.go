@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -18,10 +19,13 @@ import (
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/finalize"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/tracing"
 )
 
 // Deployment performs an update or create operator for deployment and waits until it's replicas is ready
 func Deployment(ctx context.Context, rclient client.Client, newDeploy, prevDeploy *appsv1.Deployment, hasHPA bool) error {
+	ctx, span := tracing.Start(ctx, "reconcile.Deployment", attribute.String("k8s.deployment.name", newDeploy.Name))
+	defer span.End()
 
 	var isPrevEqual bool
 	var prevSpecDiff string
@@ -39,7 +43,7 @@ func Deployment(ctx context.Context, rclient client.Client, newDeploy, prevDeplo
 		if err != nil {
 			if errors.IsNotFound(err) {
 				logger.WithContext(ctx).Info(fmt.Sprintf("creating new Deployment %s", newDeploy.Name))
-				if err := rclient.Create(ctx, newDeploy); err != nil {
+				if err := createObject(ctx, rclient, newDeploy); err != nil {
 					return fmt.Errorf("cannot create new deployment for app: %s, err: %w", newDeploy.Name, err)
 				}
 				return waitDeploymentReady(ctx, rclient, newDeploy, appWaitReadyDeadline)
@@ -58,6 +62,12 @@ func Deployment(ctx context.Context, rclient client.Client, newDeploy, prevDeplo
 			prevAnnotations = prevDeploy.Annotations
 			prevTemplateAnnotations = prevDeploy.Spec.Template.Annotations
 		}
+		// kubernetes restarts pods of Deployment right after pod template change
+		// keep the current template outside of maintenance window and apply other changes
+		if !equality.Semantic.DeepDerivative(newDeploy.Spec.Template, currentDeploy.Spec.Template) &&
+			!allowDisruptive(ctx, fmt.Sprintf("rolling update of Deployment=%s", newDeploy.Name)) {
+			newDeploy.Spec.Template = *currentDeploy.Spec.Template.DeepCopy()
+		}
 		isEqual := equality.Semantic.DeepDerivative(newDeploy.Spec, currentDeploy.Spec)
 		if isEqual &&
 			isPrevEqual &&
@@ -66,10 +76,12 @@ func Deployment(ctx context.Context, rclient client.Client, newDeploy, prevDeplo
 			return waitDeploymentReady(ctx, rclient, newDeploy, appWaitReadyDeadline)
 		}
 
-		vmv1beta1.AddFinalizer(newDeploy, &currentDeploy)
-		newDeploy.Annotations = mergeAnnotations(currentDeploy.Annotations, newDeploy.Annotations, prevAnnotations)
-		newDeploy.Spec.Template.Annotations = mergeAnnotations(currentDeploy.Spec.Template.Annotations, newDeploy.Spec.Template.Annotations, prevTemplateAnnotations)
-		cloneSignificantMetadata(newDeploy, &currentDeploy)
+		isIntentEqual := isPrevEqual && isPrevMetaEqual(newDeploy, prevDeploy)
+		if detectDrift(ctx, "Deployment", &currentDeploy, isIntentEqual, func() []string {
+			return append(metadataDriftFields(newDeploy, &currentDeploy, prevAnnotations), specDriftFields("spec", newDeploy.Spec, currentDeploy.Spec)...)
+		}) {
+			return waitDeploymentReady(ctx, rclient, newDeploy, appWaitReadyDeadline)
+		}
 
 		logMsg := fmt.Sprintf("updating Deployment %s configuration"+
 			"is_prev_equal=%v,is_current_equal=%v,is_prev_nil=%v",
@@ -84,7 +96,13 @@ func Deployment(ctx context.Context, rclient client.Client, newDeploy, prevDeplo
 
 		logger.WithContext(ctx).Info(logMsg)
 
-		if err := rclient.Update(ctx, newDeploy); err != nil {
+		if err := updateObject(ctx, rclient, newDeploy, &currentDeploy, func() client.Object {
+			vmv1beta1.AddFinalizer(newDeploy, &currentDeploy)
+			newDeploy.Annotations = mergeAnnotations(currentDeploy.Annotations, newDeploy.Annotations, prevAnnotations)
+			newDeploy.Spec.Template.Annotations = mergeAnnotations(currentDeploy.Spec.Template.Annotations, newDeploy.Spec.Template.Annotations, prevTemplateAnnotations)
+			cloneSignificantMetadata(newDeploy, &currentDeploy)
+			return newDeploy
+		}); err != nil {
 			return fmt.Errorf("cannot update deployment for app: %s, err: %w", newDeploy.Name, err)
 		}
 
@@ -94,6 +112,9 @@ func Deployment(ctx context.Context, rclient client.Client, newDeploy, prevDeplo
 
 // waitDeploymentReady waits until deployment's replicaSet rollouts and all new pods is ready
 func waitDeploymentReady(ctx context.Context, rclient client.Client, dep *appsv1.Deployment, deadline time.Duration) error {
+	ctx, span := tracing.Start(ctx, "waitDeploymentReady", attribute.String("k8s.deployment.name", dep.Name))
+	defer span.End()
+
 	var isErrDealine bool
 	err := wait.PollUntilContextTimeout(ctx, time.Second, deadline, false, func(ctx context.Context) (done bool, err error) {
 		var actualDeploy appsv1.Deployment
@@ -11,6 +11,7 @@ import (
 type fieldDiffRecorder struct {
 	path              cmp.Path
 	diffs             []string
+	paths             []string
 	useDerivativeDiff bool
 }
 
@@ -49,6 +50,7 @@ func (r *fieldDiffRecorder) Report(rs cmp.Result) {
 			}
 		}
 		r.diffs = append(r.diffs, fmt.Sprintf("%#v:-%q +%q", r.path, formatDiffValue(a2), formatDiffValue(a1)))
+		r.paths = append(r.paths, r.path.String())
 	}
 }
 
@@ -82,6 +84,16 @@ func diffDeepInternal(a1, a2 interface{}, useDerivative bool) string {
 
 }
 
+// diffPathsDeepDerivative returns paths of fields, which differ at a1 and a2
+// unset fields at a1 are ignored, similar to diffDeepDerivative
+func diffPathsDeepDerivative(a1, a2 interface{}) []string {
+	r := fieldDiffRecorder{
+		useDerivativeDiff: true,
+	}
+	cmp.Diff(a1, a2, cmp.Reporter(&r))
+	return r.paths
+}
+
 func formatDiffValue(v reflect.Value) string {
 	if !v.IsValid() {
 		return "nil"
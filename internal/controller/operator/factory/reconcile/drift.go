@@ -0,0 +1,212 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+)
+
+var childDriftTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "operator_child_drift_total",
+	Help: "Counts number of child objects modified outside of operator",
+}, []string{"kind", "controller"})
+
+func init() {
+	metrics.Registry.MustRegister(childDriftTotal)
+}
+
+// operatorFieldManagers contains field managers used by operator
+var operatorFieldManagers = map[string]struct{}{
+	FieldManager:       {},
+	updateFieldManager: {},
+}
+
+type driftRecorderKey struct{}
+
+// ChildDrift defines child object modified outside of operator
+type ChildDrift struct {
+	Kind   string
+	Name   string
+	Fields []string
+	// Actor is a field manager, which made the last change
+	Actor string
+}
+
+// String implements Stringer interface
+func (cd ChildDrift) String() string {
+	return fmt.Sprintf("%s=%s modified by %s, fields: %s", cd.Kind, cd.Name, cd.Actor, strings.Join(cd.Fields, ","))
+}
+
+// DriftRecorder collects child objects modified outside of operator during parent object reconcile
+type DriftRecorder struct {
+	controller string
+	reportOnly bool
+
+	mu     sync.Mutex
+	drifts []ChildDrift
+}
+
+// NewDriftRecorder returns recorder for parent object controller
+// with reportOnly set, modified child objects are not reverted
+func NewDriftRecorder(controller string, reportOnly bool) *DriftRecorder {
+	return &DriftRecorder{controller: controller, reportOnly: reportOnly}
+}
+
+// WithDriftRecorder adds recorder to the given context
+func WithDriftRecorder(ctx context.Context, dr *DriftRecorder) context.Context {
+	return context.WithValue(ctx, driftRecorderKey{}, dr)
+}
+
+// Drifts returns recorded drifts
+func (dr *DriftRecorder) Drifts() []ChildDrift {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	return append([]ChildDrift(nil), dr.drifts...)
+}
+
+// ReportOnly checks if modified child objects are not reverted
+func (dr *DriftRecorder) ReportOnly() bool {
+	return dr.reportOnly
+}
+
+// WriteCondition sets DriftCorrected condition to the given status if any drift was recorded
+// condition is left as is otherwise, it keeps information about the last drift
+//
+// In report-only mode drift isn't reverted and it's detected at each reconcile,
+// so drifts already reported with the same condition message are skipped.
+// Returns true if drifts must be reported
+func (dr *DriftRecorder) WriteCondition(stm *vmv1beta1.StatusMetadata, generation int64) bool {
+	drifts := dr.Drifts()
+	if len(drifts) == 0 {
+		return false
+	}
+	msgs := make([]string, 0, len(drifts))
+	for _, cd := range drifts {
+		msgs = append(msgs, cd.String())
+	}
+	now := metav1.NewTime(time.Now())
+	cond := vmv1beta1.Condition{
+		Type:               vmv1beta1.ConditionDriftCorrected,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ChildObjectsReverted",
+		Message:            strings.Join(msgs, "; "),
+		ObservedGeneration: generation,
+		LastTransitionTime: now,
+		LastUpdateTime:     now,
+	}
+	if dr.reportOnly {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "DriftReportOnly"
+		for _, c := range stm.Conditions {
+			if c.Type == cond.Type && c.Reason == cond.Reason && c.Message == cond.Message {
+				return false
+			}
+		}
+	}
+	for _, cd := range drifts {
+		childDriftTotal.WithLabelValues(cd.Kind, dr.controller).Inc()
+	}
+	stm.Conditions = setConditionTo(stm.Conditions, cond)
+	return true
+}
+
+func (dr *DriftRecorder) add(cd ChildDrift) {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	dr.drifts = append(dr.drifts, cd)
+}
+
+// lastExternalActor returns field manager, which modified the object after operator
+// or empty string if the last change was made by operator
+// changes of sub-resources are ignored, since operator doesn't manage them
+func lastExternalActor(obj client.Object) string {
+	var actor string
+	var lastOperatorUpdate, lastExternalUpdate int64
+	for _, mf := range obj.GetManagedFields() {
+		if mf.Subresource != "" || mf.Time == nil {
+			continue
+		}
+		ts := mf.Time.Unix()
+		if _, ok := operatorFieldManagers[mf.Manager]; ok {
+			lastOperatorUpdate = max(lastOperatorUpdate, ts)
+			continue
+		}
+		if ts >= lastExternalUpdate {
+			lastExternalUpdate = ts
+			actor = fmt.Sprintf("%s (%s)", mf.Manager, mf.Operation)
+		}
+	}
+	if lastExternalUpdate <= lastOperatorUpdate {
+		return ""
+	}
+	return actor
+}
+
+// detectDrift checks if current state of child object was modified outside of operator
+// isIntentEqual must be true if rendered object is equal to the previously rendered one,
+// otherwise changes are caused by parent object update
+//
+// returns true if the object must not be reverted
+func detectDrift(ctx context.Context, kind string, currObj client.Object, isIntentEqual bool, fields func() []string) bool {
+	if !isIntentEqual {
+		return false
+	}
+	actor := lastExternalActor(currObj)
+	if actor == "" {
+		return false
+	}
+	dr, _ := ctx.Value(driftRecorderKey{}).(*DriftRecorder)
+	cd := ChildDrift{
+		Kind:   kind,
+		Name:   currObj.GetName(),
+		Fields: fields(),
+		Actor:  actor,
+	}
+	logger.WithContext(ctx).Info(fmt.Sprintf("detected drift of child object: %s", cd))
+	if dr == nil {
+		childDriftTotal.WithLabelValues(kind, "unknown").Inc()
+		return false
+	}
+	// drifts recorded with DriftRecorder are counted on report
+	dr.add(cd)
+	return dr.reportOnly
+}
+
+// metadataDriftFields returns names of metadata fields, which differ at new and current objects
+func metadataDriftFields(newObj, currObj client.Object, prevAnnotations map[string]string) []string {
+	var fields []string
+	if !equality.Semantic.DeepEqual(newObj.GetLabels(), currObj.GetLabels()) {
+		fields = append(fields, "metadata.labels")
+	}
+	if !isAnnotationsEqual(currObj.GetAnnotations(), newObj.GetAnnotations(), prevAnnotations) {
+		fields = append(fields, "metadata.annotations")
+	}
+	return fields
+}
+
+// specDriftFields returns paths of spec fields, which differ at new and current spec
+func specDriftFields(prefix string, newSpec, currSpec any) []string {
+	paths := diffPathsDeepDerivative(newSpec, currSpec)
+	fields := make([]string, 0, len(paths))
+	for _, p := range paths {
+		fields = append(fields, prefix+"."+p)
+	}
+	return fields
+}
+
+// isPrevMetaEqual checks if labels and annotations of rendered object weren't changed since the previous reconcile
+func isPrevMetaEqual(newObj, prevObj client.Object) bool {
+	return equality.Semantic.DeepEqual(newObj.GetLabels(), prevObj.GetLabels()) &&
+		equality.Semantic.DeepEqual(newObj.GetAnnotations(), prevObj.GetAnnotations())
+}
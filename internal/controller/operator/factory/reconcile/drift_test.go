@@ -0,0 +1,103 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func newManagedField(manager string, op metav1.ManagedFieldsOperationType, subresource string, ts int64) metav1.ManagedFieldsEntry {
+	t := metav1.NewTime(time.Unix(ts, 0))
+	return metav1.ManagedFieldsEntry{Manager: manager, Operation: op, Subresource: subresource, Time: &t}
+}
+
+func TestLastExternalActor(t *testing.T) {
+	f := func(entries []metav1.ManagedFieldsEntry, want string) {
+		t.Helper()
+		obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{ManagedFields: entries}}
+		assert.Equal(t, want, lastExternalActor(obj))
+	}
+	f(nil, "")
+	// operator is the last writer
+	f([]metav1.ManagedFieldsEntry{
+		newManagedField("kubectl-edit", metav1.ManagedFieldsOperationUpdate, "", 10),
+		newManagedField(FieldManager, metav1.ManagedFieldsOperationApply, "", 20),
+	}, "")
+	// status updates are ignored
+	f([]metav1.ManagedFieldsEntry{
+		newManagedField(FieldManager, metav1.ManagedFieldsOperationApply, "", 10),
+		newManagedField("kube-controller-manager", metav1.ManagedFieldsOperationUpdate, "status", 20),
+	}, "")
+	// external change after operator
+	f([]metav1.ManagedFieldsEntry{
+		newManagedField(FieldManager, metav1.ManagedFieldsOperationApply, "", 10),
+		newManagedField("kubectl-edit", metav1.ManagedFieldsOperationUpdate, "", 20),
+	}, "kubectl-edit (Update)")
+}
+
+func TestConfigMapDrift(t *testing.T) {
+	f := func(reportOnly bool, wantData string) {
+		t.Helper()
+		ctx := context.Background()
+		current := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cm",
+				Namespace: "default",
+				ManagedFields: []metav1.ManagedFieldsEntry{
+					newManagedField(FieldManager, metav1.ManagedFieldsOperationApply, "", 10),
+					newManagedField("kubectl-edit", metav1.ManagedFieldsOperationUpdate, "", 20),
+				},
+			},
+			Data: map[string]string{"key": "edited"},
+		}
+		rclient := k8stools.GetTestClientWithObjects([]runtime.Object{current})
+		dr := NewDriftRecorder("vmagent", reportOnly)
+		newCM := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"},
+			Data:       map[string]string{"key": "rendered"},
+		}
+		assert.NoError(t, ConfigMap(WithDriftRecorder(ctx, dr), rclient, newCM, nil))
+		assert.Equal(t, []ChildDrift{{Kind: "ConfigMap", Name: "cm", Fields: []string{"data"}, Actor: "kubectl-edit (Update)"}}, dr.Drifts())
+
+		var got corev1.ConfigMap
+		assert.NoError(t, rclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "cm"}, &got))
+		assert.Equal(t, wantData, got.Data["key"])
+
+		var stm vmv1beta1.StatusMetadata
+		assert.True(t, dr.WriteCondition(&stm, 1))
+		assert.Len(t, stm.Conditions, 1)
+		assert.Equal(t, vmv1beta1.ConditionDriftCorrected, stm.Conditions[0].Type)
+		if reportOnly {
+			assert.Equal(t, metav1.ConditionFalse, stm.Conditions[0].Status)
+			// already reported drift isn't reported again
+			assert.False(t, dr.WriteCondition(&stm, 1))
+		} else {
+			assert.Equal(t, metav1.ConditionTrue, stm.Conditions[0].Status)
+		}
+	}
+	// drift is reverted
+	f(false, "rendered")
+	// drift is only reported
+	f(true, "edited")
+}
+
+func TestDiffPathsDeepDerivative(t *testing.T) {
+	f := func(a1, a2 any, want []string) {
+		t.Helper()
+		assert.Equal(t, want, diffPathsDeepDerivative(a1, a2))
+	}
+	container := corev1.Container{Name: "app", Image: "app:v1"}
+	f(corev1.PodSpec{Containers: []corev1.Container{container}}, corev1.PodSpec{Containers: []corev1.Container{container}}, nil)
+	changed := container
+	changed.Image = "app:v2"
+	f(corev1.PodSpec{Containers: []corev1.Container{container}}, corev1.PodSpec{Containers: []corev1.Container{changed}}, []string{"Containers.Image"})
+}
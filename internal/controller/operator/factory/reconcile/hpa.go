@@ -22,7 +22,7 @@ func HPA(ctx context.Context, rclient client.Client, newHPA, prevHPA *v2.Horizon
 		if err := rclient.Get(ctx, types.NamespacedName{Name: newHPA.GetName(), Namespace: newHPA.GetNamespace()}, &currentHPA); err != nil {
 			if errors.IsNotFound(err) {
 				logger.WithContext(ctx).Info(fmt.Sprintf("creating HPA %s configuration", newHPA.Name))
-				return rclient.Create(ctx, newHPA)
+				return createObject(ctx, rclient, newHPA)
 			}
 			return fmt.Errorf("cannot get exist hpa object: %w", err)
 		}
@@ -40,13 +40,21 @@ func HPA(ctx context.Context, rclient client.Client, newHPA, prevHPA *v2.Horizon
 			return nil
 		}
 
-		newHPA.Annotations = mergeAnnotations(currentHPA.Annotations, newHPA.Annotations, prevAnnotations)
-		cloneSignificantMetadata(newHPA, &currentHPA)
-		newHPA.Status = currentHPA.Status
+		isIntentEqual := prevHPA != nil && equality.Semantic.DeepEqual(newHPA.Spec, prevHPA.Spec) && isPrevMetaEqual(newHPA, prevHPA)
+		if detectDrift(ctx, "HorizontalPodAutoscaler", &currentHPA, isIntentEqual, func() []string {
+			return append(metadataDriftFields(newHPA, &currentHPA, prevAnnotations), specDriftFields("spec", newHPA.Spec, currentHPA.Spec)...)
+		}) {
+			return nil
+		}
 
 		logMsg := fmt.Sprintf("updating HPA %s configuration spec_diff: %s", newHPA.Name, diffDeepDerivative(newHPA.Spec, currentHPA.Spec))
 		logger.WithContext(ctx).Info(logMsg)
 
-		return rclient.Update(ctx, newHPA)
+		return updateObject(ctx, rclient, newHPA, &currentHPA, func() client.Object {
+			newHPA.Annotations = mergeAnnotations(currentHPA.Annotations, newHPA.Annotations, prevAnnotations)
+			cloneSignificantMetadata(newHPA, &currentHPA)
+			newHPA.Status = currentHPA.Status
+			return newHPA
+		})
 	})
 }
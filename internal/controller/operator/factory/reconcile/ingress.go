@@ -0,0 +1,57 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/finalize"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+)
+
+// Ingress creates or updates ingress object
+func Ingress(ctx context.Context, rclient client.Client, newIngress, prevIngress *networkingv1.Ingress) error {
+	var currentIngress networkingv1.Ingress
+	if err := rclient.Get(ctx, types.NamespacedName{Namespace: newIngress.Namespace, Name: newIngress.Name}, &currentIngress); err != nil {
+		if errors.IsNotFound(err) {
+			logger.WithContext(ctx).Info(fmt.Sprintf("creating new Ingress %s", newIngress.Name))
+			return createObject(ctx, rclient, newIngress)
+		}
+		return fmt.Errorf("cannot get existing ingress: %w", err)
+	}
+	if err := finalize.FreeIfNeeded(ctx, rclient, &currentIngress); err != nil {
+		return err
+	}
+	var prevAnnotations map[string]string
+	if prevIngress != nil {
+		prevAnnotations = prevIngress.Annotations
+	}
+	if equality.Semantic.DeepDerivative(newIngress.Spec, currentIngress.Spec) &&
+		equality.Semantic.DeepEqual(newIngress.Labels, currentIngress.Labels) &&
+		isAnnotationsEqual(currentIngress.Annotations, newIngress.Annotations, prevAnnotations) {
+		return nil
+	}
+
+	isIntentEqual := prevIngress != nil && equality.Semantic.DeepEqual(newIngress.Spec, prevIngress.Spec) && isPrevMetaEqual(newIngress, prevIngress)
+	if detectDrift(ctx, "Ingress", &currentIngress, isIntentEqual, func() []string {
+		return append(metadataDriftFields(newIngress, &currentIngress, prevAnnotations), specDriftFields("spec", newIngress.Spec, currentIngress.Spec)...)
+	}) {
+		return nil
+	}
+
+	logger.WithContext(ctx).Info(fmt.Sprintf("updating Ingress %s configuration spec_diff: %s", newIngress.Name, diffDeepDerivative(newIngress.Spec, currentIngress.Spec)))
+
+	return updateObject(ctx, rclient, newIngress, &currentIngress, func() client.Object {
+		vmv1beta1.AddFinalizer(newIngress, &currentIngress)
+		newIngress.Annotations = mergeAnnotations(currentIngress.Annotations, newIngress.Annotations, prevAnnotations)
+		cloneSignificantMetadata(newIngress, &currentIngress)
+		newIngress.Status = currentIngress.Status
+		return newIngress
+	})
+}
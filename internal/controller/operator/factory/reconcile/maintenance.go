@@ -0,0 +1,158 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+)
+
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+type maintenanceGateKey struct{}
+
+// errMaintenanceDeferred indicates that disruptive change was deferred until maintenance window
+var errMaintenanceDeferred = errors.New("deferred until maintenance window")
+
+type maintenanceWindow struct {
+	schedule cron.Schedule
+	duration time.Duration
+	location *time.Location
+}
+
+// MaintenanceGate defers disruptive changes of parent object outside of maintenance windows
+type MaintenanceGate struct {
+	open     bool
+	nextOpen time.Time
+
+	mu      sync.Mutex
+	actions []string
+}
+
+// NewMaintenanceGate returns gate for the given maintenance windows at the given time
+// gate is always open if windows are not defined or force is set
+func NewMaintenanceGate(windows []vmv1beta1.MaintenanceWindow, force bool, now time.Time) (*MaintenanceGate, error) {
+	mg := &MaintenanceGate{open: len(windows) == 0 || force}
+	for idx, w := range windows {
+		mw, err := parseMaintenanceWindow(w)
+		if err != nil {
+			return nil, fmt.Errorf("incorrect maintenanceWindows[%d]: %w", idx, err)
+		}
+		localNow := now.In(mw.location)
+		// window is open, if it was started during the last duration
+		if !mw.schedule.Next(localNow.Add(-mw.duration)).After(localNow) {
+			mg.open = true
+		}
+		next := mw.schedule.Next(localNow)
+		if mg.nextOpen.IsZero() || next.Before(mg.nextOpen) {
+			mg.nextOpen = next
+		}
+	}
+	return mg, nil
+}
+
+func parseMaintenanceWindow(w vmv1beta1.MaintenanceWindow) (*maintenanceWindow, error) {
+	schedule, err := cronParser.Parse(w.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse schedule=%q: %w", w.Schedule, err)
+	}
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse duration=%q: %w", w.Duration, err)
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("duration=%q must be positive", w.Duration)
+	}
+	location := time.UTC
+	if w.TimeZone != "" {
+		location, err = time.LoadLocation(w.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load timeZone=%q: %w", w.TimeZone, err)
+		}
+	}
+	return &maintenanceWindow{schedule: schedule, duration: duration, location: location}, nil
+}
+
+// WithMaintenanceGate adds gate to the given context
+func WithMaintenanceGate(ctx context.Context, mg *MaintenanceGate) context.Context {
+	return context.WithValue(ctx, maintenanceGateKey{}, mg)
+}
+
+// IsOpen checks if disruptive changes are allowed
+func (mg *MaintenanceGate) IsOpen() bool {
+	return mg.open
+}
+
+// NextOpen returns start time of the next maintenance window
+// it returns zero time if windows are not defined
+func (mg *MaintenanceGate) NextOpen() time.Time {
+	return mg.nextOpen
+}
+
+// PendingActions returns disruptive changes deferred until maintenance window
+func (mg *MaintenanceGate) PendingActions() []string {
+	mg.mu.Lock()
+	defer mg.mu.Unlock()
+	return append([]string(nil), mg.actions...)
+}
+
+// WriteCondition sets PendingMaintenance condition with list of deferred changes
+// condition is changed to False if there are no deferred changes anymore
+func (mg *MaintenanceGate) WriteCondition(stm *vmv1beta1.StatusMetadata, generation int64) {
+	actions := mg.PendingActions()
+	now := metav1.NewTime(time.Now())
+	cond := vmv1beta1.Condition{
+		Type:               vmv1beta1.ConditionPendingMaintenance,
+		Status:             metav1.ConditionTrue,
+		Reason:             "DisruptiveChangesDeferred",
+		Message:            fmt.Sprintf("%s, next maintenance window starts at %s", strings.Join(actions, "; "), mg.nextOpen.Format(time.RFC3339)),
+		ObservedGeneration: generation,
+		LastTransitionTime: now,
+		LastUpdateTime:     now,
+	}
+	if len(actions) == 0 {
+		var hasPending bool
+		for _, c := range stm.Conditions {
+			if c.Type == vmv1beta1.ConditionPendingMaintenance && c.Status == metav1.ConditionTrue {
+				hasPending = true
+				break
+			}
+		}
+		if !hasPending {
+			return
+		}
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "NoPendingChanges"
+		cond.Message = ""
+	}
+	stm.Conditions = setConditionTo(stm.Conditions, cond)
+}
+
+// allowDisruptive checks if disruptive action could be performed
+// otherwise action is recorded as pending until maintenance window
+func allowDisruptive(ctx context.Context, action string) bool {
+	mg, _ := ctx.Value(maintenanceGateKey{}).(*MaintenanceGate)
+	if mg == nil || mg.open {
+		return true
+	}
+	logger.WithContext(ctx).Info(fmt.Sprintf("deferring %s until maintenance window", action))
+	mg.mu.Lock()
+	defer mg.mu.Unlock()
+	if !slices.Contains(mg.actions, action) {
+		mg.actions = append(mg.actions, action)
+	}
+	return false
+}
+
+func isMaintenanceDeferred(err error) bool {
+	return errors.Is(err, errMaintenanceDeferred)
+}
@@ -0,0 +1,283 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func TestNewMaintenanceGate(t *testing.T) {
+	f := func(windows []vmv1beta1.MaintenanceWindow, force bool, now string, wantOpen bool, wantNextOpen string, wantErr bool) {
+		t.Helper()
+		ts, err := time.Parse(time.RFC3339, now)
+		assert.NoError(t, err)
+		mg, err := NewMaintenanceGate(windows, force, ts)
+		if wantErr {
+			assert.Error(t, err)
+			return
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, wantOpen, mg.IsOpen())
+		if wantNextOpen == "" {
+			assert.True(t, mg.NextOpen().IsZero())
+			return
+		}
+		wantNext, err := time.Parse(time.RFC3339, wantNextOpen)
+		assert.NoError(t, err)
+		assert.True(t, wantNext.Equal(mg.NextOpen()), "unexpected next open time: %s", mg.NextOpen())
+	}
+	saturdayNight := []vmv1beta1.MaintenanceWindow{{Schedule: "0 2 * * 6", Duration: "2h"}}
+
+	// no windows
+	f(nil, false, "2025-01-01T10:00:00Z", true, "", false)
+
+	// inside window
+	f(saturdayNight, false, "2025-01-04T03:00:00Z", true, "2025-01-11T02:00:00Z", false)
+	f(saturdayNight, false, "2025-01-04T02:00:00Z", true, "2025-01-11T02:00:00Z", false)
+
+	// outside window
+	f(saturdayNight, false, "2025-01-04T04:00:00Z", false, "2025-01-11T02:00:00Z", false)
+	f(saturdayNight, false, "2025-01-03T10:00:00Z", false, "2025-01-04T02:00:00Z", false)
+
+	// forced apply
+	f(saturdayNight, true, "2025-01-03T10:00:00Z", true, "2025-01-04T02:00:00Z", false)
+
+	// time zone
+	f([]vmv1beta1.MaintenanceWindow{{Schedule: "0 2 * * *", Duration: "1h", TimeZone: "Europe/Berlin"}}, false, "2025-01-04T01:30:00Z", true, "2025-01-05T01:00:00Z", false)
+	f([]vmv1beta1.MaintenanceWindow{{Schedule: "0 2 * * *", Duration: "1h", TimeZone: "Europe/Berlin"}}, false, "2025-01-04T02:30:00Z", false, "2025-01-05T01:00:00Z", false)
+
+	// multiple windows
+	f([]vmv1beta1.MaintenanceWindow{
+		{Schedule: "0 2 * * 6", Duration: "2h"},
+		{Schedule: "0 12 * * 1-5", Duration: "30m"},
+	}, false, "2025-01-03T12:10:00Z", true, "2025-01-04T02:00:00Z", false)
+
+	// incorrect windows
+	f([]vmv1beta1.MaintenanceWindow{{Schedule: "0 2 * *", Duration: "2h"}}, false, "2025-01-03T12:10:00Z", false, "", true)
+	f([]vmv1beta1.MaintenanceWindow{{Schedule: "0 2 * * *", Duration: "2d"}}, false, "2025-01-03T12:10:00Z", false, "", true)
+	f([]vmv1beta1.MaintenanceWindow{{Schedule: "0 2 * * *", Duration: "2h", TimeZone: "Mars/Olympus"}}, false, "2025-01-03T12:10:00Z", false, "", true)
+}
+
+func TestMaintenanceGateWriteCondition(t *testing.T) {
+	mg, err := NewMaintenanceGate([]vmv1beta1.MaintenanceWindow{{Schedule: "0 2 * * 6", Duration: "2h"}}, false, time.Date(2025, 1, 3, 10, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	var stm vmv1beta1.StatusMetadata
+
+	// no pending changes
+	mg.WriteCondition(&stm, 1)
+	assert.Empty(t, stm.Conditions)
+
+	ctx := WithMaintenanceGate(context.Background(), mg)
+	assert.False(t, allowDisruptive(ctx, "rolling update of StatefulSet=vmstorage-example pods=3"))
+	assert.False(t, allowDisruptive(ctx, "rolling update of StatefulSet=vmstorage-example pods=3"))
+	assert.Equal(t, []string{"rolling update of StatefulSet=vmstorage-example pods=3"}, mg.PendingActions())
+	mg.WriteCondition(&stm, 1)
+	assert.Len(t, stm.Conditions, 1)
+	assert.Equal(t, vmv1beta1.ConditionPendingMaintenance, stm.Conditions[0].Type)
+	assert.Equal(t, metav1.ConditionTrue, stm.Conditions[0].Status)
+	assert.Equal(t, "rolling update of StatefulSet=vmstorage-example pods=3, next maintenance window starts at 2025-01-04T02:00:00Z", stm.Conditions[0].Message)
+
+	// changes were applied
+	mg, err = NewMaintenanceGate(nil, false, time.Now())
+	assert.NoError(t, err)
+	ctx = WithMaintenanceGate(context.Background(), mg)
+	assert.True(t, allowDisruptive(ctx, "rolling update of StatefulSet=vmstorage-example pods=3"))
+	mg.WriteCondition(&stm, 1)
+	assert.Len(t, stm.Conditions, 1)
+	assert.Equal(t, metav1.ConditionFalse, stm.Conditions[0].Status)
+}
+
+func TestMaintenanceGateDefersPVCExpansion(t *testing.T) {
+	f := func(open bool, wantSize string) {
+		t.Helper()
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "vmstorage", Namespace: "default"},
+			Spec: appsv1.StatefulSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "vmstorage"}},
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "data",
+						Annotations: map[string]string{vmv1beta1.PVCExpandableLabel: "true"},
+					},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse("20Gi"),
+						}},
+					},
+				}},
+			},
+		}
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "data-vmstorage-0",
+				Namespace: "default",
+				Labels:    map[string]string{"app": "vmstorage"},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("10Gi"),
+				}},
+			},
+		}
+		rclient := k8stools.GetTestClientWithObjects([]runtime.Object{pvc})
+		var windows []vmv1beta1.MaintenanceWindow
+		if !open {
+			windows = []vmv1beta1.MaintenanceWindow{{Schedule: "0 2 1 1 *", Duration: "1m"}}
+		}
+		mg, err := NewMaintenanceGate(windows, false, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		ctx := WithMaintenanceGate(context.Background(), mg)
+		assert.NoError(t, growSTSPVC(ctx, rclient, sts))
+
+		var got corev1.PersistentVolumeClaim
+		assert.NoError(t, rclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "data-vmstorage-0"}, &got))
+		assert.Equal(t, wantSize, got.Spec.Resources.Requests.Storage().String())
+		if open {
+			assert.Empty(t, mg.PendingActions())
+		} else {
+			assert.Equal(t, []string{"expansion of PVC=data-vmstorage-0 to size=20Gi"}, mg.PendingActions())
+		}
+	}
+	f(true, "20Gi")
+	f(false, "10Gi")
+}
+
+func TestMaintenanceGateDefersRollingUpdate(t *testing.T) {
+	f := func(open bool, wantImage string, wantPending []string) {
+		t.Helper()
+		newSts := func(image string) *appsv1.StatefulSet {
+			return &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "vmagent", Namespace: "default"},
+				Spec: appsv1.StatefulSetSpec{
+					Replicas:       ptr.To[int32](2),
+					Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "vmagent"}},
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "vmagent"}},
+						Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "vmagent", Image: image}}},
+					},
+				},
+				Status: appsv1.StatefulSetStatus{ReadyReplicas: 2, UpdatedReplicas: 2},
+			}
+		}
+		prevSts := newSts("vmagent:v1.0.0")
+		rclient := k8stools.GetTestClientWithObjects([]runtime.Object{prevSts.DeepCopy()})
+		var windows []vmv1beta1.MaintenanceWindow
+		if !open {
+			windows = []vmv1beta1.MaintenanceWindow{{Schedule: "0 2 1 1 *", Duration: "1m"}}
+		}
+		mg, err := NewMaintenanceGate(windows, false, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		ctx := WithMaintenanceGate(context.Background(), mg)
+		sts := newSts("vmagent:v1.1.0")
+		sts.Labels = map[string]string{"version": "v1.1.0"}
+		assert.NoError(t, HandleSTSUpdate(ctx, rclient, STSOptions{}, sts, prevSts))
+
+		var got appsv1.StatefulSet
+		assert.NoError(t, rclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "vmagent"}, &got))
+		assert.Equal(t, wantImage, got.Spec.Template.Spec.Containers[0].Image)
+		// not disruptive changes are applied
+		assert.Equal(t, "v1.1.0", got.Labels["version"])
+		assert.Equal(t, wantPending, mg.PendingActions())
+	}
+	f(true, "vmagent:v1.1.0", nil)
+	f(false, "vmagent:v1.0.0", []string{"rolling update of StatefulSet=vmagent"})
+}
+
+func TestMaintenanceGateDefersStandalonePVCExpansion(t *testing.T) {
+	f := func(open bool, wantSize string) {
+		t.Helper()
+		newPVC := func(size string) *corev1.PersistentVolumeClaim {
+			return &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "vmsingle-data",
+					Namespace:   "default",
+					Labels:      map[string]string{"app": "vmsingle"},
+					Annotations: map[string]string{vmv1beta1.PVCExpandableLabel: "true"},
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse(size),
+					}},
+				},
+			}
+		}
+		rclient := k8stools.GetTestClientWithObjects([]runtime.Object{newPVC("10Gi")})
+		var windows []vmv1beta1.MaintenanceWindow
+		if !open {
+			windows = []vmv1beta1.MaintenanceWindow{{Schedule: "0 2 1 1 *", Duration: "1m"}}
+		}
+		mg, err := NewMaintenanceGate(windows, false, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		ctx := WithMaintenanceGate(context.Background(), mg)
+		pvc := newPVC("20Gi")
+		pvc.Labels["team"] = "a"
+		assert.NoError(t, PersistentVolumeClaim(ctx, rclient, pvc, nil))
+
+		var got corev1.PersistentVolumeClaim
+		assert.NoError(t, rclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "vmsingle-data"}, &got))
+		assert.Equal(t, wantSize, got.Spec.Resources.Requests.Storage().String())
+		// not disruptive changes are applied
+		assert.Equal(t, "a", got.Labels["team"])
+		if open {
+			assert.Empty(t, mg.PendingActions())
+		} else {
+			assert.Equal(t, []string{"expansion of PVC=vmsingle-data to size=20Gi"}, mg.PendingActions())
+		}
+	}
+	f(true, "20Gi")
+	f(false, "10Gi")
+}
+
+func TestMaintenanceGateDefersDeploymentRollingUpdate(t *testing.T) {
+	f := func(open bool, wantImage string, wantPending []string) {
+		t.Helper()
+		newDeploy := func(image string) *appsv1.Deployment {
+			return &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "vmsingle", Namespace: "default"},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: ptr.To[int32](1),
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "vmsingle"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "vmsingle"}},
+						Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "vmsingle", Image: image}}},
+					},
+				},
+				Status: appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+			}
+		}
+		prevDeploy := newDeploy("vmsingle:v1.0.0")
+		rclient := k8stools.GetTestClientWithObjects([]runtime.Object{prevDeploy.DeepCopy()})
+		var windows []vmv1beta1.MaintenanceWindow
+		if !open {
+			windows = []vmv1beta1.MaintenanceWindow{{Schedule: "0 2 1 1 *", Duration: "1m"}}
+		}
+		mg, err := NewMaintenanceGate(windows, false, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		ctx := WithMaintenanceGate(context.Background(), mg)
+		dep := newDeploy("vmsingle:v1.1.0")
+		dep.Labels = map[string]string{"version": "v1.1.0"}
+		assert.NoError(t, Deployment(ctx, rclient, dep, prevDeploy, false))
+
+		var got appsv1.Deployment
+		assert.NoError(t, rclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "vmsingle"}, &got))
+		assert.Equal(t, wantImage, got.Spec.Template.Spec.Containers[0].Image)
+		// not disruptive changes are applied
+		assert.Equal(t, "v1.1.0", got.Labels["version"])
+		assert.Equal(t, wantPending, mg.PendingActions())
+	}
+	f(true, "vmsingle:v1.1.0", nil)
+	f(false, "vmsingle:v1.0.0", []string{"rolling update of Deployment=vmsingle"})
+}
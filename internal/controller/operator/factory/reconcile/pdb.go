@@ -23,7 +23,7 @@ func PDB(ctx context.Context, rclient client.Client, newPDB, prevPDB *policyv1.P
 		if err != nil {
 			if errors.IsNotFound(err) {
 				logger.WithContext(ctx).Info(fmt.Sprintf("creating new PDB %s", newPDB.Name))
-				return rclient.Create(ctx, newPDB)
+				return createObject(ctx, rclient, newPDB)
 			}
 			return fmt.Errorf("cannot get existing pdb: %s, err: %w", newPDB.Name, err)
 		}
@@ -41,14 +41,22 @@ func PDB(ctx context.Context, rclient client.Client, newPDB, prevPDB *policyv1.P
 			isAnnotationsEqual(currentPdb.Annotations, newPDB.Annotations, prevAnnotations) {
 			return nil
 		}
+		isIntentEqual := prevPDB != nil && equality.Semantic.DeepEqual(newPDB.Spec, prevPDB.Spec) && isPrevMetaEqual(newPDB, prevPDB)
+		if detectDrift(ctx, "PodDisruptionBudget", currentPdb, isIntentEqual, func() []string {
+			return append(metadataDriftFields(newPDB, currentPdb, prevAnnotations), specDriftFields("spec", newPDB.Spec, currentPdb.Spec)...)
+		}) {
+			return nil
+		}
+
 		logMsg := fmt.Sprintf("updating PDB %s configuration spec_diff: %s", newPDB.Name, diffDeep(newPDB.Spec, currentPdb.Spec))
 		logger.WithContext(ctx).Info(logMsg)
 
-		cloneSignificantMetadata(newPDB, currentPdb)
-		// for some reason Status is not marked as status sub-resource at PDB CRD
-		newPDB.Status = currentPdb.Status
-		newPDB.Annotations = mergeAnnotations(currentPdb.Annotations, newPDB.Annotations, prevAnnotations)
-
-		return rclient.Update(ctx, newPDB)
+		return updateObject(ctx, rclient, newPDB, currentPdb, func() client.Object {
+			cloneSignificantMetadata(newPDB, currentPdb)
+			// for some reason Status is not marked as status sub-resource at PDB CRD
+			newPDB.Status = currentPdb.Status
+			newPDB.Annotations = mergeAnnotations(currentPdb.Annotations, newPDB.Annotations, prevAnnotations)
+			return newPDB
+		})
 	})
 }
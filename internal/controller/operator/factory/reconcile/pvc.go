@@ -28,7 +28,7 @@ func PersistentVolumeClaim(ctx context.Context, rclient client.Client, newPVC, p
 	if err != nil {
 		if errors.IsNotFound(err) {
 			l.Info(fmt.Sprintf("creating new PVC %s", newPVC.Name))
-			if err := rclient.Create(ctx, newPVC); err != nil {
+			if err := createObject(ctx, rclient, newPVC); err != nil {
 				return fmt.Errorf("cannot create new PVC: %w", err)
 			}
 			return nil
@@ -48,11 +48,6 @@ func PersistentVolumeClaim(ctx context.Context, rclient client.Client, newPVC, p
 	}
 
 	isResizeNeeded := mayGrow(ctx, newPVC, newSize, oldSize)
-	if !isResizeNeeded &&
-		equality.Semantic.DeepEqual(newPVC.Labels, currentPVC.Labels) &&
-		isAnnotationsEqual(currentPVC.Annotations, newPVC.Annotations, prevAnnotations) {
-		return nil
-	}
 	if isResizeNeeded {
 		// check if storage class is expandable
 		isExpandable, err := isStorageClassExpandable(ctx, rclient, newPVC)
@@ -64,21 +59,33 @@ func PersistentVolumeClaim(ctx context.Context, rclient client.Client, newPVC, p
 			logger.WithContext(ctx).Info(fmt.Sprintf("storageClass %s for PVC %s doesn't support live resizing", ptr.Deref(newPVC.Spec.StorageClassName, "default"), newPVC.Name))
 			return nil
 		}
+		// volume expansion may require filesystem resize and pod restart
+		if !allowDisruptive(ctx, fmt.Sprintf("expansion of PVC=%s to size=%s", newPVC.Name, newSize)) {
+			isResizeNeeded = false
+		}
+	}
+	if !isResizeNeeded &&
+		equality.Semantic.DeepEqual(newPVC.Labels, currentPVC.Labels) &&
+		isAnnotationsEqual(currentPVC.Annotations, newPVC.Annotations, prevAnnotations) {
+		return nil
 	}
 
-	newResources := newPVC.Spec.Resources.DeepCopy()
-	// keep old spec with new resource requests
-	newPVC.Spec = currentPVC.Spec
-	newPVC.Spec.Resources = *newResources
-
-	vmv1beta1.AddFinalizer(newPVC, currentPVC)
-	newPVC.Annotations = mergeAnnotations(currentPVC.Annotations, newPVC.Annotations, prevAnnotations)
-	cloneSignificantMetadata(newPVC, currentPVC)
+	// operator owns only resources of PVC spec
+	// other fields are immutable or set by storage provisioner
+	newResources := currentPVC.Spec.Resources.DeepCopy()
+	if isResizeNeeded {
+		newResources = newPVC.Spec.Resources.DeepCopy()
+	}
+	newPVC.Spec = corev1.PersistentVolumeClaimSpec{Resources: *newResources}
 
 	logger.WithContext(ctx).Info(fmt.Sprintf("updating PVC %s configuration", newPVC.Name))
 
-	if err := rclient.Update(ctx, newPVC); err != nil {
-		return err
-	}
-	return nil
+	return updateObject(ctx, rclient, newPVC, currentPVC, func() client.Object {
+		newPVC.Spec = *currentPVC.Spec.DeepCopy()
+		newPVC.Spec.Resources = *newResources
+		vmv1beta1.AddFinalizer(newPVC, currentPVC)
+		newPVC.Annotations = mergeAnnotations(currentPVC.Annotations, newPVC.Annotations, prevAnnotations)
+		cloneSignificantMetadata(newPVC, currentPVC)
+		return newPVC
+	})
 }
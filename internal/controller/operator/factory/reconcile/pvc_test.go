@@ -0,0 +1,52 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func TestPersistentVolumeClaim(t *testing.T) {
+	pvcWithSize := func(size string, lbls map[string]string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default", Labels: lbls},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			},
+		}
+	}
+	f := func(newPVC *corev1.PersistentVolumeClaim, wantSize string, wantLabels map[string]string) {
+		t.Helper()
+		current := pvcWithSize("10Gi", map[string]string{"app": "vmsingle"})
+		current.Spec.VolumeName = "pv-1"
+		current.Spec.StorageClassName = ptr.To("standard")
+		ctx := context.Background()
+		fclient := k8stools.GetTestClientWithObjects([]runtime.Object{current})
+		assert.NoError(t, PersistentVolumeClaim(ctx, fclient, newPVC, nil))
+
+		var got corev1.PersistentVolumeClaim
+		assert.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "data"}, &got))
+		assert.Equal(t, "pv-1", got.Spec.VolumeName)
+		assert.Equal(t, "standard", ptr.Deref(got.Spec.StorageClassName, ""))
+		assert.Equal(t, resource.MustParse(wantSize), got.Spec.Resources.Requests[corev1.ResourceStorage])
+		assert.Equal(t, wantLabels, got.Labels)
+	}
+
+	// labels update keeps spec fields set by provisioner
+	f(pvcWithSize("10Gi", map[string]string{"app": "vmsingle", "team": "a"}), "10Gi", map[string]string{"app": "vmsingle", "team": "a"})
+
+	// size cannot be decreased
+	f(pvcWithSize("5Gi", map[string]string{"app": "vmsingle", "team": "a"}), "10Gi", map[string]string{"app": "vmsingle", "team": "a"})
+}
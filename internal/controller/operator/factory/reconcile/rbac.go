@@ -21,7 +21,7 @@ func RoleBinding(ctx context.Context, rclient client.Client, newRB, prevRB *rbac
 	if err := rclient.Get(ctx, types.NamespacedName{Namespace: newRB.Namespace, Name: newRB.Name}, &currentRB); err != nil {
 		if errors.IsNotFound(err) {
 			logger.WithContext(ctx).Info(fmt.Sprintf("creating new RoleBinding %s", newRB.Name))
-			return rclient.Create(ctx, newRB)
+			return createObject(ctx, rclient, newRB)
 		}
 		return fmt.Errorf("cannot get exist rolebinding: %w", err)
 	}
@@ -40,16 +40,26 @@ func RoleBinding(ctx context.Context, rclient client.Client, newRB, prevRB *rbac
 		isAnnotationsEqual(currentRB.Annotations, newRB.Annotations, prevAnnotations) {
 		return nil
 	}
+	isIntentEqual := prevRB != nil &&
+		equality.Semantic.DeepEqual(newRB.Subjects, prevRB.Subjects) &&
+		equality.Semantic.DeepEqual(newRB.RoleRef, prevRB.RoleRef) &&
+		isPrevMetaEqual(newRB, prevRB)
+	if detectDrift(ctx, "RoleBinding", &currentRB, isIntentEqual, func() []string {
+		return append(metadataDriftFields(newRB, &currentRB, prevAnnotations), specDriftFields("subjects", newRB.Subjects, currentRB.Subjects)...)
+	}) {
+		return nil
+	}
 	logger.WithContext(ctx).Info(fmt.Sprintf("updating RoleBinding %s configuration", newRB.Name))
 
-	currentRB.Annotations = mergeAnnotations(currentRB.Annotations, newRB.Annotations, prevAnnotations)
-	currentRB.Labels = newRB.Labels
-	currentRB.Subjects = newRB.Subjects
-	currentRB.RoleRef = newRB.RoleRef
-	currentRB.OwnerReferences = newRB.OwnerReferences
-	vmv1beta1.AddFinalizer(&currentRB, &currentRB)
-
-	return rclient.Update(ctx, &currentRB)
+	return updateObject(ctx, rclient, newRB, &currentRB, func() client.Object {
+		currentRB.Annotations = mergeAnnotations(currentRB.Annotations, newRB.Annotations, prevAnnotations)
+		currentRB.Labels = newRB.Labels
+		currentRB.Subjects = newRB.Subjects
+		currentRB.RoleRef = newRB.RoleRef
+		currentRB.OwnerReferences = newRB.OwnerReferences
+		vmv1beta1.AddFinalizer(&currentRB, &currentRB)
+		return &currentRB
+	})
 }
 
 // Role reconciles role object
@@ -58,7 +68,7 @@ func Role(ctx context.Context, rclient client.Client, newRL, prevRL *rbacv1.Role
 	if err := rclient.Get(ctx, types.NamespacedName{Namespace: newRL.Namespace, Name: newRL.Name}, &currentRL); err != nil {
 		if errors.IsNotFound(err) {
 			logger.WithContext(ctx).Info(fmt.Sprintf("creating new Role %s", newRL.Name))
-			return rclient.Create(ctx, newRL)
+			return createObject(ctx, rclient, newRL)
 		}
 		return fmt.Errorf("cannot get exist role: %w", err)
 	}
@@ -75,15 +85,22 @@ func Role(ctx context.Context, rclient client.Client, newRL, prevRL *rbacv1.Role
 		isAnnotationsEqual(currentRL.Annotations, newRL.Annotations, prevAnnotations) {
 		return nil
 	}
+	isIntentEqual := prevRL != nil && equality.Semantic.DeepEqual(newRL.Rules, prevRL.Rules) && isPrevMetaEqual(newRL, prevRL)
+	if detectDrift(ctx, "Role", &currentRL, isIntentEqual, func() []string {
+		return append(metadataDriftFields(newRL, &currentRL, prevAnnotations), specDriftFields("rules", newRL.Rules, currentRL.Rules)...)
+	}) {
+		return nil
+	}
 	logger.WithContext(ctx).Info(fmt.Sprintf("updating Role %s configuration", newRL.Name))
 
-	currentRL.Annotations = mergeAnnotations(currentRL.Annotations, newRL.Annotations, prevAnnotations)
-	currentRL.Labels = newRL.Labels
-	currentRL.Rules = newRL.Rules
-	currentRL.OwnerReferences = newRL.OwnerReferences
-	vmv1beta1.AddFinalizer(&currentRL, &currentRL)
-
-	return rclient.Update(ctx, &currentRL)
+	return updateObject(ctx, rclient, newRL, &currentRL, func() client.Object {
+		currentRL.Annotations = mergeAnnotations(currentRL.Annotations, newRL.Annotations, prevAnnotations)
+		currentRL.Labels = newRL.Labels
+		currentRL.Rules = newRL.Rules
+		currentRL.OwnerReferences = newRL.OwnerReferences
+		vmv1beta1.AddFinalizer(&currentRL, &currentRL)
+		return &currentRL
+	})
 }
 
 // ClusterRoleBinding reconciles cluster role binding object
@@ -93,7 +110,7 @@ func ClusterRoleBinding(ctx context.Context, rclient client.Client, newCRB, prev
 	if err := rclient.Get(ctx, types.NamespacedName{Name: newCRB.Name, Namespace: newCRB.Namespace}, &currentCRB); err != nil {
 		if errors.IsNotFound(err) {
 			logger.WithContext(ctx).Info(fmt.Sprintf("creating new ClusterRoleBinding %s", newCRB.Name))
-			return rclient.Create(ctx, newCRB)
+			return createObject(ctx, rclient, newCRB)
 		}
 		return fmt.Errorf("cannot get crb: %w", err)
 	}
@@ -112,15 +129,26 @@ func ClusterRoleBinding(ctx context.Context, rclient client.Client, newCRB, prev
 		isAnnotationsEqual(currentCRB.Annotations, newCRB.Annotations, prevAnnotations) {
 		return nil
 	}
+	isIntentEqual := prevCRB != nil &&
+		equality.Semantic.DeepEqual(newCRB.Subjects, prevCRB.Subjects) &&
+		equality.Semantic.DeepEqual(newCRB.RoleRef, prevCRB.RoleRef) &&
+		isPrevMetaEqual(newCRB, prevCRB)
+	if detectDrift(ctx, "ClusterRoleBinding", &currentCRB, isIntentEqual, func() []string {
+		return append(metadataDriftFields(newCRB, &currentCRB, prevAnnotations), specDriftFields("subjects", newCRB.Subjects, currentCRB.Subjects)...)
+	}) {
+		return nil
+	}
 	logger.WithContext(ctx).Info(fmt.Sprintf("updating ClusterRoleBinding %s", newCRB.Name))
 
-	currentCRB.OwnerReferences = newCRB.OwnerReferences
-	currentCRB.Labels = newCRB.Labels
-	currentCRB.Annotations = mergeAnnotations(currentCRB.Annotations, newCRB.Annotations, prevAnnotations)
-	currentCRB.Subjects = newCRB.Subjects
-	currentCRB.RoleRef = newCRB.RoleRef
-	vmv1beta1.AddFinalizer(&currentCRB, &currentCRB)
-	return rclient.Update(ctx, &currentCRB)
+	return updateObject(ctx, rclient, newCRB, &currentCRB, func() client.Object {
+		currentCRB.OwnerReferences = newCRB.OwnerReferences
+		currentCRB.Labels = newCRB.Labels
+		currentCRB.Annotations = mergeAnnotations(currentCRB.Annotations, newCRB.Annotations, prevAnnotations)
+		currentCRB.Subjects = newCRB.Subjects
+		currentCRB.RoleRef = newCRB.RoleRef
+		vmv1beta1.AddFinalizer(&currentCRB, &currentCRB)
+		return &currentCRB
+	})
 
 }
 
@@ -131,7 +159,7 @@ func ClusterRole(ctx context.Context, rclient client.Client, newClusterRole, pre
 	if err := rclient.Get(ctx, types.NamespacedName{Name: newClusterRole.Name, Namespace: newClusterRole.Namespace}, &currentClusterRole); err != nil {
 		if errors.IsNotFound(err) {
 			logger.WithContext(ctx).Info(fmt.Sprintf("creating new ClusterRole %s", newClusterRole.Name))
-			return rclient.Create(ctx, newClusterRole)
+			return createObject(ctx, rclient, newClusterRole)
 		}
 		return fmt.Errorf("cannot get exist cluster role: %w", err)
 	}
@@ -148,12 +176,20 @@ func ClusterRole(ctx context.Context, rclient client.Client, newClusterRole, pre
 		isAnnotationsEqual(currentClusterRole.Annotations, newClusterRole.Annotations, prevAnnotations) {
 		return nil
 	}
+	isIntentEqual := prevClusterRole != nil && equality.Semantic.DeepEqual(newClusterRole.Rules, prevClusterRole.Rules) && isPrevMetaEqual(newClusterRole, prevClusterRole)
+	if detectDrift(ctx, "ClusterRole", &currentClusterRole, isIntentEqual, func() []string {
+		return append(metadataDriftFields(newClusterRole, &currentClusterRole, prevAnnotations), specDriftFields("rules", newClusterRole.Rules, currentClusterRole.Rules)...)
+	}) {
+		return nil
+	}
 	logger.WithContext(ctx).Info(fmt.Sprintf("updating ClusterRole %s", newClusterRole.Name))
 
-	currentClusterRole.OwnerReferences = newClusterRole.OwnerReferences
-	currentClusterRole.Labels = newClusterRole.Labels
-	currentClusterRole.Annotations = mergeAnnotations(currentClusterRole.Annotations, newClusterRole.Annotations, prevAnnotations)
-	currentClusterRole.Rules = newClusterRole.Rules
-	vmv1beta1.AddFinalizer(&currentClusterRole, &currentClusterRole)
-	return rclient.Update(ctx, &currentClusterRole)
+	return updateObject(ctx, rclient, newClusterRole, &currentClusterRole, func() client.Object {
+		currentClusterRole.OwnerReferences = newClusterRole.OwnerReferences
+		currentClusterRole.Labels = newClusterRole.Labels
+		currentClusterRole.Annotations = mergeAnnotations(currentClusterRole.Annotations, newClusterRole.Annotations, prevAnnotations)
+		currentClusterRole.Rules = newClusterRole.Rules
+		vmv1beta1.AddFinalizer(&currentClusterRole, &currentClusterRole)
+		return &currentClusterRole
+	})
 }
@@ -22,7 +22,7 @@ func Secret(ctx context.Context, rclient client.Client, newS *corev1.Secret, pre
 	if err := rclient.Get(ctx, types.NamespacedName{Namespace: newS.Namespace, Name: newS.Name}, &currentS); err != nil {
 		if errors.IsNotFound(err) {
 			logger.WithContext(ctx).Info(fmt.Sprintf("creating new Secret %s", newS.Name))
-			return rclient.Create(ctx, newS)
+			return createObject(ctx, rclient, newS)
 		}
 		return err
 	}
@@ -40,10 +40,21 @@ func Secret(ctx context.Context, rclient client.Client, newS *corev1.Secret, pre
 		return nil
 	}
 
-	newS.Annotations = mergeAnnotations(currentS.Annotations, newS.Annotations, prevAnnotations)
-	cloneSignificantMetadata(newS, &currentS)
+	if detectDrift(ctx, "Secret", &currentS, true, func() []string {
+		fields := metadataDriftFields(newS, &currentS, prevAnnotations)
+		if !equality.Semantic.DeepEqual(newS.Data, currentS.Data) {
+			fields = append(fields, "data")
+		}
+		return fields
+	}) {
+		return nil
+	}
 
 	logger.WithContext(ctx).Info(fmt.Sprintf("updating configuration Secret %s", newS.Name))
 
-	return rclient.Update(ctx, newS)
+	return updateObject(ctx, rclient, newS, &currentS, func() client.Object {
+		newS.Annotations = mergeAnnotations(currentS.Annotations, newS.Annotations, prevAnnotations)
+		cloneSignificantMetadata(newS, &currentS)
+		return newS
+	})
 }
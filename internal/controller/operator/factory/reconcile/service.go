@@ -47,7 +47,7 @@ func reconcileService(ctx context.Context, rclient client.Client, newService, pr
 			return fmt.Errorf("cannot delete service at recreate: %w", err)
 		}
 		logger.WithContext(ctx).Info(fmt.Sprintf("recreating new Service %s", newService.Name))
-		if err := rclient.Create(ctx, newService); err != nil {
+		if err := createObject(ctx, rclient, newService); err != nil {
 			return fmt.Errorf("cannot create service at recreate: %w", err)
 		}
 		return nil
@@ -57,7 +57,7 @@ func reconcileService(ctx context.Context, rclient client.Client, newService, pr
 	if err != nil {
 		if errors.IsNotFound(err) {
 			logger.WithContext(ctx).Info(fmt.Sprintf("creating new Service %s", newService.Name))
-			err := rclient.Create(ctx, newService)
+			err := createObject(ctx, rclient, newService)
 			if err != nil {
 				return fmt.Errorf("cannot create new service: %w", err)
 			}
@@ -120,9 +120,12 @@ func reconcileService(ctx context.Context, rclient client.Client, newService, pr
 		return nil
 	}
 
-	vmv1beta1.AddFinalizer(newService, currentService)
-	newService.Annotations = mergeAnnotations(currentService.Annotations, newService.Annotations, prevAnnotations)
-	cloneSignificantMetadata(newService, currentService)
+	isIntentEqual := isPrevServiceEqual && isPrevMetaEqual(newService, prevService)
+	if detectDrift(ctx, "Service", currentService, isIntentEqual, func() []string {
+		return append(metadataDriftFields(newService, currentService, prevAnnotations), specDriftFields("spec", newService.Spec, currentService.Spec)...)
+	}) {
+		return nil
+	}
 
 	logMsg := fmt.Sprintf("updating service %s configuration, is_current_equal=%v, is_prev_equal=%v, is_prev_nil=%v",
 		newService.Name, isEqual, isPrevServiceEqual, prevService == nil)
@@ -135,12 +138,12 @@ func reconcileService(ctx context.Context, rclient client.Client, newService, pr
 	}
 	logger.WithContext(ctx).Info(logMsg)
 
-	err = rclient.Update(ctx, newService)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return updateObject(ctx, rclient, newService, currentService, func() client.Object {
+		vmv1beta1.AddFinalizer(newService, currentService)
+		newService.Annotations = mergeAnnotations(currentService.Annotations, newService.Annotations, prevAnnotations)
+		cloneSignificantMetadata(newService, currentService)
+		return newService
+	})
 }
 
 // AdditionalServices reconcile AdditionalServices
@@ -23,7 +23,7 @@ func ServiceAccount(ctx context.Context, rclient client.Client, newSA, prevSA *c
 		if err := rclient.Get(ctx, types.NamespacedName{Name: newSA.Name, Namespace: newSA.Namespace}, &currentSA); err != nil {
 			if errors.IsNotFound(err) {
 				logger.WithContext(ctx).Info(fmt.Sprintf("creating new ServiceAccount %s", newSA.Name))
-				return rclient.Create(ctx, newSA)
+				return createObject(ctx, rclient, newSA)
 			}
 			return fmt.Errorf("cannot get ServiceAccount: %w", err)
 		}
@@ -38,12 +38,20 @@ func ServiceAccount(ctx context.Context, rclient client.Client, newSA, prevSA *c
 			isAnnotationsEqual(currentSA.Annotations, newSA.Annotations, prevAnnotations) {
 			return nil
 		}
-		currentSA.Labels = newSA.Labels
-		currentSA.Annotations = mergeAnnotations(currentSA.Annotations, newSA.Annotations, prevAnnotations)
-		vmv1beta1.AddFinalizer(&currentSA, &currentSA)
+		isIntentEqual := prevSA != nil && isPrevMetaEqual(newSA, prevSA)
+		if detectDrift(ctx, "ServiceAccount", &currentSA, isIntentEqual, func() []string {
+			return metadataDriftFields(newSA, &currentSA, prevAnnotations)
+		}) {
+			return nil
+		}
 
 		logger.WithContext(ctx).Info(fmt.Sprintf("updating ServiceAccount %s metadata", newSA.Name))
 
-		return rclient.Update(ctx, &currentSA)
+		return updateObject(ctx, rclient, newSA, &currentSA, func() client.Object {
+			currentSA.Labels = newSA.Labels
+			currentSA.Annotations = mergeAnnotations(currentSA.Annotations, newSA.Annotations, prevAnnotations)
+			vmv1beta1.AddFinalizer(&currentSA, &currentSA)
+			return &currentSA
+		})
 	})
 }
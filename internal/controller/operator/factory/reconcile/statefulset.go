@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -21,6 +22,7 @@ import (
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/finalize"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/tracing"
 )
 
 const podRevisionLabel = "controller-revision-hash"
@@ -60,6 +62,9 @@ func waitForStatefulSetReady(ctx context.Context, rclient client.Client, newSts
 
 // HandleSTSUpdate performs create and update operations for given statefulSet with STSOptions
 func HandleSTSUpdate(ctx context.Context, rclient client.Client, cr STSOptions, newSts, prevSts *appsv1.StatefulSet) error {
+	ctx, span := tracing.Start(ctx, "reconcile.HandleSTSUpdate", attribute.String("k8s.statefulset.name", newSts.Name))
+	defer span.End()
+
 	var isPrevEqual bool
 	var prevSpecDiff string
 	if prevSts != nil {
@@ -75,7 +80,7 @@ func HandleSTSUpdate(ctx context.Context, rclient client.Client, cr STSOptions,
 		if err := rclient.Get(ctx, types.NamespacedName{Name: newSts.Name, Namespace: newSts.Namespace}, &currentSts); err != nil {
 			if errors.IsNotFound(err) {
 				logger.WithContext(ctx).Info(fmt.Sprintf("creating new StatefulSet %s", newSts.Name))
-				if err = rclient.Create(ctx, newSts); err != nil {
+				if err = createObject(ctx, rclient, newSts); err != nil {
 					return fmt.Errorf("cannot create new sts %s under namespace %s: %w", newSts.Name, newSts.Namespace, err)
 				}
 				return waitForStatefulSetReady(ctx, rclient, newSts)
@@ -101,12 +106,23 @@ func HandleSTSUpdate(ctx context.Context, rclient client.Client, cr STSOptions,
 
 		stsRecreated, podMustRecreate, err := recreateSTSIfNeed(ctx, rclient, newSts, &currentSts)
 		if err != nil {
+			// statefulset cannot be updated without recreation
+			if isMaintenanceDeferred(err) {
+				return nil
+			}
 			return err
 		}
 
 		// if sts wasn't recreated, update it first
 		// before making call for performRollingUpdateOnSts
 		if !stsRecreated {
+			// kubernetes restarts pods of StatefulSet with RollingUpdate strategy right after pod template change
+			// keep the current template outside of maintenance window and apply other changes
+			if newSts.Spec.UpdateStrategy.Type != appsv1.OnDeleteStatefulSetStrategyType &&
+				!equality.Semantic.DeepDerivative(newSts.Spec.Template, currentSts.Spec.Template) &&
+				!allowDisruptive(ctx, fmt.Sprintf("rolling update of StatefulSet=%s", newSts.Name)) {
+				newSts.Spec.Template = *currentSts.Spec.Template.DeepCopy()
+			}
 			var prevAnnotations, prevTemplateAnnotations map[string]string
 			if prevSts != nil {
 				prevAnnotations = prevSts.Annotations
@@ -119,12 +135,13 @@ func HandleSTSUpdate(ctx context.Context, rclient client.Client, cr STSOptions,
 				isAnnotationsEqual(currentSts.Annotations, newSts.Annotations, prevAnnotations)
 
 			if !shouldSkipUpdate {
+				isIntentEqual := isPrevEqual && isPrevMetaEqual(newSts, prevSts)
+				shouldSkipUpdate = detectDrift(ctx, "StatefulSet", &currentSts, isIntentEqual, func() []string {
+					return append(metadataDriftFields(newSts, &currentSts, prevAnnotations), specDriftFields("spec", newSts.Spec, currentSts.Spec)...)
+				})
+			}
 
-				vmv1beta1.AddFinalizer(newSts, &currentSts)
-				newSts.Annotations = mergeAnnotations(currentSts.Annotations, newSts.Annotations, prevAnnotations)
-				newSts.Spec.Template.Annotations = mergeAnnotations(currentSts.Spec.Template.Annotations, newSts.Spec.Template.Annotations, prevTemplateAnnotations)
-				cloneSignificantMetadata(newSts, &currentSts)
-
+			if !shouldSkipUpdate {
 				logMsg := fmt.Sprintf("updating statefulset %s configuration, is_current_equal=%v,is_prev_equal=%v,is_prev_nil=%v",
 					newSts.Name, isEqual, isPrevEqual, prevSts == nil)
 				if !isEqual {
@@ -136,7 +153,13 @@ func HandleSTSUpdate(ctx context.Context, rclient client.Client, cr STSOptions,
 
 				logger.WithContext(ctx).Info(logMsg)
 
-				if err := rclient.Update(ctx, newSts); err != nil {
+				if err := updateObject(ctx, rclient, newSts, &currentSts, func() client.Object {
+					vmv1beta1.AddFinalizer(newSts, &currentSts)
+					newSts.Annotations = mergeAnnotations(currentSts.Annotations, newSts.Annotations, prevAnnotations)
+					newSts.Spec.Template.Annotations = mergeAnnotations(currentSts.Spec.Template.Annotations, newSts.Spec.Template.Annotations, prevTemplateAnnotations)
+					cloneSignificantMetadata(newSts, &currentSts)
+					return newSts
+				}); err != nil {
 					return fmt.Errorf("cannot perform update on sts: %s, err: %w", newSts.Name, err)
 				}
 			}
@@ -285,6 +308,10 @@ func performRollingUpdateOnSts(ctx context.Context, podMustRecreate bool, rclien
 		}
 	}
 
+	if len(podsForUpdate) > 0 && !allowDisruptive(ctx, fmt.Sprintf("rolling update of StatefulSet=%s pods=%d", stsName, len(podsForUpdate))) {
+		return nil
+	}
+
 	// perform update for not updated pods
 	for _, pod := range podsForUpdate {
 		l.Info(fmt.Sprintf("updating pod=%s revision label=%q", pod.Name, pod.Labels[podRevisionLabel]))
@@ -326,6 +353,9 @@ func PodIsReady(pod *corev1.Pod, minReadySeconds int32) bool {
 }
 
 func waitForPodReady(ctx context.Context, rclient client.Client, nsn types.NamespacedName, desiredRevision string, minReadySeconds int32) error {
+	ctx, span := tracing.Start(ctx, "waitForPodReady", attribute.String("k8s.pod.name", nsn.Name))
+	defer span.End()
+
 	var pod *corev1.Pod
 	if err := wait.PollUntilContextTimeout(ctx, podWaitReadyIntervalCheck, podWaitReadyTimeout, false, func(_ context.Context) (done bool, err error) {
 		pod = &corev1.Pod{}
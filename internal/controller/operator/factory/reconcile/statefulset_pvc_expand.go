@@ -40,6 +40,9 @@ import (
 func recreateSTSIfNeed(ctx context.Context, rclient client.Client, newSTS, oldStatefulSet *appsv1.StatefulSet) (bool, bool, error) {
 	l := logger.WithContext(ctx)
 	handleRemove := func() error {
+		if !allowDisruptive(ctx, fmt.Sprintf("recreation of StatefulSet=%s", newSTS.Name)) {
+			return errMaintenanceDeferred
+		}
 		return removeStatefulSetKeepPods(ctx, rclient, newSTS, oldStatefulSet)
 	}
 	if newSTS.Spec.ServiceName != oldStatefulSet.Spec.ServiceName {
@@ -135,6 +138,9 @@ func growSTSPVC(ctx context.Context, rclient client.Client, sts *appsv1.Stateful
 			l.Info(fmt.Sprintf("storage class=%s for PVC=%s doesn't support live resizing", ptr.Deref(pvc.Spec.StorageClassName, "default"), pvc.Name))
 			continue
 		}
+		if !allowDisruptive(ctx, fmt.Sprintf("expansion of PVC=%s to size=%s", pvc.Name, newSize)) {
+			continue
+		}
 		err = growPVCs(ctx, rclient, newSize, &pvc)
 		if err != nil {
 			return fmt.Errorf("failed to expand size for pvc %s: %v", pvc.Name, err)
@@ -206,8 +212,17 @@ func isStorageClassExpandable(ctx context.Context, rclient client.Client, pvc *c
 }
 
 func growPVCs(ctx context.Context, rclient client.Client, size *resource.Quantity, pvc *corev1.PersistentVolumeClaim) error {
-	pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *size
-	return rclient.Update(ctx, pvc)
+	// PVC is created by StatefulSet controller, operator owns only storage request
+	newPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: pvc.Name, Namespace: pvc.Namespace},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: *size}},
+		},
+	}
+	return updateObject(ctx, rclient, newPVC, pvc, func() client.Object {
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *size
+		return pvc
+	})
 }
 
 // checks if pvc needs to be resized.
@@ -314,10 +329,10 @@ func removeStatefulSetKeepPods(ctx context.Context, rclient client.Client, state
 		return fmt.Errorf("cannot wait for sts to be deleted: %w", err)
 	}
 
-	if err := rclient.Create(ctx, statefulSet); err != nil {
+	if err := createObject(ctx, rclient, statefulSet); err != nil {
 		// try to restore previous one and throw error
 		oldStatefulSet.ResourceVersion = ""
-		if err2 := rclient.Create(ctx, oldStatefulSet); err2 != nil {
+		if err2 := createObject(ctx, rclient, oldStatefulSet); err2 != nil {
 			return fmt.Errorf("cannot restore previous sts: %s configuration after remove original error: %s: restore error %w", oldStatefulSet.Name, err, err2)
 		}
 		return fmt.Errorf("cannot create new sts: %s instead of replaced, perform manual action to handle this error or report BUG, err: %w", statefulSet.Name, err)
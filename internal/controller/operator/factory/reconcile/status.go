@@ -16,6 +16,7 @@ import (
 
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/tracing"
 )
 
 var (
@@ -35,6 +36,19 @@ type objectWithStatus interface {
 	GetStatusMetadata() *vmv1beta1.StatusMetadata
 }
 
+// objectWithScrapeDefaults is implemented by scrape objects,
+// which could receive settings from VMScrapeDefaults or VMClusterScrapeDefaults
+type objectWithScrapeDefaults interface {
+	GetScrapeObjectStatus() *vmv1beta1.ScrapeObjectStatus
+}
+
+// objectWithUnreachableRoutes is implemented by VMAlertmanagerConfig,
+// which reports routes unreachable at parent VMAlertmanagers
+type objectWithUnreachableRoutes interface {
+	GetUnreachableRoutes(alertmanager string) []vmv1beta1.UnreachableRoute
+	SetUnreachableRoutes(alertmanager string, routes []vmv1beta1.UnreachableRoute, parents map[string]struct{}) bool
+}
+
 // StatusForChildObjects reconciles status sub-resources
 // Expects parentObjectName in the following form:
 // NAME.NAMESPACE.RESOURCE
@@ -97,11 +111,37 @@ func updateChildStatusConditions[T any, PT interface {
 		st := dst.GetStatusMetadata()
 		prevSt := st.DeepCopy()
 
+		if currCond.Status == "False" {
+			var prevMsg string
+			for _, c := range st.Conditions {
+				if c.Type == currCond.Type {
+					prevMsg = c.Message
+					break
+				}
+			}
+			currCond.Message = tracing.WithTraceID(ctx, currCond.Message, prevMsg)
+		}
 		st.Conditions = setConditionTo(st.Conditions, currCond)
 		st.Conditions = removeStaleConditionsBySuffix(st.Conditions, vmv1beta1.ConditionDomainTypeAppliedSuffix)
 		st.ObservedGeneration = dst.GetGeneration()
 		writeAggregatedStatus(st, vmv1beta1.ConditionDomainTypeAppliedSuffix)
-		if !reflect.DeepEqual(prevSt, st) {
+		var prevDefaults, currDefaults []vmv1beta1.AppliedScrapeDefaults
+		if src, ok := any(childObject).(objectWithScrapeDefaults); ok {
+			dstSt := any(dst).(objectWithScrapeDefaults).GetScrapeObjectStatus()
+			prevDefaults = dstSt.AppliedDefaults
+			dstSt.AppliedDefaults = src.GetScrapeObjectStatus().AppliedDefaults
+			currDefaults = dstSt.AppliedDefaults
+		}
+		var unreachableRoutesChanged bool
+		if src, ok := any(childObject).(objectWithUnreachableRoutes); ok {
+			parent := parentKeyFromConditionType(currCond.Type)
+			parents := make(map[string]struct{}, len(st.Conditions))
+			for _, c := range st.Conditions {
+				parents[parentKeyFromConditionType(c.Type)] = struct{}{}
+			}
+			unreachableRoutesChanged = any(dst).(objectWithUnreachableRoutes).SetUnreachableRoutes(parent, src.GetUnreachableRoutes(parent), parents)
+		}
+		if !reflect.DeepEqual(prevSt, st) || !reflect.DeepEqual(prevDefaults, currDefaults) || unreachableRoutesChanged {
 			if err := rclient.Status().Update(ctx, dst); err != nil {
 				return fmt.Errorf("failed to patch status of broken VMAlertmanagerConfig=%q: %w", childObject.GetName(), err)
 			}
@@ -110,6 +150,15 @@ func updateChildStatusConditions[T any, PT interface {
 	})
 }
 
+// parentKeyFromConditionType converts NAME.NAMESPACE.RESOURCE prefix of condition type into NAMESPACE/NAME
+func parentKeyFromConditionType(conditionType string) string {
+	n := strings.Split(strings.TrimSuffix(conditionType, vmv1beta1.ConditionDomainTypeAppliedSuffix), ".")
+	if len(n) != 3 {
+		return conditionType
+	}
+	return n[1] + "/" + n[0]
+}
+
 func setConditionTo(dst []vmv1beta1.Condition, cond vmv1beta1.Condition) []vmv1beta1.Condition {
 	// update TTL with jitter in order to reduce load on kubernetes API server
 	// jitter should cover configured resync period (60s default value)
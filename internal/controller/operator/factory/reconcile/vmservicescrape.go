@@ -23,7 +23,7 @@ func VMServiceScrapeForCRD(ctx context.Context, rclient client.Client, vss *vmv1
 		if err != nil {
 			if errors.IsNotFound(err) {
 				logger.WithContext(ctx).Info(fmt.Sprintf("creating VMServiceScrape %s", vss.Name))
-				return rclient.Create(ctx, vss)
+				return createObject(ctx, rclient, vss)
 			}
 			return err
 		}
@@ -36,14 +36,16 @@ func VMServiceScrapeForCRD(ctx context.Context, rclient client.Client, vss *vmv1
 			equality.Semantic.DeepEqual(vss.Annotations, existVSS.Annotations) {
 			return nil
 		}
-		// TODO: @f41gh7 allow 3rd party applications to add annotations for generated VMServiceScrape
-		existVSS.Annotations = vss.Annotations
-		existVSS.Spec = vss.Spec
-		existVSS.Labels = vss.Labels
 		logMsg := fmt.Sprintf("updating VMServiceScrape %s for CRD object spec_diff: %s", vss.Name, diffDeep(vss.Spec, existVSS.Spec))
 		logger.WithContext(ctx).Info(logMsg)
 
-		return rclient.Update(ctx, &existVSS)
+		return updateObject(ctx, rclient, vss, &existVSS, func() client.Object {
+			// TODO: @f41gh7 allow 3rd party applications to add annotations for generated VMServiceScrape
+			existVSS.Annotations = vss.Annotations
+			existVSS.Spec = vss.Spec
+			existVSS.Labels = vss.Labels
+			return &existVSS
+		})
 	})
 }
 
@@ -55,7 +57,7 @@ func VMPodScrapeForCRD(ctx context.Context, rclient client.Client, vps *vmv1beta
 		if err != nil {
 			if errors.IsNotFound(err) {
 				logger.WithContext(ctx).Info(fmt.Sprintf("creating VMPodScrape %s", vps.Name))
-				return rclient.Create(ctx, vps)
+				return createObject(ctx, rclient, vps)
 			}
 			return err
 		}
@@ -68,13 +70,15 @@ func VMPodScrapeForCRD(ctx context.Context, rclient client.Client, vps *vmv1beta
 			equality.Semantic.DeepEqual(vps.Annotations, existVPS.Annotations) {
 			return nil
 		}
-		// TODO: @f41gh7 allow 3rd party applications to add annotations for generated VMPodScrape
-		existVPS.Annotations = vps.Annotations
-		existVPS.Spec = vps.Spec
-		existVPS.Labels = vps.Labels
 		logMsg := fmt.Sprintf("updating VMPodScrape %s for CRD object spec_diff: %s", vps.Name, diffDeep(vps.Spec, existVPS.Spec))
 		logger.WithContext(ctx).Info(logMsg)
 
-		return rclient.Update(ctx, &existVPS)
+		return updateObject(ctx, rclient, vps, &existVPS, func() client.Object {
+			// TODO: @f41gh7 allow 3rd party applications to add annotations for generated VMPodScrape
+			existVPS.Annotations = vps.Annotations
+			existVPS.Spec = vps.Spec
+			existVPS.Labels = vps.Labels
+			return &existVPS
+		})
 	})
 }
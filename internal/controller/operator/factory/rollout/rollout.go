@@ -0,0 +1,398 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+)
+
+// WaitInterval defines how often objects postponed by rollout check if they could be updated
+const WaitInterval = 10 * time.Second
+
+var (
+	// batchSettleDelay defines delay between the first postponed object and the first batch
+	// it allows to collect objects for proper ordering, since objects are registered on reconcile
+	batchSettleDelay = 15 * time.Second
+	syncInterval     = 5 * time.Second
+	// pendingTTL defines how long postponed object is tracked without reconcile
+	// it removes deleted objects from rollout
+	pendingTTL = 3 * WaitInterval
+)
+
+var (
+	rolloutObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "operator_defaults_rollout_objects",
+		Help: "Number of objects at the current defaults rollout by state",
+	}, []string{"state"})
+	rolloutPaused = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "operator_defaults_rollout_paused",
+		Help: "Whether defaults rollout is paused or aborted",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(rolloutObjects, rolloutPaused)
+}
+
+// candidate is an object, which spec was changed by operator defaults
+type candidate struct {
+	key      string
+	obj      client.Object
+	priority int64
+	order    string
+	hasOrder bool
+	lastSeen time.Time
+	// admittedAt is a time, when object was moved to the current batch
+	admittedAt time.Time
+}
+
+// Coordinator updates objects affected by change of operator defaults in batches
+// according to VMRolloutPolicy
+type Coordinator struct {
+	rclient   client.Client
+	policyNSN types.NamespacedName
+
+	mu             sync.Mutex
+	policy         vmv1beta1.VMRolloutPolicy
+	pending        map[string]*candidate
+	updating       map[string]*candidate
+	failed         map[string]*candidate
+	updated        int32
+	started        bool
+	firstPendingAt time.Time
+}
+
+var (
+	globalMu    sync.RWMutex
+	coordinator *Coordinator
+)
+
+// NewCoordinator returns coordinator, which uses VMRolloutPolicy with the given namespaced name
+func NewCoordinator(rclient client.Client, policyNSN types.NamespacedName) (*Coordinator, error) {
+	if policyNSN.Namespace == "" || policyNSN.Name == "" {
+		return nil, fmt.Errorf("rollout policy must be defined in namespace/name format, got: %q", policyNSN.String())
+	}
+	return &Coordinator{
+		rclient:   rclient,
+		policyNSN: policyNSN,
+		pending:   make(map[string]*candidate),
+		updating:  make(map[string]*candidate),
+		failed:    make(map[string]*candidate),
+	}, nil
+}
+
+// SetCoordinator configures global coordinator used by controllers
+// nil value disables rollout of defaults in batches
+func SetCoordinator(c *Coordinator) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	coordinator = c
+}
+
+func getCoordinator() *Coordinator {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return coordinator
+}
+
+// MustWait checks if update of the object with changed defaults must be postponed
+// it always returns false if rollout is disabled
+func MustWait(controller string, object client.Object) bool {
+	c := getCoordinator()
+	if c == nil {
+		return false
+	}
+	return c.mustWait(controller, object, time.Now())
+}
+
+// Release marks object as updated
+// it must be called after object reaches operational state
+func Release(controller string, object client.Object) {
+	c := getCoordinator()
+	if c == nil {
+		return
+	}
+	c.release(objectKey(controller, object))
+}
+
+func objectKey(controller string, object client.Object) string {
+	return fmt.Sprintf("%s/%s/%s", controller, object.GetNamespace(), object.GetName())
+}
+
+func (c *Coordinator) mustWait(controller string, object client.Object, now time.Time) bool {
+	key := objectKey(controller, object)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cd, ok := c.updating[key]; ok {
+		cd.lastSeen = now
+		return false
+	}
+	if c.policy.Spec.Abort {
+		delete(c.pending, key)
+		return true
+	}
+	cd, ok := c.pending[key]
+	if !ok {
+		if !c.started {
+			c.started = true
+			c.updated = 0
+			c.firstPendingAt = now
+			if !c.isFailed() {
+				clear(c.failed)
+			}
+		}
+		cd = &candidate{key: key}
+		c.pending[key] = cd
+	}
+	cd.obj = object.DeepCopyObject().(client.Object)
+	cd.lastSeen = now
+	cd.priority, _ = strconv.ParseInt(object.GetAnnotations()[vmv1beta1.RolloutPriorityAnnotation], 10, 64)
+	cd.order, cd.hasOrder = "", false
+	if c.policy.Spec.OrderByLabel != "" {
+		cd.order, cd.hasOrder = object.GetLabels()[c.policy.Spec.OrderByLabel]
+	}
+	c.admitBatch(now)
+	_, ok = c.updating[key]
+	return !ok
+}
+
+// admitBatch moves the next batch of pending objects to updating state
+// batch is started only if all objects of the previous batch were updated
+func (c *Coordinator) admitBatch(now time.Time) {
+	if len(c.updating) > 0 || len(c.pending) == 0 || c.policy.Spec.Paused || c.policy.Spec.Abort || c.isFailed() {
+		return
+	}
+	if c.updated == 0 && now.Sub(c.firstPendingAt) < batchSettleDelay {
+		return
+	}
+	candidates := make([]*candidate, 0, len(c.pending))
+	for _, cd := range c.pending {
+		candidates = append(candidates, cd)
+	}
+	slices.SortFunc(candidates, func(a, b *candidate) int {
+		switch {
+		case a.priority != b.priority:
+			if a.priority > b.priority {
+				return -1
+			}
+			return 1
+		case a.hasOrder != b.hasOrder:
+			if a.hasOrder {
+				return -1
+			}
+			return 1
+		case a.order != b.order:
+			return strings.Compare(a.order, b.order)
+		}
+		return strings.Compare(a.key, b.key)
+	})
+	for _, cd := range candidates[:min(c.policy.GetMaxConcurrent(), len(candidates))] {
+		delete(c.pending, cd.key)
+		cd.lastSeen = now
+		cd.admittedAt = now
+		c.updating[cd.key] = cd
+	}
+}
+
+func (c *Coordinator) release(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.updating[key]; ok {
+		delete(c.updating, key)
+		c.updated++
+	} else if _, ok := c.failed[key]; ok {
+		// object reached operational state after progress deadline
+		delete(c.failed, key)
+		c.updated++
+	} else {
+		// object was updated without rollout, e.g. its spec was changed by user
+		delete(c.pending, key)
+	}
+	if len(c.updating) == 0 {
+		if len(c.pending) == 0 {
+			c.started = false
+			return
+		}
+		c.admitBatch(time.Now())
+	}
+}
+
+// setPolicy applies the given policy
+func (c *Coordinator) setPolicy(policy *vmv1beta1.VMRolloutPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if policy.Generation != c.policy.Generation {
+		// failed rollout is resumed after change of policy spec
+		clear(c.failed)
+	}
+	c.policy = *policy
+	if policy.Spec.Abort {
+		clear(c.pending)
+	}
+}
+
+// isFailed checks if number of failed objects exceeds policy limit
+func (c *Coordinator) isFailed() bool {
+	return len(c.failed) > int(c.policy.Spec.MaxFailures)
+}
+
+// cleanup removes deleted objects from rollout
+// and marks objects, which didn't reach operational state within progress deadline, as failed
+func (c *Coordinator) cleanup(ctx context.Context, now time.Time) {
+	c.mu.Lock()
+	for key, cd := range c.pending {
+		if now.Sub(cd.lastSeen) > pendingTTL {
+			delete(c.pending, key)
+		}
+	}
+	deadline := c.policy.GetProgressDeadline()
+	for key, cd := range c.updating {
+		if now.Sub(cd.admittedAt) > deadline {
+			logger.WithContext(ctx).Info(fmt.Sprintf("object=%s didn't reach operational state within progress deadline=%s, marking it as failed", key, deadline))
+			delete(c.updating, key)
+			c.failed[key] = cd
+		}
+	}
+	updating := make([]*candidate, 0, len(c.updating))
+	for _, cd := range c.updating {
+		updating = append(updating, cd)
+	}
+	c.mu.Unlock()
+
+	for _, cd := range updating {
+		obj := cd.obj.DeepCopyObject().(client.Object)
+		err := c.rclient.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if err == nil {
+			continue
+		}
+		if !k8serrors.IsNotFound(err) {
+			logger.WithContext(ctx).Error(err, fmt.Sprintf("cannot check rollout object=%s", cd.key))
+			continue
+		}
+		c.mu.Lock()
+		delete(c.updating, cd.key)
+		c.mu.Unlock()
+	}
+	c.mu.Lock()
+	if len(c.updating) == 0 {
+		if len(c.pending) == 0 {
+			c.started = false
+		} else {
+			c.admitBatch(now)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// buildStatus returns status of the current rollout
+func (c *Coordinator) buildStatus() vmv1beta1.VMRolloutPolicyStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	st := vmv1beta1.VMRolloutPolicyStatus{
+		Pending: int32(len(c.pending)),
+		Updated: c.updated,
+		Total:   int32(len(c.pending)+len(c.updating)+len(c.failed)) + c.updated,
+	}
+	for key := range c.updating {
+		st.Updating = append(st.Updating, key)
+	}
+	slices.Sort(st.Updating)
+	for key := range c.failed {
+		st.Failed = append(st.Failed, key)
+	}
+	slices.Sort(st.Failed)
+	switch {
+	case c.policy.Spec.Abort:
+		st.Phase = vmv1beta1.RolloutPhaseAborted
+	case c.isFailed():
+		st.Phase = vmv1beta1.RolloutPhaseFailed
+	case c.started && c.policy.Spec.Paused:
+		st.Phase = vmv1beta1.RolloutPhasePaused
+	case c.started:
+		st.Phase = vmv1beta1.RolloutPhaseProgressing
+	case st.Total > 0:
+		st.Phase = vmv1beta1.RolloutPhaseCompleted
+	default:
+		st.Phase = vmv1beta1.RolloutPhaseIdle
+	}
+	return st
+}
+
+// Sync refreshes rollout policy and updates its status
+func (c *Coordinator) Sync(ctx context.Context) error {
+	var policy vmv1beta1.VMRolloutPolicy
+	if err := c.rclient.Get(ctx, c.policyNSN, &policy); err != nil {
+		if k8serrors.IsNotFound(err) {
+			// keep the last known policy
+			logger.WithContext(ctx).Info(fmt.Sprintf("rollout policy=%s doesn't exist", c.policyNSN))
+			return nil
+		}
+		return fmt.Errorf("cannot get rollout policy: %w", err)
+	}
+	c.setPolicy(&policy)
+	c.cleanup(ctx, time.Now())
+
+	st := c.buildStatus()
+	rolloutObjects.WithLabelValues("pending").Set(float64(st.Pending))
+	rolloutObjects.WithLabelValues("updating").Set(float64(len(st.Updating)))
+	rolloutObjects.WithLabelValues("updated").Set(float64(st.Updated))
+	paused := 0.0
+	if policy.Spec.Paused || policy.Spec.Abort {
+		paused = 1
+	}
+	rolloutPaused.Set(paused)
+
+	prevStatus := policy.Status.DeepCopy()
+	st.LastUpdateTime = prevStatus.LastUpdateTime
+	if equality.Semantic.DeepEqual(&st, prevStatus) {
+		return nil
+	}
+	st.LastUpdateTime = ptrTime(metav1.Now())
+	patch := client.MergeFrom(policy.DeepCopy())
+	policy.Status = st
+	if err := c.rclient.Status().Patch(ctx, &policy, patch); err != nil {
+		return fmt.Errorf("cannot update rollout policy status: %w", err)
+	}
+	return nil
+}
+
+func ptrTime(t metav1.Time) *metav1.Time {
+	return &t
+}
+
+// Start implements manager.Runnable interface
+func (c *Coordinator) Start(ctx context.Context) error {
+	t := time.NewTicker(syncInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+			if err := c.Sync(ctx); err != nil {
+				logger.WithContext(ctx).Error(err, "cannot sync defaults rollout")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable interface
+// rollout state is tracked only by the leader, which runs controllers
+func (c *Coordinator) NeedLeaderElection() bool {
+	return true
+}
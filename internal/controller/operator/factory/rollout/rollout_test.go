@@ -0,0 +1,240 @@
+package rollout
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func newTestAgent(name string, labels, annotations map[string]string) *vmv1beta1.VMAgent {
+	return &vmv1beta1.VMAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Labels:      labels,
+			Annotations: annotations,
+		},
+	}
+}
+
+func newTestCoordinator(t *testing.T, spec vmv1beta1.VMRolloutPolicySpec, objects []runtime.Object) *Coordinator {
+	t.Helper()
+	c, err := NewCoordinator(k8stools.GetTestClientWithObjects(objects), types.NamespacedName{Namespace: "default", Name: "rollout"})
+	assert.NoError(t, err)
+	c.setPolicy(&vmv1beta1.VMRolloutPolicy{Spec: spec})
+	return c
+}
+
+func TestNewCoordinator(t *testing.T) {
+	f := func(nsn types.NamespacedName, wantErr bool) {
+		t.Helper()
+		_, err := NewCoordinator(k8stools.GetTestClientWithObjects(nil), nsn)
+		if wantErr {
+			assert.Error(t, err)
+			return
+		}
+		assert.NoError(t, err)
+	}
+	f(types.NamespacedName{Namespace: "default", Name: "rollout"}, false)
+	f(types.NamespacedName{Name: "rollout"}, true)
+	f(types.NamespacedName{Namespace: "default"}, true)
+}
+
+func TestCoordinatorBatchOrder(t *testing.T) {
+	f := func(spec vmv1beta1.VMRolloutPolicySpec, objects []*vmv1beta1.VMAgent, wantBatches [][]string) {
+		t.Helper()
+		c := newTestCoordinator(t, spec, nil)
+		now := time.Now()
+		for _, obj := range objects {
+			assert.True(t, c.mustWait("vmagent", obj, now))
+		}
+		now = now.Add(batchSettleDelay)
+		released := make(map[string]bool)
+		for _, want := range wantBatches {
+			var admitted []string
+			for _, obj := range objects {
+				// updated objects have no spec changes and aren't checked
+				if released[obj.Name] {
+					continue
+				}
+				if !c.mustWait("vmagent", obj, now) {
+					admitted = append(admitted, obj.Name)
+				}
+			}
+			slices.Sort(admitted)
+			assert.Equal(t, want, admitted)
+			for _, name := range admitted {
+				released[name] = true
+				c.release("vmagent/default/" + name)
+			}
+		}
+		st := c.buildStatus()
+		assert.Equal(t, vmv1beta1.RolloutPhaseCompleted, st.Phase)
+		assert.Equal(t, int32(len(objects)), st.Updated)
+	}
+
+	// default order by name
+	f(vmv1beta1.VMRolloutPolicySpec{}, []*vmv1beta1.VMAgent{
+		newTestAgent("c", nil, nil),
+		newTestAgent("a", nil, nil),
+		newTestAgent("b", nil, nil),
+	}, [][]string{{"a"}, {"b"}, {"c"}})
+
+	// order by label, objects without label are last
+	f(vmv1beta1.VMRolloutPolicySpec{MaxConcurrent: 2, OrderByLabel: "env"}, []*vmv1beta1.VMAgent{
+		newTestAgent("a", nil, nil),
+		newTestAgent("b", map[string]string{"env": "prod"}, nil),
+		newTestAgent("c", map[string]string{"env": "dev"}, nil),
+		newTestAgent("d", map[string]string{"env": "dev"}, nil),
+	}, [][]string{{"c", "d"}, {"a", "b"}})
+
+	// priority annotation takes precedence over label
+	f(vmv1beta1.VMRolloutPolicySpec{MaxConcurrent: 2, OrderByLabel: "env"}, []*vmv1beta1.VMAgent{
+		newTestAgent("a", nil, map[string]string{vmv1beta1.RolloutPriorityAnnotation: "10"}),
+		newTestAgent("b", map[string]string{"env": "prod"}, nil),
+		newTestAgent("c", map[string]string{"env": "dev"}, nil),
+	}, [][]string{{"a", "c"}, {"b"}})
+}
+
+func TestCoordinatorHealthGating(t *testing.T) {
+	c := newTestCoordinator(t, vmv1beta1.VMRolloutPolicySpec{MaxConcurrent: 1}, nil)
+	a := newTestAgent("a", nil, nil)
+	b := newTestAgent("b", nil, nil)
+	now := time.Now()
+	assert.True(t, c.mustWait("vmagent", a, now))
+	assert.True(t, c.mustWait("vmagent", b, now))
+
+	// first batch waits for settle delay
+	now = now.Add(batchSettleDelay)
+	assert.False(t, c.mustWait("vmagent", a, now))
+	// next batch waits until the previous batch is operational
+	assert.True(t, c.mustWait("vmagent", b, now))
+	assert.True(t, c.mustWait("vmagent", b, now.Add(time.Hour)))
+	assert.Equal(t, vmv1beta1.RolloutPhaseProgressing, c.buildStatus().Phase)
+
+	c.release("vmagent/default/a")
+	assert.False(t, c.mustWait("vmagent", b, now))
+	c.release("vmagent/default/b")
+	st := c.buildStatus()
+	assert.Equal(t, vmv1beta1.RolloutPhaseCompleted, st.Phase)
+	assert.Equal(t, int32(2), st.Total)
+}
+
+func TestCoordinatorPauseAbort(t *testing.T) {
+	c := newTestCoordinator(t, vmv1beta1.VMRolloutPolicySpec{Paused: true}, nil)
+	a := newTestAgent("a", nil, nil)
+	b := newTestAgent("b", nil, nil)
+	now := time.Now()
+	assert.True(t, c.mustWait("vmagent", a, now))
+	assert.True(t, c.mustWait("vmagent", b, now))
+	now = now.Add(batchSettleDelay)
+	assert.True(t, c.mustWait("vmagent", a, now))
+	assert.Equal(t, vmv1beta1.RolloutPhasePaused, c.buildStatus().Phase)
+
+	c.setPolicy(&vmv1beta1.VMRolloutPolicy{})
+	assert.False(t, c.mustWait("vmagent", a, now))
+
+	// current batch is finished after abort
+	c.setPolicy(&vmv1beta1.VMRolloutPolicy{Spec: vmv1beta1.VMRolloutPolicySpec{Abort: true}})
+	assert.False(t, c.mustWait("vmagent", a, now))
+	assert.True(t, c.mustWait("vmagent", b, now))
+	st := c.buildStatus()
+	assert.Equal(t, vmv1beta1.RolloutPhaseAborted, st.Phase)
+	assert.Equal(t, int32(0), st.Pending)
+
+	// object changed by user is released without rollout
+	c.release("vmagent/default/b")
+	c.release("vmagent/default/a")
+	assert.Equal(t, int32(1), c.buildStatus().Updated)
+}
+
+func TestCoordinatorSync(t *testing.T) {
+	policy := &vmv1beta1.VMRolloutPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "rollout", Namespace: "default"},
+		Spec:       vmv1beta1.VMRolloutPolicySpec{MaxConcurrent: 2},
+	}
+	a := newTestAgent("a", nil, nil)
+	c := newTestCoordinator(t, vmv1beta1.VMRolloutPolicySpec{}, []runtime.Object{policy, a})
+	ctx := context.Background()
+	now := time.Now().Add(-batchSettleDelay)
+	assert.True(t, c.mustWait("vmagent", a, now))
+	assert.True(t, c.mustWait("vmagent", newTestAgent("deleted", nil, nil), now))
+	assert.NoError(t, c.Sync(ctx))
+
+	var got vmv1beta1.VMRolloutPolicy
+	assert.NoError(t, c.rclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "rollout"}, &got))
+	assert.Equal(t, vmv1beta1.RolloutPhaseProgressing, got.Status.Phase)
+	assert.Equal(t, int32(2), got.Status.Total)
+	assert.Equal(t, []string{"vmagent/default/a", "vmagent/default/deleted"}, got.Status.Updating)
+	assert.NotNil(t, got.Status.LastUpdateTime)
+
+	// deleted object is removed from rollout
+	assert.NoError(t, c.Sync(ctx))
+	assert.NoError(t, c.rclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "rollout"}, &got))
+	assert.Equal(t, []string{"vmagent/default/a"}, got.Status.Updating)
+	assert.Equal(t, int32(1), got.Status.Total)
+}
+
+func TestCoordinatorProgressDeadline(t *testing.T) {
+	f := func(spec vmv1beta1.VMRolloutPolicySpec, wantPhase vmv1beta1.RolloutPhase, wantSecondAdmitted bool) {
+		t.Helper()
+		a := newTestAgent("a", nil, nil)
+		b := newTestAgent("b", nil, nil)
+		c := newTestCoordinator(t, spec, []runtime.Object{a, b})
+		ctx := context.Background()
+		now := time.Now()
+		assert.True(t, c.mustWait("vmagent", a, now))
+		assert.True(t, c.mustWait("vmagent", b, now))
+		now = now.Add(batchSettleDelay)
+		assert.False(t, c.mustWait("vmagent", a, now))
+
+		// object of the current batch doesn't reach operational state
+		now = now.Add(c.policy.GetProgressDeadline() + time.Second)
+		assert.True(t, c.mustWait("vmagent", b, now))
+		c.cleanup(ctx, now)
+		st := c.buildStatus()
+		assert.Equal(t, wantPhase, st.Phase)
+		assert.Equal(t, []string{"vmagent/default/a"}, st.Failed)
+		assert.Equal(t, wantSecondAdmitted, !c.mustWait("vmagent", b, now))
+
+		// failed object is counted as updated after reaching operational state
+		c.release("vmagent/default/a")
+		st = c.buildStatus()
+		assert.Empty(t, st.Failed)
+		assert.Equal(t, int32(1), st.Updated)
+	}
+
+	// the first failed object stops rollout
+	f(vmv1beta1.VMRolloutPolicySpec{}, vmv1beta1.RolloutPhaseFailed, false)
+
+	// failures are allowed
+	f(vmv1beta1.VMRolloutPolicySpec{MaxFailures: 1, ProgressDeadline: "1m"}, vmv1beta1.RolloutPhaseProgressing, true)
+}
+
+func TestCoordinatorResumeFailed(t *testing.T) {
+	a := newTestAgent("a", nil, nil)
+	b := newTestAgent("b", nil, nil)
+	c := newTestCoordinator(t, vmv1beta1.VMRolloutPolicySpec{}, []runtime.Object{a, b})
+	now := time.Now()
+	assert.True(t, c.mustWait("vmagent", a, now))
+	assert.True(t, c.mustWait("vmagent", b, now))
+	now = now.Add(batchSettleDelay)
+	assert.False(t, c.mustWait("vmagent", a, now))
+	now = now.Add(c.policy.GetProgressDeadline() + time.Second)
+	assert.True(t, c.mustWait("vmagent", b, now))
+	c.cleanup(context.Background(), now)
+	assert.True(t, c.mustWait("vmagent", b, now))
+
+	// change of policy spec resumes rollout
+	c.setPolicy(&vmv1beta1.VMRolloutPolicy{ObjectMeta: metav1.ObjectMeta{Generation: 2}})
+	assert.False(t, c.mustWait("vmagent", b, now))
+}
@@ -0,0 +1,390 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+)
+
+const (
+	// ShardGroupLabel defines name of the shard group at shard Lease objects
+	ShardGroupLabel = "operator.victoriametrics.com/shard-group"
+
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+var (
+	shardMembers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "operator_shard_members",
+		Help: "Number of live operator shards",
+	})
+	shardRebalances = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "operator_shard_rebalances_total",
+		Help: "Number of shard membership changes",
+	})
+	shardOwnedObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "operator_shard_owned_objects",
+		Help: "Number of parent objects owned by the current shard",
+	}, []string{"controller"})
+	shardSkippedReconciles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "operator_shard_skipped_reconciles_total",
+		Help: "Number of reconcile requests skipped, since object is owned by another shard",
+	}, []string{"controller"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(shardMembers, shardRebalances, shardOwnedObjects, shardSkippedReconciles)
+}
+
+// Config defines sharding configuration
+type Config struct {
+	// Name is a name of shard group, replicas with the same name share objects
+	Name string
+	// Namespace for shard Lease objects, namespace of operator is used if empty
+	Namespace string
+	// Identity of the current replica, hostname is used if empty
+	Identity string
+	// LeaseDuration defines how long Lease of replica is valid without renew
+	LeaseDuration time.Duration
+	// RenewInterval defines how often replica renews Lease and refreshes membership
+	RenewInterval time.Duration
+	// TakeoverGracePeriod defines how long replica waits after membership change
+	// before it starts reconcile of objects moved from other replicas.
+	// It must be greater than RenewInterval, so previous owner observes membership change and stops reconcile.
+	TakeoverGracePeriod time.Duration
+}
+
+// Coordinator maintains membership of operator shards with Lease objects
+// and assigns parent objects to shards with rendezvous hashing
+//
+// Object is considered owned only if its ownership is the same before and after membership change
+// or TakeoverGracePeriod passed since the last change.
+// Replica, which cannot renew its Lease for LeaseDuration, doesn't own any object,
+// since other replicas consider it dead and take over its objects.
+type Coordinator struct {
+	rclient client.Client
+	cfg     Config
+	now     func() time.Time
+
+	mu sync.RWMutex
+	// members is a sorted list of live shards
+	members []string
+	// stableMembers is the last list of live shards, which wasn't changed during TakeoverGracePeriod
+	stableMembers []string
+	changedAt     time.Time
+	// pendingTakeover is set on membership change until TakeoverGracePeriod passes
+	pendingTakeover bool
+	lastRenew       time.Time
+	subscribers     []chan event.GenericEvent
+}
+
+var (
+	globalMu    sync.RWMutex
+	coordinator *Coordinator
+)
+
+// NewCoordinator returns coordinator for the given config
+func NewCoordinator(rclient client.Client, cfg Config) (*Coordinator, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("shard group name cannot be empty")
+	}
+	if cfg.Identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("cannot get hostname for shard identity: %w", err)
+		}
+		cfg.Identity = hostname
+	}
+	if cfg.Namespace == "" {
+		ns, err := os.ReadFile(serviceAccountNamespaceFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot detect operator namespace for shard leases, it must be set explicitly: %w", err)
+		}
+		cfg.Namespace = strings.TrimSpace(string(ns))
+	}
+	if cfg.RenewInterval <= 0 || cfg.LeaseDuration <= cfg.RenewInterval {
+		return nil, fmt.Errorf("lease duration=%s must be greater than renew interval=%s", cfg.LeaseDuration, cfg.RenewInterval)
+	}
+	if cfg.TakeoverGracePeriod <= cfg.RenewInterval {
+		return nil, fmt.Errorf("takeover grace period=%s must be greater than renew interval=%s", cfg.TakeoverGracePeriod, cfg.RenewInterval)
+	}
+	now := time.Now()
+	return &Coordinator{
+		rclient: rclient,
+		cfg:     cfg,
+		now:     time.Now,
+		members: []string{cfg.Identity},
+		// objects of the joined replica are owned by other replicas until they observe its Lease
+		changedAt:       now,
+		pendingTakeover: true,
+	}, nil
+}
+
+// SetCoordinator configures global coordinator used by controllers
+// nil value disables sharding
+func SetCoordinator(c *Coordinator) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	coordinator = c
+}
+
+func getCoordinator() *Coordinator {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return coordinator
+}
+
+// IsEnabled checks if sharding is enabled
+func IsEnabled() bool {
+	return getCoordinator() != nil
+}
+
+// IsOwned checks if object with the given namespaced name belongs to the current shard
+// it always returns true if sharding is disabled
+func IsOwned(nsn types.NamespacedName) bool {
+	c := getCoordinator()
+	if c == nil {
+		return true
+	}
+	return c.isOwned(nsn.String())
+}
+
+// MustSkipReconcile checks if reconcile request for parent object must be skipped by the current shard
+func MustSkipReconcile(controller string, nsn types.NamespacedName) bool {
+	if IsOwned(nsn) {
+		return false
+	}
+	shardSkippedReconciles.WithLabelValues(controller).Inc()
+	return true
+}
+
+// SetOwnedObjects updates number of objects owned by the current shard
+func SetOwnedObjects(controller string, count int) {
+	shardOwnedObjects.WithLabelValues(controller).Set(float64(count))
+}
+
+// Subscribe returns channel, which receives event on each membership change
+// it returns nil if sharding is disabled
+func Subscribe() <-chan event.GenericEvent {
+	c := getCoordinator()
+	if c == nil {
+		return nil
+	}
+	ch := make(chan event.GenericEvent, 1)
+	c.mu.Lock()
+	c.subscribers = append(c.subscribers, ch)
+	c.mu.Unlock()
+	return ch
+}
+
+func (c *Coordinator) isOwned(key string) bool {
+	now := c.now()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if now.Sub(c.lastRenew) >= c.cfg.LeaseDuration {
+		// Lease of the current replica could be expired, objects could be reconciled by other replicas
+		return false
+	}
+	if ownerOf(c.members, key) != c.cfg.Identity {
+		return false
+	}
+	if now.Sub(c.changedAt) >= c.cfg.TakeoverGracePeriod {
+		return true
+	}
+	// previous owner may reconcile object until it observes membership change
+	return ownerOf(c.stableMembers, key) == c.cfg.Identity
+}
+
+// ownerOf returns shard with the highest score for the given key
+func ownerOf(members []string, key string) string {
+	var owner string
+	var maxScore uint64
+	for _, m := range members {
+		score := xxhash.Sum64String(m + "\x00" + key)
+		if owner == "" || score > maxScore {
+			owner = m
+			maxScore = score
+		}
+	}
+	return owner
+}
+
+// Members returns sorted list of live shards
+func (c *Coordinator) Members() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return slices.Clone(c.members)
+}
+
+func (c *Coordinator) leaseName() string {
+	return c.cfg.Name + "-" + c.cfg.Identity
+}
+
+// Sync renews Lease of the current shard and refreshes membership
+// subscribers are notified if membership was changed
+func (c *Coordinator) Sync(ctx context.Context) error {
+	if err := c.renew(ctx); err != nil {
+		return err
+	}
+	var leases coordinationv1.LeaseList
+	if err := c.rclient.List(ctx, &leases, client.InNamespace(c.cfg.Namespace), client.MatchingLabels{ShardGroupLabel: c.cfg.Name}); err != nil {
+		return fmt.Errorf("cannot list shard leases: %w", err)
+	}
+	now := c.now()
+	members := []string{c.cfg.Identity}
+	for _, l := range leases.Items {
+		if !isLeaseAlive(&l, now) || l.Spec.HolderIdentity == nil || *l.Spec.HolderIdentity == c.cfg.Identity {
+			continue
+		}
+		members = append(members, *l.Spec.HolderIdentity)
+	}
+	slices.Sort(members)
+	members = slices.Compact(members)
+
+	c.mu.Lock()
+	changed := !slices.Equal(c.members, members)
+	if changed {
+		if now.Sub(c.changedAt) >= c.cfg.TakeoverGracePeriod {
+			c.stableMembers = c.members
+		}
+		c.members = members
+		c.changedAt = now
+		c.pendingTakeover = true
+	}
+	takeover := c.pendingTakeover && now.Sub(c.changedAt) >= c.cfg.TakeoverGracePeriod
+	if takeover {
+		c.pendingTakeover = false
+	}
+	subscribers := c.subscribers
+	c.mu.Unlock()
+	shardMembers.Set(float64(len(members)))
+	if changed {
+		shardRebalances.Inc()
+		logger.WithContext(ctx).Info(fmt.Sprintf("shard membership changed, shards: %s", strings.Join(members, ",")))
+	}
+	if changed || takeover {
+		// objects moved to the current replica are reconciled after grace period
+		c.notify(subscribers)
+	}
+	return nil
+}
+
+func (c *Coordinator) notify(subscribers []chan event.GenericEvent) {
+	// object is ignored by subscribers, it only triggers listing of owned objects
+	e := event.GenericEvent{Object: &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: c.leaseName(), Namespace: c.cfg.Namespace}}}
+	for _, ch := range subscribers {
+		select {
+		case ch <- e:
+		default:
+			// rebalance is already pending
+		}
+	}
+}
+
+func isLeaseAlive(l *coordinationv1.Lease, now time.Time) bool {
+	if l.Spec.RenewTime == nil || l.Spec.LeaseDurationSeconds == nil {
+		return false
+	}
+	return l.Spec.RenewTime.Add(time.Duration(*l.Spec.LeaseDurationSeconds) * time.Second).After(now)
+}
+
+func (c *Coordinator) renew(ctx context.Context) error {
+	renewedAt := c.now()
+	now := metav1.NewMicroTime(renewedAt)
+	var l coordinationv1.Lease
+	err := c.rclient.Get(ctx, types.NamespacedName{Namespace: c.cfg.Namespace, Name: c.leaseName()}, &l)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("cannot get shard lease: %w", err)
+		}
+		l = coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      c.leaseName(),
+				Namespace: c.cfg.Namespace,
+				Labels:    map[string]string{ShardGroupLabel: c.cfg.Name},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(c.cfg.Identity),
+				LeaseDurationSeconds: ptr.To(int32(c.cfg.LeaseDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		if err := c.rclient.Create(ctx, &l); err != nil {
+			return fmt.Errorf("cannot create shard lease: %w", err)
+		}
+		c.setRenewed(renewedAt)
+		return nil
+	}
+	l.Spec.HolderIdentity = ptr.To(c.cfg.Identity)
+	l.Spec.LeaseDurationSeconds = ptr.To(int32(c.cfg.LeaseDuration.Seconds()))
+	l.Spec.RenewTime = &now
+	if err := c.rclient.Update(ctx, &l); err != nil {
+		return fmt.Errorf("cannot renew shard lease: %w", err)
+	}
+	c.setRenewed(renewedAt)
+	return nil
+}
+
+func (c *Coordinator) setRenewed(t time.Time) {
+	c.mu.Lock()
+	c.lastRenew = t
+	c.mu.Unlock()
+}
+
+// Start implements manager.Runnable interface
+// it renews Lease until context is cancelled and removes it on exit,
+// so other shards take ownership of objects after TakeoverGracePeriod without waiting for Lease expiration
+func (c *Coordinator) Start(ctx context.Context) error {
+	// controllers are subscribed at this point
+	// initial notification populates owned objects metrics
+	c.mu.RLock()
+	subscribers := c.subscribers
+	c.mu.RUnlock()
+	c.notify(subscribers)
+
+	t := time.NewTicker(c.cfg.RenewInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return c.leave()
+		case <-t.C:
+			if err := c.Sync(ctx); err != nil {
+				logger.WithContext(ctx).Error(err, "cannot sync operator shards")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable interface
+func (c *Coordinator) NeedLeaderElection() bool {
+	return false
+}
+
+func (c *Coordinator) leave() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	l := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: c.leaseName(), Namespace: c.cfg.Namespace}}
+	if err := c.rclient.Delete(ctx, l); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("cannot delete shard lease: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func newTestCoordinator(t *testing.T, identity string, objects []runtime.Object) *Coordinator {
+	t.Helper()
+	c, err := NewCoordinator(k8stools.GetTestClientWithObjects(objects), Config{
+		Name:                "vm-operator",
+		Namespace:           "vm",
+		Identity:            identity,
+		LeaseDuration:       15 * time.Second,
+		RenewInterval:       5 * time.Second,
+		TakeoverGracePeriod: 10 * time.Second,
+	})
+	assert.NoError(t, err)
+	return c
+}
+
+func newShardLease(identity string, renewTime time.Time) *coordinationv1.Lease {
+	rt := metav1.NewMicroTime(renewTime)
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vm-operator-" + identity,
+			Namespace: "vm",
+			Labels:    map[string]string{ShardGroupLabel: "vm-operator"},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       ptr.To(identity),
+			LeaseDurationSeconds: ptr.To(int32(15)),
+			RenewTime:            &rt,
+		},
+	}
+}
+
+func TestNewCoordinator(t *testing.T) {
+	f := func(cfg Config, wantErr bool) {
+		t.Helper()
+		_, err := NewCoordinator(k8stools.GetTestClientWithObjects(nil), cfg)
+		if wantErr {
+			assert.Error(t, err)
+			return
+		}
+		assert.NoError(t, err)
+	}
+	f(Config{Name: "vm-operator", Namespace: "vm", Identity: "a", LeaseDuration: 15 * time.Second, RenewInterval: 5 * time.Second, TakeoverGracePeriod: 10 * time.Second}, false)
+	f(Config{Namespace: "vm", Identity: "a", LeaseDuration: 15 * time.Second, RenewInterval: 5 * time.Second, TakeoverGracePeriod: 10 * time.Second}, true)
+	f(Config{Name: "vm-operator", Namespace: "vm", Identity: "a", LeaseDuration: 5 * time.Second, RenewInterval: 5 * time.Second, TakeoverGracePeriod: 10 * time.Second}, true)
+	f(Config{Name: "vm-operator", Namespace: "vm", Identity: "a", LeaseDuration: 15 * time.Second, RenewInterval: 5 * time.Second, TakeoverGracePeriod: 5 * time.Second}, true)
+}
+
+func TestOwnerRebalance(t *testing.T) {
+	keys := make([]string, 0, 1000)
+	for i := range 1000 {
+		keys = append(keys, fmt.Sprintf("ns-%d/vmagent-%d", i%10, i))
+	}
+	ownersOf := func(members ...string) map[string]string {
+		owners := make(map[string]string, len(keys))
+		for _, k := range keys {
+			owners[k] = ownerOf(members, k)
+		}
+		return owners
+	}
+	before := ownersOf("a", "b", "c")
+	perShard := make(map[string]int)
+	for _, o := range before {
+		perShard[o]++
+	}
+	for _, m := range []string{"a", "b", "c"} {
+		assert.Greater(t, perShard[m], 250, "shard %s owns too few objects", m)
+	}
+
+	// only objects of removed shard are moved
+	after := ownersOf("a", "b")
+	for k, o := range before {
+		if o != "c" {
+			assert.Equal(t, o, after[k])
+		}
+	}
+	// only objects moved to new shard change owner
+	after = ownersOf("a", "b", "c", "d")
+	for k, o := range after {
+		if o != "d" {
+			assert.Equal(t, before[k], o)
+		}
+	}
+}
+
+func TestCoordinatorSync(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := newTestCoordinator(t, "a", []runtime.Object{
+		newShardLease("b", now),
+		// expired
+		newShardLease("c", now.Add(-time.Minute)),
+	})
+	c.now = func() time.Time { return now }
+	SetCoordinator(c)
+	defer SetCoordinator(nil)
+	ch := Subscribe()
+
+	assert.NoError(t, c.Sync(ctx))
+	assert.Equal(t, []string{"a", "b"}, c.Members())
+	assert.Len(t, ch, 1)
+	<-ch
+
+	// own lease is created
+	var l coordinationv1.Lease
+	assert.NoError(t, c.rclient.Get(ctx, types.NamespacedName{Namespace: "vm", Name: "vm-operator-a"}, &l))
+	assert.Equal(t, "a", *l.Spec.HolderIdentity)
+
+	// membership isn't changed
+	assert.NoError(t, c.Sync(ctx))
+	assert.Empty(t, ch)
+
+	// objects are owned after takeover grace period
+	c.now = func() time.Time { return now.Add(10 * time.Second) }
+	assert.NoError(t, c.Sync(ctx))
+	assert.Len(t, ch, 1)
+	<-ch
+
+	owned := make(map[bool]int)
+	for i := range 100 {
+		owned[IsOwned(types.NamespacedName{Namespace: "default", Name: fmt.Sprintf("vmagent-%d", i)})]++
+	}
+	assert.Greater(t, owned[true], 0)
+	assert.Greater(t, owned[false], 0)
+
+	// lease is removed on exit
+	assert.NoError(t, c.leave())
+	err := c.rclient.Get(ctx, types.NamespacedName{Namespace: "vm", Name: "vm-operator-a"}, &l)
+	assert.True(t, k8serrors.IsNotFound(err))
+}
+
+func TestIsOwnedDisabled(t *testing.T) {
+	SetCoordinator(nil)
+	assert.False(t, IsEnabled())
+	assert.Nil(t, Subscribe())
+	assert.True(t, IsOwned(types.NamespacedName{Namespace: "default", Name: "vmagent"}))
+	assert.False(t, MustSkipReconcile("vmagent", types.NamespacedName{Namespace: "default", Name: "vmagent"}))
+}
+
+func TestCoordinatorTakeover(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := newTestCoordinator(t, "a", []runtime.Object{newShardLease("b", now)})
+	c.now = func() time.Time { return now }
+	var keys []string
+	for i := range 100 {
+		keys = append(keys, fmt.Sprintf("default/vmagent-%d", i))
+	}
+	ownedKeys := func() map[string]bool {
+		owned := make(map[string]bool)
+		for _, k := range keys {
+			if c.isOwned(k) {
+				owned[k] = true
+			}
+		}
+		return owned
+	}
+
+	// joined replica waits for other replicas to observe its lease
+	assert.NoError(t, c.Sync(ctx))
+	assert.Empty(t, ownedKeys())
+	now = now.Add(10 * time.Second)
+	assert.NoError(t, c.Sync(ctx))
+	before := ownedKeys()
+	assert.NotEmpty(t, before)
+	assert.Less(t, len(before), len(keys))
+
+	// objects of expired replica are taken over after grace period
+	now = now.Add(15 * time.Second)
+	assert.NoError(t, c.Sync(ctx))
+	assert.Equal(t, []string{"a"}, c.Members())
+	assert.Equal(t, before, ownedKeys())
+	now = now.Add(10 * time.Second)
+	assert.Len(t, ownedKeys(), len(keys))
+
+	// replica without renewed lease doesn't own objects
+	now = now.Add(15 * time.Second)
+	assert.Empty(t, ownedKeys())
+	assert.NoError(t, c.Sync(ctx))
+	assert.Len(t, ownedKeys(), len(keys))
+}
@@ -0,0 +1,156 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ExporterOTLP sends spans to OpenTelemetry collector with OTLP HTTP protocol
+	ExporterOTLP = "otlp"
+	// ExporterStdout prints spans to stdout, it's intended for local testing
+	ExporterStdout = "stdout"
+
+	tracerName  = "github.com/VictoriaMetrics/operator"
+	serviceName = "victoria-metrics-operator"
+)
+
+// Config defines tracing configuration
+type Config struct {
+	// Exporter is one of otlp or stdout, empty value disables tracing
+	Exporter string
+	// Endpoint is OTLP HTTP endpoint URL, for example http://otel-collector:4318
+	// if empty, OTEL_EXPORTER_OTLP_ENDPOINT and OTEL_EXPORTER_OTLP_TRACES_ENDPOINT env vars are used
+	Endpoint string
+	// SamplingRatio defines ratio of sampled reconcile traces
+	SamplingRatio float64
+	// Version is added to the service.version resource attribute
+	Version string
+}
+
+// Init configures global tracer provider
+//
+// returned function flushes buffered spans and must be called before exit
+func Init(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	var exp sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		exp, err = otlptracehttp.New(ctx, opts...)
+	case ExporterStdout:
+		exp, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter=%q, supported values: %s, %s", cfg.Exporter, ExporterOTLP, ExporterStdout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot create %s tracing exporter: %w", cfg.Exporter, err)
+	}
+	if cfg.SamplingRatio < 0 || cfg.SamplingRatio > 1 {
+		return nil, fmt.Errorf("tracing sampling ratio must be in range [0..1], got: %f", cfg.SamplingRatio)
+	}
+	res := resource.NewSchemaless(
+		attribute.String("service.name", serviceName),
+		attribute.String("service.version", cfg.Version),
+	)
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplingRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return tp.Shutdown, nil
+}
+
+// Start starts a new span with the given name
+// it's no-op if tracing is not configured
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records error if any and ends the given span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceID returns trace ID of sampled span from context
+// or empty string if there is no such span
+func TraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() || !sc.IsSampled() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// WithTraceID adds trace ID of the current span to the given error message
+//
+// if prevMsg contains the same message with trace ID, it's returned as is.
+// It keeps trace ID of the first failed reconcile and prevents status updates on each reconcile loop
+func WithTraceID(ctx context.Context, msg, prevMsg string) string {
+	traceID := TraceID(ctx)
+	if traceID == "" {
+		return msg
+	}
+	if strings.HasPrefix(prevMsg, msg+" (trace_id=") {
+		return prevMsg
+	}
+	return fmt.Sprintf("%s (trace_id=%s)", msg, traceID)
+}
+
+// WrapTransport returns RoundTripper, which adds span for each Kubernetes API request
+// requests without parent span, such as informers watch and list calls, are not traced
+func WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &tracingTransport{origin: rt}
+}
+
+type tracingTransport struct {
+	origin http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper interface
+func (tt *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return tt.origin.RoundTrip(req)
+	}
+	_, span := otel.Tracer(tracerName).Start(ctx, "k8s.api "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.path", req.URL.Path),
+			attribute.String("server.address", req.URL.Host),
+		))
+	resp, err := tt.origin.RoundTrip(req)
+	if err == nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	End(span, err)
+	return resp, err
+}
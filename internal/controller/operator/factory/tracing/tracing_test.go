@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func withTestProvider(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exp := tracetest.NewInMemoryExporter()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+	})
+	return exp
+}
+
+func TestInit(t *testing.T) {
+	f := func(cfg Config, wantErr bool) {
+		t.Helper()
+		prev := otel.GetTracerProvider()
+		defer otel.SetTracerProvider(prev)
+		shutdown, err := Init(context.Background(), cfg)
+		if wantErr {
+			assert.Error(t, err)
+			return
+		}
+		assert.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	}
+	f(Config{}, false)
+	f(Config{Exporter: ExporterStdout, SamplingRatio: 1}, false)
+	f(Config{Exporter: ExporterOTLP, Endpoint: "http://127.0.0.1:4318", SamplingRatio: 0.5}, false)
+	f(Config{Exporter: "jaeger"}, true)
+	f(Config{Exporter: ExporterStdout, SamplingRatio: 2}, true)
+}
+
+func TestWithTraceID(t *testing.T) {
+	withTestProvider(t)
+
+	// tracing is not configured
+	assert.Equal(t, "cannot reconcile", WithTraceID(context.Background(), "cannot reconcile", ""))
+
+	ctx, span := Start(context.Background(), "reconcile")
+	defer span.End()
+	traceID := TraceID(ctx)
+	assert.NotEmpty(t, traceID)
+
+	got := WithTraceID(ctx, "cannot reconcile", "")
+	assert.Equal(t, "cannot reconcile (trace_id="+traceID+")", got)
+	// trace ID of the first failure is kept
+	prev := "cannot reconcile (trace_id=0af7651916cd43dd8448eb211c80319c)"
+	assert.Equal(t, prev, WithTraceID(ctx, "cannot reconcile", prev))
+	// message was changed
+	assert.Equal(t, got, WithTraceID(ctx, "cannot reconcile", "other error (trace_id=0af7651916cd43dd8448eb211c80319c)"))
+}
+
+func TestWrapTransport(t *testing.T) {
+	exp := withTestProvider(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/namespaces/default/secrets/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	hc := &http.Client{Transport: WrapTransport(http.DefaultTransport)}
+	do := func(ctx context.Context, path string) {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+path, nil)
+		assert.NoError(t, err)
+		resp, err := hc.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// requests without parent span are not traced
+	do(context.Background(), "/api/v1/namespaces/default/configmaps/cm")
+	assert.Empty(t, exp.GetSpans())
+
+	ctx, span := Start(context.Background(), "reconcile")
+	do(ctx, "/api/v1/namespaces/default/configmaps/cm")
+	do(ctx, "/api/v1/namespaces/default/secrets/missing")
+	span.End()
+
+	spans := exp.GetSpans()
+	assert.Len(t, spans, 3)
+	assert.Equal(t, "k8s.api GET", spans[0].Name)
+	assert.Equal(t, span.SpanContext().SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+	assert.Equal(t, "reconcile", spans[2].Name)
+}
@@ -0,0 +1,477 @@
+package vmagent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/build"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+)
+
+const (
+	// max interval between canary health checks
+	canaryCheckInterval = 30 * time.Second
+
+	canaryReloadSuccessMetric   = "vm_promscrape_config_last_reload_successful"
+	canaryReloadTimestampMetric = "vm_promscrape_config_last_reload_success_timestamp_seconds"
+	canaryTargetsMetric         = "vm_promscrape_targets"
+)
+
+// canaryDroppedSamplesMetrics defines counters, which are used for dropped samples calculation
+var canaryDroppedSamplesMetrics = []string{
+	"vm_promscrape_series_limit_rows_dropped_total",
+	"vmagent_remotewrite_samples_dropped_total",
+	"vmagent_remotewrite_relabel_metrics_dropped_total",
+}
+
+const canaryHTTPTimeout = 5 * time.Second
+
+// fetchCanaryMetrics returns metrics exposed by the given url
+// it's a variable for tests
+var fetchCanaryMetrics = func(ctx context.Context, hc *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code=%d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// ConfigCanaryRequeueAfter returns interval for the next canary health check
+// or zero if there is no canary rollout in progress
+func ConfigCanaryRequeueAfter(cr *vmv1beta1.VMAgent) time.Duration {
+	if !cr.IsConfigCanaryEnabled() || cr.Status.ConfigCanary == nil || cr.Status.ConfigCanary.Phase != vmv1beta1.ConfigCanaryInProgress {
+		return 0
+	}
+	return min(cr.Spec.ConfigCanary.GetSoakDuration(), canaryCheckInterval)
+}
+
+// reconcileConfigSecret updates vmagent configuration secret and secrets with scrape config parts
+//
+// if configCanary is enabled, scrape configuration changes are applied to the canary secret first
+// and promoted to the main secret after successful soak
+func reconcileConfigSecret(ctx context.Context, rclient client.Client, cr, prevCR *vmv1beta1.VMAgent, s *corev1.Secret, parts [][]byte) error {
+	var prevSecretMeta *metav1.ObjectMeta
+	if prevCR != nil {
+		prevSecretMeta = ptr.To(buildConfigMeta(prevCR))
+	}
+	if !cr.IsConfigCanaryEnabled() {
+		return reconcileSplitConfigSecret(ctx, rclient, cr, s, parts, prevSecretMeta)
+	}
+	return reconcileConfigCanary(ctx, rclient, cr, s, parts, prevSecretMeta)
+}
+
+func reconcileConfigCanary(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAgent, newSecret *corev1.Secret, newParts [][]byte, prevSecretMeta *metav1.ObjectMeta) error {
+	newConfig := &vmagentConfig{main: newSecret.Data[vmagentGzippedFilename], parts: newParts}
+	var stable corev1.Secret
+	if err := rclient.Get(ctx, types.NamespacedName{Namespace: newSecret.Namespace, Name: newSecret.Name}, &stable); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("cannot get vmagent config secret: %w", err)
+		}
+		// there is no stable configuration yet, it must be applied to all shards at once
+		if err := reconcileSplitConfigSecret(ctx, rclient, cr, newSecret.DeepCopy(), newConfig.parts, prevSecretMeta); err != nil {
+			return fmt.Errorf("cannot reconcile vmagent config secret: %w", err)
+		}
+		return reconcileCanaryConfigSecret(ctx, rclient, cr, newSecret, newConfig, prevSecretMeta)
+	}
+	stableParts, err := loadConfigParts(ctx, rclient, stable.Namespace, stable.Name)
+	if err != nil {
+		return err
+	}
+	stableConfig := &vmagentConfig{main: stable.Data[vmagentGzippedFilename], parts: stableParts}
+	canaryConfig := stableConfig
+	newHash := newConfig.hash()
+
+	prevStatus := cr.Status.DeepCopy()
+	st := cr.Status.ConfigCanary
+	switch {
+	case newConfig.equal(stableConfig):
+		if st != nil && st.Phase == vmv1beta1.ConfigCanaryInProgress {
+			setConfigCanaryPhase(ctx, rclient, cr, vmv1beta1.ConfigCanaryCancelled, "configuration matches the stable one")
+		}
+	case st != nil && st.Phase == vmv1beta1.ConfigCanaryRolledBack && st.ConfigHash == newHash:
+		// configuration was rolled back, wait for the next change
+	case st == nil || st.Phase != vmv1beta1.ConfigCanaryInProgress || st.ConfigHash != newHash:
+		canaryConfig = newConfig
+		cr.Status.ConfigCanary = &vmv1beta1.VMAgentConfigCanaryStatus{
+			ConfigHash: newHash,
+			StartedAt:  ptr.To(metav1.Now()),
+		}
+		setConfigCanaryPhase(ctx, rclient, cr, vmv1beta1.ConfigCanaryInProgress, fmt.Sprintf("applying configuration to canary shard=%d", cr.Spec.ConfigCanary.GetShardNum()))
+	default:
+		canaryConfig = newConfig
+		verdict, msg := evaluateConfigCanary(ctx, rclient, cr)
+		switch verdict {
+		case canaryHealthy:
+			stableConfig = newConfig
+			setConfigCanaryPhase(ctx, rclient, cr, vmv1beta1.ConfigCanaryPromoted, msg)
+		case canaryFailed:
+			canaryConfig = stableConfig
+			setConfigCanaryPhase(ctx, rclient, cr, vmv1beta1.ConfigCanaryRolledBack, msg)
+		}
+	}
+
+	// only scrape configuration is a subject of canary rollout
+	// remote write credentials must be always up-to-date
+	stableSecret := newSecret.DeepCopy()
+	stableSecret.Data[vmagentGzippedFilename] = stableConfig.main
+	if err := reconcileSplitConfigSecret(ctx, rclient, cr, stableSecret, stableConfig.parts, prevSecretMeta); err != nil {
+		return fmt.Errorf("cannot reconcile vmagent config secret: %w", err)
+	}
+	if err := reconcileCanaryConfigSecret(ctx, rclient, cr, newSecret, canaryConfig, prevSecretMeta); err != nil {
+		return err
+	}
+	if !equality.Semantic.DeepEqual(prevStatus, &cr.Status) {
+		if err := patchConfigCanaryStatus(ctx, rclient, cr); err != nil {
+			return fmt.Errorf("cannot update configCanary status: %w", err)
+		}
+	}
+	return nil
+}
+
+func reconcileCanaryConfigSecret(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAgent, newSecret *corev1.Secret, config *vmagentConfig, prevMeta *metav1.ObjectMeta) error {
+	canarySecret := newSecret.DeepCopy()
+	canarySecret.Name = cr.CanaryConfigSecretName()
+	canarySecret.Data[vmagentGzippedFilename] = config.main
+	if err := reconcileSplitConfigSecret(ctx, rclient, cr, canarySecret, config.parts, prevMeta); err != nil {
+		return fmt.Errorf("cannot reconcile vmagent canary config secret: %w", err)
+	}
+	return nil
+}
+
+type canaryVerdict int
+
+const (
+	canaryPending canaryVerdict = iota
+	canaryHealthy
+	canaryFailed
+)
+
+// canaryHealth contains aggregated metrics of canary shard pods
+type canaryHealth struct {
+	reloaded       bool
+	reloadFailed   bool
+	targetsUp      float64
+	targetsDown    float64
+	droppedSamples float64
+}
+
+// evaluateConfigCanary checks canary shard health and returns verdict with a human readable reason
+func evaluateConfigCanary(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAgent) (canaryVerdict, string) {
+	cc := cr.Spec.ConfigCanary
+	st := cr.Status.ConfigCanary
+	soakDone := time.Since(st.StartedAt.Time) >= cc.GetSoakDuration()
+
+	h, err := collectCanaryHealth(ctx, rclient, cr, st.StartedAt.Time)
+	if err != nil {
+		if soakDone {
+			return canaryFailed, fmt.Sprintf("cannot check canary shard health: %s", err)
+		}
+		logger.WithContext(ctx).Info(fmt.Sprintf("cannot check vmagent canary shard health, will retry: %s", err))
+		return canaryPending, ""
+	}
+	if h.reloadFailed {
+		return canaryFailed, "canary shard failed to reload configuration"
+	}
+	if !h.reloaded {
+		if soakDone {
+			return canaryFailed, "canary shard didn't apply new configuration during soak duration"
+		}
+		return canaryPending, ""
+	}
+	dropped := int64(h.droppedSamples)
+	// counters could be reset by pod restart
+	if st.DroppedSamplesBaseline == nil || *st.DroppedSamplesBaseline > dropped {
+		st.DroppedSamplesBaseline = ptr.To(dropped)
+	}
+	if delta := dropped - *st.DroppedSamplesBaseline; delta > cc.GetMaxDroppedSamples() {
+		return canaryFailed, fmt.Sprintf("canary shard dropped %d samples, max allowed: %d", delta, cc.GetMaxDroppedSamples())
+	}
+	if !soakDone {
+		return canaryPending, ""
+	}
+	if total := h.targetsUp + h.targetsDown; total > 0 {
+		downPercent := h.targetsDown * 100 / total
+		if downPercent > float64(cc.GetMaxScrapeErrorsPercent()) {
+			return canaryFailed, fmt.Sprintf("canary shard has %.2f%% of down targets, max allowed: %d%%", downPercent, cc.GetMaxScrapeErrorsPercent())
+		}
+	}
+	return canaryHealthy, fmt.Sprintf("configuration was healthy at canary shard=%d during %s", cc.GetShardNum(), cc.GetSoakDuration())
+}
+
+func collectCanaryHealth(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAgent, startedAt time.Time) (*canaryHealth, error) {
+	selector := cr.SelectorLabels()
+	selector["shard-num"] = strconv.Itoa(cr.Spec.ConfigCanary.GetShardNum())
+	var pods corev1.PodList
+	if err := rclient.List(ctx, &pods, client.InNamespace(cr.Namespace), client.MatchingLabels(selector)); err != nil {
+		return nil, fmt.Errorf("cannot list canary pods: %w", err)
+	}
+	// pods are accessed with the same settings as self-scrape
+	ep := build.VMPodScrapeForObjectWithSpec(cr, cr.Spec.ServiceScrapeSpec, cr.Spec.ExtraArgs).Spec.PodMetricsEndpoints[0]
+	hc, err := k8stools.NewHTTPClient(ctx, rclient, cr.Namespace, &ep.EndpointAuth, canaryHTTPTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build http client for canary pods: %w", err)
+	}
+	scheme := strings.ToLower(ep.Scheme)
+	if scheme == "" {
+		scheme = "http"
+	}
+	port := cr.Spec.Port
+	if port == "" {
+		port = "8429"
+	}
+	metricPath := ep.Path
+	if metricPath == "" {
+		metricPath = cr.GetMetricPath()
+	}
+	if len(ep.Params) > 0 {
+		metricPath += "?" + url.Values(ep.Params).Encode()
+	}
+	h := &canaryHealth{reloaded: true}
+	var podsCount int
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		podsCount++
+		data, err := fetchCanaryMetrics(ctx, hc, fmt.Sprintf("%s://%s:%s%s", scheme, pod.Status.PodIP, port, metricPath))
+		if err != nil {
+			return nil, fmt.Errorf("cannot fetch metrics of pod=%s: %w", pod.Name, err)
+		}
+		if err := h.addPodMetrics(data, startedAt); err != nil {
+			return nil, fmt.Errorf("cannot parse metrics of pod=%s: %w", pod.Name, err)
+		}
+	}
+	if podsCount == 0 {
+		return nil, fmt.Errorf("canary shard has no running pods")
+	}
+	return h, nil
+}
+
+// addPodMetrics parses metrics in Prometheus text exposition format and adds it to the health
+func (h *canaryHealth) addPodMetrics(data []byte, startedAt time.Time) error {
+	var reloadSuccess, reloadTimestamp float64
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		name, labels, value, ok := parseMetricLine(line)
+		if !ok {
+			continue
+		}
+		switch name {
+		case canaryReloadSuccessMetric:
+			reloadSuccess = value
+		case canaryReloadTimestampMetric:
+			reloadTimestamp = value
+		case canaryTargetsMetric:
+			switch {
+			case strings.Contains(labels, `status="up"`):
+				h.targetsUp += value
+			case strings.Contains(labels, `status="down"`):
+				h.targetsDown += value
+			}
+		default:
+			for _, m := range canaryDroppedSamplesMetrics {
+				if name == m {
+					h.droppedSamples += value
+					break
+				}
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if reloadSuccess != 1 {
+		h.reloadFailed = true
+	}
+	// timestamp has seconds precision
+	if int64(reloadTimestamp) < startedAt.Unix() {
+		h.reloaded = false
+	}
+	return nil
+}
+
+// parseMetricLine parses `name{labels} value [timestamp]` line
+func parseMetricLine(line string) (string, string, float64, bool) {
+	var name, labels, tail string
+	if n := strings.IndexByte(line, '{'); n >= 0 {
+		end := strings.LastIndexByte(line, '}')
+		if end < n {
+			return "", "", 0, false
+		}
+		name, labels, tail = line[:n], line[n+1:end], line[end+1:]
+	} else {
+		n := strings.IndexByte(line, ' ')
+		if n < 0 {
+			return "", "", 0, false
+		}
+		name, tail = line[:n], line[n:]
+	}
+	fields := strings.Fields(tail)
+	if len(fields) == 0 {
+		return "", "", 0, false
+	}
+	v, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", "", 0, false
+	}
+	return name, labels, v, true
+}
+
+func hashConfig(data ...[]byte) string {
+	h := fnv.New64a()
+	for _, d := range data {
+		h.Write(d) //nolint:errcheck
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// setConfigCanaryPhase updates canary phase, status condition and emits event on phase change
+func setConfigCanaryPhase(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAgent, phase vmv1beta1.ConfigCanaryPhase, message string) {
+	st := cr.Status.ConfigCanary
+	st.Phase = phase
+	st.Message = message
+
+	eventType := corev1.EventTypeNormal
+	condStatus := metav1.ConditionTrue
+	switch phase {
+	case vmv1beta1.ConfigCanaryInProgress:
+		condStatus = metav1.ConditionUnknown
+	case vmv1beta1.ConfigCanaryRolledBack:
+		eventType = corev1.EventTypeWarning
+		condStatus = metav1.ConditionFalse
+	}
+	now := metav1.Now()
+	cond := vmv1beta1.Condition{
+		Type:               vmv1beta1.ConditionConfigCanary,
+		Status:             condStatus,
+		Reason:             string(phase),
+		Message:            message,
+		ObservedGeneration: cr.Generation,
+		LastTransitionTime: now,
+		LastUpdateTime:     now,
+	}
+	idx := -1
+	for i, c := range cr.Status.Conditions {
+		if c.Type == cond.Type {
+			idx = i
+			if c.Status == cond.Status {
+				cond.LastTransitionTime = c.LastTransitionTime
+			}
+			break
+		}
+	}
+	if idx >= 0 {
+		cr.Status.Conditions[idx] = cond
+	} else {
+		cr.Status.Conditions = append(cr.Status.Conditions, cond)
+	}
+
+	logger.WithContext(ctx).Info(fmt.Sprintf("vmagent config canary phase=%s: %s", phase, message))
+	ev := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "victoria-metrics-operator-" + uuid.New().String(),
+			Namespace: cr.Namespace,
+		},
+		Type:    eventType,
+		Reason:  "ConfigCanary" + string(phase),
+		Message: message,
+		Source: corev1.EventSource{
+			Component: "victoria-metrics-operator",
+		},
+		LastTimestamp: now,
+		InvolvedObject: corev1.ObjectReference{
+			Kind:            "VMAgent",
+			Namespace:       cr.Namespace,
+			Name:            cr.Name,
+			UID:             cr.UID,
+			ResourceVersion: cr.ResourceVersion,
+		},
+	}
+	if err := rclient.Create(ctx, ev); err != nil {
+		logger.WithContext(ctx).Error(err, "cannot create config canary event")
+	}
+}
+
+func patchConfigCanaryStatus(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAgent) error {
+	data, err := json.Marshal(map[string]any{
+		"status": map[string]any{
+			"configCanary": cr.Status.ConfigCanary,
+			"conditions":   cr.Status.Conditions,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("BUG: cannot serialize status patch: %w", err)
+	}
+	return rclient.Status().Patch(ctx, cr.DeepCopy(), client.RawPatch(types.MergePatchType, data))
+}
+
+// addConfigCanarySettingsToVMAgent switches configuration secret of canary shard
+func addConfigCanarySettingsToVMAgent(cr *vmv1beta1.VMAgent, dep runtime.Object) {
+	var podSpec *corev1.PodSpec
+	switch dep := dep.(type) {
+	case *appsv1.StatefulSet:
+		podSpec = &dep.Spec.Template.Spec
+	case *appsv1.Deployment:
+		podSpec = &dep.Spec.Template.Spec
+	default:
+		return
+	}
+	for i := range podSpec.Volumes {
+		v := &podSpec.Volumes[i]
+		if v.Name == "config" && v.Secret != nil {
+			v.Secret.SecretName = cr.CanaryConfigSecretName()
+		}
+	}
+	secretArg := fmt.Sprintf("--config-secret-name=%s/%s", cr.Namespace, cr.PrefixedName())
+	canarySecretArg := fmt.Sprintf("--config-secret-name=%s/%s", cr.Namespace, cr.CanaryConfigSecretName())
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			for j, arg := range containers[i].Args {
+				if arg == secretArg {
+					containers[i].Args[j] = canarySecretArg
+				}
+			}
+		}
+	}
+}
+
+// isConfigCanaryShard checks if given shard must use canary configuration
+func isConfigCanaryShard(cr *vmv1beta1.VMAgent, shardNum int) bool {
+	return cr != nil && cr.IsConfigCanaryEnabled() && cr.Spec.ConfigCanary.GetShardNum() == shardNum
+}
@@ -0,0 +1,283 @@
+package vmagent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func TestCanaryHealthAddPodMetrics(t *testing.T) {
+	f := func(data string, want canaryHealth) {
+		t.Helper()
+		startedAt := time.Unix(1700000000, 0)
+		h := canaryHealth{reloaded: true}
+		assert.NoError(t, h.addPodMetrics([]byte(data), startedAt))
+		assert.Equal(t, want, h)
+	}
+
+	// reloaded config
+	f(`
+# HELP vm_promscrape_config_last_reload_successful
+vm_promscrape_config_last_reload_successful 1
+vm_promscrape_config_last_reload_success_timestamp_seconds 1700000010
+vm_promscrape_targets{type="kubernetes_sd_configs/pod",status="up"} 8
+vm_promscrape_targets{type="kubernetes_sd_configs/pod",status="down"} 2
+vm_promscrape_targets{type="static_configs",status="up"} 1
+vmagent_remotewrite_samples_dropped_total{url="1:secret-url"} 5
+vm_promscrape_series_limit_rows_dropped_total 3
+vm_promscrape_scrapes_total 100
+`, canaryHealth{reloaded: true, targetsUp: 9, targetsDown: 2, droppedSamples: 8})
+
+	// config wasn't reloaded yet
+	f(`
+vm_promscrape_config_last_reload_successful 1
+vm_promscrape_config_last_reload_success_timestamp_seconds 1699999990
+`, canaryHealth{})
+
+	// failed reload
+	f(`
+vm_promscrape_config_last_reload_successful 0
+vm_promscrape_config_last_reload_success_timestamp_seconds 1699999990
+`, canaryHealth{reloadFailed: true})
+}
+
+func TestReconcileConfigCanary(t *testing.T) {
+	type opts struct {
+		status       *vmv1beta1.VMAgentConfigCanaryStatus
+		metrics      string
+		stableConfig string
+		newConfig    string
+		wantPhase    vmv1beta1.ConfigCanaryPhase
+		wantStable   string
+		wantCanary   string
+	}
+	f := func(o opts) {
+		t.Helper()
+		ctx := context.Background()
+		cr := &vmv1beta1.VMAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+			Spec: vmv1beta1.VMAgentSpec{
+				ShardCount: ptr.To(3),
+				ConfigCanary: &vmv1beta1.VMAgentConfigCanary{
+					ShardNum:     ptr.To(1),
+					SoakDuration: "1m",
+				},
+			},
+			Status: vmv1beta1.VMAgentStatus{ConfigCanary: o.status},
+		}
+		predefinedObjects := []runtime.Object{
+			cr.DeepCopy(),
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "vmagent-agent-1-0",
+					Namespace: "default",
+					Labels:    map[string]string{"app.kubernetes.io/name": "vmagent", "app.kubernetes.io/instance": "agent", "app.kubernetes.io/component": "monitoring", "managed-by": "vm-operator", "shard-num": "1"},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+			},
+		}
+		if o.stableConfig != "" {
+			predefinedObjects = append(predefinedObjects, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: cr.PrefixedName(), Namespace: cr.Namespace},
+				Data:       map[string][]byte{vmagentGzippedFilename: []byte(o.stableConfig)},
+			})
+		}
+		fclient := k8stools.GetTestClientWithObjects(predefinedObjects)
+
+		var fetchedURL string
+		origin := fetchCanaryMetrics
+		fetchCanaryMetrics = func(_ context.Context, _ *http.Client, url string) ([]byte, error) {
+			fetchedURL = url
+			return []byte(o.metrics), nil
+		}
+		defer func() {
+			fetchCanaryMetrics = origin
+		}()
+
+		s := makeConfigSecret(cr, &scrapesSecretsCache{})
+		s.Data[vmagentGzippedFilename] = []byte(o.newConfig)
+		assert.NoError(t, reconcileConfigSecret(ctx, fclient, cr, nil, s, nil))
+
+		var stable, canary corev1.Secret
+		assert.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: cr.PrefixedName()}, &stable))
+		assert.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: cr.CanaryConfigSecretName()}, &canary))
+		assert.Equal(t, o.wantStable, string(stable.Data[vmagentGzippedFilename]))
+		assert.Equal(t, o.wantCanary, string(canary.Data[vmagentGzippedFilename]))
+
+		var got vmv1beta1.VMAgent
+		assert.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "agent"}, &got))
+		if o.wantPhase == "" {
+			assert.Nil(t, got.Status.ConfigCanary)
+			return
+		}
+		assert.NotNil(t, got.Status.ConfigCanary)
+		assert.Equal(t, o.wantPhase, got.Status.ConfigCanary.Phase)
+		for _, cond := range got.Status.Conditions {
+			assert.Equal(t, vmv1beta1.ConditionConfigCanary, cond.Type)
+			assert.Equal(t, string(o.wantPhase), cond.Reason)
+		}
+		if o.metrics != "" {
+			assert.Equal(t, "http://10.0.0.1:8429/metrics", fetchedURL)
+		}
+	}
+	healthyMetrics := func(reloadedAt time.Time) string {
+		return fmt.Sprintf(`
+vm_promscrape_config_last_reload_successful 1
+vm_promscrape_config_last_reload_success_timestamp_seconds %d
+vm_promscrape_targets{type="static_configs",status="up"} 10
+`, reloadedAt.Unix())
+	}
+
+	// initial config
+	f(opts{
+		newConfig:  "v1",
+		wantStable: "v1",
+		wantCanary: "v1",
+	})
+
+	// start canary
+	f(opts{
+		stableConfig: "v1",
+		newConfig:    "v2",
+		wantPhase:    vmv1beta1.ConfigCanaryInProgress,
+		wantStable:   "v1",
+		wantCanary:   "v2",
+	})
+
+	// soak in progress
+	startedAt := time.Now().Add(-30 * time.Second)
+	f(opts{
+		stableConfig: "v1",
+		newConfig:    "v2",
+		status: &vmv1beta1.VMAgentConfigCanaryStatus{
+			Phase:      vmv1beta1.ConfigCanaryInProgress,
+			ConfigHash: hashConfig([]byte("v2")),
+			StartedAt:  &metav1.Time{Time: startedAt},
+		},
+		metrics:    healthyMetrics(startedAt.Add(time.Second)),
+		wantPhase:  vmv1beta1.ConfigCanaryInProgress,
+		wantStable: "v1",
+		wantCanary: "v2",
+	})
+
+	// promote after soak
+	startedAt = time.Now().Add(-2 * time.Minute)
+	f(opts{
+		stableConfig: "v1",
+		newConfig:    "v2",
+		status: &vmv1beta1.VMAgentConfigCanaryStatus{
+			Phase:      vmv1beta1.ConfigCanaryInProgress,
+			ConfigHash: hashConfig([]byte("v2")),
+			StartedAt:  &metav1.Time{Time: startedAt},
+		},
+		metrics:    healthyMetrics(startedAt.Add(time.Second)),
+		wantPhase:  vmv1beta1.ConfigCanaryPromoted,
+		wantStable: "v2",
+		wantCanary: "v2",
+	})
+
+	// rollback on reload error
+	f(opts{
+		stableConfig: "v1",
+		newConfig:    "v2",
+		status: &vmv1beta1.VMAgentConfigCanaryStatus{
+			Phase:      vmv1beta1.ConfigCanaryInProgress,
+			ConfigHash: hashConfig([]byte("v2")),
+			StartedAt:  &metav1.Time{Time: time.Now()},
+		},
+		metrics:    `vm_promscrape_config_last_reload_successful 0`,
+		wantPhase:  vmv1beta1.ConfigCanaryRolledBack,
+		wantStable: "v1",
+		wantCanary: "v1",
+	})
+
+	// rollback on down targets after soak
+	f(opts{
+		stableConfig: "v1",
+		newConfig:    "v2",
+		status: &vmv1beta1.VMAgentConfigCanaryStatus{
+			Phase:      vmv1beta1.ConfigCanaryInProgress,
+			ConfigHash: hashConfig([]byte("v2")),
+			StartedAt:  &metav1.Time{Time: startedAt},
+		},
+		metrics: healthyMetrics(startedAt.Add(time.Second)) + `
+vm_promscrape_targets{type="static_configs",status="down"} 5
+`,
+		wantPhase:  vmv1beta1.ConfigCanaryRolledBack,
+		wantStable: "v1",
+		wantCanary: "v1",
+	})
+
+	// rolled back config isn't applied again
+	f(opts{
+		stableConfig: "v1",
+		newConfig:    "v2",
+		status: &vmv1beta1.VMAgentConfigCanaryStatus{
+			Phase:      vmv1beta1.ConfigCanaryRolledBack,
+			ConfigHash: hashConfig([]byte("v2")),
+			StartedAt:  &metav1.Time{Time: startedAt},
+		},
+		wantPhase:  vmv1beta1.ConfigCanaryRolledBack,
+		wantStable: "v1",
+		wantCanary: "v1",
+	})
+
+	// config change during soak restarts canary
+	f(opts{
+		stableConfig: "v1",
+		newConfig:    "v3",
+		status: &vmv1beta1.VMAgentConfigCanaryStatus{
+			Phase:      vmv1beta1.ConfigCanaryInProgress,
+			ConfigHash: hashConfig([]byte("v2")),
+			StartedAt:  &metav1.Time{Time: startedAt},
+		},
+		wantPhase:  vmv1beta1.ConfigCanaryInProgress,
+		wantStable: "v1",
+		wantCanary: "v3",
+	})
+}
+
+func TestAddConfigCanarySettingsToVMAgent(t *testing.T) {
+	cr := &vmv1beta1.VMAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+		Spec: vmv1beta1.VMAgentSpec{
+			ShardCount:   ptr.To(2),
+			ConfigCanary: &vmv1beta1.VMAgentConfigCanary{},
+			CommonConfigReloaderParams: vmv1beta1.CommonConfigReloaderParams{
+				UseVMConfigReloader: ptr.To(true),
+			},
+		},
+	}
+	dep, err := newDeployForVMAgent(cr, &scrapesSecretsCache{})
+	assert.NoError(t, err)
+	addConfigCanarySettingsToVMAgent(cr, dep)
+	d := dep.(*appsv1.Deployment)
+	var secretNames []string
+	for _, v := range d.Spec.Template.Spec.Volumes {
+		if v.Secret != nil && v.Name == "config" {
+			secretNames = append(secretNames, v.Secret.SecretName)
+		}
+	}
+	assert.Equal(t, []string{"vmagent-agent-canary"}, secretNames)
+	for _, c := range append(d.Spec.Template.Spec.InitContainers, d.Spec.Template.Spec.Containers...) {
+		for _, arg := range c.Args {
+			if strings.HasPrefix(arg, "--config-secret-name=") {
+				assert.Equal(t, "--config-secret-name=default/vmagent-agent-canary", arg)
+			}
+		}
+	}
+}
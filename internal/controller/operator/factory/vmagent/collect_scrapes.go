@@ -9,10 +9,14 @@ import (
 	"github.com/VictoriaMetrics/operator/internal/config"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/tracing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func selectScrapeConfig(ctx context.Context, cr *vmv1beta1.VMAgent, rclient client.Client) ([]*vmv1beta1.VMScrapeConfig, error) {
+	ctx, span := tracing.Start(ctx, "selectScrapeConfig")
+	defer span.End()
+
 	if cr.Spec.DaemonSetMode {
 		return nil, nil
 	}
@@ -40,6 +44,9 @@ func selectScrapeConfig(ctx context.Context, cr *vmv1beta1.VMAgent, rclient clie
 }
 
 func selectPodScrapes(ctx context.Context, cr *vmv1beta1.VMAgent, rclient client.Client) ([]*vmv1beta1.VMPodScrape, error) {
+	ctx, span := tracing.Start(ctx, "selectPodScrapes")
+	defer span.End()
+
 	var podScrapesCombined []*vmv1beta1.VMPodScrape
 	var namespacedNames []string
 
@@ -64,6 +71,9 @@ func selectPodScrapes(ctx context.Context, cr *vmv1beta1.VMAgent, rclient client
 }
 
 func selectVMProbes(ctx context.Context, cr *vmv1beta1.VMAgent, rclient client.Client) ([]*vmv1beta1.VMProbe, error) {
+	ctx, span := tracing.Start(ctx, "selectVMProbes")
+	defer span.End()
+
 	if cr.Spec.DaemonSetMode {
 		return nil, nil
 	}
@@ -89,6 +99,9 @@ func selectVMProbes(ctx context.Context, cr *vmv1beta1.VMAgent, rclient client.C
 }
 
 func selectVMNodeScrapes(ctx context.Context, cr *vmv1beta1.VMAgent, rclient client.Client) ([]*vmv1beta1.VMNodeScrape, error) {
+	ctx, span := tracing.Start(ctx, "selectVMNodeScrapes")
+	defer span.End()
+
 	if cr.Spec.DaemonSetMode {
 		return nil, nil
 	}
@@ -123,6 +136,9 @@ func selectVMNodeScrapes(ctx context.Context, cr *vmv1beta1.VMAgent, rclient cli
 }
 
 func selectStaticScrapes(ctx context.Context, cr *vmv1beta1.VMAgent, rclient client.Client) ([]*vmv1beta1.VMStaticScrape, error) {
+	ctx, span := tracing.Start(ctx, "selectStaticScrapes")
+	defer span.End()
+
 	if cr.Spec.DaemonSetMode {
 		return nil, nil
 	}
@@ -148,6 +164,9 @@ func selectStaticScrapes(ctx context.Context, cr *vmv1beta1.VMAgent, rclient cli
 }
 
 func selectServiceScrapes(ctx context.Context, cr *vmv1beta1.VMAgent, rclient client.Client) ([]*vmv1beta1.VMServiceScrape, error) {
+	ctx, span := tracing.Start(ctx, "selectServiceScrapes")
+	defer span.End()
+
 	if cr.Spec.DaemonSetMode {
 		return nil, nil
 	}
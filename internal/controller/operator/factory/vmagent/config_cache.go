@@ -0,0 +1,290 @@
+package vmagent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+)
+
+var (
+	configGenerationDuration *prometheus.HistogramVec
+	configCacheRequestsTotal *prometheus.CounterVec
+)
+
+func init() {
+	configGenerationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "operator_vmagent_config_generation_duration_seconds",
+		Help:    "Time spent on vmagent scrape configuration generation by stage",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 15),
+	}, []string{"stage"})
+	configCacheRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "operator_vmagent_config_cache_requests_total",
+		Help: "Number of lookups of scrape object secrets and scrape configs at vmagent config cache",
+	}, []string{"type", "result"})
+	metrics.Registry.MustRegister(configGenerationDuration, configCacheRequestsTotal)
+}
+
+func observeConfigGenerationStage(stage string, startedAt time.Time) {
+	configGenerationDuration.WithLabelValues(stage).Observe(time.Since(startedAt).Seconds())
+}
+
+// scrapeConfigCache holds loaded secrets and generated scrape configs of scrape objects per VMAgent
+//
+// it allows to rebuild only changed objects during config generation
+var scrapeConfigCache = &configCache{agents: map[string]*agentConfigCache{}}
+
+type configCache struct {
+	mu     sync.Mutex
+	agents map[string]*agentConfigCache
+}
+
+// agentConfigCache contains cached scrape objects of a single VMAgent
+type agentConfigCache struct {
+	mu      sync.Mutex
+	objects map[string]*cachedScrapeObject
+
+	// fields below are valid only during a single config generation
+	specHash            string
+	sharedHash          string
+	defaultsGenerations map[string]int64
+	seen                map[string]struct{}
+	refVersions         map[string]string
+}
+
+// cachedScrapeObject is valid until object generation, VMAgent spec,
+// applied VMScrapeDefaults or resourceVersion of any referenced Secret or ConfigMap changes
+type cachedScrapeObject struct {
+	uid        types.UID
+	generation int64
+	specHash   string
+	refs       map[string]string
+	secrets    *scrapesSecretsCache
+
+	// scrapeConfigs are generated after secrets loading
+	// and depend on object position at the list and shared VMAgent secrets
+	scrapeConfigs []yaml.MapSlice
+	position      int
+	sharedHash    string
+}
+
+// acquire returns locked cache of the given VMAgent
+// release must be called after config generation
+//
+// defaultsGenerations contains generations of VMScrapeDefaults and VMClusterScrapeDefaults by its keys
+func (cc *configCache) acquire(cr *vmv1beta1.VMAgent, defaultsGenerations map[string]int64) (*agentConfigCache, error) {
+	data, err := json.Marshal(&cr.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build config cache key: %w", err)
+	}
+
+	agentKey := cr.Namespace + "/" + cr.Name
+	cc.mu.Lock()
+	ac, ok := cc.agents[agentKey]
+	if !ok {
+		ac = &agentConfigCache{objects: map[string]*cachedScrapeObject{}}
+		cc.agents[agentKey] = ac
+	}
+	cc.mu.Unlock()
+
+	ac.mu.Lock()
+	ac.specHash = hashConfig(data)
+	ac.sharedHash = ""
+	ac.defaultsGenerations = defaultsGenerations
+	ac.seen = map[string]struct{}{}
+	ac.refVersions = map[string]string{}
+	return ac, nil
+}
+
+// release removes objects, which weren't seen during config generation and unlocks cache
+func (ac *agentConfigCache) release(success bool) {
+	if success {
+		for key := range ac.objects {
+			if _, ok := ac.seen[key]; !ok {
+				delete(ac.objects, key)
+			}
+		}
+	}
+	ac.seen = nil
+	ac.refVersions = nil
+	ac.defaultsGenerations = nil
+	ac.mu.Unlock()
+}
+
+// DropConfigCache removes cached scrape objects of the given VMAgent
+func DropConfigCache(cr *vmv1beta1.VMAgent) {
+	scrapeConfigCache.mu.Lock()
+	delete(scrapeConfigCache.agents, cr.Namespace+"/"+cr.Name)
+	scrapeConfigCache.mu.Unlock()
+}
+
+// scrapeObjectCacheKey returns cache key of scrape object
+//
+// key includes name and generation of VMScrapeDefaults and VMClusterScrapeDefaults applied to the object,
+// since defaults selection depends on namespace labels, which aren't tracked by object generation
+func (ac *agentConfigCache) scrapeObjectCacheKey(o client.Object) string {
+	key := fmt.Sprintf("%T/%s/%s", o, o.GetNamespace(), o.GetName())
+	so, ok := o.(scrapeObjectWithDefaults)
+	if !ok {
+		return key
+	}
+	for _, applied := range so.GetScrapeObjectStatus().AppliedDefaults {
+		key += fmt.Sprintf("/%s@%d", applied.Name, ac.defaultsGenerations[applied.Name])
+	}
+	return key
+}
+
+// withSecretsCache returns function, which loads secrets of scrape object into dst
+// or takes it from cache if object and referenced secrets weren't changed
+func withSecretsCache[T client.Object](ctx context.Context, ac *agentConfigCache, rclient client.Client, dst *scrapesSecretsCache, load func(o T, rclient client.Client, ssCache *scrapesSecretsCache) error) func(T) error {
+	return func(o T) error {
+		if ac == nil {
+			return load(o, rclient, dst)
+		}
+		key := ac.scrapeObjectCacheKey(o)
+		ac.seen[key] = struct{}{}
+		if co, ok := ac.objects[key]; ok {
+			isValid, err := ac.isValid(ctx, rclient, co, o)
+			if err != nil {
+				return err
+			}
+			if isValid {
+				configCacheRequestsTotal.WithLabelValues("secrets", "hit").Inc()
+				dst.merge(co.secrets)
+				return nil
+			}
+		}
+		configCacheRequestsTotal.WithLabelValues("secrets", "miss").Inc()
+		delete(ac.objects, key)
+
+		rc := &refsRecordingClient{Client: rclient, refs: map[string]string{}}
+		objSecrets := newScrapesSecretsCache()
+		if err := load(o, rc, objSecrets); err != nil {
+			return err
+		}
+		for ref, rv := range rc.refs {
+			ac.refVersions[ref] = rv
+		}
+		ac.objects[key] = &cachedScrapeObject{
+			uid:        o.GetUID(),
+			generation: o.GetGeneration(),
+			specHash:   ac.specHash,
+			refs:       rc.refs,
+			secrets:    objSecrets,
+		}
+		dst.merge(objSecrets)
+		return nil
+	}
+}
+
+func (ac *agentConfigCache) isValid(ctx context.Context, rclient client.Client, co *cachedScrapeObject, o client.Object) (bool, error) {
+	if co.uid != o.GetUID() || co.generation != o.GetGeneration() || co.specHash != ac.specHash {
+		return false, nil
+	}
+	for ref, rv := range co.refs {
+		currentRV, ok := ac.refVersions[ref]
+		if !ok {
+			var err error
+			currentRV, err = getRefResourceVersion(ctx, rclient, ref)
+			if err != nil {
+				return false, err
+			}
+			ac.refVersions[ref] = currentRV
+		}
+		if currentRV != rv {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// scrapeConfigsFor returns cached scrape configs of object or generates it
+func (ac *agentConfigCache) scrapeConfigsFor(o client.Object, position int, generate func() []yaml.MapSlice) []yaml.MapSlice {
+	if ac == nil {
+		return generate()
+	}
+	co, ok := ac.objects[ac.scrapeObjectCacheKey(o)]
+	if !ok {
+		return generate()
+	}
+	if co.scrapeConfigs != nil && co.position == position && co.sharedHash == ac.sharedHash {
+		configCacheRequestsTotal.WithLabelValues("scrape_configs", "hit").Inc()
+		return co.scrapeConfigs
+	}
+	configCacheRequestsTotal.WithLabelValues("scrape_configs", "miss").Inc()
+	co.scrapeConfigs = generate()
+	co.position = position
+	co.sharedHash = ac.sharedHash
+	return co.scrapeConfigs
+}
+
+// setSharedSecrets tracks changes of VMAgent secrets, which are used by scrape configs of all objects
+func (ac *agentConfigCache) setSharedSecrets(ssCache *scrapesSecretsCache) error {
+	if ac == nil {
+		return nil
+	}
+	data, err := json.Marshal([]any{ssCache.baSecrets["apiserver"], ssCache.authorizationSecrets["apiserver"]})
+	if err != nil {
+		return fmt.Errorf("cannot build shared secrets cache key: %w", err)
+	}
+	ac.sharedHash = hashConfig(data)
+	return nil
+}
+
+func getRefResourceVersion(ctx context.Context, rclient client.Client, ref string) (string, error) {
+	var kind, namespace, name string
+	if _, err := fmt.Sscanf(ref, "%s %s %s", &kind, &namespace, &name); err != nil {
+		return "", fmt.Errorf("BUG: unexpected ref format=%q: %w", ref, err)
+	}
+	var obj client.Object
+	switch kind {
+	case "Secret":
+		obj = &corev1.Secret{}
+	case "ConfigMap":
+		obj = &corev1.ConfigMap{}
+	default:
+		return "", fmt.Errorf("BUG: unexpected ref kind=%q", kind)
+	}
+	if err := rclient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, obj); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return obj.GetResourceVersion(), nil
+}
+
+// refsRecordingClient records resourceVersions of Secrets and ConfigMaps fetched by scrape object secrets loader
+type refsRecordingClient struct {
+	client.Client
+	refs map[string]string
+}
+
+// Get implements client.Client interface
+func (c *refsRecordingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	err := c.Client.Get(ctx, key, obj, opts...)
+	var kind string
+	switch obj.(type) {
+	case *corev1.Secret:
+		kind = "Secret"
+	case *corev1.ConfigMap:
+		kind = "ConfigMap"
+	default:
+		return err
+	}
+	if err == nil {
+		c.refs[fmt.Sprintf("%s %s %s", kind, key.Namespace, key.Name)] = obj.GetResourceVersion()
+	}
+	return err
+}
@@ -0,0 +1,135 @@
+package vmagent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func TestScrapeConfigCache(t *testing.T) {
+	ctx := context.Background()
+	cr := &vmv1beta1.VMAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "cached", Namespace: "default"},
+		Spec: vmv1beta1.VMAgentSpec{
+			SelectAllByDefault: true,
+		},
+	}
+	defer DropConfigCache(cr)
+	ss := &vmv1beta1.VMServiceScrape{
+		ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", UID: "svc-uid", Generation: 1},
+		Spec: vmv1beta1.VMServiceScrapeSpec{
+			Endpoints: []vmv1beta1.Endpoint{
+				{
+					Port: "http",
+					EndpointAuth: vmv1beta1.EndpointAuth{
+						BearerTokenSecret: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "token"},
+							Key:                  "token",
+						},
+					},
+				},
+			},
+		},
+	}
+	ps := &vmv1beta1.VMPodScrape{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default", UID: "pod-uid", Generation: 1},
+		Spec: vmv1beta1.VMPodScrapeSpec{
+			PodMetricsEndpoints: []vmv1beta1.PodMetricsEndpoint{{Port: ptr.To("http")}},
+		},
+	}
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{
+		cr,
+		ss,
+		ps,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&vmv1beta1.VMScrapeDefaults{
+			ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "monitoring", Generation: 1},
+			Spec: vmv1beta1.VMScrapeDefaultsSpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+				EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+					SampleLimit: 1000,
+				},
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "default"},
+			Data:       map[string][]byte{"token": []byte("secret-v1")},
+		},
+	})
+	generate := func() string {
+		t.Helper()
+		_, err := createOrUpdateConfigurationSecret(ctx, fclient, cr.DeepCopy(), nil, nil)
+		assert.NoError(t, err)
+		var s corev1.Secret
+		assert.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: cr.PrefixedName()}, &s))
+		return mustGunzip(t, s.Data[vmagentGzippedFilename])
+	}
+	cachedObject := func(key string) *cachedScrapeObject {
+		return scrapeConfigCache.agents["default/cached"].objects[key]
+	}
+	ssKey := "*v1beta1.VMServiceScrape/default/svc"
+	psKey := "*v1beta1.VMPodScrape/default/pod"
+
+	cfg := generate()
+	assert.Contains(t, cfg, "bearer_token: secret-v1")
+	ssEntry, psEntry := cachedObject(ssKey), cachedObject(psKey)
+	assert.NotNil(t, ssEntry)
+	assert.NotNil(t, psEntry)
+
+	// nothing changed
+	assert.Equal(t, cfg, generate())
+	assert.Same(t, ssEntry, cachedObject(ssKey))
+	assert.Same(t, psEntry, cachedObject(psKey))
+
+	// referenced secret changed
+	var token corev1.Secret
+	assert.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "token"}, &token))
+	token.Data["token"] = []byte("secret-v2")
+	assert.NoError(t, fclient.Update(ctx, &token))
+	assert.Contains(t, generate(), "bearer_token: secret-v2")
+	assert.NotSame(t, ssEntry, cachedObject(ssKey))
+	assert.Same(t, psEntry, cachedObject(psKey))
+
+	// object generation changed
+	var gotPs vmv1beta1.VMPodScrape
+	assert.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "pod"}, &gotPs))
+	gotPs.Generation = 2
+	gotPs.Spec.PodMetricsEndpoints[0].Path = "/custom-metrics"
+	assert.NoError(t, fclient.Update(ctx, &gotPs))
+	assert.Contains(t, generate(), "metrics_path: /custom-metrics")
+	assert.NotSame(t, psEntry, cachedObject(psKey))
+
+	// removed object is evicted from cache
+	assert.NoError(t, fclient.Delete(ctx, &gotPs))
+	assert.NotContains(t, generate(), "podScrape/default/pod")
+	assert.Nil(t, cachedObject(psKey))
+
+	// namespace labels changed defaults applied to object
+	var ns corev1.Namespace
+	assert.NoError(t, fclient.Get(ctx, types.NamespacedName{Name: "default"}, &ns))
+	ns.Labels = map[string]string{"team": "a"}
+	assert.NoError(t, fclient.Update(ctx, &ns))
+	assert.Contains(t, generate(), "sample_limit: 1000")
+	assert.Nil(t, cachedObject(ssKey))
+	ssDefaultsEntry := cachedObject(ssKey + "/monitoring/defaults@1")
+	assert.NotNil(t, ssDefaultsEntry)
+
+	// applied defaults generation changed
+	var sd vmv1beta1.VMScrapeDefaults
+	assert.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: "monitoring", Name: "defaults"}, &sd))
+	sd.Generation = 2
+	sd.Spec.SampleLimit = 2000
+	assert.NoError(t, fclient.Update(ctx, &sd))
+	assert.Contains(t, generate(), "sample_limit: 2000")
+	assert.Nil(t, cachedObject(ssKey+"/monitoring/defaults@1"))
+	assert.NotNil(t, cachedObject(ssKey+"/monitoring/defaults@2"))
+}
@@ -0,0 +1,256 @@
+package vmagent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/finalize"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/reconcile"
+)
+
+const (
+	// config-reloader writes parts into this sub-directory of config-envsubst-file directory
+	configPartsDir = "scrape_configs"
+	maxConfigParts = 64
+)
+
+// maxConfigPartSize is a max size of gzipped configuration stored at a single secret
+// it's a variable for tests
+var maxConfigPartSize = vmv1beta1.MaxConfigMapDataSize
+
+// vmagentConfig is a gzipped scrape configuration of vmagent
+//
+// scrape_configs of oversized configuration are moved into parts,
+// which are stored at separate secrets and included by main config with scrape_config_files
+type vmagentConfig struct {
+	main  []byte
+	parts [][]byte
+}
+
+func (c *vmagentConfig) equal(o *vmagentConfig) bool {
+	if !bytes.Equal(c.main, o.main) || len(c.parts) != len(o.parts) {
+		return false
+	}
+	for i := range c.parts {
+		if !bytes.Equal(c.parts[i], o.parts[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *vmagentConfig) hash() string {
+	return hashConfig(append([][]byte{c.main}, c.parts...)...)
+}
+
+// buildVMAgentConfig compresses generated config and splits it into parts if it exceeds maxConfigPartSize
+//
+// scrape jobs are distributed between parts by job_name hash, so a change of a single job modifies only one part.
+// Number of parts is a power of two and it's increased only if any part doesn't fit into the size limit.
+// Parts are assembled by config-reloader, so split is performed only if vm config-reloader is used.
+func buildVMAgentConfig(cr *vmv1beta1.VMAgent, generated []byte) (*vmagentConfig, error) {
+	var buf bytes.Buffer
+	if err := gzipConfig(&buf, generated); err != nil {
+		return nil, fmt.Errorf("cannot gzip config for vmagent: %w", err)
+	}
+	cfg := &vmagentConfig{main: buf.Bytes()}
+	if len(cfg.main) <= maxConfigPartSize || !ptr.Deref(cr.Spec.UseVMConfigReloader, false) {
+		return cfg, nil
+	}
+
+	var mainCfg yaml.MapSlice
+	if err := yaml.Unmarshal(generated, &mainCfg); err != nil {
+		return nil, fmt.Errorf("cannot parse generated config: %w", err)
+	}
+	var scrapeConfigs []any
+	for i, item := range mainCfg {
+		if item.Key != "scrape_configs" {
+			continue
+		}
+		scrapeConfigs, _ = item.Value.([]any)
+		mainCfg = append(mainCfg[:i], mainCfg[i+1:]...)
+		break
+	}
+	mainCfg = append(mainCfg, yaml.MapItem{
+		Key:   "scrape_config_files",
+		Value: []string{path.Join(vmAgentConOfOutDir, configPartsDir, "*.yaml")},
+	})
+	mainData, err := yaml.Marshal(mainCfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal main config: %w", err)
+	}
+	buf.Reset()
+	if err := gzipConfig(&buf, mainData); err != nil {
+		return nil, fmt.Errorf("cannot gzip main config for vmagent: %w", err)
+	}
+	cfg.main = buf.Bytes()
+
+	for partsCount := 2; partsCount <= maxConfigParts; partsCount *= 2 {
+		parts, err := splitScrapeConfigs(scrapeConfigs, partsCount)
+		if err != nil {
+			return nil, err
+		}
+		if parts != nil {
+			cfg.parts = parts
+			return cfg, nil
+		}
+	}
+	return nil, fmt.Errorf("scrape configuration doesn't fit into %d secrets with size limit=%d bytes", maxConfigParts, maxConfigPartSize)
+}
+
+// splitScrapeConfigs returns gzipped parts or nil if any part exceeds maxConfigPartSize
+func splitScrapeConfigs(scrapeConfigs []any, partsCount int) ([][]byte, error) {
+	buckets := make([][]any, partsCount)
+	for _, sc := range scrapeConfigs {
+		idx := scrapeJobPartIndex(sc, partsCount)
+		buckets[idx] = append(buckets[idx], sc)
+	}
+	parts := make([][]byte, 0, partsCount)
+	for _, bucket := range buckets {
+		if bucket == nil {
+			bucket = []any{}
+		}
+		data, err := yaml.Marshal(bucket)
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal scrape configs part: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := gzipConfig(&buf, data); err != nil {
+			return nil, fmt.Errorf("cannot gzip scrape configs part: %w", err)
+		}
+		if buf.Len() > maxConfigPartSize {
+			return nil, nil
+		}
+		parts = append(parts, buf.Bytes())
+	}
+	return parts, nil
+}
+
+func scrapeJobPartIndex(sc any, partsCount int) int {
+	var jobName string
+	if ms, ok := sc.(yaml.MapSlice); ok {
+		for _, item := range ms {
+			if item.Key == "job_name" {
+				jobName = fmt.Sprint(item.Value)
+				break
+			}
+		}
+	}
+	h := fnv.New32a()
+	h.Write([]byte(jobName)) // nolint:errcheck
+	return int(h.Sum32() % uint32(partsCount))
+}
+
+func configPartSecretName(secretName string, idx int) string {
+	return fmt.Sprintf("%s-part-%d", secretName, idx)
+}
+
+// reconcileSplitConfigSecret updates config secret and secrets with config parts
+//
+// parts are updated before the main secret in order to have all included files in place
+// and stale parts are removed only after the main secret update
+func reconcileSplitConfigSecret(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAgent, s *corev1.Secret, parts [][]byte, prevMeta *metav1.ObjectMeta) error {
+	lbls := cr.SelectorLabels()
+	lbls[vmv1beta1.ConfigSecretPartOfLabel] = s.Name
+	for idx, data := range parts {
+		partSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            configPartSecretName(s.Name, idx),
+				Namespace:       s.Namespace,
+				Labels:          lbls,
+				Annotations:     cr.AnnotationsFiltered(),
+				OwnerReferences: cr.AsOwner(),
+			},
+			Data: map[string][]byte{vmagentGzippedFilename: data},
+		}
+		if err := reconcile.Secret(ctx, rclient, partSecret, nil); err != nil {
+			return fmt.Errorf("cannot reconcile config part secret=%s: %w", partSecret.Name, err)
+		}
+	}
+	if err := reconcile.Secret(ctx, rclient, s, prevMeta); err != nil {
+		return err
+	}
+	return removeStaleConfigParts(ctx, rclient, s.Namespace, s.Name, len(parts))
+}
+
+// removeStaleConfigParts deletes part secrets of the given config secret with index greater or equal to partsCount
+func removeStaleConfigParts(ctx context.Context, rclient client.Client, namespace, secretName string, partsCount int) error {
+	existParts, err := listConfigParts(ctx, rclient, namespace, secretName)
+	if err != nil {
+		return err
+	}
+	for _, s := range existParts {
+		if idx, ok := configPartIndex(secretName, s.Name); ok && idx < partsCount {
+			continue
+		}
+		logger.WithContext(ctx).Info(fmt.Sprintf("removing stale config part secret=%s", s.Name))
+		if err := finalize.SafeDelete(ctx, rclient, s); err != nil {
+			return fmt.Errorf("cannot remove stale config part secret=%s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// loadConfigParts returns content of config parts ordered by part index
+func loadConfigParts(ctx context.Context, rclient client.Client, namespace, secretName string) ([][]byte, error) {
+	existParts, err := listConfigParts(ctx, rclient, namespace, secretName)
+	if err != nil {
+		return nil, err
+	}
+	type indexedPart struct {
+		idx  int
+		data []byte
+	}
+	var ips []indexedPart
+	for _, s := range existParts {
+		if idx, ok := configPartIndex(secretName, s.Name); ok {
+			ips = append(ips, indexedPart{idx: idx, data: s.Data[vmagentGzippedFilename]})
+		}
+	}
+	sort.Slice(ips, func(i, j int) bool {
+		return ips[i].idx < ips[j].idx
+	})
+	var parts [][]byte
+	for _, ip := range ips {
+		parts = append(parts, ip.data)
+	}
+	return parts, nil
+}
+
+func listConfigParts(ctx context.Context, rclient client.Client, namespace, secretName string) ([]*corev1.Secret, error) {
+	var l corev1.SecretList
+	if err := rclient.List(ctx, &l, client.InNamespace(namespace), client.MatchingLabels{vmv1beta1.ConfigSecretPartOfLabel: secretName}); err != nil {
+		return nil, fmt.Errorf("cannot list config parts of secret=%s: %w", secretName, err)
+	}
+	parts := make([]*corev1.Secret, 0, len(l.Items))
+	for i := range l.Items {
+		parts = append(parts, &l.Items[i])
+	}
+	return parts, nil
+}
+
+func configPartIndex(secretName, partName string) (int, bool) {
+	suffix, ok := strings.CutPrefix(partName, secretName+"-part-")
+	if !ok {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(suffix)
+	if err != nil {
+		return 0, false
+	}
+	return idx, true
+}
@@ -0,0 +1,133 @@
+package vmagent
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func mustGunzip(t *testing.T, data []byte) string {
+	t.Helper()
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+	content, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	return string(content)
+}
+
+func TestBuildVMAgentConfig(t *testing.T) {
+	origin := maxConfigPartSize
+	maxConfigPartSize = 1024
+	defer func() {
+		maxConfigPartSize = origin
+	}()
+	genConfig := func(jobsCount int, changedJob int) []byte {
+		var b strings.Builder
+		b.WriteString("global:\n  scrape_interval: 30s\nscrape_configs:\n")
+		for i := 0; i < jobsCount; i++ {
+			target := fmt.Sprintf("host-%d.example.com:9100", i)
+			if i == changedJob {
+				target = "changed.example.com:9100"
+			}
+			fmt.Fprintf(&b, "- job_name: job-%d\n  static_configs:\n  - targets:\n    - %s\n    labels:\n      job_hash: %x\n", i, target, i*7919)
+		}
+		return []byte(b.String())
+	}
+	cr := &vmv1beta1.VMAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+		Spec: vmv1beta1.VMAgentSpec{
+			CommonConfigReloaderParams: vmv1beta1.CommonConfigReloaderParams{
+				UseVMConfigReloader: ptr.To(true),
+			},
+		},
+	}
+
+	// small config isn't split
+	cfg, err := buildVMAgentConfig(cr, genConfig(2, -1))
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.parts)
+	assert.Equal(t, string(genConfig(2, -1)), mustGunzip(t, cfg.main))
+
+	// large config is split
+	cfg, err = buildVMAgentConfig(cr, genConfig(200, -1))
+	assert.NoError(t, err)
+	assert.Greater(t, len(cfg.parts), 1)
+	mainCfg := mustGunzip(t, cfg.main)
+	assert.NotContains(t, mainCfg, "job_name")
+	assert.Contains(t, mainCfg, "scrape_config_files:\n- /etc/vmagent/config_out/scrape_configs/*.yaml")
+	var jobsCount int
+	for _, part := range cfg.parts {
+		assert.LessOrEqual(t, len(part), maxConfigPartSize)
+		jobsCount += strings.Count(mustGunzip(t, part), "job_name: ")
+	}
+	assert.Equal(t, 200, jobsCount)
+
+	// change of a single job modifies only one part
+	changedCfg, err := buildVMAgentConfig(cr, genConfig(200, 42))
+	assert.NoError(t, err)
+	assert.Equal(t, len(cfg.parts), len(changedCfg.parts))
+	var changedParts int
+	for i := range cfg.parts {
+		if !bytes.Equal(cfg.parts[i], changedCfg.parts[i]) {
+			changedParts++
+		}
+	}
+	assert.Equal(t, 1, changedParts)
+	assert.Equal(t, cfg.main, changedCfg.main)
+
+	// config isn't split without vm config-reloader
+	cr.Spec.UseVMConfigReloader = nil
+	cfg, err = buildVMAgentConfig(cr, genConfig(200, -1))
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.parts)
+}
+
+func TestReconcileSplitConfigSecret(t *testing.T) {
+	ctx := context.Background()
+	cr := &vmv1beta1.VMAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+	}
+	partMeta := func(idx int) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:      configPartSecretName(cr.PrefixedName(), idx),
+			Namespace: cr.Namespace,
+			Labels:    map[string]string{vmv1beta1.ConfigSecretPartOfLabel: cr.PrefixedName()},
+		}
+	}
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{
+		&corev1.Secret{ObjectMeta: partMeta(0)},
+		&corev1.Secret{ObjectMeta: partMeta(1)},
+		&corev1.Secret{ObjectMeta: partMeta(2)},
+	})
+	s := makeConfigSecret(cr, &scrapesSecretsCache{})
+	s.Data[vmagentGzippedFilename] = []byte("main")
+	assert.NoError(t, reconcileSplitConfigSecret(ctx, fclient, cr, s, [][]byte{[]byte("p0"), []byte("p1")}, nil))
+
+	parts, err := loadConfigParts(ctx, fclient, cr.Namespace, cr.PrefixedName())
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("p0"), []byte("p1")}, parts)
+
+	var got corev1.Secret
+	assert.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: cr.PrefixedName()}, &got))
+	assert.Equal(t, "main", string(got.Data[vmagentGzippedFilename]))
+
+	// config is merged back
+	assert.NoError(t, reconcileSplitConfigSecret(ctx, fclient, cr, s, nil, nil))
+	parts, err = loadConfigParts(ctx, fclient, cr.Namespace, cr.PrefixedName())
+	assert.NoError(t, err)
+	assert.Empty(t, parts)
+}
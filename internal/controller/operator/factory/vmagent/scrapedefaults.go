@@ -0,0 +1,363 @@
+package vmagent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/config"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+)
+
+func selectScrapeDefaults(ctx context.Context, rclient client.Client) ([]*vmv1beta1.VMScrapeDefaults, error) {
+	var defaultsCombined []*vmv1beta1.VMScrapeDefaults
+	var namespacedNames []string
+	if err := k8stools.ListObjectsByNamespace(ctx, rclient, config.MustGetWatchNamespaces(), func(list *vmv1beta1.VMScrapeDefaultsList) {
+		for i := range list.Items {
+			item := &list.Items[i]
+			if !item.DeletionTimestamp.IsZero() {
+				continue
+			}
+			defaultsCombined = append(defaultsCombined, item)
+			namespacedNames = append(namespacedNames, item.AsKey())
+		}
+	}); err != nil {
+		return nil, err
+	}
+	logger.SelectedObjects(ctx, "VMScrapeDefaults", len(namespacedNames), 0, namespacedNames)
+	return defaultsCombined, nil
+}
+
+func selectClusterScrapeDefaults(ctx context.Context, rclient client.Client) ([]*vmv1beta1.VMClusterScrapeDefaults, error) {
+	if len(config.MustGetWatchNamespaces()) > 0 {
+		// cluster-scoped objects cannot be accessed without cluster-wide permissions
+		return nil, nil
+	}
+	var list vmv1beta1.VMClusterScrapeDefaultsList
+	if err := rclient.List(ctx, &list); err != nil {
+		return nil, err
+	}
+	var defaultsCombined []*vmv1beta1.VMClusterScrapeDefaults
+	var names []string
+	for i := range list.Items {
+		item := &list.Items[i]
+		if !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		defaultsCombined = append(defaultsCombined, item)
+		names = append(names, item.AsKey())
+	}
+	logger.SelectedObjects(ctx, "VMClusterScrapeDefaults", len(names), 0, names)
+	return defaultsCombined, nil
+}
+
+// scrapeDefaultsMatcher matches VMScrapeDefaults or VMClusterScrapeDefaults with namespaces of scrape objects
+type scrapeDefaultsMatcher struct {
+	key  string
+	spec *vmv1beta1.VMScrapeDefaultsSpec
+	// nil means any namespace
+	namespaces map[string]struct{}
+}
+
+func (sdm *scrapeDefaultsMatcher) isMatch(namespace string) bool {
+	if sdm.namespaces == nil {
+		return true
+	}
+	_, ok := sdm.namespaces[namespace]
+	return ok
+}
+
+// newScrapeDefaultsMatcher returns matcher for defaults spec
+//
+// ownNamespace is used if namespaceSelector isn't defined, empty value matches any namespace
+func newScrapeDefaultsMatcher(ctx context.Context, rclient client.Client, key, ownNamespace string, spec *vmv1beta1.VMScrapeDefaultsSpec) (*scrapeDefaultsMatcher, error) {
+	sdm := &scrapeDefaultsMatcher{key: key, spec: spec}
+	nsSelector := spec.NamespaceSelector
+	switch {
+	case nsSelector == nil:
+		if ownNamespace != "" {
+			sdm.namespaces = map[string]struct{}{ownNamespace: {}}
+		}
+	case len(config.MustGetWatchNamespaces()) > 0:
+		// filters by namespace is disabled, since operator cannot access cluster-wide APIs
+	case len(nsSelector.MatchLabels) == 0 && len(nsSelector.MatchExpressions) == 0:
+		// empty selector matches any namespace
+	default:
+		selector, err := metav1.LabelSelectorAsSelector(nsSelector)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse namespaceSelector: %w", err)
+		}
+		namespaces, err := k8stools.SelectNamespaces(ctx, rclient, selector)
+		if err != nil {
+			return nil, fmt.Errorf("cannot select namespaces: %w", err)
+		}
+		sdm.namespaces = make(map[string]struct{}, len(namespaces))
+		for _, ns := range namespaces {
+			sdm.namespaces[ns] = struct{}{}
+		}
+	}
+	return sdm, nil
+}
+
+var skipAnyValidationError = func(_ error) bool {
+	return true
+}
+
+func validateScrapeDefaultsSpec(spec *vmv1beta1.VMScrapeDefaultsSpec) error {
+	if spec.NamespaceSelector == nil {
+		return nil
+	}
+	if _, err := metav1.LabelSelectorAsSelector(spec.NamespaceSelector); err != nil {
+		return fmt.Errorf("cannot parse namespaceSelector: %w", err)
+	}
+	return nil
+}
+
+// scrapeDefaultsGenerations returns generations of VMScrapeDefaults and VMClusterScrapeDefaults by its keys
+func (so *scrapeObjects) scrapeDefaultsGenerations() map[string]int64 {
+	generations := make(map[string]int64, len(so.sds)+len(so.csds))
+	for _, sd := range so.sds {
+		generations[sd.AsKey()] = sd.Generation
+	}
+	for _, csd := range so.csds {
+		generations[csd.AsKey()] = csd.Generation
+	}
+	return generations
+}
+
+// applyScrapeDefaults merges VMScrapeDefaults and VMClusterScrapeDefaults into endpoints of selected scrape objects
+//
+// it must be called before secrets loading and validation,
+// since defaults may add references to secrets or access file system.
+func (so *scrapeObjects) applyScrapeDefaults(ctx context.Context, rclient client.Client) error {
+	var err error
+	// objects with invalid selectors must be excluded from config generation
+	so.sds, so.sdsBroken, err = forEachCollectSkipOn(so.sds, so.sdsBroken, func(sd *vmv1beta1.VMScrapeDefaults) error {
+		return validateScrapeDefaultsSpec(&sd.Spec)
+	}, skipAnyValidationError)
+	if err != nil {
+		return fmt.Errorf("BUG: validation cannot return error for VMScrapeDefaults: %w", err)
+	}
+	so.csds, so.csdsBroken, err = forEachCollectSkipOn(so.csds, so.csdsBroken, func(csd *vmv1beta1.VMClusterScrapeDefaults) error {
+		return validateScrapeDefaultsSpec(&csd.Spec)
+	}, skipAnyValidationError)
+	if err != nil {
+		return fmt.Errorf("BUG: validation cannot return error for VMClusterScrapeDefaults: %w", err)
+	}
+	matchers := make([]*scrapeDefaultsMatcher, 0, len(so.sds)+len(so.csds))
+	for _, sd := range so.sds {
+		sdm, err := newScrapeDefaultsMatcher(ctx, rclient, sd.AsKey(), sd.Namespace, &sd.Spec)
+		if err != nil {
+			return fmt.Errorf("cannot apply VMScrapeDefaults=%s: %w", sd.AsKey(), err)
+		}
+		matchers = append(matchers, sdm)
+	}
+	for _, csd := range so.csds {
+		sdm, err := newScrapeDefaultsMatcher(ctx, rclient, csd.AsKey(), "", &csd.Spec)
+		if err != nil {
+			return fmt.Errorf("cannot apply VMClusterScrapeDefaults=%s: %w", csd.AsKey(), err)
+		}
+		matchers = append(matchers, sdm)
+	}
+	if len(matchers) == 0 {
+		return nil
+	}
+	// objects with higher priority must be applied first
+	sort.SliceStable(matchers, func(i, j int) bool {
+		if matchers[i].spec.Priority != matchers[j].spec.Priority {
+			return matchers[i].spec.Priority > matchers[j].spec.Priority
+		}
+		return matchers[i].key < matchers[j].key
+	})
+	for _, ss := range so.sss {
+		applyDefaultsTo(ss, matchers, func(d *vmv1beta1.VMScrapeDefaultsSpec, add func(string, []string)) {
+			for i := range ss.Spec.Endpoints {
+				ep := &ss.Spec.Endpoints[i]
+				add(fmt.Sprintf("spec.endpoints[%d]", i), mergeEndpointDefaults(&ep.EndpointRelabelings, &ep.EndpointAuth, &ep.EndpointScrapeParams, d))
+			}
+		})
+	}
+	for _, ps := range so.pss {
+		applyDefaultsTo(ps, matchers, func(d *vmv1beta1.VMScrapeDefaultsSpec, add func(string, []string)) {
+			for i := range ps.Spec.PodMetricsEndpoints {
+				ep := &ps.Spec.PodMetricsEndpoints[i]
+				add(fmt.Sprintf("spec.podMetricsEndpoints[%d]", i), mergeEndpointDefaults(&ep.EndpointRelabelings, &ep.EndpointAuth, &ep.EndpointScrapeParams, d))
+			}
+		})
+	}
+	for _, sts := range so.stss {
+		applyDefaultsTo(sts, matchers, func(d *vmv1beta1.VMScrapeDefaultsSpec, add func(string, []string)) {
+			for i := range sts.Spec.TargetEndpoints {
+				ep := sts.Spec.TargetEndpoints[i]
+				add(fmt.Sprintf("spec.targetEndpoints[%d]", i), mergeEndpointDefaults(&ep.EndpointRelabelings, &ep.EndpointAuth, &ep.EndpointScrapeParams, d))
+			}
+		})
+	}
+	for _, ns := range so.nss {
+		applyDefaultsTo(ns, matchers, func(d *vmv1beta1.VMScrapeDefaultsSpec, add func(string, []string)) {
+			add("spec", mergeEndpointDefaults(&ns.Spec.EndpointRelabelings, &ns.Spec.EndpointAuth, &ns.Spec.EndpointScrapeParams, d))
+		})
+	}
+	for _, prs := range so.prss {
+		applyDefaultsTo(prs, matchers, func(d *vmv1beta1.VMScrapeDefaultsSpec, add func(string, []string)) {
+			// path, scheme and params are defined by vmProberSpec and relabelConfigs are defined per probe targets
+			d = d.DeepCopy()
+			d.Path, d.Scheme, d.Params, d.RelabelConfigs = "", "", nil, nil
+			rs := vmv1beta1.EndpointRelabelings{MetricRelabelConfigs: prs.Spec.MetricRelabelConfigs}
+			add("spec", mergeEndpointDefaults(&rs, &prs.Spec.EndpointAuth, &prs.Spec.EndpointScrapeParams, d))
+			prs.Spec.MetricRelabelConfigs = rs.MetricRelabelConfigs
+		})
+	}
+	for _, scs := range so.scss {
+		applyDefaultsTo(scs, matchers, func(d *vmv1beta1.VMScrapeDefaultsSpec, add func(string, []string)) {
+			add("spec", mergeEndpointDefaults(&scs.Spec.EndpointRelabelings, &scs.Spec.EndpointAuth, &scs.Spec.EndpointScrapeParams, d))
+		})
+	}
+	return nil
+}
+
+type scrapeObjectWithDefaults interface {
+	client.Object
+	GetScrapeObjectStatus() *vmv1beta1.ScrapeObjectStatus
+}
+
+func applyDefaultsTo(o scrapeObjectWithDefaults, matchers []*scrapeDefaultsMatcher, apply func(d *vmv1beta1.VMScrapeDefaultsSpec, add func(prefix string, fields []string))) {
+	st := o.GetScrapeObjectStatus()
+	st.AppliedDefaults = nil
+	for _, sdm := range matchers {
+		if !sdm.isMatch(o.GetNamespace()) {
+			continue
+		}
+		applied := vmv1beta1.AppliedScrapeDefaults{Name: sdm.key}
+		apply(sdm.spec, func(prefix string, fields []string) {
+			for _, f := range fields {
+				applied.Fields = append(applied.Fields, prefix+"."+f)
+			}
+		})
+		if len(applied.Fields) > 0 {
+			st.AppliedDefaults = append(st.AppliedDefaults, applied)
+		}
+	}
+}
+
+// mergeEndpointDefaults sets fields of endpoint from defaults if they are not defined
+// and returns json names of changed fields
+func mergeEndpointDefaults(rs *vmv1beta1.EndpointRelabelings, auth *vmv1beta1.EndpointAuth, sp *vmv1beta1.EndpointScrapeParams, d *vmv1beta1.VMScrapeDefaultsSpec) []string {
+	var fields []string
+	if len(rs.RelabelConfigs) == 0 && len(d.RelabelConfigs) > 0 {
+		for _, rc := range d.RelabelConfigs {
+			rs.RelabelConfigs = append(rs.RelabelConfigs, rc.DeepCopy())
+		}
+		fields = append(fields, "relabelConfigs")
+	}
+	if len(rs.MetricRelabelConfigs) == 0 && len(d.MetricRelabelConfigs) > 0 {
+		for _, rc := range d.MetricRelabelConfigs {
+			rs.MetricRelabelConfigs = append(rs.MetricRelabelConfigs, rc.DeepCopy())
+		}
+		fields = append(fields, "metricRelabelConfigs")
+	}
+
+	if auth.TLSConfig == nil && d.TLSConfig != nil {
+		auth.TLSConfig = d.TLSConfig.DeepCopy()
+		fields = append(fields, "tlsConfig")
+	}
+	// authorization methods are mutually exclusive
+	// defaults could be applied only if endpoint doesn't have any of it
+	hasAuth := auth.OAuth2 != nil || auth.BearerTokenFile != "" || auth.BearerTokenSecret != nil || auth.BasicAuth != nil || auth.Authorization != nil
+	if !hasAuth {
+		if d.OAuth2 != nil {
+			auth.OAuth2 = d.OAuth2.DeepCopy()
+			fields = append(fields, "oauth2")
+		}
+		if d.BearerTokenFile != "" {
+			auth.BearerTokenFile = d.BearerTokenFile
+			fields = append(fields, "bearerTokenFile")
+		}
+		if d.BearerTokenSecret != nil {
+			auth.BearerTokenSecret = d.BearerTokenSecret.DeepCopy()
+			fields = append(fields, "bearerTokenSecret")
+		}
+		if d.BasicAuth != nil {
+			auth.BasicAuth = d.BasicAuth.DeepCopy()
+			fields = append(fields, "basicAuth")
+		}
+		if d.Authorization != nil {
+			auth.Authorization = d.Authorization.DeepCopy()
+			fields = append(fields, "authorization")
+		}
+	}
+
+	if sp.Path == "" && d.Path != "" {
+		sp.Path = d.Path
+		fields = append(fields, "path")
+	}
+	if sp.Scheme == "" && d.Scheme != "" {
+		sp.Scheme = d.Scheme
+		fields = append(fields, "scheme")
+	}
+	if len(sp.Params) == 0 && len(d.Params) > 0 {
+		sp.Params = make(map[string][]string, len(d.Params))
+		for k, v := range d.Params {
+			sp.Params[k] = append([]string(nil), v...)
+		}
+		fields = append(fields, "params")
+	}
+	if sp.FollowRedirects == nil && d.FollowRedirects != nil {
+		sp.FollowRedirects = ptrCopy(d.FollowRedirects)
+		fields = append(fields, "follow_redirects")
+	}
+	if sp.SampleLimit == 0 && d.SampleLimit != 0 {
+		sp.SampleLimit = d.SampleLimit
+		fields = append(fields, "sampleLimit")
+	}
+	if sp.SeriesLimit == 0 && d.SeriesLimit != 0 {
+		sp.SeriesLimit = d.SeriesLimit
+		fields = append(fields, "seriesLimit")
+	}
+	if sp.Interval == "" && sp.ScrapeInterval == "" {
+		switch {
+		case d.ScrapeInterval != "":
+			sp.ScrapeInterval = d.ScrapeInterval
+			fields = append(fields, "scrape_interval")
+		case d.Interval != "":
+			sp.ScrapeInterval = d.Interval
+			fields = append(fields, "scrape_interval")
+		}
+	}
+	if sp.ScrapeTimeout == "" && d.ScrapeTimeout != "" {
+		sp.ScrapeTimeout = d.ScrapeTimeout
+		fields = append(fields, "scrapeTimeout")
+	}
+	if sp.ProxyURL == nil && d.ProxyURL != nil {
+		sp.ProxyURL = ptrCopy(d.ProxyURL)
+		fields = append(fields, "proxyURL")
+	}
+	// false value cannot be distinguished from missing value
+	if !sp.HonorLabels && d.HonorLabels {
+		sp.HonorLabels = true
+		fields = append(fields, "honorLabels")
+	}
+	if sp.HonorTimestamps == nil && d.HonorTimestamps != nil {
+		sp.HonorTimestamps = ptrCopy(d.HonorTimestamps)
+		fields = append(fields, "honorTimestamps")
+	}
+	if sp.MaxScrapeSize == "" && d.MaxScrapeSize != "" {
+		sp.MaxScrapeSize = d.MaxScrapeSize
+		fields = append(fields, "max_scrape_size")
+	}
+	if sp.VMScrapeParams == nil && d.VMScrapeParams != nil {
+		sp.VMScrapeParams = d.VMScrapeParams.DeepCopy()
+		fields = append(fields, "vm_scrape_params")
+	}
+	return fields
+}
+
+func ptrCopy[T any](src *T) *T {
+	v := *src
+	return &v
+}
@@ -0,0 +1,355 @@
+package vmagent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func TestApplyScrapeDefaults(t *testing.T) {
+	type opts struct {
+		predefinedObjects []runtime.Object
+		sos               *scrapeObjects
+		wantEndpoints     []vmv1beta1.Endpoint
+		wantApplied       []vmv1beta1.AppliedScrapeDefaults
+		wantBroken        int
+	}
+	f := func(o opts) {
+		t.Helper()
+		ctx := context.Background()
+		fclient := k8stools.GetTestClientWithObjects(o.predefinedObjects)
+		sds, err := selectScrapeDefaults(ctx, fclient)
+		assert.NoError(t, err)
+		o.sos.sds = sds
+		csds, err := selectClusterScrapeDefaults(ctx, fclient)
+		assert.NoError(t, err)
+		o.sos.csds = csds
+		assert.NoError(t, o.sos.applyScrapeDefaults(ctx, fclient))
+		assert.Equal(t, o.wantBroken, len(o.sos.sdsBroken)+len(o.sos.csdsBroken))
+		ss := o.sos.sss[0]
+		assert.Equal(t, o.wantEndpoints, ss.Spec.Endpoints)
+		assert.Equal(t, o.wantApplied, ss.Status.AppliedDefaults)
+	}
+	namespaces := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"team": "a"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring", Labels: map[string]string{"team": "b"}}},
+	}
+	newServiceScrape := func(eps ...vmv1beta1.Endpoint) *scrapeObjects {
+		return &scrapeObjects{
+			sss: []*vmv1beta1.VMServiceScrape{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+					Spec:       vmv1beta1.VMServiceScrapeSpec{Endpoints: eps},
+				},
+			},
+		}
+	}
+
+	// defaults without namespaceSelector at another namespace
+	f(opts{
+		predefinedObjects: append(namespaces,
+			&vmv1beta1.VMScrapeDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "monitoring"},
+				Spec: vmv1beta1.VMScrapeDefaultsSpec{
+					EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{ScrapeTimeout: "10s"},
+				},
+			},
+		),
+		sos:           newServiceScrape(vmv1beta1.Endpoint{Port: "http"}),
+		wantEndpoints: []vmv1beta1.Endpoint{{Port: "http"}},
+	})
+
+	// endpoint fields have priority over defaults
+	f(opts{
+		predefinedObjects: append(namespaces,
+			&vmv1beta1.VMScrapeDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "monitoring"},
+				Spec: vmv1beta1.VMScrapeDefaultsSpec{
+					NamespaceSelector: &metav1.LabelSelector{},
+					EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+						Interval:        "30s",
+						ScrapeTimeout:   "10s",
+						SampleLimit:     1000,
+						HonorTimestamps: ptr.To(true),
+					},
+					EndpointAuth: vmv1beta1.EndpointAuth{
+						BearerTokenFile: "/var/run/token",
+						TLSConfig:       &vmv1beta1.TLSConfig{InsecureSkipVerify: true},
+					},
+				},
+			},
+		),
+		sos: newServiceScrape(
+			vmv1beta1.Endpoint{
+				Port: "http",
+				EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+					ScrapeInterval: "5s",
+					SampleLimit:    10,
+				},
+				EndpointAuth: vmv1beta1.EndpointAuth{
+					BasicAuth: &vmv1beta1.BasicAuth{},
+				},
+			},
+			vmv1beta1.Endpoint{Port: "metrics"},
+		),
+		wantEndpoints: []vmv1beta1.Endpoint{
+			{
+				Port: "http",
+				EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+					ScrapeInterval:  "5s",
+					SampleLimit:     10,
+					ScrapeTimeout:   "10s",
+					HonorTimestamps: ptr.To(true),
+				},
+				EndpointAuth: vmv1beta1.EndpointAuth{
+					BasicAuth: &vmv1beta1.BasicAuth{},
+					TLSConfig: &vmv1beta1.TLSConfig{InsecureSkipVerify: true},
+				},
+			},
+			{
+				Port: "metrics",
+				EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+					ScrapeInterval:  "30s",
+					SampleLimit:     1000,
+					ScrapeTimeout:   "10s",
+					HonorTimestamps: ptr.To(true),
+				},
+				EndpointAuth: vmv1beta1.EndpointAuth{
+					BearerTokenFile: "/var/run/token",
+					TLSConfig:       &vmv1beta1.TLSConfig{InsecureSkipVerify: true},
+				},
+			},
+		},
+		wantApplied: []vmv1beta1.AppliedScrapeDefaults{
+			{
+				Name: "monitoring/defaults",
+				Fields: []string{
+					"spec.endpoints[0].tlsConfig",
+					"spec.endpoints[0].scrapeTimeout",
+					"spec.endpoints[0].honorTimestamps",
+					"spec.endpoints[1].tlsConfig",
+					"spec.endpoints[1].bearerTokenFile",
+					"spec.endpoints[1].sampleLimit",
+					"spec.endpoints[1].scrape_interval",
+					"spec.endpoints[1].scrapeTimeout",
+					"spec.endpoints[1].honorTimestamps",
+				},
+			},
+		},
+	})
+
+	// higher priority wins and namespaceSelector filters namespaces
+	f(opts{
+		predefinedObjects: append(namespaces,
+			&vmv1beta1.VMScrapeDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: "low", Namespace: "monitoring"},
+				Spec: vmv1beta1.VMScrapeDefaultsSpec{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+					EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+						ScrapeTimeout: "20s",
+						Path:          "/metrics",
+					},
+				},
+			},
+			&vmv1beta1.VMScrapeDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: "high", Namespace: "default"},
+				Spec: vmv1beta1.VMScrapeDefaultsSpec{
+					Priority: 10,
+					EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+						ScrapeTimeout: "5s",
+					},
+				},
+			},
+			&vmv1beta1.VMScrapeDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-team", Namespace: "monitoring"},
+				Spec: vmv1beta1.VMScrapeDefaultsSpec{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "b"}},
+					EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+						Scheme: "https",
+					},
+				},
+			},
+		),
+		sos: newServiceScrape(vmv1beta1.Endpoint{Port: "http"}),
+		wantEndpoints: []vmv1beta1.Endpoint{
+			{
+				Port: "http",
+				EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+					ScrapeTimeout: "5s",
+					Path:          "/metrics",
+				},
+			},
+		},
+		wantApplied: []vmv1beta1.AppliedScrapeDefaults{
+			{Name: "default/high", Fields: []string{"spec.endpoints[0].scrapeTimeout"}},
+			{Name: "monitoring/low", Fields: []string{"spec.endpoints[0].path"}},
+		},
+	})
+
+	// cluster defaults without namespaceSelector are applied to any namespace
+	// and merged with namespaced defaults by priority
+	f(opts{
+		predefinedObjects: append(namespaces,
+			&vmv1beta1.VMClusterScrapeDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: vmv1beta1.VMScrapeDefaultsSpec{
+					EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+						ScrapeTimeout: "20s",
+						Path:          "/metrics",
+					},
+				},
+			},
+			&vmv1beta1.VMClusterScrapeDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-team"},
+				Spec: vmv1beta1.VMScrapeDefaultsSpec{
+					Priority:          20,
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "b"}},
+					EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+						Scheme: "https",
+					},
+				},
+			},
+			&vmv1beta1.VMScrapeDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: "high", Namespace: "default"},
+				Spec: vmv1beta1.VMScrapeDefaultsSpec{
+					Priority: 10,
+					EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+						ScrapeTimeout: "5s",
+					},
+				},
+			},
+		),
+		sos: newServiceScrape(vmv1beta1.Endpoint{Port: "http"}),
+		wantEndpoints: []vmv1beta1.Endpoint{
+			{
+				Port: "http",
+				EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+					ScrapeTimeout: "5s",
+					Path:          "/metrics",
+				},
+			},
+		},
+		wantApplied: []vmv1beta1.AppliedScrapeDefaults{
+			{Name: "default/high", Fields: []string{"spec.endpoints[0].scrapeTimeout"}},
+			{Name: "cluster", Fields: []string{"spec.endpoints[0].path"}},
+		},
+	})
+
+	// invalid namespaceSelector of cluster defaults
+	f(opts{
+		predefinedObjects: append(namespaces,
+			&vmv1beta1.VMClusterScrapeDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: "broken"},
+				Spec: vmv1beta1.VMScrapeDefaultsSpec{
+					NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "team", Operator: "unknown"},
+					}},
+					EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+						ScrapeTimeout: "5s",
+					},
+				},
+			},
+		),
+		sos:           newServiceScrape(vmv1beta1.Endpoint{Port: "http"}),
+		wantEndpoints: []vmv1beta1.Endpoint{{Port: "http"}},
+		wantBroken:    1,
+	})
+
+	// invalid namespaceSelector
+	f(opts{
+		predefinedObjects: append(namespaces,
+			&vmv1beta1.VMScrapeDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "default"},
+				Spec: vmv1beta1.VMScrapeDefaultsSpec{
+					NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "team", Operator: "unknown"},
+					}},
+					EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+						ScrapeTimeout: "5s",
+					},
+				},
+			},
+		),
+		sos:           newServiceScrape(vmv1beta1.Endpoint{Port: "http"}),
+		wantEndpoints: []vmv1beta1.Endpoint{{Port: "http"}},
+		wantBroken:    1,
+	})
+}
+
+func TestApplyScrapeDefaultsToProbeAndScrapeConfig(t *testing.T) {
+	ctx := context.Background()
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{
+		&vmv1beta1.VMScrapeDefaults{
+			ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "default"},
+			Spec: vmv1beta1.VMScrapeDefaultsSpec{
+				EndpointScrapeParams: vmv1beta1.EndpointScrapeParams{
+					ScrapeTimeout: "10s",
+					Path:          "/metrics",
+					Scheme:        "https",
+					Params:        map[string][]string{"format": {"prometheus"}},
+				},
+				EndpointRelabelings: vmv1beta1.EndpointRelabelings{
+					RelabelConfigs:       []*vmv1beta1.RelabelConfig{{TargetLabel: "team", Replacement: ptr.To("a")}},
+					MetricRelabelConfigs: []*vmv1beta1.RelabelConfig{{Action: "drop", SourceLabels: []string{"debug"}}},
+				},
+			},
+		},
+	})
+	sds, err := selectScrapeDefaults(ctx, fclient)
+	assert.NoError(t, err)
+	probe := &vmv1beta1.VMProbe{
+		ObjectMeta: metav1.ObjectMeta{Name: "probe", Namespace: "default"},
+		Spec: vmv1beta1.VMProbeSpec{
+			VMProberSpec: vmv1beta1.VMProberSpec{URL: "blackbox-exporter:9115"},
+		},
+	}
+	scrapeConfig := &vmv1beta1.VMScrapeConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+		Spec: vmv1beta1.VMScrapeConfigSpec{
+			StaticConfigs: []vmv1beta1.StaticConfig{{Targets: []string{"host:9100"}}},
+		},
+	}
+	sos := &scrapeObjects{
+		prss: []*vmv1beta1.VMProbe{probe},
+		scss: []*vmv1beta1.VMScrapeConfig{scrapeConfig},
+		sds:  sds,
+	}
+	assert.NoError(t, sos.applyScrapeDefaults(ctx, fclient))
+
+	// path, scheme, params and relabelConfigs aren't applied to probe
+	assert.Equal(t, vmv1beta1.EndpointScrapeParams{ScrapeTimeout: "10s"}, probe.Spec.EndpointScrapeParams)
+	assert.Len(t, probe.Spec.MetricRelabelConfigs, 1)
+	assert.Equal(t, []vmv1beta1.AppliedScrapeDefaults{
+		{Name: "default/defaults", Fields: []string{"spec.metricRelabelConfigs", "spec.scrapeTimeout"}},
+	}, probe.Status.AppliedDefaults)
+
+	assert.Equal(t, vmv1beta1.EndpointScrapeParams{
+		ScrapeTimeout: "10s",
+		Path:          "/metrics",
+		Scheme:        "https",
+		Params:        map[string][]string{"format": {"prometheus"}},
+	}, scrapeConfig.Spec.EndpointScrapeParams)
+	assert.Len(t, scrapeConfig.Spec.RelabelConfigs, 1)
+	assert.Len(t, scrapeConfig.Spec.MetricRelabelConfigs, 1)
+	assert.Equal(t, []vmv1beta1.AppliedScrapeDefaults{
+		{
+			Name: "default/defaults",
+			Fields: []string{
+				"spec.relabelConfigs",
+				"spec.metricRelabelConfigs",
+				"spec.path",
+				"spec.scheme",
+				"spec.params",
+				"spec.scrapeTimeout",
+			},
+		},
+	}, scrapeConfig.Status.AppliedDefaults)
+}
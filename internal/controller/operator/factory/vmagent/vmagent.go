@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"iter"
 	"path"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -97,6 +98,16 @@ func CreateOrUpdateVMAgent(ctx context.Context, cr *vmv1beta1.VMAgent, rclient c
 		prevCR = cr.DeepCopy()
 		prevCR.Spec = *cr.ParsedLastAppliedSpec
 	}
+	origin := cr
+	cr, err := resolveRemoteWriteTargetRefs(ctx, rclient, cr)
+	if err != nil {
+		return err
+	}
+	// status is changed by config canary during reconcile
+	// it must be propagated to the given object, since the caller updates status from it
+	defer func() {
+		origin.Status = cr.Status
+	}()
 	if err := deletePrevStateResources(ctx, rclient, cr, prevCR); err != nil {
 		return fmt.Errorf("cannot delete objects from prev state: %w", err)
 	}
@@ -270,9 +281,15 @@ func createOrUpdateShardedDeploy(ctx context.Context, rclient client.Client, cr,
 		shardedDeploy := newDeploy.DeepCopyObject()
 		var prevShardedObject runtime.Object
 		addShardSettingsToVMAgent(shardNum, shardsCount, shardedDeploy)
+		if isConfigCanaryShard(cr, shardNum) {
+			addConfigCanarySettingsToVMAgent(cr, shardedDeploy)
+		}
 		if prevDeploy != nil {
 			prevShardedObject = prevDeploy.DeepCopyObject()
 			addShardSettingsToVMAgent(shardNum, shardsCount, prevShardedObject)
+			if isConfigCanaryShard(prevCR, shardNum) {
+				addConfigCanarySettingsToVMAgent(prevCR, prevShardedObject)
+			}
 		}
 		placeholders := map[string]string{shardNumPlaceholder: strconv.Itoa(shardNum)}
 		switch shardedDeploy := shardedDeploy.(type) {
@@ -1188,6 +1205,35 @@ func sortMap(m map[string]string) []item {
 	return kv
 }
 
+// resolveRemoteWriteTargetRefs returns a copy of given VMAgent with url, credentials and tls config set for remoteWrite defined with targetRef
+//
+// given VMAgent is returned as is if there are no targetRefs
+func resolveRemoteWriteTargetRefs(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAgent) (*vmv1beta1.VMAgent, error) {
+	if !slices.ContainsFunc(cr.Spec.RemoteWrite, func(rw vmv1beta1.VMAgentRemoteWriteSpec) bool { return rw.TargetRef != nil }) {
+		return cr, nil
+	}
+	cr = cr.DeepCopy()
+	for i := range cr.Spec.RemoteWrite {
+		rw := &cr.Spec.RemoteWrite[i]
+		if rw.TargetRef == nil {
+			continue
+		}
+		rt, err := build.ResolveRemoteTargetRef(ctx, rclient, rw.TargetRef, cr.Namespace, true)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve targetRef for remoteWrite at idx=%d: %w", i, err)
+		}
+		rw.URL = rt.URL + "/api/v1/write"
+		if rw.BasicAuth == nil && rw.BearerTokenSecret == nil && rw.OAuth2 == nil {
+			rw.BasicAuth = rt.BasicAuth
+			rw.BearerTokenSecret = rt.BearerTokenSecret
+		}
+		if rw.TLSConfig, err = rt.TLSConfigFor(rw.TLSConfig); err != nil {
+			return nil, fmt.Errorf("cannot resolve targetRef for remoteWrite at idx=%d: %w", i, err)
+		}
+	}
+	return cr, nil
+}
+
 func buildRemoteWrites(cr *vmv1beta1.VMAgent, ssCache *scrapesSecretsCache) []string {
 	var finalArgs []string
 	var remoteArgs []remoteFlag
@@ -1632,6 +1678,15 @@ func deletePrevStateResources(ctx context.Context, rclient client.Client, cr, pr
 		}
 	}
 
+	if prevCR.IsConfigCanaryEnabled() && !cr.IsConfigCanaryEnabled() {
+		if err := finalize.SafeDeleteWithFinalizer(ctx, rclient, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: cr.CanaryConfigSecretName(), Namespace: cr.Namespace}}); err != nil {
+			return fmt.Errorf("cannot remove canary config secret: %w", err)
+		}
+		if err := removeStaleConfigParts(ctx, rclient, cr.Namespace, cr.CanaryConfigSecretName(), 0); err != nil {
+			return err
+		}
+	}
+
 	if ptr.Deref(cr.Spec.DisableSelfServiceScrape, false) && !ptr.Deref(cr.ParsedLastAppliedSpec.DisableSelfServiceScrape, false) {
 		if err := finalize.SafeDeleteWithFinalizer(ctx, rclient, &vmv1beta1.VMServiceScrape{ObjectMeta: objMeta}); err != nil {
 			return fmt.Errorf("cannot remove serviceScrape: %w", err)
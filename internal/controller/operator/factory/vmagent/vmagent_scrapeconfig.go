@@ -6,11 +6,13 @@ import (
 	"context"
 	stderrors "errors"
 	"fmt"
+	"maps"
 	"path"
 	"reflect"
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/VictoriaMetrics/metricsql"
 	"github.com/prometheus/client_golang/prometheus"
@@ -19,7 +21,6 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/reconcile"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/tracing"
 )
 
 var vmagentSecretFetchErrsTotal prometheus.Counter
@@ -50,6 +52,29 @@ type scrapesSecretsCache struct {
 	tlsAssets            map[string]string
 }
 
+func newScrapesSecretsCache() *scrapesSecretsCache {
+	return &scrapesSecretsCache{
+		baSecrets:            map[string]*k8stools.BasicAuthCredentials{},
+		oauth2Secrets:        map[string]*k8stools.OAuthCreds{},
+		bearerTokens:         map[string]string{},
+		authorizationSecrets: map[string]string{},
+		nsSecretCache:        map[string]*corev1.Secret{},
+		nsCMCache:            map[string]*corev1.ConfigMap{},
+		tlsAssets:            map[string]string{},
+	}
+}
+
+// merge copies all values from src
+func (ssc *scrapesSecretsCache) merge(src *scrapesSecretsCache) {
+	maps.Copy(ssc.baSecrets, src.baSecrets)
+	maps.Copy(ssc.oauth2Secrets, src.oauth2Secrets)
+	maps.Copy(ssc.bearerTokens, src.bearerTokens)
+	maps.Copy(ssc.authorizationSecrets, src.authorizationSecrets)
+	maps.Copy(ssc.nsSecretCache, src.nsSecretCache)
+	maps.Copy(ssc.nsCMCache, src.nsCMCache)
+	maps.Copy(ssc.tlsAssets, src.tlsAssets)
+}
+
 type scrapeObjects struct {
 	sss              []*vmv1beta1.VMServiceScrape
 	pss              []*vmv1beta1.VMPodScrape
@@ -57,13 +82,19 @@ type scrapeObjects struct {
 	nss              []*vmv1beta1.VMNodeScrape
 	prss             []*vmv1beta1.VMProbe
 	scss             []*vmv1beta1.VMScrapeConfig
+	sds              []*vmv1beta1.VMScrapeDefaults
+	csds             []*vmv1beta1.VMClusterScrapeDefaults
 	sssBroken        []*vmv1beta1.VMServiceScrape
 	pssBroken        []*vmv1beta1.VMPodScrape
 	stssBroken       []*vmv1beta1.VMStaticScrape
 	nssBroken        []*vmv1beta1.VMNodeScrape
 	prssBroken       []*vmv1beta1.VMProbe
 	scssBroken       []*vmv1beta1.VMScrapeConfig
+	sdsBroken        []*vmv1beta1.VMScrapeDefaults
+	csdsBroken       []*vmv1beta1.VMClusterScrapeDefaults
 	totalBrokenCount int
+	// cache is optional, it allows to skip secrets loading and config generation for unchanged objects
+	cache *agentConfigCache
 }
 
 var skipAnyError = func(_ error) bool {
@@ -167,11 +198,22 @@ func (so *scrapeObjects) mustValidateObjects(vmagentCR *vmv1beta1.VMAgent) {
 
 // CreateOrUpdateConfigurationSecret builds scrape configuration for VMAgent
 func CreateOrUpdateConfigurationSecret(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAgent, childObject client.Object) error {
+	ctx, span := tracing.Start(ctx, "vmagent.CreateOrUpdateConfigurationSecret")
+	defer span.End()
+
 	var prevCR *vmv1beta1.VMAgent
 	if cr.ParsedLastAppliedSpec != nil {
 		prevCR = cr.DeepCopy()
 		prevCR.Spec = *cr.ParsedLastAppliedSpec
 	}
+	origin := cr
+	cr, err := resolveRemoteWriteTargetRefs(ctx, rclient, cr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		origin.Status = cr.Status
+	}()
 	if _, err := createOrUpdateConfigurationSecret(ctx, rclient, cr, prevCR, childObject); err != nil {
 		return err
 	}
@@ -182,6 +224,9 @@ func createOrUpdateConfigurationSecret(ctx context.Context, rclient client.Clien
 	if cr.Spec.IngestOnlyMode {
 		return nil, nil
 	}
+	startedAt := time.Now()
+	defer observeConfigGenerationStage("total", startedAt)
+
 	sss, err := selectServiceScrapes(ctx, cr, rclient)
 	if err != nil {
 		return nil, fmt.Errorf("selecting ServiceScrapes failed: %w", err)
@@ -211,6 +256,15 @@ func createOrUpdateConfigurationSecret(ctx context.Context, rclient client.Clien
 	if err != nil {
 		return nil, fmt.Errorf("selecting ScrapeConfigs failed: %w", err)
 	}
+
+	scrapeDefaults, err := selectScrapeDefaults(ctx, rclient)
+	if err != nil {
+		return nil, fmt.Errorf("selecting ScrapeDefaults failed: %w", err)
+	}
+	clusterScrapeDefaults, err := selectClusterScrapeDefaults(ctx, rclient)
+	if err != nil {
+		return nil, fmt.Errorf("selecting ClusterScrapeDefaults failed: %w", err)
+	}
 	sos := &scrapeObjects{
 		sss:  sss,
 		pss:  pScrapes,
@@ -218,13 +272,30 @@ func createOrUpdateConfigurationSecret(ctx context.Context, rclient client.Clien
 		nss:  nodes,
 		stss: statics,
 		scss: scrapeConfigs,
+		sds:  scrapeDefaults,
+		csds: clusterScrapeDefaults,
+	}
+	if err := sos.applyScrapeDefaults(ctx, rclient); err != nil {
+		return nil, err
 	}
 	sos.mustValidateObjects(cr)
+	observeConfigGenerationStage("select_objects", startedAt)
+
+	sos.cache, err = scrapeConfigCache.acquire(cr, sos.scrapeDefaultsGenerations())
+	if err != nil {
+		return nil, err
+	}
+	var isGenerated bool
+	defer func() {
+		sos.cache.release(isGenerated)
+	}()
 
+	loadStartedAt := time.Now()
 	ssCache, err := loadScrapeSecrets(ctx, rclient, sos, cr.Namespace, cr.Spec.APIServerConfig, cr.Spec.RemoteWrite)
 	if err != nil {
 		return nil, fmt.Errorf("cannot load scrape target secrets: %w", err)
 	}
+	observeConfigGenerationStage("load_secrets", loadStartedAt)
 
 	if err := createOrUpdateTLSAssets(ctx, rclient, cr, prevCR, ssCache.tlsAssets); err != nil {
 		return nil, fmt.Errorf("cannot create tls assets secret for vmagent: %w", err)
@@ -236,6 +307,7 @@ func createOrUpdateConfigurationSecret(ctx context.Context, rclient client.Clien
 	}
 
 	// Update secret based on the most recent configuration.
+	generateStartedAt := time.Now()
 	generatedConfig, err := generateConfig(
 		ctx,
 		cr,
@@ -246,24 +318,23 @@ func createOrUpdateConfigurationSecret(ctx context.Context, rclient client.Clien
 	if err != nil {
 		return nil, fmt.Errorf("generating config for vmagent failed: %w", err)
 	}
+	isGenerated = true
+	observeConfigGenerationStage("generate", generateStartedAt)
 
 	s := makeConfigSecret(cr, ssCache)
 	s.Annotations = map[string]string{
 		"generated": "true",
 	}
 
-	// Compress config to avoid 1mb secret limit for a while
-	var buf bytes.Buffer
-	if err = gzipConfig(&buf, generatedConfig); err != nil {
-		return nil, fmt.Errorf("cannot gzip config for vmagent: %w", err)
+	// Compress config to avoid 1mb secret limit
+	// and split it into multiple secrets if it's still too large
+	vmaCfg, err := buildVMAgentConfig(cr, generatedConfig)
+	if err != nil {
+		return nil, err
 	}
-	s.Data[vmagentGzippedFilename] = buf.Bytes()
+	s.Data[vmagentGzippedFilename] = vmaCfg.main
 
-	var prevSecretMeta *metav1.ObjectMeta
-	if prevCR != nil {
-		prevSecretMeta = ptr.To(buildConfigMeta(prevCR))
-	}
-	if err := reconcile.Secret(ctx, rclient, s, prevSecretMeta); err != nil {
+	if err := reconcileConfigSecret(ctx, rclient, cr, prevCR, s, vmaCfg.parts); err != nil {
 		return nil, fmt.Errorf("cannot reconcile vmagent config secret: %w", err)
 	}
 	if err := updateStatusesForScrapeObjects(ctx, rclient, cr, sos, childObject); err != nil {
@@ -349,6 +420,28 @@ func updateStatusesForScrapeObjects(ctx context.Context, rclient client.Client,
 					return reconcile.StatusForChildObjects(ctx, rclient, parentObject, []*vmv1beta1.VMServiceScrape{o})
 				}
 			}
+		case *vmv1beta1.VMScrapeDefaults:
+			for _, o := range sos.sds {
+				if o.Name == t.Name && o.Namespace == t.Namespace {
+					return reconcile.StatusForChildObjects(ctx, rclient, parentObject, []*vmv1beta1.VMScrapeDefaults{o})
+				}
+			}
+			for _, o := range sos.sdsBroken {
+				if o.Name == t.Name && o.Namespace == t.Namespace {
+					return reconcile.StatusForChildObjects(ctx, rclient, parentObject, []*vmv1beta1.VMScrapeDefaults{o})
+				}
+			}
+		case *vmv1beta1.VMClusterScrapeDefaults:
+			for _, o := range sos.csds {
+				if o.Name == t.Name {
+					return reconcile.StatusForChildObjects(ctx, rclient, parentObject, []*vmv1beta1.VMClusterScrapeDefaults{o})
+				}
+			}
+			for _, o := range sos.csdsBroken {
+				if o.Name == t.Name {
+					return reconcile.StatusForChildObjects(ctx, rclient, parentObject, []*vmv1beta1.VMClusterScrapeDefaults{o})
+				}
+			}
 		}
 	}
 	if err := reconcile.StatusForChildObjects(ctx, rclient, parentObject, sos.sss); err != nil {
@@ -387,6 +480,18 @@ func updateStatusesForScrapeObjects(ctx context.Context, rclient client.Client,
 	if err := reconcile.StatusForChildObjects(ctx, rclient, parentObject, sos.scssBroken); err != nil {
 		return fmt.Errorf("cannot update statuses for broken scrapeconfig scrape objects: %w", err)
 	}
+	if err := reconcile.StatusForChildObjects(ctx, rclient, parentObject, sos.sds); err != nil {
+		return fmt.Errorf("cannot update statuses for scrape defaults objects: %w", err)
+	}
+	if err := reconcile.StatusForChildObjects(ctx, rclient, parentObject, sos.sdsBroken); err != nil {
+		return fmt.Errorf("cannot update statuses for broken scrape defaults objects: %w", err)
+	}
+	if err := reconcile.StatusForChildObjects(ctx, rclient, parentObject, sos.csds); err != nil {
+		return fmt.Errorf("cannot update statuses for cluster scrape defaults objects: %w", err)
+	}
+	if err := reconcile.StatusForChildObjects(ctx, rclient, parentObject, sos.csdsBroken); err != nil {
+		return fmt.Errorf("cannot update statuses for broken cluster scrape defaults objects: %w", err)
+	}
 	return nil
 }
 
@@ -475,17 +580,12 @@ func loadScrapeSecrets(
 	apiserverConfig *vmv1beta1.APIServerConfig,
 	remoteWriteSpecs []vmv1beta1.VMAgentRemoteWriteSpec,
 ) (*scrapesSecretsCache, error) {
-	ssCache := &scrapesSecretsCache{
-		baSecrets:            map[string]*k8stools.BasicAuthCredentials{},
-		oauth2Secrets:        map[string]*k8stools.OAuthCreds{},
-		bearerTokens:         map[string]string{},
-		authorizationSecrets: map[string]string{},
-		nsSecretCache:        map[string]*corev1.Secret{},
-		nsCMCache:            map[string]*corev1.ConfigMap{},
-		tlsAssets:            map[string]string{},
-	}
+	ctx, span := tracing.Start(ctx, "loadScrapeSecrets")
+	defer span.End()
+
+	ssCache := newScrapesSecretsCache()
 	var err error
-	sos.sss, sos.sssBroken, err = forEachCollectSkipOn(sos.sss, sos.sssBroken, func(mon *vmv1beta1.VMServiceScrape) error {
+	sos.sss, sos.sssBroken, err = forEachCollectSkipOn(sos.sss, sos.sssBroken, withSecretsCache(ctx, sos.cache, rclient, ssCache, func(mon *vmv1beta1.VMServiceScrape, rclient client.Client, ssCache *scrapesSecretsCache) error {
 		for i, ep := range mon.Spec.Endpoints {
 			if err := loadSecretsToCacheFrom(ctx, rclient, &ep.EndpointAuth, mon.AsMapKey(i), mon.Namespace, ssCache); err != nil {
 				return err
@@ -506,12 +606,12 @@ func loadScrapeSecrets(
 			}
 		}
 		return nil
-	}, skipOnNotFound)
+	}), skipOnNotFound)
 	if err != nil {
 		return nil, err
 	}
 
-	sos.nss, sos.nssBroken, err = forEachCollectSkipOn(sos.nss, sos.nssBroken, func(node *vmv1beta1.VMNodeScrape) error {
+	sos.nss, sos.nssBroken, err = forEachCollectSkipOn(sos.nss, sos.nssBroken, withSecretsCache(ctx, sos.cache, rclient, ssCache, func(node *vmv1beta1.VMNodeScrape, rclient client.Client, ssCache *scrapesSecretsCache) error {
 		if err := loadSecretsToCacheFrom(ctx, rclient, &node.Spec.EndpointAuth, node.AsMapKey(), node.Namespace, ssCache); err != nil {
 			return err
 		}
@@ -530,12 +630,12 @@ func loadScrapeSecrets(
 		}
 
 		return nil
-	}, skipOnNotFound)
+	}), skipOnNotFound)
 	if err != nil {
 		return nil, err
 	}
 
-	sos.pss, sos.pssBroken, err = forEachCollectSkipOn(sos.pss, sos.pssBroken, func(pod *vmv1beta1.VMPodScrape) error {
+	sos.pss, sos.pssBroken, err = forEachCollectSkipOn(sos.pss, sos.pssBroken, withSecretsCache(ctx, sos.cache, rclient, ssCache, func(pod *vmv1beta1.VMPodScrape, rclient client.Client, ssCache *scrapesSecretsCache) error {
 		for i, ep := range pod.Spec.PodMetricsEndpoints {
 			if err := loadSecretsToCacheFrom(ctx, rclient, &ep.EndpointAuth, pod.AsMapKey(i), pod.Namespace, ssCache); err != nil {
 				return err
@@ -556,12 +656,12 @@ func loadScrapeSecrets(
 		}
 
 		return nil
-	}, skipOnNotFound)
+	}), skipOnNotFound)
 	if err != nil {
 		return nil, err
 	}
 
-	sos.prss, sos.prssBroken, err = forEachCollectSkipOn(sos.prss, sos.prssBroken, func(probe *vmv1beta1.VMProbe) error {
+	sos.prss, sos.prssBroken, err = forEachCollectSkipOn(sos.prss, sos.prssBroken, withSecretsCache(ctx, sos.cache, rclient, ssCache, func(probe *vmv1beta1.VMProbe, rclient client.Client, ssCache *scrapesSecretsCache) error {
 		if err := loadSecretsToCacheFrom(ctx, rclient, &probe.Spec.EndpointAuth, probe.AsMapKey(), probe.Namespace, ssCache); err != nil {
 			return err
 		}
@@ -579,12 +679,12 @@ func loadScrapeSecrets(
 			}
 		}
 		return nil
-	}, skipOnNotFound)
+	}), skipOnNotFound)
 	if err != nil {
 		return nil, err
 	}
 
-	sos.stss, sos.stssBroken, err = forEachCollectSkipOn(sos.stss, sos.stssBroken, func(staticCfg *vmv1beta1.VMStaticScrape) error {
+	sos.stss, sos.stssBroken, err = forEachCollectSkipOn(sos.stss, sos.stssBroken, withSecretsCache(ctx, sos.cache, rclient, ssCache, func(staticCfg *vmv1beta1.VMStaticScrape, rclient client.Client, ssCache *scrapesSecretsCache) error {
 		for i, ep := range staticCfg.Spec.TargetEndpoints {
 			if err := loadSecretsToCacheFrom(ctx, rclient, &ep.EndpointAuth, staticCfg.AsMapKey(i), staticCfg.Namespace, ssCache); err != nil {
 				return err
@@ -605,12 +705,12 @@ func loadScrapeSecrets(
 			}
 		}
 		return nil
-	}, skipOnNotFound)
+	}), skipOnNotFound)
 	if err != nil {
 		return nil, err
 	}
 
-	sos.scss, sos.scssBroken, err = forEachCollectSkipOn(sos.scss, sos.scssBroken, func(scrapeConfig *vmv1beta1.VMScrapeConfig) error {
+	sos.scss, sos.scssBroken, err = forEachCollectSkipOn(sos.scss, sos.scssBroken, withSecretsCache(ctx, sos.cache, rclient, ssCache, func(scrapeConfig *vmv1beta1.VMScrapeConfig, rclient client.Client, ssCache *scrapesSecretsCache) error {
 		if err := loadSecretsToCacheFrom(ctx, rclient, &scrapeConfig.Spec.EndpointAuth, scrapeConfig.AsMapKey("", 0), scrapeConfig.Namespace, ssCache); err != nil {
 			return err
 		}
@@ -795,7 +895,7 @@ func loadScrapeSecrets(
 		}
 
 		return nil
-	}, skipOnNotFound)
+	}), skipOnNotFound)
 	if err != nil {
 		return nil, err
 	}
@@ -1046,83 +1146,107 @@ func generateConfig(
 
 	apiserverConfig := cr.Spec.APIServerConfig
 
+	if err := sos.cache.setSharedSecrets(secretsCache); err != nil {
+		return nil, err
+	}
 	var scrapeConfigs []yaml.MapSlice
-	for _, ss := range sos.sss {
-		for i, ep := range ss.Spec.Endpoints {
-			scrapeConfigs = append(scrapeConfigs,
-				generateServiceScrapeConfig(
+	for pos, ss := range sos.sss {
+		scrapeConfigs = append(scrapeConfigs, sos.cache.scrapeConfigsFor(ss, pos, func() []yaml.MapSlice {
+			var scs []yaml.MapSlice
+			for i, ep := range ss.Spec.Endpoints {
+				scs = append(scs,
+					generateServiceScrapeConfig(
+						ctx,
+						cr,
+						ss,
+						ep, i,
+						apiserverConfig,
+						secretsCache,
+						cr.Spec.VMAgentSecurityEnforcements,
+					))
+			}
+			return scs
+		})...)
+	}
+	for pos, identifier := range sos.pss {
+		scrapeConfigs = append(scrapeConfigs, sos.cache.scrapeConfigsFor(identifier, pos, func() []yaml.MapSlice {
+			var scs []yaml.MapSlice
+			for i, ep := range identifier.Spec.PodMetricsEndpoints {
+				scs = append(scs,
+					generatePodScrapeConfig(
+						ctx,
+						cr,
+						identifier, ep, i,
+						apiserverConfig,
+						secretsCache,
+						cr.Spec.VMAgentSecurityEnforcements,
+					))
+			}
+			return scs
+		})...)
+	}
+
+	for i, identifier := range sos.prss {
+		scrapeConfigs = append(scrapeConfigs, sos.cache.scrapeConfigsFor(identifier, i, func() []yaml.MapSlice {
+			return []yaml.MapSlice{
+				generateProbeConfig(
 					ctx,
 					cr,
-					ss,
-					ep, i,
+					identifier,
+					i,
 					apiserverConfig,
 					secretsCache,
 					cr.Spec.VMAgentSecurityEnforcements,
-				))
-		}
+				),
+			}
+		})...)
 	}
-	for _, identifier := range sos.pss {
-		for i, ep := range identifier.Spec.PodMetricsEndpoints {
-			scrapeConfigs = append(scrapeConfigs,
-				generatePodScrapeConfig(
+	for i, identifier := range sos.nss {
+		scrapeConfigs = append(scrapeConfigs, sos.cache.scrapeConfigsFor(identifier, i, func() []yaml.MapSlice {
+			return []yaml.MapSlice{
+				generateNodeScrapeConfig(
 					ctx,
 					cr,
-					identifier, ep, i,
+					identifier,
+					i,
 					apiserverConfig,
 					secretsCache,
 					cr.Spec.VMAgentSecurityEnforcements,
-				))
-		}
+				),
+			}
+		})...)
 	}
 
-	for i, identifier := range sos.prss {
-		scrapeConfigs = append(scrapeConfigs,
-			generateProbeConfig(
-				ctx,
-				cr,
-				identifier,
-				i,
-				apiserverConfig,
-				secretsCache,
-				cr.Spec.VMAgentSecurityEnforcements,
-			))
+	for pos, identifier := range sos.stss {
+		scrapeConfigs = append(scrapeConfigs, sos.cache.scrapeConfigsFor(identifier, pos, func() []yaml.MapSlice {
+			var scs []yaml.MapSlice
+			for i, ep := range identifier.Spec.TargetEndpoints {
+				scs = append(scs,
+					generateStaticScrapeConfig(
+						ctx,
+						cr,
+						identifier,
+						ep, i,
+						secretsCache,
+						cr.Spec.VMAgentSecurityEnforcements,
+					))
+			}
+			return scs
+		})...)
 	}
-	for i, identifier := range sos.nss {
-		scrapeConfigs = append(scrapeConfigs,
-			generateNodeScrapeConfig(
-				ctx,
-				cr,
-				identifier,
-				i,
-				apiserverConfig,
-				secretsCache,
-				cr.Spec.VMAgentSecurityEnforcements,
-			))
-	}
-
-	for _, identifier := range sos.stss {
-		for i, ep := range identifier.Spec.TargetEndpoints {
-			scrapeConfigs = append(scrapeConfigs,
-				generateStaticScrapeConfig(
+
+	for pos, identifier := range sos.scss {
+		scrapeConfigs = append(scrapeConfigs, sos.cache.scrapeConfigsFor(identifier, pos, func() []yaml.MapSlice {
+			return []yaml.MapSlice{
+				generateScrapeConfig(
 					ctx,
 					cr,
 					identifier,
-					ep, i,
 					secretsCache,
 					cr.Spec.VMAgentSecurityEnforcements,
-				))
-		}
-	}
-
-	for _, identifier := range sos.scss {
-		scrapeConfigs = append(scrapeConfigs,
-			generateScrapeConfig(
-				ctx,
-				cr,
-				identifier,
-				secretsCache,
-				cr.Spec.VMAgentSecurityEnforcements,
-			))
+				),
+			}
+		})...)
 	}
 
 	var additionalScrapeConfigsYaml []yaml.MapSlice
@@ -1710,6 +1710,8 @@ scrape_configs:
 				}()
 			}
 			build.AddDefaults(testClient.Scheme())
+			// test objects have the same names and generations
+			DropConfigCache(tt.args.cr)
 			if _, err := createOrUpdateConfigurationSecret(context.TODO(), testClient, tt.args.cr, nil, nil); (err != nil) != tt.wantErr {
 				t.Errorf("CreateOrUpdateConfigurationSecret() error = %v, wantErr %v", err, tt.wantErr)
 			}
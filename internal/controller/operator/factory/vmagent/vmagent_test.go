@@ -617,6 +617,7 @@ func TestCreateOrUpdateVMAgent(t *testing.T) {
 			errC := make(chan error)
 			build.AddDefaults(fclient.Scheme())
 			fclient.Scheme().Default(tt.args.cr)
+			DropConfigCache(tt.args.cr)
 			go func() {
 				err := CreateOrUpdateVMAgent(context.TODO(), tt.args.cr, fclient)
 				select {
@@ -2681,3 +2682,126 @@ serviceaccountname: vmagent-agent
     `)
 
 }
+
+func TestResolveRemoteWriteTargetRefs(t *testing.T) {
+	f := func(cr *vmv1beta1.VMAgent, predefinedObjects []runtime.Object, want []vmv1beta1.VMAgentRemoteWriteSpec) {
+		t.Helper()
+		fclient := k8stools.GetTestClientWithObjects(predefinedObjects)
+		origin := cr.DeepCopy()
+		got, err := resolveRemoteWriteTargetRefs(context.Background(), fclient, cr)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got.Spec.RemoteWrite)
+		// given object must not be modified
+		assert.Equal(t, origin, cr)
+	}
+	vmuser := &vmv1beta1.VMUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+		Spec:       vmv1beta1.VMUserSpec{BearerToken: ptr.To("token")},
+	}
+	vmauth := &vmv1beta1.VMAuth{ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"}}
+	vmcluster := &vmv1beta1.VMCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"},
+		Spec:       vmv1beta1.VMClusterSpec{VMInsert: &vmv1beta1.VMInsert{}},
+	}
+
+	// url and targetRefs
+	f(&vmv1beta1.VMAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+		Spec: vmv1beta1.VMAgentSpec{
+			RemoteWrite: []vmv1beta1.VMAgentRemoteWriteSpec{
+				{URL: "http://some-url/api/v1/write"},
+				{TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMCluster", Name: "main", Namespace: "monitoring", TenantID: "5"}},
+				{TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMAuth", Name: "main", User: "agent"}},
+			},
+		},
+	}, []runtime.Object{vmuser, vmauth, vmcluster}, []vmv1beta1.VMAgentRemoteWriteSpec{
+		{URL: "http://some-url/api/v1/write"},
+		{
+			URL:       "http://vminsert-main.monitoring.svc:8480/insert/5/prometheus/api/v1/write",
+			TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMCluster", Name: "main", Namespace: "monitoring", TenantID: "5"},
+		},
+		{
+			URL:               "http://vmauth-main.default.svc:8427/api/v1/write",
+			TargetRef:         &vmv1beta1.RemoteTargetRef{Kind: "VMAuth", Name: "main", User: "agent"},
+			BearerTokenSecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "vmuser-agent"}, Key: "bearerToken"},
+		},
+	})
+
+	// user defined auth has priority over vmuser credentials
+	f(&vmv1beta1.VMAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+		Spec: vmv1beta1.VMAgentSpec{
+			RemoteWrite: []vmv1beta1.VMAgentRemoteWriteSpec{
+				{
+					TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMAuth", Name: "main", User: "agent"},
+					BasicAuth: &vmv1beta1.BasicAuth{PasswordFile: "/etc/password"},
+				},
+			},
+		},
+	}, []runtime.Object{vmuser, vmauth}, []vmv1beta1.VMAgentRemoteWriteSpec{
+		{
+			URL:       "http://vmauth-main.default.svc:8427/api/v1/write",
+			TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMAuth", Name: "main", User: "agent"},
+			BasicAuth: &vmv1beta1.BasicAuth{PasswordFile: "/etc/password"},
+		},
+	})
+
+	// tls config of https targets
+	vmsingle := &vmv1beta1.VMSingle{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"},
+		Spec: vmv1beta1.VMSingleSpec{
+			CommonApplicationDeploymentParams: vmv1beta1.CommonApplicationDeploymentParams{
+				Secrets:   []string{"vmsingle-tls"},
+				ExtraArgs: map[string]string{"tls": "true", "tlsCertFile": "/etc/vm/secrets/vmsingle-tls/tls.crt"},
+			},
+		},
+	}
+	tlsCluster := vmcluster.DeepCopy()
+	tlsCluster.Spec.VMInsert.ExtraArgs = map[string]string{"tls": "true"}
+	f(&vmv1beta1.VMAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+		Spec: vmv1beta1.VMAgentSpec{
+			RemoteWrite: []vmv1beta1.VMAgentRemoteWriteSpec{
+				{TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMSingle", Name: "main"}},
+				{
+					TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMCluster", Name: "main", Namespace: "monitoring"},
+					TLSConfig: &vmv1beta1.TLSConfig{CAFile: "/etc/ca.crt"},
+				},
+				{
+					TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMSingle", Name: "main"},
+					TLSConfig: &vmv1beta1.TLSConfig{CAFile: "/etc/ca.crt"},
+				},
+			},
+		},
+	}, []runtime.Object{vmsingle, tlsCluster}, []vmv1beta1.VMAgentRemoteWriteSpec{
+		{
+			URL:       "https://vmsingle-main.default.svc:8429/api/v1/write",
+			TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMSingle", Name: "main"},
+			TLSConfig: &vmv1beta1.TLSConfig{CA: vmv1beta1.SecretOrConfigMap{
+				Secret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "vmsingle-tls"}, Key: "tls.crt"},
+			}},
+		},
+		{
+			URL:       "https://vminsert-main.monitoring.svc:8480/insert/0/prometheus/api/v1/write",
+			TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMCluster", Name: "main", Namespace: "monitoring"},
+			TLSConfig: &vmv1beta1.TLSConfig{CAFile: "/etc/ca.crt"},
+		},
+		{
+			URL:       "https://vmsingle-main.default.svc:8429/api/v1/write",
+			TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMSingle", Name: "main"},
+			TLSConfig: &vmv1beta1.TLSConfig{CAFile: "/etc/ca.crt"},
+		},
+	})
+
+	// https target at another namespace requires tls config
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{tlsCluster})
+	_, err := resolveRemoteWriteTargetRefs(context.Background(), fclient, &vmv1beta1.VMAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+		Spec: vmv1beta1.VMAgentSpec{
+			RemoteWrite: []vmv1beta1.VMAgentRemoteWriteSpec{
+				{TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMCluster", Name: "main", Namespace: "monitoring"}},
+			},
+		},
+	})
+	assert.ErrorContains(t, err, "tlsConfig must be defined explicitly")
+}
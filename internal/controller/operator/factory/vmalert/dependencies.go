@@ -0,0 +1,360 @@
+package vmalert
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/VictoriaMetrics/metricsql"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/build"
+)
+
+const defaultEvaluationInterval = time.Minute
+
+// RulesGraph defines dependencies between rule groups of VMAlert
+// group depends on another group, if it uses series recorded by another group
+type RulesGraph struct {
+	Groups []RuleGroupNode `json:"groups"`
+	Edges  []RuleGroupEdge `json:"edges,omitempty"`
+	// Cycles contains groups, which depend on each other
+	Cycles [][]string `json:"cycles,omitempty"`
+	// MissingInputs contains series, which look like recorded series, but aren't recorded by any group
+	MissingInputs []RuleMissingInput `json:"missingInputs,omitempty"`
+}
+
+// RuleGroupNode defines rule group of VMRule
+type RuleGroupNode struct {
+	// ID has form of namespace/name/group
+	ID       string   `json:"id"`
+	Interval string   `json:"interval"`
+	Records  []string `json:"records,omitempty"`
+	// Level is evaluation order of group, groups at lower levels must be evaluated first
+	// it's set to -1 for groups at cycles or depending on cycles
+	Level int `json:"level"`
+	// EvalOffset is explicitly defined or assigned eval_offset
+	EvalOffset         string `json:"evalOffset,omitempty"`
+	AssignedEvalOffset bool   `json:"assignedEvalOffset,omitempty"`
+
+	interval time.Duration
+}
+
+// RuleGroupEdge defines dependency of group To on series recorded by group From
+type RuleGroupEdge struct {
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Series []string `json:"series"`
+}
+
+// RuleMissingInput defines series used by rule, which isn't recorded by any group
+type RuleMissingInput struct {
+	Group  string `json:"group"`
+	Rule   string `json:"rule"`
+	Series string `json:"series"`
+}
+
+// BuildRulesGraph returns dependencies graph of rule groups selected by the given VMAlert
+func BuildRulesGraph(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlert) (*RulesGraph, error) {
+	vmRules, _, err := selectVMRules(ctx, rclient, cr)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select vmrules: %w", err)
+	}
+	validRules := make([]*vmv1beta1.VMRule, 0, len(vmRules))
+	for _, pRule := range vmRules {
+		if !build.MustSkipRuntimeValidation {
+			if err := pRule.Validate(); err != nil {
+				continue
+			}
+		}
+		validRules = append(validRules, pRule)
+	}
+	enforcedRules, _, err := enforceRulePolicies(ctx, rclient, cr, validRules)
+	if err != nil {
+		return nil, err
+	}
+	g := buildRulesGraph(cr, enforcedRules)
+	if cr.IsAutoEvalOffsetEnabled() {
+		g.evalOffsets()
+	}
+	return g, nil
+}
+
+func ruleGroupID(pRule *vmv1beta1.VMRule, group *vmv1beta1.RuleGroup) string {
+	return fmt.Sprintf("%s/%s/%s", pRule.Namespace, pRule.Name, group.Name)
+}
+
+// ruleInputs returns metric names and metric name regexps used by expression
+func ruleInputs(expr string) ([]string, []*regexp.Regexp) {
+	e, err := metricsql.Parse(expr)
+	if err != nil {
+		// it must be reported by vmalert itself
+		return nil, nil
+	}
+	var names []string
+	var regexps []*regexp.Regexp
+	metricsql.VisitAll(e, func(expr metricsql.Expr) {
+		me, ok := expr.(*metricsql.MetricExpr)
+		if !ok {
+			return
+		}
+		for _, lfs := range me.LabelFilterss {
+			for _, lf := range lfs {
+				if lf.Label != "__name__" || lf.IsNegative {
+					continue
+				}
+				if !lf.IsRegexp {
+					names = append(names, lf.Value)
+					continue
+				}
+				re, err := regexp.Compile("^(?:" + lf.Value + ")$")
+				if err == nil {
+					regexps = append(regexps, re)
+				}
+			}
+		}
+	})
+	return names, regexps
+}
+
+// buildRulesGraph builds dependencies graph of rule groups from the given valid rules
+func buildRulesGraph(cr *vmv1beta1.VMAlert, vmRules []*vmv1beta1.VMRule) *RulesGraph {
+	defaultInterval := defaultEvaluationInterval
+	if cr.Spec.EvaluationInterval != "" {
+		if d, err := parseRuleDuration(cr.Spec.EvaluationInterval); err == nil {
+			defaultInterval = d
+		}
+	}
+	type groupRef struct {
+		rule  *vmv1beta1.VMRule
+		group *vmv1beta1.RuleGroup
+	}
+	var g RulesGraph
+	var refs []groupRef
+	producers := make(map[string][]int)
+	for _, pRule := range vmRules {
+		for gi := range pRule.Spec.Groups {
+			group := &pRule.Spec.Groups[gi]
+			if group.Type != "" && group.Type != "prometheus" {
+				continue
+			}
+			node := RuleGroupNode{
+				ID:         ruleGroupID(pRule, group),
+				EvalOffset: group.EvalOffset,
+				interval:   defaultInterval,
+			}
+			if group.Interval != "" {
+				if d, err := parseRuleDuration(group.Interval); err == nil {
+					node.interval = d
+				}
+			}
+			node.Interval = node.interval.String()
+			idx := len(g.Groups)
+			for _, r := range group.Rules {
+				if r.Record == "" || slices.Contains(node.Records, r.Record) {
+					continue
+				}
+				node.Records = append(node.Records, r.Record)
+				producers[r.Record] = append(producers[r.Record], idx)
+			}
+			g.Groups = append(g.Groups, node)
+			refs = append(refs, groupRef{rule: pRule, group: group})
+		}
+	}
+	recorded := make([]string, 0, len(producers))
+	for name := range producers {
+		recorded = append(recorded, name)
+	}
+	sort.Strings(recorded)
+
+	type edgeKey struct{ from, to int }
+	edges := make(map[edgeKey][]string)
+	addEdges := func(to int, series string) bool {
+		var found bool
+		for _, from := range producers[series] {
+			found = true
+			if from == to {
+				// rules of the same group are evaluated sequentially
+				continue
+			}
+			k := edgeKey{from: from, to: to}
+			if !slices.Contains(edges[k], series) {
+				edges[k] = append(edges[k], series)
+			}
+		}
+		return found
+	}
+	for idx, ref := range refs {
+		for _, r := range ref.group.Rules {
+			names, regexps := ruleInputs(r.Expr)
+			ruleName := r.Record
+			if ruleName == "" {
+				ruleName = r.Alert
+			}
+			for _, name := range names {
+				if !addEdges(idx, name) && strings.Contains(name, ":") {
+					// colons are reserved for recording rules by naming convention
+					g.MissingInputs = append(g.MissingInputs, RuleMissingInput{Group: g.Groups[idx].ID, Rule: ruleName, Series: name})
+				}
+			}
+			for _, re := range regexps {
+				for _, name := range recorded {
+					if re.MatchString(name) {
+						addEdges(idx, name)
+					}
+				}
+			}
+		}
+	}
+
+	adj := make([][]int, len(g.Groups))
+	inDegree := make([]int, len(g.Groups))
+	for k, series := range edges {
+		sort.Strings(series)
+		g.Edges = append(g.Edges, RuleGroupEdge{From: g.Groups[k.from].ID, To: g.Groups[k.to].ID, Series: series})
+		adj[k.from] = append(adj[k.from], k.to)
+		inDegree[k.to]++
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+
+	// assign levels with topological sort
+	// groups, which left unvisited, belong to cycles or depend on them
+	for i := range g.Groups {
+		g.Groups[i].Level = -1
+	}
+	var queue []int
+	for i, d := range inDegree {
+		if d == 0 {
+			queue = append(queue, i)
+			g.Groups[i].Level = 0
+		}
+	}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+		for _, to := range adj[from] {
+			g.Groups[to].Level = max(g.Groups[to].Level, g.Groups[from].Level+1)
+			inDegree[to]--
+			if inDegree[to] == 0 {
+				queue = append(queue, to)
+			}
+		}
+	}
+	g.Cycles = findCycles(g.Groups, adj)
+	return &g
+}
+
+// findCycles returns strongly connected components with more than one group
+func findCycles(groups []RuleGroupNode, adj [][]int) [][]string {
+	var cycles [][]string
+	index := make([]int, len(groups))
+	lowLink := make([]int, len(groups))
+	onStack := make([]bool, len(groups))
+	for i := range index {
+		index[i] = -1
+	}
+	var stack []int
+	var counter int
+	var strongConnect func(v int)
+	strongConnect = func(v int) {
+		index[v] = counter
+		lowLink[v] = counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, w := range adj[v] {
+			switch {
+			case index[w] < 0:
+				strongConnect(w)
+				lowLink[v] = min(lowLink[v], lowLink[w])
+			case onStack[w]:
+				lowLink[v] = min(lowLink[v], index[w])
+			}
+		}
+		if lowLink[v] != index[v] {
+			return
+		}
+		var component []string
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component = append(component, groups[w].ID)
+			if w == v {
+				break
+			}
+		}
+		if len(component) > 1 {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+	for v := range groups {
+		if index[v] < 0 {
+			strongConnect(v)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// evalOffsets assigns eval_offset to dependent groups without explicit eval_offset
+// producers are evaluated at the beginning of interval and consumers are shifted by their level
+//
+// returns map of group ID to eval_offset
+func (g *RulesGraph) evalOffsets() map[string]string {
+	dependent := make(map[string]struct{})
+	for _, e := range g.Edges {
+		dependent[e.From] = struct{}{}
+		dependent[e.To] = struct{}{}
+	}
+	var maxLevel int
+	for _, node := range g.Groups {
+		if _, ok := dependent[node.ID]; ok {
+			maxLevel = max(maxLevel, node.Level)
+		}
+	}
+	offsets := make(map[string]string)
+	for i := range g.Groups {
+		node := &g.Groups[i]
+		if _, ok := dependent[node.ID]; !ok || node.Level < 0 || node.EvalOffset != "" {
+			continue
+		}
+		step := (node.interval / time.Duration(maxLevel+1)).Truncate(time.Second)
+		if node.Level > 0 && step == 0 {
+			continue
+		}
+		node.EvalOffset = (step * time.Duration(node.Level)).String()
+		node.AssignedEvalOffset = true
+		offsets[node.ID] = node.EvalOffset
+	}
+	return offsets
+}
+
+// withEvalOffsets returns spec of the given rule with assigned eval offsets
+func withEvalOffsets(pRule *vmv1beta1.VMRule, offsets map[string]string) vmv1beta1.VMRuleSpec {
+	spec := pRule.Spec
+	var copied bool
+	for gi := range spec.Groups {
+		offset, ok := offsets[ruleGroupID(pRule, &spec.Groups[gi])]
+		if !ok {
+			continue
+		}
+		if !copied {
+			spec = *spec.DeepCopy()
+			copied = true
+		}
+		spec.Groups[gi].EvalOffset = offset
+	}
+	return spec
+}
@@ -0,0 +1,198 @@
+package vmalert
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func newGraphRule(name string, groups ...vmv1beta1.RuleGroup) *vmv1beta1.VMRule {
+	return &vmv1beta1.VMRule{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       vmv1beta1.VMRuleSpec{Groups: groups},
+	}
+}
+
+func TestBuildRulesGraph(t *testing.T) {
+	f := func(vmRules []*vmv1beta1.VMRule, wantEdges []RuleGroupEdge, wantLevels map[string]int, wantCycles [][]string, wantMissing []RuleMissingInput) {
+		t.Helper()
+		g := buildRulesGraph(&vmv1beta1.VMAlert{}, vmRules)
+		assert.Equal(t, wantEdges, g.Edges)
+		assert.Equal(t, wantCycles, g.Cycles)
+		assert.Equal(t, wantMissing, g.MissingInputs)
+		levels := make(map[string]int, len(g.Groups))
+		for _, node := range g.Groups {
+			levels[node.ID] = node.Level
+		}
+		assert.Equal(t, wantLevels, levels)
+	}
+
+	// chain of recording rules
+	f([]*vmv1beta1.VMRule{
+		newGraphRule("alerts", vmv1beta1.RuleGroup{Name: "alerts", Rules: []vmv1beta1.Rule{
+			{Alert: "HighErrors", Expr: `job:errors:ratio5m > 0.1`},
+		}}),
+		newGraphRule("recording",
+			vmv1beta1.RuleGroup{Name: "requests", Rules: []vmv1beta1.Rule{
+				{Record: "job:requests:rate5m", Expr: `sum(rate(http_requests_total[5m])) by (job)`},
+				{Record: "job:errors:rate5m", Expr: `sum(rate(http_requests_total{code=~"5.."}[5m])) by (job)`},
+			}},
+			vmv1beta1.RuleGroup{Name: "ratio", Rules: []vmv1beta1.Rule{
+				{Record: "job:errors:ratio5m", Expr: `job:errors:rate5m / job:requests:rate5m`},
+			}},
+		),
+	}, []RuleGroupEdge{
+		{From: "default/recording/ratio", To: "default/alerts/alerts", Series: []string{"job:errors:ratio5m"}},
+		{From: "default/recording/requests", To: "default/recording/ratio", Series: []string{"job:errors:rate5m", "job:requests:rate5m"}},
+	}, map[string]int{
+		"default/alerts/alerts":      2,
+		"default/recording/requests": 0,
+		"default/recording/ratio":    1,
+	}, nil, nil)
+
+	// regexp selectors, self references and missing inputs
+	f([]*vmv1beta1.VMRule{
+		newGraphRule("recording", vmv1beta1.RuleGroup{Name: "recording", Rules: []vmv1beta1.Rule{
+			{Record: "job:up:sum", Expr: `sum(up) by (job)`},
+			{Record: "job:up:avg", Expr: `job:up:sum / count(up) by (job)`},
+		}}),
+		newGraphRule("alerts", vmv1beta1.RuleGroup{Name: "alerts", Rules: []vmv1beta1.Rule{
+			{Alert: "Down", Expr: `{__name__=~"job:up:.+"} == 0`},
+			{Alert: "Missing", Expr: `absent(job:latency:p99)`},
+			{Alert: "Negative", Expr: `{__name__!~"job:.+", job="a"} == 0`},
+		}}),
+		// non-prometheus groups are ignored
+		newGraphRule("logs", vmv1beta1.RuleGroup{Name: "logs", Type: "vlogs", Rules: []vmv1beta1.Rule{
+			{Record: "logs:errors:count", Expr: `error | stats count()`},
+		}}),
+	}, []RuleGroupEdge{
+		{From: "default/recording/recording", To: "default/alerts/alerts", Series: []string{"job:up:avg", "job:up:sum"}},
+	}, map[string]int{
+		"default/recording/recording": 0,
+		"default/alerts/alerts":       1,
+	}, nil, []RuleMissingInput{
+		{Group: "default/alerts/alerts", Rule: "Missing", Series: "job:latency:p99"},
+	})
+
+	// cycle
+	f([]*vmv1beta1.VMRule{
+		newGraphRule("cycle",
+			vmv1beta1.RuleGroup{Name: "a", Rules: []vmv1beta1.Rule{{Record: "a:metric", Expr: `b:metric`}}},
+			vmv1beta1.RuleGroup{Name: "b", Rules: []vmv1beta1.Rule{{Record: "b:metric", Expr: `a:metric`}}},
+			vmv1beta1.RuleGroup{Name: "c", Rules: []vmv1beta1.Rule{{Alert: "C", Expr: `b:metric > 0`}}},
+		),
+	}, []RuleGroupEdge{
+		{From: "default/cycle/a", To: "default/cycle/b", Series: []string{"a:metric"}},
+		{From: "default/cycle/b", To: "default/cycle/a", Series: []string{"b:metric"}},
+		{From: "default/cycle/b", To: "default/cycle/c", Series: []string{"b:metric"}},
+	}, map[string]int{
+		"default/cycle/a": -1,
+		"default/cycle/b": -1,
+		"default/cycle/c": -1,
+	}, [][]string{{"default/cycle/a", "default/cycle/b"}}, nil)
+}
+
+func TestEvalOffsets(t *testing.T) {
+	f := func(cr *vmv1beta1.VMAlert, vmRules []*vmv1beta1.VMRule, want map[string]string) {
+		t.Helper()
+		got := buildRulesGraph(cr, vmRules).evalOffsets()
+		assert.Equal(t, want, got)
+	}
+	cr := &vmv1beta1.VMAlert{Spec: vmv1beta1.VMAlertSpec{EvaluationInterval: "30s"}}
+	rules := []*vmv1beta1.VMRule{
+		newGraphRule("recording",
+			vmv1beta1.RuleGroup{Name: "requests", Rules: []vmv1beta1.Rule{{Record: "job:requests:rate5m", Expr: `sum(rate(requests_total[5m])) by (job)`}}},
+			vmv1beta1.RuleGroup{Name: "total", Interval: "1m", Rules: []vmv1beta1.Rule{{Record: "requests:rate5m", Expr: `sum(job:requests:rate5m)`}}},
+		),
+		newGraphRule("alerts",
+			vmv1beta1.RuleGroup{Name: "alerts", Rules: []vmv1beta1.Rule{{Alert: "NoRequests", Expr: `requests:rate5m == 0`}}},
+			vmv1beta1.RuleGroup{Name: "explicit", EvalOffset: "5s", Rules: []vmv1beta1.Rule{{Alert: "NoJobRequests", Expr: `job:requests:rate5m == 0`}}},
+			vmv1beta1.RuleGroup{Name: "independent", Rules: []vmv1beta1.Rule{{Alert: "Down", Expr: `up == 0`}}},
+		),
+	}
+	f(cr, rules, map[string]string{
+		"default/recording/requests": "0s",
+		"default/recording/total":    "20s",
+		"default/alerts/alerts":      "20s",
+	})
+
+	// cycles are not ordered
+	f(cr, []*vmv1beta1.VMRule{newGraphRule("cycle",
+		vmv1beta1.RuleGroup{Name: "a", Rules: []vmv1beta1.Rule{{Record: "a:metric", Expr: `b:metric`}}},
+		vmv1beta1.RuleGroup{Name: "b", Rules: []vmv1beta1.Rule{{Record: "b:metric", Expr: `a:metric`}}},
+	)}, map[string]string{})
+}
+
+func TestSelectRulesContentAutoEvalOffset(t *testing.T) {
+	cr := &vmv1beta1.VMAlert{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"},
+		Spec: vmv1beta1.VMAlertSpec{
+			SelectAllByDefault: true,
+			EvaluationInterval: "1m",
+			RuleDependencies:   &vmv1beta1.VMAlertRuleDependencies{AutoEvalOffset: true},
+		},
+	}
+	producer := newGraphRule("producer", vmv1beta1.RuleGroup{Name: "producer", Rules: []vmv1beta1.Rule{
+		{Record: "job:up:sum", Expr: `sum(up) by (job)`},
+	}})
+	consumer := newGraphRule("consumer", vmv1beta1.RuleGroup{Name: "consumer", Rules: []vmv1beta1.Rule{
+		{Alert: "Down", Expr: `job:up:sum == 0`},
+	}})
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{cr, producer, consumer})
+	content, _, _, err := selectRulesContent(context.Background(), fclient, cr)
+	assert.NoError(t, err)
+	assert.Len(t, content, 2)
+	for name, data := range content {
+		switch {
+		case strings.Contains(name, "consumer"):
+			assert.Contains(t, data, "eval_offset: 30s")
+		case strings.Contains(name, "producer"):
+			assert.Contains(t, data, "eval_offset: 0s")
+		}
+	}
+	// source objects must not be modified
+	assert.Empty(t, producer.Spec.Groups[0].EvalOffset)
+
+	g, err := BuildRulesGraph(context.Background(), fclient, cr)
+	assert.NoError(t, err)
+	assert.Len(t, g.Edges, 1)
+	for _, node := range g.Groups {
+		assert.True(t, node.AssignedEvalOffset)
+	}
+}
+
+func TestBuildRulesGraphWithPolicies(t *testing.T) {
+	cr := &vmv1beta1.VMAlert{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"},
+		Spec: vmv1beta1.VMAlertSpec{
+			SelectAllByDefault: true,
+			RulePolicies: []vmv1beta1.VMAlertRulePolicy{
+				{Name: "strict", MinInterval: "1m"},
+			},
+		},
+	}
+	producer := newGraphRule("producer", vmv1beta1.RuleGroup{Name: "producer", Interval: "10s", Rules: []vmv1beta1.Rule{
+		{Record: "job:up:sum", Expr: `sum(up) by (job)`},
+	}})
+	consumer := newGraphRule("consumer", vmv1beta1.RuleGroup{Name: "consumer", Rules: []vmv1beta1.Rule{
+		{Alert: "Down", Expr: `job:up:sum == 0`},
+	}})
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{
+		cr, producer, consumer,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+	})
+	// producer violates policy and isn't loaded by vmalert
+	g, err := BuildRulesGraph(context.Background(), fclient, cr)
+	assert.NoError(t, err)
+	assert.Len(t, g.Groups, 1)
+	assert.Empty(t, g.Edges)
+	assert.Equal(t, []RuleMissingInput{{Group: "default/consumer/consumer", Rule: "Down", Series: "job:up:sum"}}, g.MissingInputs)
+}
@@ -0,0 +1,152 @@
+package vmalert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+)
+
+// rulePoliciesCache caches rule policies matched by namespace labels
+type rulePoliciesCache struct {
+	rclient  client.Client
+	policies []vmv1beta1.VMAlertRulePolicy
+	byNs     map[string][]vmv1beta1.VMAlertRulePolicy
+}
+
+func newRulePoliciesCache(rclient client.Client, policies []vmv1beta1.VMAlertRulePolicy) *rulePoliciesCache {
+	return &rulePoliciesCache{
+		rclient:  rclient,
+		policies: policies,
+		byNs:     make(map[string][]vmv1beta1.VMAlertRulePolicy),
+	}
+}
+
+// get returns policies matching the given namespace in the defined order
+func (rpc *rulePoliciesCache) get(ctx context.Context, namespace string) ([]vmv1beta1.VMAlertRulePolicy, error) {
+	if len(rpc.policies) == 0 {
+		return nil, nil
+	}
+	if policies, ok := rpc.byNs[namespace]; ok {
+		return policies, nil
+	}
+	var ns corev1.Namespace
+	if err := rpc.rclient.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		return nil, fmt.Errorf("cannot get namespace=%q for rule policies matching: %w", namespace, err)
+	}
+	var matched []vmv1beta1.VMAlertRulePolicy
+	for _, policy := range rpc.policies {
+		selector, err := metav1.LabelSelectorAsSelector(policy.NamespaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse namespaceSelector of rule policy=%q: %w", policy.Name, err)
+		}
+		if policy.NamespaceSelector == nil {
+			selector = labels.Everything()
+		}
+		if selector.Matches(labels.Set(ns.Labels)) {
+			matched = append(matched, policy)
+		}
+	}
+	rpc.byNs[namespace] = matched
+	return matched, nil
+}
+
+// enforceRulePolicies returns rules with enforced policies of rule namespaces
+// and origin rules for them at the same positions
+//
+// rules, which violate policies, are skipped and error is set at their status
+func enforceRulePolicies(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlert, vmRules []*vmv1beta1.VMRule) ([]*vmv1beta1.VMRule, []*vmv1beta1.VMRule, error) {
+	if len(cr.Spec.RulePolicies) == 0 {
+		return vmRules, vmRules, nil
+	}
+	nsPolicies := newRulePoliciesCache(rclient, cr.Spec.RulePolicies)
+	enforced := make([]*vmv1beta1.VMRule, 0, len(vmRules))
+	origins := make([]*vmv1beta1.VMRule, 0, len(vmRules))
+	for _, pRule := range vmRules {
+		policies, err := nsPolicies.get(ctx, pRule.Namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		spec, err := enforceRuleSpec(pRule.Spec, policies)
+		if err != nil {
+			pRule.Status.CurrentSyncError = fmt.Sprintf("cannot apply rule policies to rule: %s, err :%s", pRule.Name, err)
+			continue
+		}
+		enforced = append(enforced, &vmv1beta1.VMRule{ObjectMeta: pRule.ObjectMeta, Spec: spec})
+		origins = append(origins, pRule)
+	}
+	return enforced, origins, nil
+}
+
+// enforceRuleSpec returns copy of the given rule spec with applied policies
+func enforceRuleSpec(promRule vmv1beta1.VMRuleSpec, policies []vmv1beta1.VMAlertRulePolicy) (vmv1beta1.VMRuleSpec, error) {
+	if len(policies) == 0 {
+		return promRule, nil
+	}
+	// groups are shared with the origin object
+	promRule = *promRule.DeepCopy()
+	for _, policy := range policies {
+		if err := applyRulePolicy(&promRule, policy); err != nil {
+			return promRule, err
+		}
+	}
+	return promRule, nil
+}
+
+// applyRulePolicy enforces policy settings at groups of the given rule
+// it returns error if rule violates policy restrictions
+func applyRulePolicy(promRule *vmv1beta1.VMRuleSpec, policy vmv1beta1.VMAlertRulePolicy) error {
+	var minInterval time.Duration
+	if policy.MinInterval != "" {
+		d, err := time.ParseDuration(policy.MinInterval)
+		if err != nil {
+			return fmt.Errorf("cannot parse minInterval=%q of rule policy=%q: %w", policy.MinInterval, policy.Name, err)
+		}
+		minInterval = d
+	}
+	for gi := range promRule.Groups {
+		group := &promRule.Groups[gi]
+		if minInterval > 0 && group.Interval != "" {
+			interval, err := parseRuleDuration(group.Interval)
+			if err != nil {
+				return fmt.Errorf("cannot parse interval=%q of group=%q: %w", group.Interval, group.Name, err)
+			}
+			if interval < minInterval {
+				return fmt.Errorf("group=%q interval=%s is lower than minInterval=%s of rule policy=%q", group.Name, group.Interval, policy.MinInterval, policy.Name)
+			}
+		}
+		if policy.MaxConcurrency > 0 && group.Concurrency > policy.MaxConcurrency {
+			return fmt.Errorf("group=%q concurrency=%d is higher than maxConcurrency=%d of rule policy=%q", group.Name, group.Concurrency, policy.MaxConcurrency, policy.Name)
+		}
+		if policy.Tenant != "" {
+			group.Tenant = policy.Tenant
+		}
+		if len(policy.ExtraFilterLabels) > 0 {
+			if group.ExtraFilterLabels == nil {
+				group.ExtraFilterLabels = make(map[string]string, len(policy.ExtraFilterLabels))
+			}
+			for k, v := range policy.ExtraFilterLabels {
+				group.ExtraFilterLabels[k] = v
+			}
+		}
+		if len(policy.Labels) > 0 {
+			for ri := range group.Rules {
+				rule := &group.Rules[ri]
+				if rule.Labels == nil {
+					rule.Labels = make(map[string]string, len(policy.Labels))
+				}
+				for k, v := range policy.Labels {
+					rule.Labels[k] = v
+				}
+			}
+		}
+	}
+	return nil
+}
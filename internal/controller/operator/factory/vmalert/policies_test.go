@@ -0,0 +1,132 @@
+package vmalert
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func TestGenerateContentWithPolicies(t *testing.T) {
+	f := func(spec vmv1beta1.VMRuleSpec, policies []vmv1beta1.VMAlertRulePolicy, want, wantErr string) {
+		t.Helper()
+		origin := spec.DeepCopy()
+		enforced, err := enforceRuleSpec(spec, policies)
+		if wantErr != "" {
+			assert.EqualError(t, err, wantErr)
+			return
+		}
+		assert.NoError(t, err)
+		got, err := generateContent(enforced, "", "team-a")
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+		// origin object must not be changed
+		assert.Equal(t, origin, &spec)
+	}
+	spec := vmv1beta1.VMRuleSpec{Groups: []vmv1beta1.RuleGroup{{
+		Name:              "group",
+		Interval:          "1m",
+		Concurrency:       2,
+		Tenant:            "5",
+		ExtraFilterLabels: map[string]string{"job": "app"},
+		Rules: []vmv1beta1.Rule{
+			{Alert: "down", Expr: "up == 0", Labels: map[string]string{"team": "b", "severity": "critical"}},
+		},
+	}}}
+
+	// without policies
+	f(spec, nil, `groups:
+- concurrency: 2
+  extra_filter_labels:
+    job: app
+  interval: 1m
+  name: group
+  rules:
+  - alert: down
+    expr: up == 0
+    labels:
+      severity: critical
+      team: b
+  tenant: "5"
+`, "")
+
+	// policies are applied in order
+	f(spec, []vmv1beta1.VMAlertRulePolicy{
+		{Name: "tenant", Tenant: "1:0", ExtraFilterLabels: map[string]string{"namespace": "other"}, Labels: map[string]string{"team": "other"}},
+		{Name: "team-a", ExtraFilterLabels: map[string]string{"namespace": "team-a"}, Labels: map[string]string{"team": "a"}, MinInterval: "30s", MaxConcurrency: 2},
+	}, `groups:
+- concurrency: 2
+  extra_filter_labels:
+    job: app
+    namespace: team-a
+  interval: 1m
+  name: group
+  rules:
+  - alert: down
+    expr: up == 0
+    labels:
+      severity: critical
+      team: a
+  tenant: "1:0"
+`, "")
+
+	// violations
+	f(spec, []vmv1beta1.VMAlertRulePolicy{{Name: "strict", MinInterval: "5m"}}, "",
+		`group="group" interval=1m is lower than minInterval=5m of rule policy="strict"`)
+	f(spec, []vmv1beta1.VMAlertRulePolicy{{Name: "strict", MaxConcurrency: 1}}, "",
+		`group="group" concurrency=2 is higher than maxConcurrency=1 of rule policy="strict"`)
+}
+
+func TestSelectRulesContentWithPolicies(t *testing.T) {
+	cr := &vmv1beta1.VMAlert{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "monitoring"},
+		Spec: vmv1beta1.VMAlertSpec{
+			SelectAllByDefault: true,
+			RulePolicies: []vmv1beta1.VMAlertRulePolicy{
+				{
+					Name:              "teams",
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "team-a"}},
+					Tenant:            "1",
+					MaxConcurrency:    1,
+				},
+			},
+		},
+	}
+	newRule := func(ns string, concurrency int) *vmv1beta1.VMRule {
+		return &vmv1beta1.VMRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "rule", Namespace: ns},
+			Spec: vmv1beta1.VMRuleSpec{Groups: []vmv1beta1.RuleGroup{{
+				Name:        "group",
+				Concurrency: concurrency,
+				Rules:       []vmv1beta1.Rule{{Alert: "down", Expr: "up == 0"}},
+			}}},
+		}
+	}
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{
+		cr,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"tenant": "team-a"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"tenant": "team-a"}}},
+		newRule("monitoring", 2),
+		newRule("team-a", 1),
+		newRule("team-b", 2),
+	})
+	got, rules, _, err := selectRulesContent(context.Background(), fclient, cr)
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.NotContains(t, got["monitoring-rule.yaml"], "tenant")
+	assert.Contains(t, got["team-a-rule.yaml"], `tenant: "1"`)
+	for _, r := range rules {
+		if r.Namespace == "team-b" {
+			assert.Contains(t, r.Status.CurrentSyncError, `concurrency=2 is higher than maxConcurrency=1 of rule policy="teams"`)
+		} else {
+			assert.Empty(t, r.Status.CurrentSyncError)
+		}
+	}
+}
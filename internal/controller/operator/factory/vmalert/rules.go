@@ -6,6 +6,7 @@ import (
 	"hash/fnv"
 	"sort"
 	"strconv"
+	"time"
 
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/build"
@@ -13,6 +14,7 @@ import (
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/reconcile"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/tracing"
 	"github.com/ghodss/yaml"
 	"github.com/prometheus/client_golang/prometheus"
 	corev1 "k8s.io/api/core/v1"
@@ -80,12 +82,7 @@ func reconcileConfigsData(ctx context.Context, rclient client.Client, cr *vmv1be
 
 	if len(currentCMs) == 0 {
 		for _, cm := range newConfigMaps {
-			logger.WithContext(ctx).Info(fmt.Sprintf("creating new ConfigMap %s for rules", cm.Name))
-			err := rclient.Create(ctx, &cm)
-			if err != nil {
-				if errors.IsAlreadyExists(err) {
-					continue
-				}
+			if err := reconcile.ConfigMap(ctx, rclient, &cm, nil); err != nil {
 				return nil, fmt.Errorf("failed to create Configmap: %s, err: %w", cm.Name, err)
 			}
 		}
@@ -100,15 +97,16 @@ func reconcileConfigsData(ctx context.Context, rclient client.Client, cr *vmv1be
 	sort.Slice(newConfigMaps, func(i, j int) bool {
 		return newConfigMaps[i].Name < newConfigMaps[j].Name
 	})
+	newCMByName := make(map[string]*corev1.ConfigMap, len(newConfigMaps))
+	for i := range newConfigMaps {
+		newCMByName[newConfigMaps[i].Name] = newConfigMaps[i].DeepCopy()
+	}
 
 	// compute diff for current and needed rules configmaps.
+	// diff is used to trigger config reload, rendered configmaps are written by reconcile helper
 	toCreate, toUpdate := rulesCMDiff(currentCMs, newConfigMaps)
 	for _, cm := range toCreate {
-		logger.WithContext(ctx).Info(fmt.Sprintf("creating additional configmap=%s for rules", cm.Name))
-		if err := rclient.Create(ctx, &cm); err != nil {
-			if errors.IsAlreadyExists(err) {
-				continue
-			}
+		if err := reconcile.ConfigMap(ctx, rclient, newCMByName[cm.Name], nil); err != nil {
 			return nil, fmt.Errorf("failed to create new rules Configmap: %s, err: %w", cm.Name, err)
 		}
 	}
@@ -116,8 +114,7 @@ func reconcileConfigsData(ctx context.Context, rclient client.Client, cr *vmv1be
 		if err := finalize.FreeIfNeeded(ctx, rclient, &cm); err != nil {
 			return nil, err
 		}
-		logger.WithContext(ctx).Info(fmt.Sprintf("updating ConfigMap %s configuration", cm.Name))
-		if err := rclient.Update(ctx, &cm); err != nil {
+		if err := reconcile.ConfigMap(ctx, rclient, newCMByName[cm.Name], nil); err != nil {
 			return nil, fmt.Errorf("failed to update rules Configmap: %s, err: %w", cm.Name, err)
 		}
 	}
@@ -167,10 +164,13 @@ func rulesCMDiff(currentCMs []corev1.ConfigMap, newCMs []corev1.ConfigMap) (toCr
 }
 
 func reconcileVMAlertConfig(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlert, childCR *vmv1beta1.VMRule) ([]string, error) {
-	rulesData, vmRules, err := selectRulesContent(ctx, rclient, cr)
+	rulesData, vmRules, maxFor, err := selectRulesContent(ctx, rclient, cr)
 	if err != nil {
 		return nil, err
 	}
+	if err := updateStatePersistenceStatus(ctx, rclient, cr, statePersistenceStatusWithLookback(cr, maxFor)); err != nil {
+		return nil, err
+	}
 	// perform config maps content update
 	ruleCMNames, err := reconcileConfigsData(ctx, rclient, cr, rulesData)
 	if err != nil {
@@ -195,7 +195,8 @@ func reconcileVMAlertConfig(ctx context.Context, rclient client.Client, cr *vmv1
 	return ruleCMNames, nil
 }
 
-func selectRulesContent(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlert) (map[string]string, []*vmv1beta1.VMRule, error) {
+// selectVMRules returns VMRules selected by the given VMAlert
+func selectVMRules(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlert) ([]*vmv1beta1.VMRule, []string, error) {
 	var vmRules []*vmv1beta1.VMRule
 	var namespacedNames []string
 	if err := k8stools.VisitObjectsForSelectorsAtNs(ctx, rclient, cr.Spec.RuleNamespaceSelector, cr.Spec.RuleSelector, cr.Namespace, cr.Spec.SelectAllByDefault,
@@ -210,14 +211,28 @@ func selectRulesContent(ctx context.Context, rclient client.Client, cr *vmv1beta
 		}); err != nil {
 		return nil, nil, err
 	}
-
-	rules := make(map[string]string, len(vmRules))
-
 	if cr.NeedDedupRules() {
 		logger.WithContext(ctx).Info("deduplicating vmalert rules")
 		vmRules = deduplicateRules(ctx, vmRules)
 	}
+	return vmRules, namespacedNames, nil
+}
+
+// selectRulesContent returns rule files content, selected rules and the longest `for` of alerting rules
+func selectRulesContent(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlert) (map[string]string, []*vmv1beta1.VMRule, time.Duration, error) {
+	ctx, span := tracing.Start(ctx, "selectRulesContent")
+	defer span.End()
+
+	vmRules, namespacedNames, err := selectVMRules(ctx, rclient, cr)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	rules := make(map[string]string, len(vmRules))
+	var maxFor time.Duration
+
 	var brokenRulesCnt int
+	validRules := make([]*vmv1beta1.VMRule, 0, len(vmRules))
 	for _, pRule := range vmRules {
 		if !build.MustSkipRuntimeValidation {
 			if err := pRule.Validate(); err != nil {
@@ -226,17 +241,31 @@ func selectRulesContent(ctx context.Context, rclient client.Client, cr *vmv1beta
 				continue
 			}
 		}
-		content, err := generateContent(pRule.Spec, cr.Spec.EnforcedNamespaceLabel, pRule.Namespace)
+		validRules = append(validRules, pRule)
+	}
+	enforcedRules, origins, err := enforceRulePolicies(ctx, rclient, cr, validRules)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	brokenRulesCnt += len(validRules) - len(origins)
+	var evalOffsets map[string]string
+	if cr.IsAutoEvalOffsetEnabled() {
+		evalOffsets = buildRulesGraph(cr, enforcedRules).evalOffsets()
+	}
+	for idx, pRule := range origins {
+		enforcedRule := enforcedRules[idx]
+		content, err := generateContent(withEvalOffsets(enforcedRule, evalOffsets), cr.Spec.EnforcedNamespaceLabel, pRule.Namespace)
 		if err != nil {
 			pRule.Status.CurrentSyncError = fmt.Sprintf("cannot generate content for rule: %s, err :%s", pRule.Name, err)
 			brokenRulesCnt++
 			continue
 		}
 		rules[fmt.Sprintf("%s-%s.yaml", pRule.Namespace, pRule.Name)] = content
+		maxFor = max(maxFor, maxAlertingRuleFor(enforcedRule.Spec))
 	}
 	logger.SelectedObjects(ctx, "VMRules", len(namespacedNames), brokenRulesCnt, namespacedNames)
 	badConfigsTotal.Add(float64(brokenRulesCnt))
-	return rules, vmRules, nil
+	return rules, vmRules, maxFor, nil
 }
 
 func generateContent(promRule vmv1beta1.VMRuleSpec, enforcedNsLabel, ns string) (string, error) {
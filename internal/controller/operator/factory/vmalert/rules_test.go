@@ -274,7 +274,7 @@ groups:
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
 			fclient := k8stools.GetTestClientWithObjects(tt.predefinedObjects)
-			got, _, err := selectRulesContent(ctx, fclient, tt.args.p)
+			got, _, _, err := selectRulesContent(ctx, fclient, tt.args.p)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SelectRules() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,130 @@
+package vmalert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+)
+
+// maxAlertingRuleFor returns the longest `for` of alerting rules
+func maxAlertingRuleFor(spec vmv1beta1.VMRuleSpec) time.Duration {
+	var maxFor time.Duration
+	for _, group := range spec.Groups {
+		for _, rule := range group.Rules {
+			if rule.Alert == "" || rule.For == "" {
+				continue
+			}
+			d, err := parseRuleDuration(rule.For)
+			if err != nil {
+				// it must be reported by vmalert itself
+				continue
+			}
+			maxFor = max(maxFor, d)
+		}
+	}
+	return maxFor
+}
+
+// parseRuleDuration parses duration in the same formats as vmalert does
+func parseRuleDuration(s string) (time.Duration, error) {
+	if d, err := model.ParseDuration(s); err == nil {
+		return time.Duration(d), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// statePersistenceStatusWithLookback returns state persistence status with lookback computed from the longest alerting rule `for`
+func statePersistenceStatusWithLookback(cr *vmv1beta1.VMAlert, maxFor time.Duration) *vmv1beta1.VMAlertStatePersistenceStatus {
+	if !cr.IsStatePersistenceEnabled() {
+		return nil
+	}
+	st := &vmv1beta1.VMAlertStatePersistenceStatus{}
+	if cr.Status.StatePersistence != nil {
+		st = cr.Status.StatePersistence.DeepCopy()
+	}
+	st.Lookback = ""
+	if maxFor > 0 {
+		st.Lookback = maxFor.String()
+	}
+	return st
+}
+
+// applyStatePersistence configures remoteWrite and remoteRead with datasource storage
+// it must be called before remote targetRefs resolving
+//
+// returns a warning if alerts state cannot be restored with the given configuration
+// and an error if alerts state cannot be persisted
+func applyStatePersistence(cr *vmv1beta1.VMAlert) (string, error) {
+	if !cr.IsStatePersistenceEnabled() {
+		return "", nil
+	}
+	if err := cr.ValidateStatePersistence(); err != nil {
+		return "", err
+	}
+	var lookback string
+	if cr.Status.StatePersistence != nil {
+		lookback = cr.Status.StatePersistence.Lookback
+	}
+	var warnings []string
+	ds := cr.Spec.Datasource
+	if cr.Spec.RemoteWrite == nil {
+		cr.Spec.RemoteWrite = &vmv1beta1.VMAlertRemoteWriteSpec{
+			URL:       ds.URL,
+			TargetRef: ds.TargetRef.DeepCopy(),
+			HTTPAuth:  *ds.HTTPAuth.DeepCopy(),
+		}
+	}
+	if cr.Spec.RemoteRead == nil {
+		cr.Spec.RemoteRead = &vmv1beta1.VMAlertRemoteReadSpec{
+			URL:       ds.URL,
+			TargetRef: ds.TargetRef.DeepCopy(),
+			HTTPAuth:  *ds.HTTPAuth.DeepCopy(),
+		}
+	}
+	rr := cr.Spec.RemoteRead
+	switch {
+	case rr.Lookback == nil:
+		if lookback != "" {
+			rr.Lookback = &lookback
+		}
+	case lookback != "":
+		want, _ := time.ParseDuration(lookback)
+		got, err := time.ParseDuration(*rr.Lookback)
+		if err == nil && got < want {
+			warnings = append(warnings, fmt.Sprintf("spec.remoteRead.lookback=%s is shorter than the longest `for`=%s of alerting rules, state of such alerts cannot be restored", *rr.Lookback, lookback))
+		}
+	}
+	return strings.Join(warnings, "; "), nil
+}
+
+// updateStatePersistenceStatus patches status with the given alerts state persistence status
+func updateStatePersistenceStatus(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlert, st *vmv1beta1.VMAlertStatePersistenceStatus) error {
+	if equality.Semantic.DeepEqual(cr.Status.StatePersistence, st) {
+		return nil
+	}
+	type patch struct {
+		Status struct {
+			StatePersistence *vmv1beta1.VMAlertStatePersistenceStatus `json:"statePersistence"`
+		} `json:"status"`
+	}
+	var p patch
+	p.Status.StatePersistence = st
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("BUG: cannot marshal state persistence status: %w", err)
+	}
+	if err := rclient.Status().Patch(ctx, cr.DeepCopy(), client.RawPatch(types.MergePatchType, data)); err != nil {
+		return fmt.Errorf("cannot update state persistence status of vmalert=%s/%s: %w", cr.Namespace, cr.Name, err)
+	}
+	cr.Status.StatePersistence = st
+	return nil
+}
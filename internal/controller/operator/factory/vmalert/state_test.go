@@ -0,0 +1,123 @@
+package vmalert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func TestMaxAlertingRuleFor(t *testing.T) {
+	f := func(rules []vmv1beta1.Rule, want time.Duration) {
+		t.Helper()
+		got := maxAlertingRuleFor(vmv1beta1.VMRuleSpec{Groups: []vmv1beta1.RuleGroup{{Name: "group", Rules: rules}}})
+		assert.Equal(t, want, got)
+	}
+	f(nil, 0)
+	// recording rules are ignored
+	f([]vmv1beta1.Rule{{Record: "r", For: "1h"}, {Alert: "a", For: "5m"}}, 5*time.Minute)
+	f([]vmv1beta1.Rule{{Alert: "a", For: "1d"}, {Alert: "b", For: "90m"}, {Alert: "c"}}, 24*time.Hour)
+	// bad duration is ignored
+	f([]vmv1beta1.Rule{{Alert: "a", For: "bad"}, {Alert: "b", For: "1m30s"}}, 90*time.Second)
+}
+
+func TestSelectRulesContentMaxFor(t *testing.T) {
+	cr := &vmv1beta1.VMAlert{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"},
+		Spec:       vmv1beta1.VMAlertSpec{SelectAllByDefault: true},
+	}
+	newRule := func(name, forDuration string) *vmv1beta1.VMRule {
+		return &vmv1beta1.VMRule{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: vmv1beta1.VMRuleSpec{Groups: []vmv1beta1.RuleGroup{{
+				Name:  name,
+				Rules: []vmv1beta1.Rule{{Alert: name, Expr: "up == 0", For: forDuration}},
+			}}},
+		}
+	}
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{cr, newRule("short", "5m"), newRule("long", "2h")})
+	_, _, maxFor, err := selectRulesContent(context.Background(), fclient, cr)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, maxFor)
+}
+
+func TestApplyStatePersistence(t *testing.T) {
+	f := func(spec vmv1beta1.VMAlertSpec, lookback string, wantWrite, wantRead string, wantLookback *string, wantWarning string) {
+		t.Helper()
+		cr := &vmv1beta1.VMAlert{Spec: spec}
+		cr.Spec.StatePersistence = &vmv1beta1.VMAlertStatePersistence{Enabled: true}
+		cr.Status.StatePersistence = &vmv1beta1.VMAlertStatePersistenceStatus{Lookback: lookback}
+		warning, err := applyStatePersistence(cr)
+		assert.NoError(t, err)
+		assert.Equal(t, wantWarning, warning)
+		assert.Equal(t, wantWrite, cr.Spec.RemoteWrite.URL)
+		assert.Equal(t, wantRead, cr.Spec.RemoteRead.URL)
+		assert.Equal(t, wantLookback, cr.Spec.RemoteRead.Lookback)
+	}
+
+	// derived from datasource
+	f(vmv1beta1.VMAlertSpec{
+		Datasource: vmv1beta1.VMAlertDatasourceSpec{URL: "http://vmsingle:8429"},
+	}, "1h0m0s", "http://vmsingle:8429", "http://vmsingle:8429", ptr.To("1h0m0s"), "")
+
+	// explicit remote write is kept
+	f(vmv1beta1.VMAlertSpec{
+		Datasource:  vmv1beta1.VMAlertDatasourceSpec{URL: "http://vmselect:8481/select/0/prometheus"},
+		RemoteWrite: &vmv1beta1.VMAlertRemoteWriteSpec{URL: "http://vminsert:8480/insert/0/prometheus"},
+	}, "", "http://vminsert:8480/insert/0/prometheus", "http://vmselect:8481/select/0/prometheus", nil, "")
+
+	// explicit lookback is shorter than the longest for
+	f(vmv1beta1.VMAlertSpec{
+		Datasource: vmv1beta1.VMAlertDatasourceSpec{URL: "http://vmsingle:8429"},
+		RemoteRead: &vmv1beta1.VMAlertRemoteReadSpec{URL: "http://vmsingle:8429", Lookback: ptr.To("30m")},
+	}, "1h0m0s", "http://vmsingle:8429", "http://vmsingle:8429", ptr.To("30m"),
+		"spec.remoteRead.lookback=30m is shorter than the longest `for`=1h0m0s of alerting rules, state of such alerts cannot be restored")
+}
+
+func TestApplyStatePersistenceVMSelect(t *testing.T) {
+	// vmselect cannot be used for remote write
+	cr := &vmv1beta1.VMAlert{Spec: vmv1beta1.VMAlertSpec{
+		Datasource:       vmv1beta1.VMAlertDatasourceSpec{URL: "http://vmselect:8481/select/0/prometheus"},
+		StatePersistence: &vmv1beta1.VMAlertStatePersistence{Enabled: true},
+	}}
+	_, err := applyStatePersistence(cr)
+	assert.ErrorContains(t, err, `datasource url="http://vmselect:8481/select/0/prometheus" points to vmselect`)
+	assert.Nil(t, cr.Spec.RemoteWrite)
+}
+
+func TestStatePersistenceStatus(t *testing.T) {
+	ctx := context.Background()
+	cr := &vmv1beta1.VMAlert{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"},
+		Spec: vmv1beta1.VMAlertSpec{
+			StatePersistence: &vmv1beta1.VMAlertStatePersistence{Enabled: true},
+		},
+		Status: vmv1beta1.VMAlertStatus{
+			StatePersistence: &vmv1beta1.VMAlertStatePersistenceStatus{Warning: "some warning"},
+		},
+	}
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{cr})
+	get := func() *vmv1beta1.VMAlertStatePersistenceStatus {
+		t.Helper()
+		var got vmv1beta1.VMAlert
+		assert.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}, &got))
+		return got.Status.StatePersistence
+	}
+
+	assert.NoError(t, updateStatePersistenceStatus(ctx, fclient, cr, statePersistenceStatusWithLookback(cr, 15*time.Minute)))
+	assert.Equal(t, &vmv1beta1.VMAlertStatePersistenceStatus{Lookback: "15m0s", Warning: "some warning"}, get())
+
+	// disabling removes status
+	cr.Spec.StatePersistence = nil
+	assert.NoError(t, updateStatePersistenceStatus(ctx, fclient, cr, statePersistenceStatusWithLookback(cr, 15*time.Minute)))
+	assert.Nil(t, get())
+	assert.Nil(t, cr.Status.StatePersistence)
+}
@@ -149,6 +149,24 @@ func CreateOrUpdateVMAlert(ctx context.Context, cr *vmv1beta1.VMAlert, rclient c
 	if err := discoverNotifierIfNeeded(ctx, rclient, cr); err != nil {
 		return fmt.Errorf("cannot discover additional notifiers: %w", err)
 	}
+	if cr.IsStatePersistenceEnabled() {
+		st := cr.Status.StatePersistence.DeepCopy()
+		if st == nil {
+			st = &vmv1beta1.VMAlertStatePersistenceStatus{}
+		}
+		warning, err := applyStatePersistence(cr)
+		if err != nil {
+			return err
+		}
+		st.Warning = warning
+		if err := updateStatePersistenceStatus(ctx, rclient, cr, st); err != nil {
+			return err
+		}
+	}
+	cr, err := resolveRemoteTargetRefs(ctx, rclient, cr)
+	if err != nil {
+		return err
+	}
 
 	remoteSecrets, err := loadVMAlertRemoteSecrets(ctx, rclient, cr)
 	if err != nil {
@@ -1025,6 +1043,52 @@ func discoverNotifierIfNeeded(ctx context.Context, rclient client.Client, cr *vm
 	return nil
 }
 
+// resolveRemoteTargetRefs returns a copy of given VMAlert with url, credentials and tls config set for datasource, remoteWrite and remoteRead defined with targetRef
+//
+// given VMAlert is returned as is if there are no targetRefs
+func resolveRemoteTargetRefs(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMAlert) (*vmv1beta1.VMAlert, error) {
+	if cr.Spec.Datasource.TargetRef == nil &&
+		(cr.Spec.RemoteWrite == nil || cr.Spec.RemoteWrite.TargetRef == nil) &&
+		(cr.Spec.RemoteRead == nil || cr.Spec.RemoteRead.TargetRef == nil) {
+		return cr, nil
+	}
+	cr = cr.DeepCopy()
+	resolve := func(name string, ref *vmv1beta1.RemoteTargetRef, forWrite bool, url *string, ha *vmv1beta1.HTTPAuth) error {
+		if ref == nil {
+			return nil
+		}
+		rt, err := build.ResolveRemoteTargetRef(ctx, rclient, ref, cr.Namespace, forWrite)
+		if err != nil {
+			return fmt.Errorf("cannot resolve targetRef for %s: %w", name, err)
+		}
+		*url = rt.URL
+		if ha.BasicAuth == nil && ha.BearerAuth == nil && ha.OAuth2 == nil {
+			ha.BasicAuth = rt.BasicAuth
+			if rt.BearerTokenSecret != nil {
+				ha.BearerAuth = &vmv1beta1.BearerAuth{TokenSecret: rt.BearerTokenSecret}
+			}
+		}
+		if ha.TLSConfig, err = rt.TLSConfigFor(ha.TLSConfig); err != nil {
+			return fmt.Errorf("cannot resolve targetRef for %s: %w", name, err)
+		}
+		return nil
+	}
+	if err := resolve("datasource", cr.Spec.Datasource.TargetRef, false, &cr.Spec.Datasource.URL, &cr.Spec.Datasource.HTTPAuth); err != nil {
+		return nil, err
+	}
+	if rw := cr.Spec.RemoteWrite; rw != nil {
+		if err := resolve("remoteWrite", rw.TargetRef, true, &rw.URL, &rw.HTTPAuth); err != nil {
+			return nil, err
+		}
+	}
+	if rr := cr.Spec.RemoteRead; rr != nil {
+		if err := resolve("remoteRead", rr.TargetRef, false, &rr.URL, &rr.HTTPAuth); err != nil {
+			return nil, err
+		}
+	}
+	return cr, nil
+}
+
 func deletePrevStateResources(ctx context.Context, cr *vmv1beta1.VMAlert, rclient client.Client) error {
 	if cr.ParsedLastAppliedSpec == nil {
 		return nil
@@ -779,3 +779,62 @@ func Test_buildVMAlertArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveRemoteTargetRefs(t *testing.T) {
+	f := func(cr *vmv1beta1.VMAlert, predefinedObjects []runtime.Object, want vmv1beta1.VMAlertSpec) {
+		t.Helper()
+		fclient := k8stools.GetTestClientWithObjects(predefinedObjects)
+		origin := cr.DeepCopy()
+		got, err := resolveRemoteTargetRefs(context.Background(), fclient, cr)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got.Spec)
+		// given object must not be modified
+		assert.Equal(t, origin, cr)
+	}
+	vmsingle := &vmv1beta1.VMSingle{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"},
+		Spec: vmv1beta1.VMSingleSpec{
+			CommonApplicationDeploymentParams: vmv1beta1.CommonApplicationDeploymentParams{
+				Secrets:   []string{"vmsingle-tls"},
+				ExtraArgs: map[string]string{"tls": "true", "tlsCertFile": "/etc/vm/secrets/vmsingle-tls/tls.crt"},
+			},
+		},
+	}
+
+	// without targetRefs
+	f(&vmv1beta1.VMAlert{
+		ObjectMeta: metav1.ObjectMeta{Name: "alert", Namespace: "default"},
+		Spec: vmv1beta1.VMAlertSpec{
+			Datasource: vmv1beta1.VMAlertDatasourceSpec{URL: "http://some-url"},
+		},
+	}, nil, vmv1beta1.VMAlertSpec{
+		Datasource: vmv1beta1.VMAlertDatasourceSpec{URL: "http://some-url"},
+	})
+
+	// https targets with user defined tls config
+	f(&vmv1beta1.VMAlert{
+		ObjectMeta: metav1.ObjectMeta{Name: "alert", Namespace: "default"},
+		Spec: vmv1beta1.VMAlertSpec{
+			Datasource: vmv1beta1.VMAlertDatasourceSpec{
+				TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMSingle", Name: "main"},
+			},
+			RemoteWrite: &vmv1beta1.VMAlertRemoteWriteSpec{
+				TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMSingle", Name: "main"},
+				HTTPAuth:  vmv1beta1.HTTPAuth{TLSConfig: &vmv1beta1.TLSConfig{CAFile: "/etc/ca.crt"}},
+			},
+		},
+	}, []runtime.Object{vmsingle}, vmv1beta1.VMAlertSpec{
+		Datasource: vmv1beta1.VMAlertDatasourceSpec{
+			URL:       "https://vmsingle-main.default.svc:8429",
+			TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMSingle", Name: "main"},
+			HTTPAuth: vmv1beta1.HTTPAuth{TLSConfig: &vmv1beta1.TLSConfig{CA: vmv1beta1.SecretOrConfigMap{
+				Secret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "vmsingle-tls"}, Key: "tls.crt"},
+			}}},
+		},
+		RemoteWrite: &vmv1beta1.VMAlertRemoteWriteSpec{
+			URL:       "https://vmsingle-main.default.svc:8429",
+			TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMSingle", Name: "main"},
+			HTTPAuth:  vmv1beta1.HTTPAuth{TLSConfig: &vmv1beta1.TLSConfig{CAFile: "/etc/ca.crt"}},
+		},
+	})
+}
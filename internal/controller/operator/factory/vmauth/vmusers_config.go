@@ -15,7 +15,7 @@ import (
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/build"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
-	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/reconcile"
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -121,10 +121,7 @@ func buildVMAuthConfig(ctx context.Context, rclient client.Client, vmauth *vmv1b
 	}
 	// update secrets.
 	for i := range toUpdate {
-		secret := toUpdate[i]
-		logger.WithContext(ctx).Info(fmt.Sprintf("updating vmuser secret %s configuration", secret.Name))
-
-		if err := rclient.Update(ctx, secret); err != nil {
+		if err := reconcile.Secret(ctx, rclient, toUpdate[i], nil); err != nil {
 			return nil, err
 		}
 	}
@@ -134,11 +131,7 @@ func buildVMAuthConfig(ctx context.Context, rclient client.Client, vmauth *vmv1b
 
 func createVMUserSecrets(ctx context.Context, rclient client.Client, secrets []*corev1.Secret) error {
 	for i := range secrets {
-		secret := secrets[i]
-		if err := rclient.Create(ctx, secret); err != nil {
-			if errors.IsAlreadyExists(err) {
-				continue
-			}
+		if err := reconcile.Secret(ctx, rclient, secrets[i], nil); err != nil {
 			return err
 		}
 	}
@@ -230,7 +223,7 @@ func addAuthCredentialsBuildSecrets(ctx context.Context, rclient client.Client,
 			} else {
 				// secret exists, check it's state
 				if injectAuthSettings(&vmus, user) {
-					needToUpdateSecrets = append(needToUpdateSecrets, &vmus)
+					needToUpdateSecrets = append(needToUpdateSecrets, vmUserSecretWithCredentials(user, vmus.Data))
 				}
 			}
 		}
@@ -922,19 +915,7 @@ func selectVMUsers(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMA
 // note, username and password must be filled by operator
 // with default values if need.
 func buildVMUserSecret(src *vmv1beta1.VMUser) (*corev1.Secret, error) {
-	s := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:            src.SecretName(),
-			Namespace:       src.Namespace,
-			Labels:          src.AllLabels(),
-			Annotations:     src.AnnotationsFiltered(),
-			OwnerReferences: src.AsOwner(),
-			Finalizers: []string{
-				vmv1beta1.FinalizerName,
-			},
-		},
-		Data: map[string][]byte{},
-	}
+	s := vmUserSecretWithCredentials(src, nil)
 	if src.Spec.GeneratePassword && src.Spec.Password == nil {
 		pwd, err := genPassword()
 		if err != nil {
@@ -957,6 +938,30 @@ func buildVMUserSecret(src *vmv1beta1.VMUser) (*corev1.Secret, error) {
 	return s, nil
 }
 
+// vmUserSecretWithCredentials returns secret of vmuser with credential keys of the given data
+// other keys aren't managed by operator
+func vmUserSecretWithCredentials(src *vmv1beta1.VMUser, data map[string][]byte) *corev1.Secret {
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            src.SecretName(),
+			Namespace:       src.Namespace,
+			Labels:          src.AllLabels(),
+			Annotations:     src.AnnotationsFiltered(),
+			OwnerReferences: src.AsOwner(),
+			Finalizers: []string{
+				vmv1beta1.FinalizerName,
+			},
+		},
+		Data: map[string][]byte{},
+	}
+	for _, key := range []string{"name", "bearerToken", "username", "password"} {
+		if v, ok := data[key]; ok {
+			s.Data[key] = v
+		}
+	}
+	return s
+}
+
 func addVMInsertPaths(src []string) []string {
 	return append(src,
 		"/newrelic/.*",
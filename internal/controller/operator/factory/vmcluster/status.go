@@ -0,0 +1,266 @@
+package vmcluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+)
+
+const (
+	componentVMSelect             = "vmselect"
+	componentVMInsert             = "vminsert"
+	componentVMStorage            = "vmstorage"
+	componentRequestsLoadBalancer = "vmauth"
+)
+
+// componentError is an error occurred during reconcile of cluster component
+type componentError struct {
+	component string
+	origin    error
+}
+
+func (e *componentError) Error() string {
+	return e.origin.Error()
+}
+
+func (e *componentError) Unwrap() error {
+	return e.origin
+}
+
+func withComponentError(component string, err error) error {
+	return &componentError{component: component, origin: err}
+}
+
+type clusterComponent struct {
+	name          string
+	conditionType string
+	enabled       bool
+	status        **vmv1beta1.VMClusterComponentStatus
+}
+
+func clusterComponents(cr *vmv1beta1.VMCluster) []clusterComponent {
+	return []clusterComponent{
+		{
+			name:          componentVMSelect,
+			conditionType: vmv1beta1.ConditionVMSelectReady,
+			enabled:       cr.Spec.VMSelect != nil,
+			status:        &cr.Status.VMSelect,
+		},
+		{
+			name:          componentVMInsert,
+			conditionType: vmv1beta1.ConditionVMInsertReady,
+			enabled:       cr.Spec.VMInsert != nil,
+			status:        &cr.Status.VMInsert,
+		},
+		{
+			name:          componentVMStorage,
+			conditionType: vmv1beta1.ConditionVMStorageReady,
+			enabled:       cr.Spec.VMStorage != nil,
+			status:        &cr.Status.VMStorage,
+		},
+		{
+			name:          componentRequestsLoadBalancer,
+			conditionType: vmv1beta1.ConditionRequestsLoadBalancerReady,
+			enabled:       cr.Spec.RequestsLoadBalancer.Enabled,
+			status:        &cr.Status.RequestsLoadBalancer,
+		},
+	}
+}
+
+func getClusterComponent(cr *vmv1beta1.VMCluster, name string) clusterComponent {
+	for _, c := range clusterComponents(cr) {
+		if c.name == name {
+			return c
+		}
+	}
+	panic(fmt.Sprintf("BUG: unexpected cluster component: %q", name))
+}
+
+// recordComponentStatus fetches current workload of cluster component and updates component status.
+// It's called by component reconcile before and after workload update,
+// so status reflects the progress of rolling update, which may take a long time.
+// Component is reported as progressing, if current workload differs from the desired one.
+func recordComponentStatus(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMCluster, name string, desired client.Object) error {
+	c := getClusterComponent(cr, name)
+	prevStatus := cr.Status.DeepCopy()
+	current := desired.DeepCopyObject().(client.Object)
+	st := &vmv1beta1.VMClusterComponentStatus{Phase: vmv1beta1.ClusterComponentPending, Ready: "0/0"}
+	if err := rclient.Get(ctx, client.ObjectKeyFromObject(desired), current); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("cannot get %s workload: %w", name, err)
+		}
+	} else {
+		st = getComponentStatus(current, desired, name)
+	}
+	*c.status = st
+	cr.Status.Conditions = setComponentCondition(cr.Status.Conditions, c.conditionType, st, cr.Generation)
+	return patchComponentsStatus(ctx, rclient, cr, prevStatus)
+}
+
+// UpdateComponentsStatus sets status of cluster components after reconcile
+// status of enabled components is recorded during reconcile,
+// reconcileErr is reported as the last error of component, which reconcile failed
+func UpdateComponentsStatus(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMCluster, reconcileErr error) error {
+	prevStatus := cr.Status.DeepCopy()
+	var ce *componentError
+	errors.As(reconcileErr, &ce)
+
+	for _, c := range clusterComponents(cr) {
+		if !c.enabled {
+			*c.status = nil
+			cr.Status.Conditions = removeCondition(cr.Status.Conditions, c.conditionType)
+			continue
+		}
+		if ce == nil || ce.component != c.name {
+			continue
+		}
+		st := &vmv1beta1.VMClusterComponentStatus{Ready: "0/0"}
+		if *c.status != nil {
+			st = (*c.status).DeepCopy()
+		}
+		st.LastError = ce.Error()
+		st.Phase = vmv1beta1.ClusterComponentFailed
+		*c.status = st
+		cr.Status.Conditions = setComponentCondition(cr.Status.Conditions, c.conditionType, st, cr.Generation)
+	}
+	return patchComponentsStatus(ctx, rclient, cr, prevStatus)
+}
+
+// CopyComponentsStatus copies status of cluster components from src to dst
+// conditions of other types at dst are kept as is
+func CopyComponentsStatus(dst, src *vmv1beta1.VMCluster) {
+	dst.Status.VMSelect = src.Status.VMSelect
+	dst.Status.VMInsert = src.Status.VMInsert
+	dst.Status.VMStorage = src.Status.VMStorage
+	dst.Status.RequestsLoadBalancer = src.Status.RequestsLoadBalancer
+	for _, c := range clusterComponents(src) {
+		dst.Status.Conditions = removeCondition(dst.Status.Conditions, c.conditionType)
+		for _, cond := range src.Status.Conditions {
+			if cond.Type == c.conditionType {
+				dst.Status.Conditions = append(dst.Status.Conditions, cond)
+				break
+			}
+		}
+	}
+}
+
+func patchComponentsStatus(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMCluster, prevStatus *vmv1beta1.VMClusterStatus) error {
+	if equality.Semantic.DeepEqual(prevStatus, &cr.Status) {
+		return nil
+	}
+	data, err := json.Marshal(map[string]any{
+		"status": map[string]any{
+			"vmselect":             cr.Status.VMSelect,
+			"vminsert":             cr.Status.VMInsert,
+			"vmstorage":            cr.Status.VMStorage,
+			"requestsLoadBalancer": cr.Status.RequestsLoadBalancer,
+			"conditions":           cr.Status.Conditions,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("BUG: cannot serialize status patch: %w", err)
+	}
+	if err := rclient.Status().Patch(ctx, cr.DeepCopy(), client.RawPatch(types.MergePatchType, data)); err != nil {
+		// object could be deleted during reconcile
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("cannot update vmcluster components status: %w", err)
+	}
+	return nil
+}
+
+// getComponentStatus returns component status from the state of its current workload
+func getComponentStatus(current, desired client.Object, containerName string) *vmv1beta1.VMClusterComponentStatus {
+	st := &vmv1beta1.VMClusterComponentStatus{}
+	var containers []corev1.Container
+	var observed, upToDate bool
+	switch w := current.(type) {
+	case *appsv1.StatefulSet:
+		d := desired.(*appsv1.StatefulSet)
+		st.Replicas = ptr.Deref(w.Spec.Replicas, 1)
+		st.ReadyReplicas = w.Status.ReadyReplicas
+		st.UpdatedReplicas = w.Status.UpdatedReplicas
+		observed = w.Status.ObservedGeneration >= w.Generation
+		upToDate = equality.Semantic.DeepDerivative(d.Spec.Template, w.Spec.Template)
+		containers = w.Spec.Template.Spec.Containers
+	case *appsv1.Deployment:
+		d := desired.(*appsv1.Deployment)
+		st.Replicas = ptr.Deref(w.Spec.Replicas, 1)
+		st.ReadyReplicas = w.Status.ReadyReplicas
+		st.UpdatedReplicas = w.Status.UpdatedReplicas
+		observed = w.Status.ObservedGeneration >= w.Generation
+		upToDate = equality.Semantic.DeepDerivative(d.Spec.Template, w.Spec.Template)
+		containers = w.Spec.Template.Spec.Containers
+	default:
+		panic(fmt.Sprintf("BUG: unexpected workload type: %T", current))
+	}
+	st.Ready = fmt.Sprintf("%d/%d", st.ReadyReplicas, st.Replicas)
+	for _, c := range containers {
+		if c.Name == containerName {
+			st.Image = c.Image
+			break
+		}
+	}
+	st.Phase = vmv1beta1.ClusterComponentProgressing
+	if observed && upToDate && st.ReadyReplicas >= st.Replicas && st.UpdatedReplicas >= st.Replicas {
+		st.Phase = vmv1beta1.ClusterComponentReady
+	}
+	return st
+}
+
+// setComponentCondition sets readiness condition of cluster component
+// condition times are changed only on status, reason or message change
+func setComponentCondition(dst []vmv1beta1.Condition, conditionType string, st *vmv1beta1.VMClusterComponentStatus, generation int64) []vmv1beta1.Condition {
+	now := metav1.Now()
+	cond := vmv1beta1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             string(st.Phase),
+		Message:            fmt.Sprintf("ready replicas %s, updated replicas %d", st.Ready, st.UpdatedReplicas),
+		ObservedGeneration: generation,
+		LastTransitionTime: now,
+		LastUpdateTime:     now,
+	}
+	if st.Phase == vmv1beta1.ClusterComponentReady {
+		cond.Status = metav1.ConditionTrue
+	}
+	if st.LastError != "" {
+		cond.Message = st.LastError
+	}
+	for idx, c := range dst {
+		if c.Type != conditionType {
+			continue
+		}
+		if c.Status == cond.Status {
+			cond.LastTransitionTime = c.LastTransitionTime
+			if c.Reason == cond.Reason && c.Message == cond.Message && c.ObservedGeneration == cond.ObservedGeneration {
+				cond.LastUpdateTime = c.LastUpdateTime
+			}
+		}
+		dst[idx] = cond
+		return dst
+	}
+	return append(dst, cond)
+}
+
+func removeCondition(dst []vmv1beta1.Condition, conditionType string) []vmv1beta1.Condition {
+	for idx, c := range dst {
+		if c.Type == conditionType {
+			return append(dst[:idx], dst[idx+1:]...)
+		}
+	}
+	return dst
+}
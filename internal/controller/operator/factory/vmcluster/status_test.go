@@ -0,0 +1,167 @@
+package vmcluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+)
+
+func TestRecordComponentStatus(t *testing.T) {
+	f := func(component string, desired client.Object, predefinedObjects []runtime.Object, want *vmv1beta1.VMClusterComponentStatus, wantCondition metav1.ConditionStatus) {
+		t.Helper()
+		cr := &vmv1beta1.VMCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+			Spec: vmv1beta1.VMClusterSpec{
+				VMSelect:  &vmv1beta1.VMSelect{},
+				VMInsert:  &vmv1beta1.VMInsert{},
+				VMStorage: &vmv1beta1.VMStorage{},
+			},
+		}
+		rclient := k8stools.GetTestClientWithObjects(append(predefinedObjects, cr))
+		ctx := context.Background()
+		assert.NoError(t, recordComponentStatus(ctx, rclient, cr, component, desired))
+
+		var got vmv1beta1.VMCluster
+		assert.NoError(t, rclient.Get(ctx, client.ObjectKeyFromObject(cr), &got))
+		c := getClusterComponent(&got, component)
+		assert.Equal(t, want, *c.status)
+		assert.Len(t, got.Status.Conditions, 1)
+		assert.Equal(t, c.conditionType, got.Status.Conditions[0].Type)
+		assert.Equal(t, wantCondition, got.Status.Conditions[0].Status)
+
+		// status must not be changed on the next call with the same state
+		prevVersion := got.ResourceVersion
+		assert.NoError(t, recordComponentStatus(ctx, rclient, &got, component, desired))
+		assert.NoError(t, rclient.Get(ctx, client.ObjectKeyFromObject(cr), &got))
+		assert.Equal(t, prevVersion, got.ResourceVersion)
+	}
+
+	podTemplate := func(name, image string) corev1.PodTemplateSpec {
+		return corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: name, Image: image}}}}
+	}
+	vmselect := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "vmselect-cluster", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr.To[int32](2),
+			Template: podTemplate("vmselect", "vmselect:v1.0.0"),
+		},
+		Status: appsv1.StatefulSetStatus{ReadyReplicas: 2, UpdatedReplicas: 2},
+	}
+	vminsert := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "vminsert-cluster", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Template: podTemplate("vminsert", "vminsert:v1.0.0"),
+		},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: 0, UpdatedReplicas: 1},
+	}
+
+	// no workload yet
+	f(componentVMStorage, &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "vmstorage-cluster", Namespace: "default"}}, nil,
+		&vmv1beta1.VMClusterComponentStatus{Ready: "0/0", Phase: vmv1beta1.ClusterComponentPending}, metav1.ConditionFalse)
+
+	// ready workload
+	f(componentVMSelect, vmselect.DeepCopy(), []runtime.Object{vmselect},
+		&vmv1beta1.VMClusterComponentStatus{Replicas: 2, ReadyReplicas: 2, UpdatedReplicas: 2, Ready: "2/2", Image: "vmselect:v1.0.0", Phase: vmv1beta1.ClusterComponentReady}, metav1.ConditionTrue)
+
+	// ready workload, which must be updated
+	desired := vmselect.DeepCopy()
+	desired.Spec.Template = podTemplate("vmselect", "vmselect:v1.1.0")
+	f(componentVMSelect, desired, []runtime.Object{vmselect},
+		&vmv1beta1.VMClusterComponentStatus{Replicas: 2, ReadyReplicas: 2, UpdatedReplicas: 2, Ready: "2/2", Image: "vmselect:v1.0.0", Phase: vmv1beta1.ClusterComponentProgressing}, metav1.ConditionFalse)
+
+	// not ready workload
+	f(componentVMInsert, vminsert.DeepCopy(), []runtime.Object{vminsert},
+		&vmv1beta1.VMClusterComponentStatus{Replicas: 1, UpdatedReplicas: 1, Ready: "0/1", Image: "vminsert:v1.0.0", Phase: vmv1beta1.ClusterComponentProgressing}, metav1.ConditionFalse)
+}
+
+func TestUpdateComponentsStatus(t *testing.T) {
+	cr := &vmv1beta1.VMCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec: vmv1beta1.VMClusterSpec{
+			VMSelect:  &vmv1beta1.VMSelect{},
+			VMStorage: &vmv1beta1.VMStorage{},
+		},
+		Status: vmv1beta1.VMClusterStatus{
+			VMSelect:  &vmv1beta1.VMClusterComponentStatus{Replicas: 1, ReadyReplicas: 1, UpdatedReplicas: 1, Ready: "1/1", Phase: vmv1beta1.ClusterComponentReady},
+			VMInsert:  &vmv1beta1.VMClusterComponentStatus{Replicas: 1, ReadyReplicas: 1, UpdatedReplicas: 1, Ready: "1/1", Phase: vmv1beta1.ClusterComponentReady},
+			VMStorage: &vmv1beta1.VMClusterComponentStatus{Replicas: 1, UpdatedReplicas: 1, Ready: "0/1", Phase: vmv1beta1.ClusterComponentProgressing},
+			StatusMetadata: vmv1beta1.StatusMetadata{
+				Conditions: []vmv1beta1.Condition{
+					{Type: vmv1beta1.ConditionVMSelectReady, Status: metav1.ConditionTrue},
+					{Type: vmv1beta1.ConditionVMInsertReady, Status: metav1.ConditionTrue},
+					{Type: vmv1beta1.ConditionVMStorageReady, Status: metav1.ConditionFalse},
+				},
+			},
+		},
+	}
+	rclient := k8stools.GetTestClientWithObjects([]runtime.Object{cr})
+	ctx := context.Background()
+	assert.NoError(t, UpdateComponentsStatus(ctx, rclient, cr, withComponentError(componentVMStorage, fmt.Errorf("cannot update vmstorage"))))
+
+	var got vmv1beta1.VMCluster
+	assert.NoError(t, rclient.Get(ctx, client.ObjectKeyFromObject(cr), &got))
+	// disabled component is removed from status
+	assert.Nil(t, got.Status.VMInsert)
+	// recorded status is kept
+	assert.Equal(t, vmv1beta1.ClusterComponentReady, got.Status.VMSelect.Phase)
+	// failed component keeps recorded replicas
+	assert.Equal(t, &vmv1beta1.VMClusterComponentStatus{
+		Replicas:        1,
+		UpdatedReplicas: 1,
+		Ready:           "0/1",
+		Phase:           vmv1beta1.ClusterComponentFailed,
+		LastError:       "cannot update vmstorage",
+	}, got.Status.VMStorage)
+	gotConditions := make(map[string]metav1.ConditionStatus)
+	for _, c := range got.Status.Conditions {
+		gotConditions[c.Type] = c.Status
+	}
+	assert.Equal(t, map[string]metav1.ConditionStatus{
+		vmv1beta1.ConditionVMSelectReady:  metav1.ConditionTrue,
+		vmv1beta1.ConditionVMStorageReady: metav1.ConditionFalse,
+	}, gotConditions)
+}
+
+func TestCopyComponentsStatus(t *testing.T) {
+	dst := &vmv1beta1.VMCluster{
+		Status: vmv1beta1.VMClusterStatus{
+			StatusMetadata: vmv1beta1.StatusMetadata{
+				Conditions: []vmv1beta1.Condition{
+					{Type: "DriftCorrected", Status: metav1.ConditionTrue},
+					{Type: vmv1beta1.ConditionVMSelectReady, Status: metav1.ConditionFalse},
+					{Type: vmv1beta1.ConditionVMInsertReady, Status: metav1.ConditionTrue},
+				},
+			},
+		},
+	}
+	src := &vmv1beta1.VMCluster{
+		Spec: vmv1beta1.VMClusterSpec{VMSelect: &vmv1beta1.VMSelect{}},
+		Status: vmv1beta1.VMClusterStatus{
+			VMSelect: &vmv1beta1.VMClusterComponentStatus{Ready: "1/1", Phase: vmv1beta1.ClusterComponentReady},
+			StatusMetadata: vmv1beta1.StatusMetadata{
+				Conditions: []vmv1beta1.Condition{
+					{Type: "Maintenance", Status: metav1.ConditionTrue},
+					{Type: vmv1beta1.ConditionVMSelectReady, Status: metav1.ConditionTrue},
+				},
+			},
+		},
+	}
+	CopyComponentsStatus(dst, src)
+	assert.Equal(t, src.Status.VMSelect, dst.Status.VMSelect)
+	assert.Nil(t, dst.Status.VMInsert)
+	assert.Equal(t, []vmv1beta1.Condition{
+		{Type: "DriftCorrected", Status: metav1.ConditionTrue},
+		{Type: vmv1beta1.ConditionVMSelectReady, Status: metav1.ConditionTrue},
+	}, dst.Status.Conditions)
+}
@@ -57,96 +57,114 @@ func CreateOrUpdateVMCluster(ctx context.Context, cr *vmv1beta1.VMCluster, rclie
 	if cr.Spec.RequestsLoadBalancer.Enabled {
 		// create vmauth deployment
 		if err := createOrUpdateVMAuthLB(ctx, rclient, cr, prevCR); err != nil {
-			return err
+			return withComponentError(componentRequestsLoadBalancer, err)
 		}
 	}
-
 	if cr.Spec.VMStorage != nil {
-		if cr.Spec.VMStorage.PodDisruptionBudget != nil {
-			err := createOrUpdatePodDisruptionBudgetForVMStorage(ctx, rclient, cr, prevCR)
-			if err != nil {
-				return err
-			}
+		if err := createOrUpdateVMStorageComponent(ctx, rclient, cr, prevCR); err != nil {
+			return withComponentError(componentVMStorage, err)
 		}
-		if err := createOrUpdateVMStorage(ctx, rclient, cr, prevCR); err != nil {
-			return err
+	}
+	if cr.Spec.VMSelect != nil {
+		if err := createOrUpdateVMSelectComponent(ctx, rclient, cr, prevCR); err != nil {
+			return withComponentError(componentVMSelect, err)
 		}
+	}
+	if cr.Spec.VMInsert != nil {
+		if err := createOrUpdateVMInsertComponent(ctx, rclient, cr, prevCR); err != nil {
+			return withComponentError(componentVMInsert, err)
+		}
+	}
 
-		storageSvc, err := createOrUpdateVMStorageService(ctx, rclient, cr, prevCR)
+	if err := deletePrevStateResources(ctx, rclient, cr, prevCR); err != nil {
+		return fmt.Errorf("failed to remove objects from previous cluster state: %w", err)
+	}
+	return nil
+}
+
+func createOrUpdateVMStorageComponent(ctx context.Context, rclient client.Client, cr, prevCR *vmv1beta1.VMCluster) error {
+	if cr.Spec.VMStorage.PodDisruptionBudget != nil {
+		err := createOrUpdatePodDisruptionBudgetForVMStorage(ctx, rclient, cr, prevCR)
 		if err != nil {
 			return err
 		}
-		if !ptr.Deref(cr.Spec.VMStorage.DisableSelfServiceScrape, false) {
-			err := reconcile.VMServiceScrapeForCRD(ctx, rclient, build.VMServiceScrapeForServiceWithSpec(storageSvc, cr.Spec.VMStorage, "vmbackupmanager"))
-			if err != nil {
-				return fmt.Errorf("cannot create VMServiceScrape for vmStorage: %w", err)
-			}
-		}
+	}
+	if err := createOrUpdateVMStorage(ctx, rclient, cr, prevCR); err != nil {
+		return err
 	}
 
-	if cr.Spec.VMSelect != nil {
-		if cr.Spec.VMSelect.PodDisruptionBudget != nil {
-			if err := createOrUpdatePodDisruptionBudgetForVMSelect(ctx, rclient, cr, prevCR); err != nil {
-				return err
-			}
-		}
-		if err := createOrUpdateVMSelect(ctx, rclient, cr, prevCR); err != nil {
-			return err
+	storageSvc, err := createOrUpdateVMStorageService(ctx, rclient, cr, prevCR)
+	if err != nil {
+		return err
+	}
+	if !ptr.Deref(cr.Spec.VMStorage.DisableSelfServiceScrape, false) {
+		err := reconcile.VMServiceScrapeForCRD(ctx, rclient, build.VMServiceScrapeForServiceWithSpec(storageSvc, cr.Spec.VMStorage, "vmbackupmanager"))
+		if err != nil {
+			return fmt.Errorf("cannot create VMServiceScrape for vmStorage: %w", err)
 		}
+	}
+	return nil
+}
 
-		if err := createOrUpdateVMSelectHPA(ctx, rclient, cr, prevCR); err != nil {
+func createOrUpdateVMSelectComponent(ctx context.Context, rclient client.Client, cr, prevCR *vmv1beta1.VMCluster) error {
+	if cr.Spec.VMSelect.PodDisruptionBudget != nil {
+		if err := createOrUpdatePodDisruptionBudgetForVMSelect(ctx, rclient, cr, prevCR); err != nil {
 			return err
 		}
-		// create vmselect service
-		selectSvc, err := createOrUpdateVMSelectService(ctx, rclient, cr, prevCR)
-		if err != nil {
-			return err
-		}
-		if !ptr.Deref(cr.Spec.VMSelect.DisableSelfServiceScrape, false) {
+	}
+	if err := createOrUpdateVMSelect(ctx, rclient, cr, prevCR); err != nil {
+		return err
+	}
 
-			svs := build.VMServiceScrapeForServiceWithSpec(selectSvc, cr.Spec.VMSelect)
-			if cr.Spec.RequestsLoadBalancer.Enabled && !cr.Spec.RequestsLoadBalancer.DisableSelectBalancing {
-				// for backward compatibility we must keep job label value
-				svs.Spec.JobLabel = vmauthLBServiceProxyJobNameLabel
-			}
-			err := reconcile.VMServiceScrapeForCRD(ctx, rclient, svs)
-			if err != nil {
-				return fmt.Errorf("cannot create VMServiceScrape for vmSelect: %w", err)
-			}
-		}
+	if err := createOrUpdateVMSelectHPA(ctx, rclient, cr, prevCR); err != nil {
+		return err
+	}
+	// create vmselect service
+	selectSvc, err := createOrUpdateVMSelectService(ctx, rclient, cr, prevCR)
+	if err != nil {
+		return err
 	}
+	if !ptr.Deref(cr.Spec.VMSelect.DisableSelfServiceScrape, false) {
 
-	if cr.Spec.VMInsert != nil {
-		if cr.Spec.VMInsert.PodDisruptionBudget != nil {
-			if err := createOrUpdatePodDisruptionBudgetForVMInsert(ctx, rclient, cr, prevCR); err != nil {
-				return err
-			}
+		svs := build.VMServiceScrapeForServiceWithSpec(selectSvc, cr.Spec.VMSelect)
+		if cr.Spec.RequestsLoadBalancer.Enabled && !cr.Spec.RequestsLoadBalancer.DisableSelectBalancing {
+			// for backward compatibility we must keep job label value
+			svs.Spec.JobLabel = vmauthLBServiceProxyJobNameLabel
 		}
-		if err := createOrUpdateVMInsert(ctx, rclient, cr, prevCR); err != nil {
-			return err
-		}
-		insertSvc, err := createOrUpdateVMInsertService(ctx, rclient, cr, prevCR)
+		err := reconcile.VMServiceScrapeForCRD(ctx, rclient, svs)
 		if err != nil {
-			return err
+			return fmt.Errorf("cannot create VMServiceScrape for vmSelect: %w", err)
 		}
-		if err := createOrUpdateVMInsertHPA(ctx, rclient, cr, prevCR); err != nil {
+	}
+	return nil
+}
+
+func createOrUpdateVMInsertComponent(ctx context.Context, rclient client.Client, cr, prevCR *vmv1beta1.VMCluster) error {
+	if cr.Spec.VMInsert.PodDisruptionBudget != nil {
+		if err := createOrUpdatePodDisruptionBudgetForVMInsert(ctx, rclient, cr, prevCR); err != nil {
 			return err
 		}
-		if !ptr.Deref(cr.Spec.VMInsert.DisableSelfServiceScrape, false) {
-			svs := build.VMServiceScrapeForServiceWithSpec(insertSvc, cr.Spec.VMInsert)
-			if cr.Spec.RequestsLoadBalancer.Enabled && !cr.Spec.RequestsLoadBalancer.DisableInsertBalancing {
-				// for backward compatibility we must keep job label value
-				svs.Spec.JobLabel = vmauthLBServiceProxyJobNameLabel
-			}
-			err := reconcile.VMServiceScrapeForCRD(ctx, rclient, svs)
-			if err != nil {
-				return fmt.Errorf("cannot create VMServiceScrape for vmInsert: %w", err)
-			}
-		}
 	}
-
-	if err := deletePrevStateResources(ctx, rclient, cr, prevCR); err != nil {
-		return fmt.Errorf("failed to remove objects from previous cluster state: %w", err)
+	if err := createOrUpdateVMInsert(ctx, rclient, cr, prevCR); err != nil {
+		return err
+	}
+	insertSvc, err := createOrUpdateVMInsertService(ctx, rclient, cr, prevCR)
+	if err != nil {
+		return err
+	}
+	if err := createOrUpdateVMInsertHPA(ctx, rclient, cr, prevCR); err != nil {
+		return err
+	}
+	if !ptr.Deref(cr.Spec.VMInsert.DisableSelfServiceScrape, false) {
+		svs := build.VMServiceScrapeForServiceWithSpec(insertSvc, cr.Spec.VMInsert)
+		if cr.Spec.RequestsLoadBalancer.Enabled && !cr.Spec.RequestsLoadBalancer.DisableInsertBalancing {
+			// for backward compatibility we must keep job label value
+			svs.Spec.JobLabel = vmauthLBServiceProxyJobNameLabel
+		}
+		err := reconcile.VMServiceScrapeForCRD(ctx, rclient, svs)
+		if err != nil {
+			return fmt.Errorf("cannot create VMServiceScrape for vmInsert: %w", err)
+		}
 	}
 	return nil
 }
@@ -176,7 +194,31 @@ func createOrUpdateVMSelect(ctx context.Context, rclient client.Client, cr, prev
 			}
 		},
 	}
-	return reconcile.HandleSTSUpdate(ctx, rclient, stsOpts, newSts, prevSts)
+	return reconcileComponentSTS(ctx, rclient, cr, componentVMSelect, stsOpts, newSts, prevSts)
+}
+
+// reconcileComponentSTS updates StatefulSet of cluster component and records component status
+// before and after update, since update waits for rolling update of pods
+func reconcileComponentSTS(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMCluster, component string, stsOpts reconcile.STSOptions, newSts, prevSts *appsv1.StatefulSet) error {
+	if err := recordComponentStatus(ctx, rclient, cr, component, newSts); err != nil {
+		return err
+	}
+	if err := reconcile.HandleSTSUpdate(ctx, rclient, stsOpts, newSts, prevSts); err != nil {
+		return err
+	}
+	return recordComponentStatus(ctx, rclient, cr, component, newSts)
+}
+
+// reconcileComponentDeployment updates Deployment of cluster component and records component status
+// before and after update, since update waits for rollout of pods
+func reconcileComponentDeployment(ctx context.Context, rclient client.Client, cr *vmv1beta1.VMCluster, component string, newDeploy, prevDeploy *appsv1.Deployment, hasHPA bool) error {
+	if err := recordComponentStatus(ctx, rclient, cr, component, newDeploy); err != nil {
+		return err
+	}
+	if err := reconcile.Deployment(ctx, rclient, newDeploy, prevDeploy, hasHPA); err != nil {
+		return err
+	}
+	return recordComponentStatus(ctx, rclient, cr, component, newDeploy)
 }
 
 func buildVMSelectService(cr *vmv1beta1.VMCluster) *corev1.Service {
@@ -300,7 +342,7 @@ func createOrUpdateVMInsert(ctx context.Context, rclient client.Client, cr, prev
 	if err != nil {
 		return err
 	}
-	return reconcile.Deployment(ctx, rclient, newDeployment, prevDeploy, cr.Spec.VMInsert.HPA != nil)
+	return reconcileComponentDeployment(ctx, rclient, cr, componentVMInsert, newDeployment, prevDeploy, cr.Spec.VMInsert.HPA != nil)
 }
 
 func buildVMInsertService(cr *vmv1beta1.VMCluster) *corev1.Service {
@@ -391,7 +433,7 @@ func createOrUpdateVMStorage(ctx context.Context, rclient client.Client, cr, pre
 		HasClaim:       len(newSts.Spec.VolumeClaimTemplates) > 0,
 		SelectorLabels: cr.VMStorageSelectorLabels,
 	}
-	return reconcile.HandleSTSUpdate(ctx, rclient, stsOpts, newSts, prevSts)
+	return reconcileComponentSTS(ctx, rclient, cr, componentVMStorage, stsOpts, newSts, prevSts)
 }
 
 func createOrUpdateVMStorageService(ctx context.Context, rclient client.Client, cr, prevCR *vmv1beta1.VMCluster) (*corev1.Service, error) {
@@ -1601,7 +1643,7 @@ func createOrUpdateVMAuthLB(ctx context.Context, rclient client.Client, cr, prev
 			return fmt.Errorf("cannot build prev deployment for vmauth loadbalancing: %w", err)
 		}
 	}
-	if err := reconcile.Deployment(ctx, rclient, lbDep, prevLB, false); err != nil {
+	if err := reconcileComponentDeployment(ctx, rclient, cr, componentRequestsLoadBalancer, lbDep, prevLB, false); err != nil {
 		return fmt.Errorf("cannot reconcile vmauth lb deployment: %w", err)
 	}
 	if err := createOrUpdateVMAuthLBService(ctx, rclient, cr, prevCR); err != nil {
@@ -0,0 +1,421 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/config"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
+)
+
+var (
+	operatorConfigReloads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "operator_config_reloads_total",
+		Help: "Number of operator configuration reloads from ConfigMap",
+	}, []string{"status"})
+	operatorConfigRolloutReconciles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "operator_config_rollout_reconciles_total",
+		Help: "Number of reconciles triggered by operator configuration changes",
+	}, []string{"controller"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(operatorConfigReloads, operatorConfigRolloutReconciles)
+}
+
+// configKeyControllers defines controllers affected by config keys with the given prefix
+// changes of other keys affect all controllers
+var configKeyControllers = []struct {
+	prefix      string
+	controllers []string
+}{
+	{"VM_VMAGENTDEFAULT_", []string{"vmagent"}},
+	{"VM_VMALERTDEFAULT_", []string{"vmalert"}},
+	{"VM_VMALERTMANAGER_", []string{"vmalertmanager"}},
+	{"VM_VMAUTHDEFAULT_", []string{"vmauth"}},
+	{"VM_VMSINGLEDEFAULT_", []string{"vmsingle"}},
+	{"VM_VMCLUSTERDEFAULT_", []string{"vmcluster"}},
+	{"VM_VLOGSDEFAULT_", []string{"vlogs"}},
+	{"VM_VMBACKUP_", []string{"vmsingle", "vmcluster"}},
+	// applied at the next periodic reconcile
+	{"VM_FORCERESYNCINTERVAL", nil},
+}
+
+// restartRequiredKeyPrefixes defines config keys, which are used only at operator start
+var restartRequiredKeyPrefixes = []string{
+	"VM_ENABLEDPROMETHEUSCONVERTER",
+	"VM_PROMETHEUSCONVERTER",
+	"VM_FILTERPROMETHEUSCONVERTER",
+	"VM_APPREADYTIMEOUT",
+	"VM_PODWAITREADY",
+}
+
+// configReloadTarget defines controller, which reconciles its objects after operator configuration change
+type configReloadTarget struct {
+	ch   chan event.GenericEvent
+	list func(ctx context.Context) ([]client.Object, error)
+}
+
+var (
+	configReloadMu      sync.Mutex
+	configReloadEnabled bool
+	configReloadTargets = map[string]*configReloadTarget{}
+)
+
+func getConfigReloadTarget(controllerName string) *configReloadTarget {
+	configReloadMu.Lock()
+	defer configReloadMu.Unlock()
+	return configReloadTargets[controllerName]
+}
+
+// watchConfigReload enqueues objects of controller, if they're affected by operator configuration change
+// it's no-op if operator configuration ConfigMap is not configured
+func watchConfigReload[T any, PT interface {
+	*T
+	client.ObjectList
+}](b *builder.Builder, rclient client.Client, controllerName string) *builder.Builder {
+	configReloadMu.Lock()
+	defer configReloadMu.Unlock()
+	if !configReloadEnabled {
+		return b
+	}
+	target := &configReloadTarget{
+		ch: make(chan event.GenericEvent),
+		list: func(ctx context.Context) ([]client.Object, error) {
+			var objects []client.Object
+			err := k8stools.ListObjectsByNamespace(ctx, rclient, config.MustGetWatchNamespaces(), func(dst PT) {
+				_ = meta.EachListItem(dst, func(o runtime.Object) error {
+					obj := o.(client.Object)
+					if sharding.IsOwned(client.ObjectKeyFromObject(obj)) {
+						objects = append(objects, obj)
+					}
+					return nil
+				})
+			})
+			return objects, err
+		},
+	}
+	configReloadTargets[controllerName] = target
+	return b.WatchesRawSource(source.Channel(target.ch, &handler.EnqueueRequestForObject{}))
+}
+
+// affectedControllers returns sorted list of controllers affected by the given config keys
+func affectedControllers(keys []string) []string {
+	var controllers []string
+	for _, k := range keys {
+		if isRestartRequiredKey(k) {
+			continue
+		}
+		idx := -1
+		for i, kc := range configKeyControllers {
+			if strings.HasPrefix(k, kc.prefix) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return []string{"vlogs", "vmagent", "vmalert", "vmalertmanager", "vmauth", "vmcluster", "vmsingle"}
+		}
+		controllers = append(controllers, configKeyControllers[idx].controllers...)
+	}
+	slices.Sort(controllers)
+	return slices.Compact(controllers)
+}
+
+func isRestartRequiredKey(key string) bool {
+	return slices.ContainsFunc(restartRequiredKeyPrefixes, func(prefix string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// OperatorConfigWatcher watches ConfigMap with operator configuration and applies it at runtime
+//
+// ConfigMap data uses the same keys as env variables printed by -printDefaults.
+// Values from ConfigMap override values from env variables.
+// Objects affected by configuration change are reconciled with the given rate
+type OperatorConfigWatcher struct {
+	rclient       client.WithWatch
+	nsn           types.NamespacedName
+	reconcileRate float64
+
+	mu                 sync.Mutex
+	lastRejected       string
+	pendingControllers map[string]struct{}
+	cancelRollout      context.CancelFunc
+	wg                 sync.WaitGroup
+	elected            <-chan struct{}
+}
+
+// NewOperatorConfigWatcher returns watcher for ConfigMap with the given namespaced name
+// reconcileRate defines number of affected objects reconciled per second
+//
+// it must be called before controllers setup
+func NewOperatorConfigWatcher(rclient client.WithWatch, nsn types.NamespacedName, reconcileRate float64) (*OperatorConfigWatcher, error) {
+	if nsn.Namespace == "" || nsn.Name == "" {
+		return nil, fmt.Errorf("operator config ConfigMap must be defined in namespace/name format, got: %q", nsn.String())
+	}
+	if reconcileRate <= 0 {
+		return nil, fmt.Errorf("operator config reconcile rate must be greater than 0, got: %f", reconcileRate)
+	}
+	configReloadMu.Lock()
+	configReloadEnabled = true
+	configReloadMu.Unlock()
+	return &OperatorConfigWatcher{
+		rclient:            rclient,
+		nsn:                nsn,
+		reconcileRate:      reconcileRate,
+		pendingControllers: make(map[string]struct{}),
+	}, nil
+}
+
+// Start implements manager.Runnable interface
+func (w *OperatorConfigWatcher) Start(ctx context.Context) error {
+	defer w.wg.Wait()
+	l := logger.WithContext(ctx).WithValues("configmap", w.nsn.String())
+	for {
+		if err := w.watch(ctx); err != nil {
+			l.Error(err, "cannot watch operator config")
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable interface
+// configuration must be applied by each operator replica
+func (w *OperatorConfigWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Sync loads and applies operator configuration from ConfigMap
+// missing ConfigMap resets configuration to the values from env variables
+func (w *OperatorConfigWatcher) Sync(ctx context.Context) error {
+	var cm corev1.ConfigMap
+	if err := w.rclient.Get(ctx, w.nsn, &cm); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("cannot get operator config: %w", err)
+		}
+		w.apply(ctx, nil)
+		return nil
+	}
+	w.apply(ctx, &cm)
+	return nil
+}
+
+func (w *OperatorConfigWatcher) watch(ctx context.Context) error {
+	// changes could be missed during watch restart
+	if err := w.Sync(ctx); err != nil {
+		return err
+	}
+	wi, err := w.rclient.Watch(ctx, &corev1.ConfigMapList{}, client.InNamespace(w.nsn.Namespace), client.MatchingFields{"metadata.name": w.nsn.Name})
+	if err != nil {
+		return fmt.Errorf("cannot start watch for operator config: %w", err)
+	}
+	defer wi.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-wi.ResultChan():
+			if !ok {
+				// watch was closed by API server
+				return nil
+			}
+			switch e.Type {
+			case watch.Added, watch.Modified:
+				if cm, ok := e.Object.(*corev1.ConfigMap); ok {
+					w.apply(ctx, cm)
+				}
+			case watch.Deleted:
+				w.apply(ctx, nil)
+			case watch.Error:
+				return fmt.Errorf("unexpected watch error: %w", k8serrors.FromObject(e.Object))
+			}
+		}
+	}
+}
+
+// apply loads configuration from the given ConfigMap and applies it if it's valid
+// nil ConfigMap resets configuration to the values from env variables
+func (w *OperatorConfigWatcher) apply(ctx context.Context, cm *corev1.ConfigMap) {
+	l := logger.WithContext(ctx).WithValues("configmap", w.nsn.String())
+	var data map[string]string
+	if cm != nil {
+		data = cm.Data
+	}
+	newConf, err := config.LoadFromData(data)
+	if err != nil {
+		if cm == nil {
+			l.Error(err, "BUG: cannot load operator config from env variables")
+			return
+		}
+		w.mu.Lock()
+		isReported := w.lastRejected == cm.ResourceVersion
+		w.lastRejected = cm.ResourceVersion
+		w.mu.Unlock()
+		if isReported {
+			return
+		}
+		operatorConfigReloads.WithLabelValues("failure").Inc()
+		l.Error(err, "rejected invalid operator config")
+		w.createEvent(ctx, cm, corev1.EventTypeWarning, "InvalidOperatorConfig", fmt.Sprintf("operator config is rejected: %s", err))
+		return
+	}
+	changed, err := config.ChangedKeys(config.MustGetBaseConfig(), newConf)
+	if err != nil {
+		l.Error(err, "cannot compare operator configs")
+		return
+	}
+	if len(changed) == 0 {
+		return
+	}
+	config.SetBaseConfig(newConf)
+	vmv1beta1.SetLabelAndAnnotationPrefixes(newConf.FilterChildLabelPrefixes, newConf.FilterChildAnnotationPrefixes)
+	operatorConfigReloads.WithLabelValues("success").Inc()
+
+	msg := fmt.Sprintf("applied operator config, changed keys: %s", strings.Join(changed, ","))
+	if restartKeys := slices.DeleteFunc(slices.Clone(changed), func(k string) bool { return !isRestartRequiredKey(k) }); len(restartKeys) > 0 {
+		msg += fmt.Sprintf("; keys %s are applied only after operator restart", strings.Join(restartKeys, ","))
+	}
+	l.Info(msg)
+	if cm != nil {
+		w.createEvent(ctx, cm, corev1.EventTypeNormal, "OperatorConfigApplied", msg)
+	}
+	if controllers := affectedControllers(changed); len(controllers) > 0 {
+		w.startRollout(ctx, controllers)
+	}
+}
+
+// SetLeaderElected configures channel, which is closed when operator replica becomes a leader
+// configuration is applied by each replica, but events are created only by the leader
+func (w *OperatorConfigWatcher) SetLeaderElected(elected <-chan struct{}) {
+	w.elected = elected
+}
+
+func (w *OperatorConfigWatcher) createEvent(ctx context.Context, cm *corev1.ConfigMap, eventType, reason, message string) {
+	if w.elected != nil {
+		select {
+		case <-w.elected:
+		default:
+			return
+		}
+	}
+	ev := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "victoria-metrics-operator-" + uuid.New().String(),
+			Namespace: cm.Namespace,
+		},
+		Type:    eventType,
+		Reason:  reason,
+		Message: message,
+		Source: corev1.EventSource{
+			Component: "victoria-metrics-operator",
+		},
+		LastTimestamp: metav1.NewTime(time.Now()),
+		InvolvedObject: corev1.ObjectReference{
+			Kind:            "ConfigMap",
+			Namespace:       cm.Namespace,
+			Name:            cm.Name,
+			UID:             cm.UID,
+			ResourceVersion: cm.ResourceVersion,
+		},
+	}
+	if err := w.rclient.Create(ctx, ev); err != nil {
+		logger.WithContext(ctx).Error(err, "cannot create operator config event")
+	}
+}
+
+// startRollout cancels previous rollout and starts reconcile of objects for the given and not yet finished controllers
+func (w *OperatorConfigWatcher) startRollout(ctx context.Context, controllers []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancelRollout != nil {
+		w.cancelRollout()
+	}
+	for _, c := range controllers {
+		w.pendingControllers[c] = struct{}{}
+	}
+	pending := make([]string, 0, len(w.pendingControllers))
+	for c := range w.pendingControllers {
+		pending = append(pending, c)
+	}
+	slices.Sort(pending)
+	rctx, cancel := context.WithCancel(ctx)
+	w.cancelRollout = cancel
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.rollout(rctx, pending)
+	}()
+}
+
+// rollout enqueues objects of the given controllers for reconcile with configured rate
+func (w *OperatorConfigWatcher) rollout(ctx context.Context, controllers []string) {
+	t := time.NewTicker(time.Duration(float64(time.Second) / w.reconcileRate))
+	defer t.Stop()
+	for _, name := range controllers {
+		target := getConfigReloadTarget(name)
+		if target == nil {
+			w.finishRollout(ctx, name)
+			continue
+		}
+		objects, err := target.list(ctx)
+		if err != nil {
+			logger.WithContext(ctx).Error(err, fmt.Sprintf("cannot list %s objects for operator config rollout", name))
+			w.finishRollout(ctx, name)
+			continue
+		}
+		for _, o := range objects {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case target.ch <- event.GenericEvent{Object: o}:
+			}
+			operatorConfigRolloutReconciles.WithLabelValues(name).Inc()
+		}
+		w.finishRollout(ctx, name)
+	}
+}
+
+func (w *OperatorConfigWatcher) finishRollout(ctx context.Context, controllerName string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// cancelled rollout is continued by the next one
+	if ctx.Err() != nil {
+		return
+	}
+	delete(w.pendingControllers, controllerName)
+}
@@ -0,0 +1,136 @@
+package operator
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/config"
+)
+
+func TestAffectedControllers(t *testing.T) {
+	f := func(keys []string, want []string) {
+		t.Helper()
+		got := affectedControllers(keys)
+		if !slices.Equal(got, want) {
+			t.Fatalf("unexpected controllers, got: %v, want: %v", got, want)
+		}
+	}
+	f(nil, nil)
+	f([]string{"VM_VMAGENTDEFAULT_VERSION"}, []string{"vmagent"})
+	f([]string{"VM_VMCLUSTERDEFAULT_VMSTORAGEDEFAULT_VERSION", "VM_VMBACKUP_VERSION"}, []string{"vmcluster", "vmsingle"})
+	// restart is required or applied at periodic reconcile
+	f([]string{"VM_ENABLEDPROMETHEUSCONVERTER_PODMONITOR", "VM_APPREADYTIMEOUT", "VM_FORCERESYNCINTERVAL"}, nil)
+	// global keys
+	f([]string{"VM_VMAGENTDEFAULT_VERSION", "VM_CONTAINERREGISTRY"}, []string{"vlogs", "vmagent", "vmalert", "vmalertmanager", "vmauth", "vmcluster", "vmsingle"})
+}
+
+func TestOperatorConfigWatcherApply(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	envConf := config.MustGetBaseConfig()
+	defer func() {
+		cancel()
+		config.SetBaseConfig(envConf)
+		vmv1beta1.SetLabelAndAnnotationPrefixes(envConf.FilterChildLabelPrefixes, envConf.FilterChildAnnotationPrefixes)
+	}()
+	nsn := types.NamespacedName{Namespace: "vm", Name: "vm-operator-config"}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: nsn.Name, Namespace: nsn.Namespace},
+		Data:       map[string]string{"VM_VMAGENTDEFAULT_VERSION": "v1.110.0"},
+	}
+	vmagent := &vmv1beta1.VMAgent{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"}}
+	fclient := fake.NewClientBuilder().WithObjects(cm).Build()
+
+	target := &configReloadTarget{
+		ch: make(chan event.GenericEvent),
+		list: func(_ context.Context) ([]client.Object, error) {
+			return []client.Object{vmagent}, nil
+		},
+	}
+	configReloadMu.Lock()
+	configReloadTargets["vmagent"] = target
+	configReloadMu.Unlock()
+	defer func() {
+		configReloadMu.Lock()
+		delete(configReloadTargets, "vmagent")
+		configReloadMu.Unlock()
+	}()
+
+	w := &OperatorConfigWatcher{
+		rclient:            fclient,
+		nsn:                nsn,
+		reconcileRate:      1000,
+		pendingControllers: make(map[string]struct{}),
+	}
+	hasEvent := func(reason string, want int) {
+		t.Helper()
+		var events corev1.EventList
+		if err := fclient.List(ctx, &events, client.InNamespace(nsn.Namespace)); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		var got int
+		for _, ev := range events.Items {
+			if ev.Reason == reason && ev.InvolvedObject.Name == nsn.Name {
+				got++
+			}
+		}
+		if got != want {
+			t.Fatalf("unexpected number of %s events, got: %d, want: %d", reason, got, want)
+		}
+	}
+
+	// valid config is applied and affected objects are reconciled
+	if err := w.Sync(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := config.MustGetBaseConfig().VMAgentDefault.Version; got != "v1.110.0" {
+		t.Fatalf("unexpected vmagent version: %s", got)
+	}
+	select {
+	case e := <-target.ch:
+		if e.Object.GetName() != vmagent.Name {
+			t.Fatalf("unexpected object enqueued: %s", e.Object.GetName())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("vmagent wasn't enqueued for reconcile")
+	}
+	hasEvent("OperatorConfigApplied", 1)
+
+	// invalid config is rejected once
+	if err := fclient.Get(ctx, nsn, cm); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cm.Data = map[string]string{"VM_VMAGENTDEFAULT_VERSION": "v1.111.0", "VM_FORCERESYNCINTERVAL": "1 minute"}
+	if err := fclient.Update(ctx, cm); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for range 2 {
+		if err := w.Sync(ctx); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if got := config.MustGetBaseConfig().VMAgentDefault.Version; got != "v1.110.0" {
+		t.Fatalf("invalid config must not be applied, got vmagent version: %s", got)
+	}
+	hasEvent("InvalidOperatorConfig", 1)
+
+	// config is reset to env values after ConfigMap removal
+	if err := fclient.Delete(ctx, cm); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := w.Sync(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := config.MustGetBaseConfig().VMAgentDefault.Version; got != envConf.VMAgentDefault.Version {
+		t.Fatalf("unexpected vmagent version after reset: %s", got)
+	}
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/finalize"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -67,6 +68,9 @@ func (r *VLogsReconciler) Scheme() *runtime.Scheme {
 func (r *VLogsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	reqLogger := r.Log.WithValues("vlogs", req.Name, "namespace", req.Namespace)
 	ctx = logger.AddToContext(ctx, reqLogger)
+	if sharding.MustSkipReconcile("vlogs", req.NamespacedName) {
+		return
+	}
 	instance := &vmv1beta1.VLogs{}
 
 	defer func() {
@@ -92,7 +96,7 @@ func (r *VLogsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resu
 	}
 	r.Client.Scheme().Default(instance)
 
-	result, err = reconcileAndTrackStatus(ctx, r.Client, instance.DeepCopy(), func() (ctrl.Result, error) {
+	result, err = reconcileAndTrackStatus(ctx, r.Client, instance.DeepCopy(), func(ctx context.Context) (ctrl.Result, error) {
 
 		if err = vlogs.CreateOrUpdateVLogs(ctx, r, instance); err != nil {
 			return result, fmt.Errorf("failed create or update vlogs: %w", err)
@@ -101,17 +105,19 @@ func (r *VLogsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resu
 		return result, nil
 	})
 
-	result.RequeueAfter = r.BaseConf.ResyncAfterDuration()
+	result = withResyncInterval(result)
 
 	return
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *VLogsReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&vmv1beta1.VLogs{}).
 		Owns(&appsv1.Deployment{}).
-		Owns(&corev1.ServiceAccount{}).
+		Owns(&corev1.ServiceAccount{})
+	b = watchShardRebalance[vmv1beta1.VLogsList](b, r.Client, "vlogs")
+	return watchConfigReload[vmv1beta1.VLogsList](b, r.Client, "vlogs").
 		WithOptions(getDefaultOptions()).
 		Complete(r)
 }
@@ -23,21 +23,25 @@ import (
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	"github.com/VictoriaMetrics/operator/internal/config"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/finalize"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/limiter"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/vmagent"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 var (
 	vmAgentSync           sync.Mutex
-	vmAgentReconcileLimit = limiter.NewRateLimiter("vmagent", 5)
+	vmAgentReconcileLimit = limiter.NewRateLimiter("vmagent", limiter.DefaultConfig)
 )
 
 // VMAgentReconciler reconciles a VMAgent object
@@ -80,6 +84,9 @@ func (r *VMAgentReconciler) Init(rclient client.Client, l logr.Logger, sc *runti
 func (r *VMAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	reqLogger := r.Log.WithValues("vmagent", req.Name, "namespace", req.Namespace)
 	ctx = logger.AddToContext(ctx, reqLogger)
+	if sharding.MustSkipReconcile("vmagent", req.NamespacedName) {
+		return
+	}
 	instance := &vmv1beta1.VMAgent{}
 
 	defer func() {
@@ -99,6 +106,7 @@ func (r *VMAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		if err := finalize.OnVMAgentDelete(ctx, r.Client, instance); err != nil {
 			return result, err
 		}
+		vmagent.DropConfigCache(instance)
 		return
 	}
 
@@ -111,8 +119,14 @@ func (r *VMAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 	}
 	r.Client.Scheme().Default(instance)
 
-	result, err = reconcileAndTrackStatus(ctx, r.Client, instance.DeepCopy(), func() (ctrl.Result, error) {
-		if err = vmagent.CreateOrUpdateVMAgent(ctx, instance, r); err != nil {
+	trackedInstance := instance.DeepCopy()
+	result, err = reconcileAndTrackStatus(ctx, r.Client, trackedInstance, func(ctx context.Context) (ctrl.Result, error) {
+		err := vmagent.CreateOrUpdateVMAgent(ctx, instance, r)
+		// config canary state is updated during reconcile
+		// and it must not be overwritten by status update
+		trackedInstance.Status.ConfigCanary = instance.Status.ConfigCanary
+		trackedInstance.Status.Conditions = instance.Status.Conditions
+		if err != nil {
 			return result, err
 		}
 
@@ -121,7 +135,10 @@ func (r *VMAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 	if err != nil {
 		return
 	}
-	result.RequeueAfter = r.BaseConf.ResyncAfterDuration()
+	result = withResyncInterval(result)
+	if canaryRequeue := vmagent.ConfigCanaryRequeueAfter(instance); canaryRequeue > 0 && (result.RequeueAfter == 0 || canaryRequeue < result.RequeueAfter) {
+		result.RequeueAfter = canaryRequeue
+	}
 
 	return
 }
@@ -133,11 +150,34 @@ func (r *VMAgentReconciler) Scheme() *runtime.Scheme {
 
 // SetupWithManager general setup method
 func (r *VMAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&vmv1beta1.VMAgent{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&v1.ServiceAccount{}).
+		WatchesRawSource(vmAgentReconcileLimit.Source())
+	b = watchShardRebalance[vmv1beta1.VMAgentList](b, r.Client, "vmagent")
+	b = watchConfigReload[vmv1beta1.VMAgentList](b, r.Client, "vmagent")
+	return watchRemoteTargets(b, r.findRemoteTargetRefs).
 		WithOptions(getDefaultOptions()).
 		Complete(r)
 }
+
+// findRemoteTargetRefs returns requests for VMAgents, which reference given object at remoteWrite targetRef
+func (r *VMAgentReconciler) findRemoteTargetRefs(ctx context.Context, kind string, obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+	if err := k8stools.ListObjectsByNamespace(ctx, r.Client, config.MustGetWatchNamespaces(), func(dst *vmv1beta1.VMAgentList) {
+		for _, item := range dst.Items {
+			for _, rw := range item.Spec.RemoteWrite {
+				if isRemoteTargetRefMatch(rw.TargetRef, item.Namespace, kind, obj) {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: item.Name, Namespace: item.Namespace}})
+					break
+				}
+			}
+		}
+	}); err != nil {
+		r.Log.Error(err, "cannot list vmagents for remote target", "kind", kind, "name", obj.GetName(), "namespace", obj.GetNamespace())
+		return nil
+	}
+	return requests
+}
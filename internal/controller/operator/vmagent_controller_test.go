@@ -18,16 +18,23 @@ package operator
 
 import (
 	"context"
+	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
 )
 
 var _ = Describe("VMAgent Controller", func() {
@@ -82,3 +89,67 @@ var _ = Describe("VMAgent Controller", func() {
 		})
 	})
 })
+
+func TestVMAgentReconcileConfigCanary(t *testing.T) {
+	ctx := context.Background()
+	nsn := types.NamespacedName{Name: "agent", Namespace: "default"}
+	cr := &vmv1beta1.VMAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: nsn.Name, Namespace: nsn.Namespace},
+		Spec: vmv1beta1.VMAgentSpec{
+			ShardCount: ptr.To(2),
+			ConfigCanary: &vmv1beta1.VMAgentConfigCanary{
+				ShardNum:     ptr.To(1),
+				SoakDuration: "1h",
+			},
+			// targetRef is resolved into a copy of reconciled object
+			RemoteWrite: []vmv1beta1.VMAgentRemoteWriteSpec{
+				{TargetRef: &vmv1beta1.RemoteTargetRef{Kind: "VMSingle", Name: "main"}},
+			},
+		},
+	}
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{
+		cr,
+		&vmv1beta1.VMSingle{ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: nsn.Namespace}},
+		k8stools.NewReadyDeployment("vmagent-agent-0", nsn.Namespace),
+		k8stools.NewReadyDeployment("vmagent-agent-1", nsn.Namespace),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: cr.PrefixedName(), Namespace: nsn.Namespace},
+			Data:       map[string][]byte{"vmagent.yaml.gz": []byte("stable")},
+		},
+	})
+	r := &VMAgentReconciler{Client: fclient, OriginScheme: fclient.Scheme()}
+
+	getCanaryStatus := func() *vmv1beta1.VMAgentConfigCanaryStatus {
+		t.Helper()
+		var got vmv1beta1.VMAgent
+		assert.NoError(t, fclient.Get(ctx, nsn, &got))
+		assert.NotNil(t, got.Status.ConfigCanary)
+		return got.Status.ConfigCanary
+	}
+	countCanaryEvents := func() int {
+		t.Helper()
+		var events corev1.EventList
+		assert.NoError(t, fclient.List(ctx, &events))
+		var n int
+		for _, ev := range events.Items {
+			if ev.Reason == "ConfigCanary"+string(vmv1beta1.ConfigCanaryInProgress) {
+				n++
+			}
+		}
+		return n
+	}
+
+	result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: nsn})
+	assert.NoError(t, err)
+	assert.Greater(t, result.RequeueAfter, time.Duration(0))
+	started := getCanaryStatus()
+	assert.Equal(t, vmv1beta1.ConfigCanaryInProgress, started.Phase)
+	assert.Equal(t, 1, countCanaryEvents())
+
+	// soak must not be restarted by the next reconcile
+	result, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: nsn})
+	assert.NoError(t, err)
+	assert.Greater(t, result.RequeueAfter, time.Duration(0))
+	assert.Equal(t, started, getCanaryStatus())
+	assert.Equal(t, 1, countCanaryEvents())
+}
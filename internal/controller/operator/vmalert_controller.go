@@ -23,18 +23,25 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	"github.com/VictoriaMetrics/operator/internal/config"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/finalize"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/limiter"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/vmalert"
 )
 
-var vmAlertRateLimiter = limiter.NewRateLimiter("vmalert", 5)
+var vmAlertRateLimiter = limiter.NewRateLimiter("vmalert", limiter.DefaultConfig)
 
 // VMAlertReconciler reconciles a VMAlert object
 type VMAlertReconciler struct {
@@ -64,6 +71,9 @@ func (r *VMAlertReconciler) Scheme() *runtime.Scheme {
 func (r *VMAlertReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, resultErr error) {
 	reqLogger := r.Log.WithValues("vmalert", req.Name, "namespace", req.Namespace)
 	ctx = logger.AddToContext(ctx, reqLogger)
+	if sharding.MustSkipReconcile("vmalert", req.NamespacedName) {
+		return
+	}
 	instance := &vmv1beta1.VMAlert{}
 
 	defer func() {
@@ -92,7 +102,12 @@ func (r *VMAlertReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 	}
 	r.Client.Scheme().Default(instance)
 
-	result, resultErr = reconcileAndTrackStatus(ctx, r.Client, instance.DeepCopy(), func() (ctrl.Result, error) {
+	trackedInstance := instance.DeepCopy()
+	result, resultErr = reconcileAndTrackStatus(ctx, r.Client, trackedInstance, func(ctx context.Context) (ctrl.Result, error) {
+		// state persistence status is patched during reconcile, keep it in order to not overwrite with the previous value
+		defer func() {
+			trackedInstance.Status.StatePersistence = instance.Status.StatePersistence
+		}()
 		maps, err := vmalert.CreateOrUpdateRuleConfigMaps(ctx, r, instance, nil)
 		if err != nil {
 			return result, err
@@ -106,16 +121,51 @@ func (r *VMAlertReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 	if resultErr != nil {
 		return
 	}
-	result.RequeueAfter = r.BaseConf.ResyncAfterDuration()
+	result = withResyncInterval(result)
 	return
 }
 
+// vmAlertLookbackChanges receives VMAlerts with alerts state lookback changed by VMRule controller
+// lookback is rendered into vmalert deployment args, so VMAlert must be reconciled in order to apply it
+var vmAlertLookbackChanges = make(chan event.GenericEvent, 128)
+
 // SetupWithManager general setup method
 func (r *VMAlertReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&vmv1beta1.VMAlert{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&v1.ServiceAccount{}).
+		WatchesRawSource(source.Channel(vmAlertLookbackChanges, &handler.EnqueueRequestForObject{})).
+		WatchesRawSource(vmAlertRateLimiter.Source())
+	b = watchShardRebalance[vmv1beta1.VMAlertList](b, r.Client, "vmalert")
+	b = watchConfigReload[vmv1beta1.VMAlertList](b, r.Client, "vmalert")
+	return watchRemoteTargets(b, r.findRemoteTargetRefs).
 		WithOptions(getDefaultOptions()).
 		Complete(r)
 }
+
+// findRemoteTargetRefs returns requests for VMAlerts, which reference given object at datasource, remoteWrite or remoteRead targetRef
+func (r *VMAlertReconciler) findRemoteTargetRefs(ctx context.Context, kind string, obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+	if err := k8stools.ListObjectsByNamespace(ctx, r.Client, config.MustGetWatchNamespaces(), func(dst *vmv1beta1.VMAlertList) {
+		for _, item := range dst.Items {
+			refs := []*vmv1beta1.RemoteTargetRef{item.Spec.Datasource.TargetRef}
+			if item.Spec.RemoteWrite != nil {
+				refs = append(refs, item.Spec.RemoteWrite.TargetRef)
+			}
+			if item.Spec.RemoteRead != nil {
+				refs = append(refs, item.Spec.RemoteRead.TargetRef)
+			}
+			for _, ref := range refs {
+				if isRemoteTargetRefMatch(ref, item.Namespace, kind, obj) {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: item.Name, Namespace: item.Namespace}})
+					break
+				}
+			}
+		}
+	}); err != nil {
+		r.Log.Error(err, "cannot list vmalerts for remote target", "kind", kind, "name", obj.GetName(), "namespace", obj.GetNamespace())
+		return nil
+	}
+	return requests
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/alertmanager"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/finalize"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
 )
 
 // VMAlertmanagerReconciler reconciles a VMAlertmanager object
@@ -64,6 +65,9 @@ func (r *VMAlertmanagerReconciler) Scheme() *runtime.Scheme {
 func (r *VMAlertmanagerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	reqLogger := r.Log.WithValues("vmalertmanager", req.Name, "namespace", req.Namespace)
 	ctx = logger.AddToContext(ctx, reqLogger)
+	if sharding.MustSkipReconcile("vmalertmanager", req.NamespacedName) {
+		return
+	}
 	instance := &vmv1beta1.VMAlertmanager{}
 
 	defer func() {
@@ -89,12 +93,18 @@ func (r *VMAlertmanagerReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}
 	r.Client.Scheme().Default(instance)
 
-	result, err = reconcileAndTrackStatus(ctx, r.Client, instance.DeepCopy(), func() (ctrl.Result, error) {
+	trackedInstance := instance.DeepCopy()
+	result, err = reconcileAndTrackStatus(ctx, r.Client, trackedInstance, func(ctx context.Context) (ctrl.Result, error) {
 		if err := alertmanager.CreateOrUpdateConfig(ctx, r.Client, instance, nil); err != nil {
 			return result, err
 		}
+		// templates validation result is reported at conditions
+		trackedInstance.Status.Conditions = instance.Status.Conditions
 
-		if err := alertmanager.CreateOrUpdateAlertManager(ctx, instance, r); err != nil {
+		err := alertmanager.CreateOrUpdateAlertManager(ctx, instance, r)
+		// peers status is patched during reconcile, keep it in order to not overwrite with the previous value
+		trackedInstance.Status.Peers = instance.Status.Peers
+		if err != nil {
 			return result, err
 		}
 
@@ -104,16 +114,22 @@ func (r *VMAlertmanagerReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return
 	}
 
-	result.RequeueAfter = r.BaseConf.ResyncAfterDuration()
+	result = withResyncInterval(result)
+	if instance.Spec.PeerDiscovery != nil && (result.RequeueAfter == 0 || result.RequeueAfter > alertmanager.PeerDiscoveryInterval) {
+		result.RequeueAfter = alertmanager.PeerDiscoveryInterval
+	}
 	return
 }
 
 // SetupWithManager general setup method
 func (r *VMAlertmanagerReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&vmv1beta1.VMAlertmanager{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&v1.ServiceAccount{}).
+		WatchesRawSource(vmaConfigRateLimiter.Source())
+	b = watchShardRebalance[vmv1beta1.VMAlertmanagerList](b, r.Client, "vmalertmanager")
+	return watchConfigReload[vmv1beta1.VMAlertmanagerList](b, r.Client, "vmalertmanager").
 		WithOptions(getDefaultOptions()).
 		Complete(r)
 }
@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
@@ -32,9 +33,14 @@ import (
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/limiter"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
 )
 
-var vmaConfigRateLimiter = limiter.NewRateLimiter("vmalertmanager", 5)
+var vmaConfigRateLimiter = limiter.NewRateLimiter("vmalertmanager", limiter.DefaultConfig)
+
+// receiverTestRateLimiter limits test notifications sent to external services
+// it allows 1 test per 10 seconds for a single VMAlertmanagerConfig
+var receiverTestRateLimiter = limiter.NewRateLimiter("vmalertmanagerconfig_receiver_test", limiter.Config{Rate: 0.5, Burst: 2, ObjectRate: 0.1, ObjectBurst: 1})
 
 // VMAlertmanagerConfigReconciler reconciles a VMAlertmanagerConfig object
 type VMAlertmanagerConfigReconciler struct {
@@ -74,8 +80,12 @@ func (r *VMAlertmanagerConfigReconciler) Reconcile(ctx context.Context, req ctrl
 
 	RegisterObjectStat(&instance, "vmalertmanagerconfig")
 
-	if vmaConfigRateLimiter.MustThrottleReconcile() {
-		return
+	if alertmanager.IsReceiverTestRequested(&instance) {
+		if requeueAfter := receiverTestRateLimiter.ReconcileDelay(&instance); requeueAfter > 0 {
+			result.RequeueAfter = requeueAfter
+		} else if err := alertmanager.TestReceiver(ctx, r.Client, &instance); err != nil {
+			return result, fmt.Errorf("cannot test receiver: %w", err)
+		}
 	}
 
 	var objects vmv1beta1.VMAlertmanagerList
@@ -87,7 +97,7 @@ func (r *VMAlertmanagerConfigReconciler) Reconcile(ctx context.Context, req ctrl
 
 	for _, item := range objects.Items {
 		am := &item
-		if !am.DeletionTimestamp.IsZero() || am.Spec.ParsingError != "" || am.IsUnmanaged() {
+		if !am.DeletionTimestamp.IsZero() || am.Spec.ParsingError != "" || am.IsUnmanaged() || !sharding.IsOwned(client.ObjectKeyFromObject(am)) {
 			continue
 		}
 
@@ -106,6 +116,9 @@ func (r *VMAlertmanagerConfigReconciler) Reconcile(ctx context.Context, req ctrl
 				continue
 			}
 		}
+		if vmaConfigRateLimiter.ReconcileDelay(am) > 0 {
+			continue
+		}
 		if err := alertmanager.CreateOrUpdateConfig(ctx, r.Client, am, &instance); err != nil {
 			continue
 		}
@@ -117,7 +130,17 @@ func (r *VMAlertmanagerConfigReconciler) Reconcile(ctx context.Context, req ctrl
 func (r *VMAlertmanagerConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&vmv1beta1.VMAlertmanagerConfig{}).
-		WithEventFilter(predicate.TypedGenerationChangedPredicate[client.Object]{}).
+		WithEventFilter(predicate.Or[client.Object](predicate.GenerationChangedPredicate{}, receiverTestAnnotationChanged)).
 		WithOptions(getDefaultOptions()).
 		Complete(r)
 }
+
+// receiverTestAnnotationChanged triggers reconcile on changes of ReceiverTestAnnotation
+var receiverTestAnnotationChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.ObjectOld == nil || e.ObjectNew == nil {
+			return false
+		}
+		return e.ObjectOld.GetAnnotations()[vmv1beta1.ReceiverTestAnnotation] != e.ObjectNew.GetAnnotations()[vmv1beta1.ReceiverTestAnnotation]
+	},
+}
@@ -0,0 +1,124 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/config"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/alertmanager"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/finalize"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
+)
+
+// VMAlertmanagerSilenceReconciler reconciles a VMAlertmanagerSilence object
+type VMAlertmanagerSilenceReconciler struct {
+	client.Client
+	Log          logr.Logger
+	OriginScheme *runtime.Scheme
+}
+
+// Init implements crdController interface
+func (r *VMAlertmanagerSilenceReconciler) Init(rclient client.Client, l logr.Logger, sc *runtime.Scheme, cf *config.BaseOperatorConf) {
+	r.Client = rclient
+	r.Log = l.WithName("controller.VMAlertmanagerSilence")
+	r.OriginScheme = sc
+}
+
+// Scheme implements interface.
+func (r *VMAlertmanagerSilenceReconciler) Scheme() *runtime.Scheme {
+	return r.OriginScheme
+}
+
+// Reconcile implements interface
+// +kubebuilder:rbac:groups=operator.victoriametrics.com,resources=vmalertmanagersilences,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=operator.victoriametrics.com,resources=vmalertmanagersilences/status,verbs=get;update;patch
+func (r *VMAlertmanagerSilenceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, resultErr error) {
+	l := r.Log.WithValues("vmalertmanagersilence", req.Name, "namespace", req.Namespace)
+	ctx = logger.AddToContext(ctx, l)
+	defer func() {
+		result, resultErr = handleReconcileErr(ctx, r.Client, nil, result, resultErr)
+	}()
+
+	var instance vmv1beta1.VMAlertmanagerSilence
+	if err := r.Get(ctx, req.NamespacedName, &instance); err != nil {
+		return result, &getError{err, "vmalertmanagersilence", req}
+	}
+
+	RegisterObjectStat(&instance, "vmalertmanagersilence")
+
+	if !instance.DeletionTimestamp.IsZero() {
+		if err := alertmanager.DeleteSilence(ctx, r.Client, &instance); err != nil {
+			return result, fmt.Errorf("cannot expire silences of vmalertmanagersilence: %w", err)
+		}
+		if err := finalize.RemoveFinalizer(ctx, r.Client, &instance); err != nil {
+			return result, fmt.Errorf("cannot remove finalizer for vmalertmanagersilence: %w", err)
+		}
+		return
+	}
+	if err := finalize.AddFinalizer(ctx, r.Client, &instance); err != nil {
+		return result, err
+	}
+
+	var objects vmv1beta1.VMAlertmanagerList
+	if err := k8stools.ListObjectsByNamespace(ctx, r.Client, config.MustGetWatchNamespaces(), func(dst *vmv1beta1.VMAlertmanagerList) {
+		objects.Items = append(objects.Items, dst.Items...)
+	}); err != nil {
+		return result, fmt.Errorf("cannot list vmalertmanagers for vmalertmanagersilence: %w", err)
+	}
+
+	// silences are selected by the same selectors as VMAlertmanagerConfig
+	var selected []*vmv1beta1.VMAlertmanager
+	for i := range objects.Items {
+		am := &objects.Items[i]
+		if !am.DeletionTimestamp.IsZero() || am.Spec.ParsingError != "" || am.IsUnmanaged() || !sharding.IsOwned(client.ObjectKeyFromObject(am)) {
+			continue
+		}
+		match, err := isSelectorsMatchesTargetCRD(ctx, r.Client, &instance, am, am.Spec.ConfigSelector, am.Spec.ConfigNamespaceSelector, am.Spec.SelectAllByDefault)
+		if err != nil {
+			l.Error(err, "cannot match alertmanager against selector, probably bug", "vmalertmanager", am.Name, "parent_namespace", am.Namespace)
+			continue
+		}
+		if match {
+			selected = append(selected, am)
+		}
+	}
+	if err := alertmanager.SyncSilence(ctx, r.Client, &instance, selected); err != nil {
+		return result, fmt.Errorf("cannot sync vmalertmanagersilence: %w", err)
+	}
+	result.RequeueAfter = alertmanager.SilenceRequeueAfter(&instance)
+	return
+}
+
+// SetupWithManager configures reconcile
+func (r *VMAlertmanagerSilenceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&vmv1beta1.VMAlertmanagerSilence{}).
+		WithEventFilter(predicate.TypedGenerationChangedPredicate[client.Object]{}).
+		WithOptions(getDefaultOptions()).
+		Complete(r)
+}
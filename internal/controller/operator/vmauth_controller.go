@@ -24,6 +24,7 @@ import (
 	"github.com/VictoriaMetrics/operator/internal/config"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/finalize"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/vmauth"
 
 	"github.com/go-logr/logr"
@@ -61,6 +62,9 @@ func (r *VMAuthReconciler) Scheme() *runtime.Scheme {
 func (r *VMAuthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	l := r.Log.WithValues("vmauth", req.Name, "namespace", req.Namespace)
 	ctx = logger.AddToContext(ctx, l)
+	if sharding.MustSkipReconcile("vmauth", req.NamespacedName) {
+		return
+	}
 	instance := &vmv1beta1.VMAuth{}
 
 	defer func() {
@@ -88,7 +92,7 @@ func (r *VMAuthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 	}
 	r.Client.Scheme().Default(instance)
 
-	result, err = reconcileAndTrackStatus(ctx, r.Client, instance.DeepCopy(), func() (ctrl.Result, error) {
+	result, err = reconcileAndTrackStatus(ctx, r.Client, instance.DeepCopy(), func(ctx context.Context) (ctrl.Result, error) {
 		if err := vmauth.CreateOrUpdateVMAuth(ctx, instance, r); err != nil {
 			return result, fmt.Errorf("cannot create or update vmauth deploy: %w", err)
 		}
@@ -98,17 +102,20 @@ func (r *VMAuthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 	if err != nil {
 		return
 	}
-	result.RequeueAfter = r.BaseConf.ResyncAfterDuration()
+	result = withResyncInterval(result)
 
 	return
 }
 
 // SetupWithManager inits object.
 func (r *VMAuthReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&vmv1beta1.VMAuth{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.ServiceAccount{}).
+		WatchesRawSource(vmauthRateLimiter.Source())
+	b = watchShardRebalance[vmv1beta1.VMAuthList](b, r.Client, "vmauth")
+	return watchConfigReload[vmv1beta1.VMAuthList](b, r.Client, "vmauth").
 		WithOptions(getDefaultOptions()).
 		Complete(r)
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/VictoriaMetrics/operator/internal/config"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/finalize"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/vmcluster"
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
@@ -45,6 +46,9 @@ func (r *VMClusterReconciler) Scheme() *runtime.Scheme {
 func (r *VMClusterReconciler) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
 	reqLogger := r.Log.WithValues("vmcluster", request.Name, "namespace", request.Namespace)
 	ctx = logger.AddToContext(ctx, reqLogger)
+	if sharding.MustSkipReconcile("vmcluster", request.NamespacedName) {
+		return
+	}
 	instance := &vmv1beta1.VMCluster{}
 
 	defer func() {
@@ -71,8 +75,15 @@ func (r *VMClusterReconciler) Reconcile(ctx context.Context, request ctrl.Reques
 	}
 	r.Client.Scheme().Default(instance)
 
-	result, err = reconcileAndTrackStatus(ctx, r.Client, instance.DeepCopy(), func() (ctrl.Result, error) {
+	trackedInstance := instance.DeepCopy()
+	result, err = reconcileAndTrackStatus(ctx, r.Client, trackedInstance, func(ctx context.Context) (ctrl.Result, error) {
 		err = vmcluster.CreateOrUpdateVMCluster(ctx, instance, r.Client)
+		if statusErr := vmcluster.UpdateComponentsStatus(ctx, r.Client, instance, err); statusErr != nil {
+			logger.WithContext(ctx).Error(statusErr, "cannot update components status")
+		}
+		// components status is updated during reconcile
+		// and it must not be overwritten by status update
+		vmcluster.CopyComponentsStatus(trackedInstance, instance)
 		if err != nil {
 			return result, fmt.Errorf("failed create or update vmcluster: %w", err)
 		}
@@ -82,16 +93,18 @@ func (r *VMClusterReconciler) Reconcile(ctx context.Context, request ctrl.Reques
 		return
 	}
 
-	result.RequeueAfter = r.BaseConf.ResyncAfterDuration()
+	result = withResyncInterval(result)
 	return
 }
 
 // SetupWithManager general setup method
 func (r *VMClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&vmv1beta1.VMCluster{}).
 		Owns(&appsv1.Deployment{}).
-		Owns(&appsv1.StatefulSet{}).
+		Owns(&appsv1.StatefulSet{})
+	b = watchShardRebalance[vmv1beta1.VMClusterList](b, r.Client, "vmcluster")
+	return watchConfigReload[vmv1beta1.VMClusterList](b, r.Client, "vmcluster").
 		WithOptions(getDefaultOptions()).
 		Complete(r)
 }
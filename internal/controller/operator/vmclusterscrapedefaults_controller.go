@@ -0,0 +1,105 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/config"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+)
+
+// VMClusterScrapeDefaultsReconciler reconciles a VMClusterScrapeDefaults object
+type VMClusterScrapeDefaultsReconciler struct {
+	client.Client
+	Log          logr.Logger
+	OriginScheme *runtime.Scheme
+}
+
+// Init implements crdController interface
+func (r *VMClusterScrapeDefaultsReconciler) Init(rclient client.Client, l logr.Logger, sc *runtime.Scheme, cf *config.BaseOperatorConf) {
+	r.Client = rclient
+	r.Log = l.WithName("controller.VMClusterScrapeDefaults")
+	r.OriginScheme = sc
+}
+
+// Scheme implements interface.
+func (r *VMClusterScrapeDefaultsReconciler) Scheme() *runtime.Scheme {
+	return r.OriginScheme
+}
+
+// Reconcile general reconcile method for controller
+// +kubebuilder:rbac:groups=operator.victoriametrics.com,resources=vmclusterscrapedefaults,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=operator.victoriametrics.com,resources=vmclusterscrapedefaults/status,verbs=get;update;patch
+func (r *VMClusterScrapeDefaultsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	reqLogger := r.Log.WithValues("vmclusterscrapedefaults", req.Name)
+	ctx = logger.AddToContext(ctx, reqLogger)
+
+	defer func() {
+		result, err = handleReconcileErr(ctx, r.Client, nil, result, err)
+	}()
+	// Fetch the VMClusterScrapeDefaults instance
+	instance := &vmv1beta1.VMClusterScrapeDefaults{}
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		return result, &getError{err, "vmclusterscrapedefaults", req}
+	}
+
+	RegisterObjectStat(instance, "vmclusterscrapedefaults")
+
+	if err := reconcileVMAgentsForScrapeDefaults(ctx, r.Client, instance); err != nil {
+		return result, err
+	}
+	return
+}
+
+// SetupWithManager general setup method
+func (r *VMClusterScrapeDefaultsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if len(config.MustGetWatchNamespaces()) > 0 {
+		// cluster-scoped objects cannot be watched without cluster-wide permissions
+		r.Log.Info("controller is disabled, since operator watches only a subset of namespaces")
+		return nil
+	}
+	b := ctrl.NewControllerManagedBy(mgr).
+		// namespace events are filtered by watchNamespaceLabels
+		For(&vmv1beta1.VMClusterScrapeDefaults{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(getDefaultOptions())
+	return watchNamespaceLabels(b, func(ctx context.Context, oldLabels, newLabels labels.Set) []reconcile.Request {
+		var requests []reconcile.Request
+		var list vmv1beta1.VMClusterScrapeDefaultsList
+		if err := r.List(ctx, &list); err != nil {
+			logger.WithContext(ctx).Error(err, "cannot list vmclusterscrapedefaults for namespace labels change")
+			return nil
+		}
+		for i := range list.Items {
+			item := &list.Items[i]
+			if isNamespaceSelectorChanged(item.Spec.NamespaceSelector, oldLabels, newLabels) {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(item)})
+			}
+		}
+		return requests
+	}).Complete(r)
+}
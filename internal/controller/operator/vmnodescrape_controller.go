@@ -30,6 +30,7 @@ import (
 	"github.com/VictoriaMetrics/operator/internal/config"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/vmagent"
 )
 
@@ -71,11 +72,6 @@ func (r *VMNodeScrapeReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	RegisterObjectStat(instance, "vmnodescrape")
 
-	if vmAgentReconcileLimit.MustThrottleReconcile() {
-		// fast path, rate limited
-		return
-	}
-
 	vmAgentSync.Lock()
 	defer vmAgentSync.Unlock()
 
@@ -87,7 +83,7 @@ func (r *VMNodeScrapeReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	for _, vmagentItem := range objects.Items {
-		if !vmagentItem.DeletionTimestamp.IsZero() || vmagentItem.Spec.ParsingError != "" || vmagentItem.IsNodeScrapeUnmanaged() {
+		if !vmagentItem.DeletionTimestamp.IsZero() || vmagentItem.Spec.ParsingError != "" || vmagentItem.IsNodeScrapeUnmanaged() || !sharding.IsOwned(client.ObjectKeyFromObject(&vmagentItem)) {
 			continue
 		}
 		currentVMagent := &vmagentItem
@@ -109,6 +105,9 @@ func (r *VMNodeScrapeReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			}
 		}
 
+		if vmAgentReconcileLimit.ReconcileDelay(currentVMagent) > 0 {
+			continue
+		}
 		if err := vmagent.CreateOrUpdateConfigurationSecret(ctx, r, currentVMagent, instance); err != nil {
 			continue
 		}
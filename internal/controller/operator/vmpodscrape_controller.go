@@ -30,6 +30,7 @@ import (
 	"github.com/VictoriaMetrics/operator/internal/config"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/vmagent"
 )
 
@@ -70,10 +71,6 @@ func (r *VMPodScrapeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	RegisterObjectStat(instance, "vmpodscrape")
 
-	if vmAgentReconcileLimit.MustThrottleReconcile() {
-		return
-	}
-
 	vmAgentSync.Lock()
 	defer vmAgentSync.Unlock()
 
@@ -85,7 +82,7 @@ func (r *VMPodScrapeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	for _, vmagentItem := range objects.Items {
-		if !vmagentItem.DeletionTimestamp.IsZero() || vmagentItem.Spec.ParsingError != "" || vmagentItem.IsPodScrapeUnmanaged() {
+		if !vmagentItem.DeletionTimestamp.IsZero() || vmagentItem.Spec.ParsingError != "" || vmagentItem.IsPodScrapeUnmanaged() || !sharding.IsOwned(client.ObjectKeyFromObject(&vmagentItem)) {
 			continue
 		}
 		currentVMagent := &vmagentItem
@@ -105,6 +102,9 @@ func (r *VMPodScrapeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			}
 		}
 
+		if vmAgentReconcileLimit.ReconcileDelay(currentVMagent) > 0 {
+			continue
+		}
 		if err := vmagent.CreateOrUpdateConfigurationSecret(ctx, r, currentVMagent, instance); err != nil {
 			continue
 		}
@@ -30,6 +30,7 @@ import (
 	"github.com/VictoriaMetrics/operator/internal/config"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/vmagent"
 )
 
@@ -68,11 +69,6 @@ func (r *VMProbeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 	}
 
 	RegisterObjectStat(instance, "vmprobescrape")
-	if vmAgentReconcileLimit.MustThrottleReconcile() {
-		// fast path, rate limited
-		return
-	}
-
 	vmAgentSync.Lock()
 	defer vmAgentSync.Unlock()
 
@@ -84,7 +80,7 @@ func (r *VMProbeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 	}
 
 	for _, vmagentItem := range objects.Items {
-		if !vmagentItem.DeletionTimestamp.IsZero() || vmagentItem.Spec.ParsingError != "" || vmagentItem.IsProbeUnmanaged() {
+		if !vmagentItem.DeletionTimestamp.IsZero() || vmagentItem.Spec.ParsingError != "" || vmagentItem.IsProbeUnmanaged() || !sharding.IsOwned(client.ObjectKeyFromObject(&vmagentItem)) {
 			continue
 		}
 		currentVMagent := &vmagentItem
@@ -107,6 +103,9 @@ func (r *VMProbeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 			}
 		}
 
+		if vmAgentReconcileLimit.ReconcileDelay(currentVMagent) > 0 {
+			continue
+		}
 		if err := vmagent.CreateOrUpdateConfigurationSecret(ctx, r, currentVMagent, instance); err != nil {
 			continue
 		}
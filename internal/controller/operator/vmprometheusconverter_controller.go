@@ -23,6 +23,7 @@ import (
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/converter"
 	converterv1alpha1 "github.com/VictoriaMetrics/operator/internal/controller/operator/converter/v1alpha1"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	promv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 )
@@ -71,6 +72,23 @@ type ConverterController struct {
 	baseConf        *config.BaseOperatorConf
 }
 
+// shardedEventHandler skips prometheus objects owned by other operator shards
+func shardedEventHandler(h cache.ResourceEventHandlerFuncs) cache.ResourceEventHandler {
+	if !sharding.IsEnabled() {
+		return h
+	}
+	return cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj any) bool {
+			o, ok := obj.(client.Object)
+			if !ok {
+				return false
+			}
+			return sharding.IsOwned(client.ObjectKeyFromObject(o))
+		},
+		Handler: h,
+	}
+}
+
 // NewConverterController builder for vmprometheusconverter service
 func NewConverterController(ctx context.Context, baseClient *kubernetes.Clientset, rclient client.WithWatch, resyncPeriod time.Duration, baseConf *config.BaseOperatorConf) (*ConverterController, error) {
 	c := &ConverterController{
@@ -102,10 +120,10 @@ func NewConverterController(ctx context.Context, baseClient *kubernetes.Clientse
 		resyncPeriod,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 	)
-	if _, err := c.ruleInf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	if _, err := c.ruleInf.AddEventHandler(shardedEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.CreatePrometheusRule,
 		UpdateFunc: c.UpdatePrometheusRule,
-	}); err != nil {
+	})); err != nil {
 		return nil, fmt.Errorf("cannot add prometheus_rule handler: %w", err)
 	}
 	c.podInf = cache.NewSharedIndexInformer(
@@ -127,10 +145,10 @@ func NewConverterController(ctx context.Context, baseClient *kubernetes.Clientse
 		resyncPeriod,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 	)
-	if _, err := c.podInf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	if _, err := c.podInf.AddEventHandler(shardedEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.CreatePodMonitor,
 		UpdateFunc: c.UpdatePodMonitor,
-	}); err != nil {
+	})); err != nil {
 		return nil, fmt.Errorf("cannot add pod_monitor handler: %w", err)
 	}
 	c.serviceInf = cache.NewSharedIndexInformer(
@@ -153,10 +171,10 @@ func NewConverterController(ctx context.Context, baseClient *kubernetes.Clientse
 		resyncPeriod,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 	)
-	if _, err := c.serviceInf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	if _, err := c.serviceInf.AddEventHandler(shardedEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.CreateServiceMonitor,
 		UpdateFunc: c.UpdateServiceMonitor,
-	}); err != nil {
+	})); err != nil {
 		return nil, fmt.Errorf("cannot add service_monitor handler: %w", err)
 	}
 
@@ -179,10 +197,10 @@ func NewConverterController(ctx context.Context, baseClient *kubernetes.Clientse
 		resyncPeriod,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 	)
-	if _, err := amConfigInf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	if _, err := amConfigInf.AddEventHandler(shardedEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.CreateAlertmanagerConfig,
 		UpdateFunc: c.UpdateAlertmanagerConfig,
-	}); err != nil {
+	})); err != nil {
 		return nil, fmt.Errorf("cannot add alertmanager_config handler: %w", err)
 	}
 	c.amConfigInf = amConfigInf
@@ -206,10 +224,10 @@ func NewConverterController(ctx context.Context, baseClient *kubernetes.Clientse
 		resyncPeriod,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 	)
-	if _, err := c.probeInf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	if _, err := c.probeInf.AddEventHandler(shardedEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.CreateProbe,
 		UpdateFunc: c.UpdateProbe,
-	}); err != nil {
+	})); err != nil {
 		return nil, fmt.Errorf("cannot add probe handler: %w", err)
 	}
 	c.scrapeConfigInf = cache.NewSharedIndexInformer(
@@ -231,10 +249,10 @@ func NewConverterController(ctx context.Context, baseClient *kubernetes.Clientse
 		resyncPeriod,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 	)
-	if _, err := c.scrapeConfigInf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	if _, err := c.scrapeConfigInf.AddEventHandler(shardedEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.CreateScrapeConfig,
 		UpdateFunc: c.UpdateScrapeConfig,
-	}); err != nil {
+	})); err != nil {
 		return nil, fmt.Errorf("cannot add scrapeConfig handler: %w", err)
 	}
 	return c, nil
@@ -246,6 +264,12 @@ func (c *ConverterController) runInformerWithDiscovery(ctx context.Context, grou
 	return nil
 }
 
+// NeedLeaderElection implements manager.LeaderElectionRunnable interface
+// converted objects are distributed across replicas if sharding is enabled
+func (c *ConverterController) NeedLeaderElection() bool {
+	return !sharding.IsEnabled()
+}
+
 // Start implements interface.
 func (c *ConverterController) Start(ctx context.Context) error {
 	var errG errgroup.Group
@@ -24,12 +24,14 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	"github.com/VictoriaMetrics/operator/internal/config"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/vmalert"
 )
 
@@ -71,11 +73,6 @@ func (r *VMRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 
 	RegisterObjectStat(instance, "vmrule")
 
-	if vmAlertRateLimiter.MustThrottleReconcile() {
-		// fast path
-		return ctrl.Result{}, nil
-	}
-
 	var objects vmv1beta1.VMAlertList
 	if err := k8stools.ListObjectsByNamespace(ctx, r.Client, config.MustGetWatchNamespaces(), func(dst *vmv1beta1.VMAlertList) {
 		objects.Items = append(objects.Items, dst.Items...)
@@ -84,7 +81,7 @@ func (r *VMRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 	}
 
 	for _, vmalertItem := range objects.Items {
-		if vmalertItem.DeletionTimestamp != nil || vmalertItem.Spec.ParsingError != "" {
+		if vmalertItem.DeletionTimestamp != nil || vmalertItem.Spec.ParsingError != "" || !sharding.IsOwned(client.ObjectKeyFromObject(&vmalertItem)) {
 			continue
 		}
 		currVMAlert := &vmalertItem
@@ -104,14 +101,34 @@ func (r *VMRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 			}
 		}
 
+		if vmAlertRateLimiter.ReconcileDelay(currVMAlert) > 0 {
+			continue
+		}
+		prevLookback := statePersistenceLookback(currVMAlert)
 		_, err := vmalert.CreateOrUpdateRuleConfigMaps(ctx, r, currVMAlert, instance)
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("cannot update rules configmaps: %w", err)
 		}
+		if statePersistenceLookback(currVMAlert) != prevLookback {
+			select {
+			case vmAlertLookbackChanges <- event.GenericEvent{Object: currVMAlert.DeepCopy()}:
+			default:
+				// it's picked up at the next periodic resync
+				reqLogger.Info("cannot enqueue vmalert reconcile for alerts state lookback update, queue is full")
+			}
+		}
 	}
 	return
 }
 
+// statePersistenceLookback returns alerts state lookback computed from selected rules
+func statePersistenceLookback(cr *vmv1beta1.VMAlert) string {
+	if cr.Status.StatePersistence == nil {
+		return ""
+	}
+	return cr.Status.StatePersistence.Lookback
+}
+
 // SetupWithManager general setup method
 func (r *VMRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -18,16 +18,20 @@ package operator
 
 import (
 	"context"
+	"testing"
 
+	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
 )
 
 var _ = Describe("VMRule Controller", func() {
@@ -82,3 +86,48 @@ var _ = Describe("VMRule Controller", func() {
 		})
 	})
 })
+
+func TestVMRuleReconcileLookbackChange(t *testing.T) {
+	ctx := context.Background()
+	vmalertCR := &vmv1beta1.VMAlert{
+		ObjectMeta: metav1.ObjectMeta{Name: "main", Namespace: "default"},
+		Spec: vmv1beta1.VMAlertSpec{
+			SelectAllByDefault: true,
+			Datasource:         vmv1beta1.VMAlertDatasourceSpec{URL: "http://vmsingle:8429"},
+			StatePersistence:   &vmv1beta1.VMAlertStatePersistence{Enabled: true},
+		},
+	}
+	rule := &vmv1beta1.VMRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "alerts", Namespace: "default"},
+		Spec: vmv1beta1.VMRuleSpec{Groups: []vmv1beta1.RuleGroup{{
+			Name:  "alerts",
+			Rules: []vmv1beta1.Rule{{Alert: "down", Expr: "up == 0", For: "1h"}},
+		}}},
+	}
+	fclient := k8stools.GetTestClientWithObjects([]runtime.Object{vmalertCR, rule})
+	r := &VMRuleReconciler{Client: fclient, OriginScheme: fclient.Scheme(), Log: logr.Discard()}
+	reconcileRule := func() {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "alerts"}}); err != nil {
+			t.Fatalf("unexpected reconcile error: %s", err)
+		}
+	}
+
+	reconcileRule()
+	select {
+	case ev := <-vmAlertLookbackChanges:
+		if ev.Object.GetName() != "main" {
+			t.Fatalf("unexpected object enqueued: %s", ev.Object.GetName())
+		}
+	default:
+		t.Fatalf("expected vmalert to be enqueued on lookback change")
+	}
+
+	// lookback isn't changed
+	reconcileRule()
+	select {
+	case ev := <-vmAlertLookbackChanges:
+		t.Fatalf("unexpected vmalert enqueue: %s", ev.Object.GetName())
+	default:
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/VictoriaMetrics/operator/internal/config"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/vmagent"
 )
 
@@ -68,11 +69,6 @@ func (r *VMScrapeConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}
 
 	RegisterObjectStat(instance, "vmscrapeconfig")
-	if vmAgentReconcileLimit.MustThrottleReconcile() {
-		// fast path, rate limited
-		return
-	}
-
 	vmAgentSync.Lock()
 	defer vmAgentSync.Unlock()
 	var objects vmv1beta1.VMAgentList
@@ -83,7 +79,7 @@ func (r *VMScrapeConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}
 
 	for _, vmagentItem := range objects.Items {
-		if !vmagentItem.DeletionTimestamp.IsZero() || vmagentItem.Spec.ParsingError != "" || vmagentItem.IsScrapeConfigUnmanaged() {
+		if !vmagentItem.DeletionTimestamp.IsZero() || vmagentItem.Spec.ParsingError != "" || vmagentItem.IsScrapeConfigUnmanaged() || !sharding.IsOwned(client.ObjectKeyFromObject(&vmagentItem)) {
 			continue
 		}
 		currentVMagent := &vmagentItem
@@ -105,6 +101,9 @@ func (r *VMScrapeConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			}
 		}
 
+		if vmAgentReconcileLimit.ReconcileDelay(currentVMagent) > 0 {
+			continue
+		}
 		if err := vmagent.CreateOrUpdateConfigurationSecret(ctx, r, currentVMagent, instance); err != nil {
 			continue
 		}
@@ -0,0 +1,137 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/config"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/vmagent"
+)
+
+// VMScrapeDefaultsReconciler reconciles a VMScrapeDefaults object
+type VMScrapeDefaultsReconciler struct {
+	client.Client
+	Log          logr.Logger
+	OriginScheme *runtime.Scheme
+}
+
+// Init implements crdController interface
+func (r *VMScrapeDefaultsReconciler) Init(rclient client.Client, l logr.Logger, sc *runtime.Scheme, cf *config.BaseOperatorConf) {
+	r.Client = rclient
+	r.Log = l.WithName("controller.VMScrapeDefaults")
+	r.OriginScheme = sc
+}
+
+// Scheme implements interface.
+func (r *VMScrapeDefaultsReconciler) Scheme() *runtime.Scheme {
+	return r.OriginScheme
+}
+
+// Reconcile general reconcile method for controller
+// +kubebuilder:rbac:groups=operator.victoriametrics.com,resources=vmscrapedefaults,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=operator.victoriametrics.com,resources=vmscrapedefaults/status,verbs=get;update;patch
+func (r *VMScrapeDefaultsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	reqLogger := r.Log.WithValues("vmscrapedefaults", req.Name, "namespace", req.Namespace)
+	ctx = logger.AddToContext(ctx, reqLogger)
+
+	defer func() {
+		result, err = handleReconcileErr(ctx, r.Client, nil, result, err)
+	}()
+	// Fetch the VMScrapeDefaults instance
+	instance := &vmv1beta1.VMScrapeDefaults{}
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		return result, &getError{err, "vmscrapedefaults", req}
+	}
+
+	RegisterObjectStat(instance, "vmscrapedefaults")
+
+	if err := reconcileVMAgentsForScrapeDefaults(ctx, r.Client, instance); err != nil {
+		return result, err
+	}
+	return
+}
+
+// reconcileVMAgentsForScrapeDefaults regenerates scrape configuration of VMAgents owned by the current shard
+func reconcileVMAgentsForScrapeDefaults(ctx context.Context, rclient client.Client, instance client.Object) error {
+	vmAgentSync.Lock()
+	defer vmAgentSync.Unlock()
+
+	var objects vmv1beta1.VMAgentList
+	if err := k8stools.ListObjectsByNamespace(ctx, rclient, config.MustGetWatchNamespaces(), func(dst *vmv1beta1.VMAgentList) {
+		objects.Items = append(objects.Items, dst.Items...)
+	}); err != nil {
+		return fmt.Errorf("cannot list vmagents for scrape defaults: %w", err)
+	}
+
+	reqLogger := logger.WithContext(ctx)
+	for _, vmagentItem := range objects.Items {
+		// defaults aren't selected by vmagent selectors
+		// and could be applied to any scrape object selected by vmagent
+		if !vmagentItem.DeletionTimestamp.IsZero() || vmagentItem.Spec.ParsingError != "" || vmagentItem.IsUnmanaged() || !sharding.IsOwned(client.ObjectKeyFromObject(&vmagentItem)) {
+			continue
+		}
+		currentVMagent := &vmagentItem
+		reqLogger := reqLogger.WithValues("vmagent", currentVMagent.Name, "parent_namespace", currentVMagent.Namespace)
+		ctx := logger.AddToContext(ctx, reqLogger)
+
+		if vmAgentReconcileLimit.ReconcileDelay(currentVMagent) > 0 {
+			continue
+		}
+		if err := vmagent.CreateOrUpdateConfigurationSecret(ctx, rclient, currentVMagent, instance); err != nil {
+			continue
+		}
+	}
+	return nil
+}
+
+// SetupWithManager general setup method
+func (r *VMScrapeDefaultsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		// namespace events are filtered by watchNamespaceLabels
+		For(&vmv1beta1.VMScrapeDefaults{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(getDefaultOptions())
+	return watchNamespaceLabels(b, func(ctx context.Context, oldLabels, newLabels labels.Set) []reconcile.Request {
+		var requests []reconcile.Request
+		var list vmv1beta1.VMScrapeDefaultsList
+		if err := r.List(ctx, &list); err != nil {
+			logger.WithContext(ctx).Error(err, "cannot list vmscrapedefaults for namespace labels change")
+			return nil
+		}
+		for i := range list.Items {
+			item := &list.Items[i]
+			if isNamespaceSelectorChanged(item.Spec.NamespaceSelector, oldLabels, newLabels) {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(item)})
+			}
+		}
+		return requests
+	}).Complete(r)
+}
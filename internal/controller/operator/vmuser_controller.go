@@ -34,6 +34,7 @@ import (
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/limiter"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/vmauth"
 )
 
@@ -56,7 +57,7 @@ func (r *VMUserReconciler) Scheme() *runtime.Scheme {
 	return r.OriginScheme
 }
 
-var vmauthRateLimiter = limiter.NewRateLimiter("vmauth", 5)
+var vmauthRateLimiter = limiter.NewRateLimiter("vmauth", limiter.DefaultConfig)
 
 // Reconcile implements interface
 // +kubebuilder:rbac:groups=operator.victoriametrics.com,resources=vmusers,verbs=get;list;watch;create;update;patch;delete
@@ -85,9 +86,6 @@ func (r *VMUserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 		}
 	}
 
-	if vmauthRateLimiter.MustThrottleReconcile() {
-		return
-	}
 	var vmauthes vmv1beta1.VMAuthList
 	if err := k8stools.ListObjectsByNamespace(ctx, r.Client, config.MustGetWatchNamespaces(), func(dst *vmv1beta1.VMAuthList) {
 		vmauthes.Items = append(vmauthes.Items, dst.Items...)
@@ -96,7 +94,7 @@ func (r *VMUserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 	}
 
 	for _, vmauthItem := range vmauthes.Items {
-		if !vmauthItem.DeletionTimestamp.IsZero() || vmauthItem.Spec.ParsingError != "" || vmauthItem.IsUnmanaged() {
+		if !vmauthItem.DeletionTimestamp.IsZero() || vmauthItem.Spec.ParsingError != "" || vmauthItem.IsUnmanaged() || !sharding.IsOwned(client.ObjectKeyFromObject(&vmauthItem)) {
 			continue
 		}
 		// reconcile users for given vmauth.
@@ -116,6 +114,9 @@ func (r *VMUserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 				continue
 			}
 		}
+		if vmauthRateLimiter.ReconcileDelay(currentVMAuth) > 0 {
+			continue
+		}
 		if err := vmauth.CreateOrUpdateVMAuthConfig(ctx, r, currentVMAuth, &instance); err != nil {
 			return ctrl.Result{}, fmt.Errorf("cannot create or update vmauth deploy for vmuser: %w", err)
 		}
@@ -16,13 +16,19 @@ import (
 	"time"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/buildinfo"
+	vmv1 "github.com/VictoriaMetrics/operator/api/operator/v1"
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
 	"github.com/VictoriaMetrics/operator/internal/config"
 	vmcontroller "github.com/VictoriaMetrics/operator/internal/controller/operator"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/build"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/k8stools"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/logger"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/migration"
 	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/reconcile"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/rollout"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/sharding"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/tracing"
+	webhookv1 "github.com/VictoriaMetrics/operator/internal/webhook/operator/v1"
 	webhookv1beta1 "github.com/VictoriaMetrics/operator/internal/webhook/operator/v1beta1"
 	"github.com/go-logr/logr"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
@@ -33,6 +39,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -40,12 +47,15 @@ import (
 	restmetrics "k8s.io/client-go/tools/metrics"
 	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/config"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	// +kubebuilder:scaffold:imports
@@ -109,12 +119,35 @@ var (
 		"Supported fields: ts, level, caller, msg")
 	statusUpdateTTL = managerFlags.Duration("controller.statusLastUpdateTimeTTL", time.Hour, "Configures TTL for LastUpdateTime status.conditions fields. "+
 		"It's used to detect stale parent objects on child objects. Like VMAlert->VMRule .status.Conditions.Type")
+	serverSideApply = managerFlags.Bool("controller.serverSideApply", false, "Enables server-side apply with field manager=vm-operator for objects managed by operator. "+
+		"Operator owns only rendered fields and preserves fields set by other controllers. Conflicts with other field managers are reported as reconcile errors")
+	tracingExporter = managerFlags.String("tracing.exporter", "", "Enables OpenTelemetry tracing of reconcile loops and Kubernetes API calls with the given exporter. Supported values: otlp, stdout. "+
+		"Tracing is disabled by default")
+	tracingEndpoint = managerFlags.String("tracing.endpoint", "", "OTLP HTTP endpoint URL for -tracing.exporter=otlp, for example http://otel-collector:4318. "+
+		"If empty, standard OTEL_EXPORTER_OTLP_ENDPOINT and OTEL_EXPORTER_OTLP_TRACES_ENDPOINT env variables are used")
+	tracingSamplingRatio = managerFlags.Float64("tracing.samplingRatio", 1, "Ratio of sampled reconcile traces in range [0..1]")
+	shardingEnable       = managerFlags.Bool("sharding.enable", false, "Enables sharding of parent objects across operator replicas. Each replica reconciles a subset of objects selected by consistent hash of namespace/name. "+
+		"Replicas coordinate with Lease objects. Leader election is enabled automatically and used only for global tasks. Sharding does not reduce memory usage, since informers of each replica cache all watched objects")
+	shardingName           = managerFlags.String("sharding.name", "vm-operator", "Name of operator shard group. Replicas with the same name share objects")
+	shardingLeaseNamespace = managerFlags.String("sharding.leaseNamespace", "", "Namespace for shard Lease objects. Operator namespace is used by default")
+	shardingIdentity       = managerFlags.String("sharding.identity", "", "Identity of operator replica in shard group. Hostname is used by default")
+	shardingLeaseDuration  = managerFlags.Duration("sharding.leaseDuration", 15*time.Second, "Duration after which shard Lease is considered expired if it wasn't renewed. Objects of expired shard are moved to live shards")
+	shardingRenewInterval  = managerFlags.Duration("sharding.renewInterval", 5*time.Second, "Interval for shard Lease renew and shard membership refresh")
+	shardingTakeoverGrace  = managerFlags.Duration("sharding.takeoverGracePeriod", 10*time.Second, "Duration after shard membership change before reconcile of objects moved from other shards. It must be greater than -sharding.renewInterval")
+	operatorConfigMap      = managerFlags.String("operatorConfig.configMap", "", "Optional ConfigMap with operator configuration in namespace/name format. "+
+		"It uses the same keys as env variables printed by -printDefaults and overrides their values. Changes are applied without operator restart")
+	operatorConfigReconcileRate = managerFlags.Float64("operatorConfig.reconcileRate", 1, "Number of objects per second reconciled after operator configuration change at -operatorConfig.configMap")
+	defaultsRolloutPolicy       = managerFlags.String("defaultsRollout.policy", "", "Optional VMRolloutPolicy in namespace/name format. "+
+		"If set, objects affected by change of operator defaults, such as default image versions, are updated in batches according to the policy")
+	storageVersionMigration = managerFlags.Bool("storageVersionMigration.enable", false, "Rewrites objects of operator CRDs to the current storage version at start and updates CRD status.storedVersions. "+
+		"It must be performed before removal of deprecated API versions from CRDs")
 )
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(vmv1beta1.AddToScheme(scheme))
+	utilruntime.Must(vmv1.AddToScheme(scheme))
 	utilruntime.Must(metav1.AddToScheme(scheme))
 	utilruntime.Must(v1alpha1.AddToScheme(scheme))
 	utilruntime.Must(promv1.AddToScheme(scheme))
@@ -206,14 +239,44 @@ func RunManager(ctx context.Context) error {
 
 	reconcile.InitDeadlines(baseConfig.PodWaitReadyIntervalCheck, baseConfig.AppReadyTimeout, baseConfig.PodWaitReadyTimeout)
 	reconcile.SetStatusUpdateTTL(*statusUpdateTTL)
-	config := ctrl.GetConfigOrDie()
-	config.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(float32(*clientQPS), *clientBurst)
+	reconcile.SetServerSideApply(*serverSideApply)
+	restConfig := ctrl.GetConfigOrDie()
+	restConfig.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(float32(*clientQPS), *clientBurst)
+	if *tracingExporter != "" {
+		shutdownTracing, err := tracing.Init(ctx, tracing.Config{
+			Exporter:      *tracingExporter,
+			Endpoint:      *tracingEndpoint,
+			SamplingRatio: *tracingSamplingRatio,
+			Version:       buildinfo.Version,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot setup tracing: %w", err)
+		}
+		defer func() {
+			// ctx is already cancelled at shutdown
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				setupLog.Error(err, "cannot flush tracing spans")
+			}
+		}()
+		restConfig.Wrap(tracing.WrapTransport)
+		setupLog.Info(fmt.Sprintf("enabled tracing with exporter=%s", *tracingExporter))
+	}
 
+	if *shardingEnable && *defaultsRolloutPolicy != "" {
+		return fmt.Errorf("-defaultsRollout.policy cannot be used with -sharding.enable, rollout state is tracked by a single operator replica")
+	}
+
+	var leaderElectionNamespace string
+	if *shardingEnable {
+		leaderElectionNamespace = *shardingLeaseNamespace
+	}
 	co, err := getClientCacheOptions(*disableCacheForObjects)
 	if err != nil {
 		return fmt.Errorf("cannot build cache options for manager: %w", err)
 	}
-	mgr, err := ctrl.NewManager(config, ctrl.Options{
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Logger:                 ctrl.Log.WithName("manager"),
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
@@ -222,8 +285,14 @@ func RunManager(ctx context.Context) error {
 		ReadinessEndpointName:  "/ready",
 		LivenessEndpointName:   "/health",
 		WebhookServer:          webhookServer,
-		LeaderElection:         *leaderElect,
-		LeaderElectionID:       "57410f0d.victoriametrics.com",
+		// with sharding leader election is used only for global tasks, such as storage version migration,
+		// controllers run on each replica and reconcile objects owned by it
+		LeaderElection:          *leaderElect || *shardingEnable,
+		LeaderElectionID:        "57410f0d.victoriametrics.com",
+		LeaderElectionNamespace: leaderElectionNamespace,
+		Controller: ctrlconfig.Controller{
+			NeedLeaderElection: ptr.To(!*shardingEnable),
+		},
 		Cache: cache.Options{
 			DefaultNamespaces: watchNsCacheByName,
 		},
@@ -261,6 +330,10 @@ func RunManager(ctx context.Context) error {
 		return fmt.Errorf("cannot register health endpoint: %w", err)
 	}
 
+	if err := addDebugHandlers(mgr); err != nil {
+		return err
+	}
+
 	if !*disableCRDOwnership && len(watchNss) == 0 {
 		initC, err := client.New(mgr.GetConfig(), client.Options{Scheme: scheme})
 		if err != nil {
@@ -284,6 +357,84 @@ func RunManager(ctx context.Context) error {
 
 	vmv1beta1.SetLabelAndAnnotationPrefixes(baseConfig.FilterChildLabelPrefixes, baseConfig.FilterChildAnnotationPrefixes)
 
+	if *shardingEnable {
+		// leases are read without cache, since lease namespace could be out of watched namespaces
+		shardClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: scheme})
+		if err != nil {
+			return fmt.Errorf("cannot create client for operator sharding: %w", err)
+		}
+		coordinator, err := sharding.NewCoordinator(shardClient, sharding.Config{
+			Name:                *shardingName,
+			Namespace:           *shardingLeaseNamespace,
+			Identity:            *shardingIdentity,
+			LeaseDuration:       *shardingLeaseDuration,
+			RenewInterval:       *shardingRenewInterval,
+			TakeoverGracePeriod: *shardingTakeoverGrace,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot setup operator sharding: %w", err)
+		}
+		// join shard group before controllers start
+		if err := coordinator.Sync(ctx); err != nil {
+			return fmt.Errorf("cannot join operator shard group: %w", err)
+		}
+		sharding.SetCoordinator(coordinator)
+		if err := mgr.Add(coordinator); err != nil {
+			return fmt.Errorf("cannot add operator shard coordinator: %w", err)
+		}
+		setupLog.Info(fmt.Sprintf("enabled operator sharding, shards: %s", strings.Join(coordinator.Members(), ",")))
+	}
+
+	if *operatorConfigMap != "" {
+		namespace, name, _ := strings.Cut(*operatorConfigMap, "/")
+		configClient, err := client.NewWithWatch(mgr.GetConfig(), client.Options{Scheme: scheme})
+		if err != nil {
+			return fmt.Errorf("cannot create client for operator config: %w", err)
+		}
+		configWatcher, err := vmcontroller.NewOperatorConfigWatcher(configClient, types.NamespacedName{Namespace: namespace, Name: name}, *operatorConfigReconcileRate)
+		if err != nil {
+			return fmt.Errorf("cannot setup operator config watcher: %w", err)
+		}
+		// apply config before controllers start
+		if err := configWatcher.Sync(ctx); err != nil {
+			return fmt.Errorf("cannot load operator config: %w", err)
+		}
+		configWatcher.SetLeaderElected(mgr.Elected())
+		baseConfig = config.MustGetBaseConfig()
+		reconcile.InitDeadlines(baseConfig.PodWaitReadyIntervalCheck, baseConfig.AppReadyTimeout, baseConfig.PodWaitReadyTimeout)
+		if err := mgr.Add(configWatcher); err != nil {
+			return fmt.Errorf("cannot add operator config watcher: %w", err)
+		}
+	}
+
+	if *defaultsRolloutPolicy != "" {
+		namespace, name, _ := strings.Cut(*defaultsRolloutPolicy, "/")
+		// policy is read without cache, since its namespace could be out of watched namespaces
+		rolloutClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: scheme})
+		if err != nil {
+			return fmt.Errorf("cannot create client for defaults rollout: %w", err)
+		}
+		rolloutCoordinator, err := rollout.NewCoordinator(rolloutClient, types.NamespacedName{Namespace: namespace, Name: name})
+		if err != nil {
+			return fmt.Errorf("cannot setup defaults rollout: %w", err)
+		}
+		rollout.SetCoordinator(rolloutCoordinator)
+		if err := mgr.Add(rolloutCoordinator); err != nil {
+			return fmt.Errorf("cannot add defaults rollout coordinator: %w", err)
+		}
+		setupLog.Info(fmt.Sprintf("enabled defaults rollout with policy=%s", *defaultsRolloutPolicy))
+	}
+
+	if *storageVersionMigration {
+		migrationClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: scheme})
+		if err != nil {
+			return fmt.Errorf("cannot create client for storage version migration: %w", err)
+		}
+		if err := mgr.Add(migration.NewStorageVersionMigrator(migrationClient)); err != nil {
+			return fmt.Errorf("cannot add storage version migrator: %w", err)
+		}
+	}
+
 	if err := initControllers(mgr, ctrl.Log, baseConfig); err != nil {
 		return err
 	}
@@ -348,12 +499,52 @@ func addWebhooks(mgr ctrl.Manager) error {
 		webhookv1beta1.SetupVLogsWebhookWithManager,
 		webhookv1beta1.SetupVMAlertmanagerWebhookWithManager,
 		webhookv1beta1.SetupVMAlertmanagerConfigWebhookWithManager,
+		webhookv1beta1.SetupVMAlertmanagerSilenceWebhookWithManager,
 		webhookv1beta1.SetupVMAuthWebhookWithManager,
 		webhookv1beta1.SetupVMUserWebhookWithManager,
 		webhookv1beta1.SetupVMRuleWebhookWithManager,
+		webhookv1.SetupVMRuleWebhookWithManager,
 	})
 }
 
+// addDebugHandlers registers debug handlers at metrics server
+//
+// handlers expose objects and generated configs, so requests must be authenticated and authorized for get verb of handler path.
+// Objects are read from informers cache in order to not put load on kubernetes API server.
+func addDebugHandlers(mgr ctrl.Manager) error {
+	authFilter, err := filters.WithAuthenticationAndAuthorization(mgr.GetConfig(), mgr.GetHTTPClient())
+	if err != nil {
+		return fmt.Errorf("cannot build authentication and authorization filter for debug handlers: %w", err)
+	}
+	cachedClient, err := client.New(mgr.GetConfig(), client.Options{
+		Scheme:     scheme,
+		HTTPClient: mgr.GetHTTPClient(),
+		Mapper:     mgr.GetRESTMapper(),
+		Cache:      &client.CacheOptions{Reader: mgr.GetCache()},
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create cached client for debug handlers: %w", err)
+	}
+	handlers := []struct {
+		path    string
+		name    string
+		handler http.Handler
+	}{
+		{path: alertmanagerRoutesPath, name: "vmalertmanager routes", handler: newAlertmanagerRoutesHandler(cachedClient)},
+		{path: vmalertRulesGraphPath, name: "vmalert rules graph", handler: newVMAlertRulesGraphHandler(cachedClient)},
+	}
+	for _, h := range handlers {
+		handler, err := authFilter(setupLog.WithValues("path", h.path), h.handler)
+		if err != nil {
+			return fmt.Errorf("cannot protect %s endpoint: %w", h.name, err)
+		}
+		if err := mgr.AddMetricsServerExtraHandler(h.path, handler); err != nil {
+			return fmt.Errorf("cannot register %s endpoint: %w", h.name, err)
+		}
+	}
+	return nil
+}
+
 func getClientCacheOptions(disabledCacheObjects string) (*client.CacheOptions, error) {
 	var co client.CacheOptions
 	if len(disabledCacheObjects) > 0 {
@@ -422,22 +613,25 @@ type crdController interface {
 }
 
 var controllersByName = map[string]crdController{
-	"VMCluster":            &vmcontroller.VMClusterReconciler{},
-	"VMAgent":              &vmcontroller.VMAgentReconciler{},
-	"VMAuth":               &vmcontroller.VMAuthReconciler{},
-	"VMSingle":             &vmcontroller.VMSingleReconciler{},
-	"VLogs":                &vmcontroller.VLogsReconciler{},
-	"VMAlertmanager":       &vmcontroller.VMAlertmanagerReconciler{},
-	"VMAlert":              &vmcontroller.VMAlertReconciler{},
-	"VMUser":               &vmcontroller.VMUserReconciler{},
-	"VMRule":               &vmcontroller.VMRuleReconciler{},
-	"VMAlertmanagerConfig": &vmcontroller.VMAlertmanagerConfigReconciler{},
-	"VMServiceScrape":      &vmcontroller.VMServiceScrapeReconciler{},
-	"VMPodScrape":          &vmcontroller.VMPodScrapeReconciler{},
-	"VMProbe":              &vmcontroller.VMProbeReconciler{},
-	"VMNodeScrape":         &vmcontroller.VMNodeScrapeReconciler{},
-	"VMStaticScrape":       &vmcontroller.VMStaticScrapeReconciler{},
-	"VMScrapeConfig":       &vmcontroller.VMScrapeConfigReconciler{},
+	"VMCluster":               &vmcontroller.VMClusterReconciler{},
+	"VMAgent":                 &vmcontroller.VMAgentReconciler{},
+	"VMAuth":                  &vmcontroller.VMAuthReconciler{},
+	"VMSingle":                &vmcontroller.VMSingleReconciler{},
+	"VLogs":                   &vmcontroller.VLogsReconciler{},
+	"VMAlertmanager":          &vmcontroller.VMAlertmanagerReconciler{},
+	"VMAlert":                 &vmcontroller.VMAlertReconciler{},
+	"VMUser":                  &vmcontroller.VMUserReconciler{},
+	"VMRule":                  &vmcontroller.VMRuleReconciler{},
+	"VMAlertmanagerConfig":    &vmcontroller.VMAlertmanagerConfigReconciler{},
+	"VMAlertmanagerSilence":   &vmcontroller.VMAlertmanagerSilenceReconciler{},
+	"VMServiceScrape":         &vmcontroller.VMServiceScrapeReconciler{},
+	"VMPodScrape":             &vmcontroller.VMPodScrapeReconciler{},
+	"VMProbe":                 &vmcontroller.VMProbeReconciler{},
+	"VMNodeScrape":            &vmcontroller.VMNodeScrapeReconciler{},
+	"VMStaticScrape":          &vmcontroller.VMStaticScrapeReconciler{},
+	"VMScrapeConfig":          &vmcontroller.VMScrapeConfigReconciler{},
+	"VMScrapeDefaults":        &vmcontroller.VMScrapeDefaultsReconciler{},
+	"VMClusterScrapeDefaults": &vmcontroller.VMClusterScrapeDefaultsReconciler{},
 }
 
 func initControllers(mgr ctrl.Manager, l logr.Logger, bs *config.BaseOperatorConf) error {
@@ -0,0 +1,156 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/alertmanager"
+)
+
+const (
+	// AlertmanagerRouteTestCmd is a name of subcommand, which evaluates VMAlertmanager routing for the given labels
+	AlertmanagerRouteTestCmd = "alertmanager-route-test"
+
+	alertmanagerRoutesPath = "/debug/vmalertmanager/routes"
+)
+
+// labelsFlag collects repeated name=value flags
+type labelsFlag map[string]string
+
+// String implements flag.Value interface
+func (lf labelsFlag) String() string {
+	pairs := make([]string, 0, len(lf))
+	for k, v := range lf {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value interface
+func (lf labelsFlag) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("unexpected label=%q, want name=value", s)
+	}
+	lf[name] = value
+	return nil
+}
+
+// newAlertmanagerRoutesHandler returns handler, which evaluates route tree of VMAlertmanager for the given labels
+//
+// example request: /debug/vmalertmanager/routes?namespace=monitoring&name=main&label=alertname=Down&label=team=infra
+func newAlertmanagerRoutesHandler(rclient client.Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		nsn := types.NamespacedName{Namespace: q.Get("namespace"), Name: q.Get("name")}
+		if nsn.Namespace == "" || nsn.Name == "" {
+			http.Error(w, "namespace and name query args are required", http.StatusBadRequest)
+			return
+		}
+		lset := labelsFlag{}
+		for _, l := range q["label"] {
+			if err := lset.Set(l); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		var am vmv1beta1.VMAlertmanager
+		if err := rclient.Get(r.Context(), nsn, &am); err != nil {
+			http.Error(w, fmt.Sprintf("cannot get vmalertmanager: %s", err), http.StatusNotFound)
+			return
+		}
+		result, err := alertmanager.EvaluateAlertmanagerRouting(r.Context(), rclient, &am, lset)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+	})
+}
+
+// RunAlertmanagerRouteTest evaluates route tree of VMAlertmanager or alertmanager config file for the given labels
+// and prints matched routes
+func RunAlertmanagerRouteTest(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet(AlertmanagerRouteTestCmd, flag.ContinueOnError)
+	namespace := fs.String("namespace", "", "namespace of VMAlertmanager")
+	name := fs.String("name", "", "name of VMAlertmanager")
+	configFile := fs.String("config-file", "", "path to alertmanager config file. If set, it's used instead of config generated for VMAlertmanager")
+	output := fs.String("output", "table", "output format. Can be table or json")
+	lset := labelsFlag{}
+	fs.Var(lset, "label", "alert label in form of name=value. Can be set multiple times")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var result *alertmanager.RoutingResult
+	switch {
+	case *configFile != "":
+		data, err := os.ReadFile(*configFile)
+		if err != nil {
+			return fmt.Errorf("cannot read config file: %w", err)
+		}
+		result, err = alertmanager.EvaluateRouting(data, lset)
+		if err != nil {
+			return err
+		}
+	case *namespace != "" && *name != "":
+		cfg, err := ctrl.GetConfig()
+		if err != nil {
+			return fmt.Errorf("cannot get kubernetes client config: %w", err)
+		}
+		rclient, err := client.New(cfg, client.Options{Scheme: scheme})
+		if err != nil {
+			return fmt.Errorf("cannot create kubernetes client: %w", err)
+		}
+		var am vmv1beta1.VMAlertmanager
+		if err := rclient.Get(ctx, types.NamespacedName{Namespace: *namespace, Name: *name}, &am); err != nil {
+			return fmt.Errorf("cannot get vmalertmanager: %w", err)
+		}
+		result, err = alertmanager.EvaluateAlertmanagerRouting(ctx, rclient, &am, lset)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("either -config-file or -namespace and -name flags must be set")
+	}
+	return printRoutingResult(os.Stdout, result, *output)
+}
+
+func printRoutingResult(w io.Writer, result *alertmanager.RoutingResult, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "PATH\tRECEIVER\tGROUP_BY\tGROUP_WAIT\tGROUP_INTERVAL\tREPEAT_INTERVAL\tMUTE_TIME_INTERVALS\tACTIVE_TIME_INTERVALS")
+		for _, r := range result.Routes {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Path, r.Receiver, strings.Join(r.GroupBy, ","),
+				r.GroupWait, r.GroupInterval, r.RepeatInterval, strings.Join(r.MuteTimeIntervals, ","), strings.Join(r.ActiveTimeIntervals, ","))
+		}
+		if len(result.InhibitRules) > 0 {
+			fmt.Fprintln(tw)
+			fmt.Fprintln(tw, "INHIBIT_RULE\tSOURCE_MATCHERS\tEQUAL")
+			for _, ir := range result.InhibitRules {
+				fmt.Fprintf(tw, "inhibit_rules[%d]\t%s\t%s\n", ir.Index, strings.Join(ir.SourceMatchers, ","), strings.Join(ir.Equal, ","))
+			}
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unsupported output format=%q, want table or json", format)
+	}
+}
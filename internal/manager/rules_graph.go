@@ -0,0 +1,41 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/vmalert"
+)
+
+const vmalertRulesGraphPath = "/debug/vmalert/rules-graph"
+
+// newVMAlertRulesGraphHandler returns handler, which builds dependencies graph of rule groups selected by VMAlert
+//
+// example request: /debug/vmalert/rules-graph?namespace=monitoring&name=main
+func newVMAlertRulesGraphHandler(rclient client.Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		nsn := types.NamespacedName{Namespace: q.Get("namespace"), Name: q.Get("name")}
+		if nsn.Namespace == "" || nsn.Name == "" {
+			http.Error(w, "namespace and name query args are required", http.StatusBadRequest)
+			return
+		}
+		var cr vmv1beta1.VMAlert
+		if err := rclient.Get(r.Context(), nsn, &cr); err != nil {
+			http.Error(w, fmt.Sprintf("cannot get vmalert: %s", err), http.StatusNotFound)
+			return
+		}
+		result, err := vmalert.BuildRulesGraph(r.Context(), rclient, &cr)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+	})
+}
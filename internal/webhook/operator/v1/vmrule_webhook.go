@@ -0,0 +1,31 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	vmv1 "github.com/VictoriaMetrics/operator/api/operator/v1"
+)
+
+// SetupVMRuleWebhookWithManager will setup the manager to serve conversion webhook for VMRule
+// v1 objects are validated by v1beta1 webhook, since it uses Equivalent match policy
+func SetupVMRuleWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&vmv1.VMRule{}).
+		Complete()
+}
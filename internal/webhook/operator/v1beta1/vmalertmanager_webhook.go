@@ -23,26 +23,30 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/alertmanager"
 )
 
 // SetupVMAlertmanagerWebhookWithManager will setup the manager to manage the webhooks
 func SetupVMAlertmanagerWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&vmv1beta1.VMAlertmanager{}).
-		WithValidator(&VMAlertmanagerCustomValidator{}).
+		WithValidator(&VMAlertmanagerCustomValidator{client: mgr.GetClient()}).
 		Complete()
 }
 
 // +kubebuilder:webhook:path=/validate-operator-victoriametrics-com-v1beta1-vmalertmanager,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.victoriametrics.com,resources=vmalertmanagers,verbs=create;update,versions=v1beta1,name=vvmalertmanager-v1beta1.kb.io,admissionReviewVersions=v1
-type VMAlertmanagerCustomValidator struct{}
+type VMAlertmanagerCustomValidator struct {
+	client client.Client
+}
 
 var _ admission.CustomValidator = &VMAlertmanagerCustomValidator{}
 
 // ValidateCreate implements admission.CustomValidator so a webhook will be registered for the type
-func (*VMAlertmanagerCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *VMAlertmanagerCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	r, ok := obj.(*vmv1beta1.VMAlertmanager)
 	if !ok {
 		return nil, fmt.Errorf("BUG: unexpected type: %T", obj)
@@ -53,11 +57,11 @@ func (*VMAlertmanagerCustomValidator) ValidateCreate(_ context.Context, obj runt
 	if err := r.Validate(); err != nil {
 		return nil, err
 	}
-	return nil, nil
+	return v.validateTemplates(ctx, r)
 }
 
 // ValidateUpdate implements admission.CustomValidator so a webhook will be registered for the type
-func (*VMAlertmanagerCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+func (v *VMAlertmanagerCustomValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
 	r, ok := newObj.(*vmv1beta1.VMAlertmanager)
 	if !ok {
 		return nil, fmt.Errorf("BUG: unexpected type: %T", newObj)
@@ -68,6 +72,21 @@ func (*VMAlertmanagerCustomValidator) ValidateUpdate(_ context.Context, _, newOb
 	if err := r.Validate(); err != nil {
 		return nil, err
 	}
+	return v.validateTemplates(ctx, r)
+}
+
+// validateTemplates checks that templates referenced by VMAlertmanager could be parsed
+// missing templates are reported as warning, since ConfigMap could be created later
+func (v *VMAlertmanagerCustomValidator) validateTemplates(ctx context.Context, r *vmv1beta1.VMAlertmanager) (admission.Warnings, error) {
+	if v.client == nil || !r.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	if err := alertmanager.ValidateTemplates(ctx, v.client, r); err != nil {
+		if errors.Is(err, alertmanager.ErrTemplateNotLoaded) {
+			return admission.Warnings{err.Error()}, nil
+		}
+		return nil, err
+	}
 	return nil, nil
 }
 
@@ -20,29 +20,37 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	vmv1beta1 "github.com/VictoriaMetrics/operator/api/operator/v1beta1"
+	"github.com/VictoriaMetrics/operator/internal/controller/operator/factory/alertmanager"
 )
 
 // SetupVMAlertmanagerConfigWebhookWithManager will setup the manager to manage the webhooks
 func SetupVMAlertmanagerConfigWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&vmv1beta1.VMAlertmanagerConfig{}).
-		WithValidator(&VMAlertmanagerConfigCustomValidator{}).
+		WithValidator(&VMAlertmanagerConfigCustomValidator{client: mgr.GetClient()}).
 		Complete()
 }
 
 // +kubebuilder:webhook:path=/validate-operator-victoriametrics-com-v1beta1-vmalertmanagerconfig,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.victoriametrics.com,resources=vmalertmanagerconfigs,verbs=create;update,versions=v1beta1,name=vvmalertmanagerconfig-v1beta1.kb.io,admissionReviewVersions=v1
-type VMAlertmanagerConfigCustomValidator struct{}
+type VMAlertmanagerConfigCustomValidator struct {
+	client client.Client
+}
+
+// fullConfigValidationTimeout limits time spent on rendering of merged VMAlertmanager configurations
+const fullConfigValidationTimeout = 5 * time.Second
 
 var _ admission.CustomValidator = &VMAlertmanagerConfigCustomValidator{}
 
 // ValidateCreate implements admission.CustomValidator so a webhook will be registered for the type
-func (*VMAlertmanagerConfigCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *VMAlertmanagerConfigCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	r, ok := obj.(*vmv1beta1.VMAlertmanagerConfig)
 	if !ok {
 		return nil, fmt.Errorf("BUG: unexpected type: %T", obj)
@@ -53,11 +61,11 @@ func (*VMAlertmanagerConfigCustomValidator) ValidateCreate(_ context.Context, ob
 	if err := r.Validate(); err != nil {
 		return nil, err
 	}
-	return nil, nil
+	return v.validateFullConfig(ctx, r)
 }
 
 // ValidateUpdate implements admission.CustomValidator so a webhook will be registered for the type
-func (*VMAlertmanagerConfigCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+func (v *VMAlertmanagerConfigCustomValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
 	r, ok := newObj.(*vmv1beta1.VMAlertmanagerConfig)
 	if !ok {
 		return nil, fmt.Errorf("BUG: unexpected type: %T", newObj)
@@ -69,6 +77,23 @@ func (*VMAlertmanagerConfigCustomValidator) ValidateUpdate(_ context.Context, _,
 	if err := r.Validate(); err != nil {
 		return nil, err
 	}
+	return v.validateFullConfig(ctx, r)
+}
+
+// validateFullConfig checks merged configuration of each VMAlertmanager, which selects given config
+// validation is skipped with warning if it takes too long
+func (v *VMAlertmanagerConfigCustomValidator) validateFullConfig(ctx context.Context, r *vmv1beta1.VMAlertmanagerConfig) (admission.Warnings, error) {
+	if v.client == nil || !r.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, fullConfigValidationTimeout)
+	defer cancel()
+	if err := alertmanager.ValidateConfigForAlertmanagers(ctx, v.client, r); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return admission.Warnings{fmt.Sprintf("validation of merged vmalertmanager configuration was skipped: %s", err)}, nil
+		}
+		return nil, err
+	}
 	return nil, nil
 }
 